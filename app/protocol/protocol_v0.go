@@ -29,6 +29,7 @@ import (
 	"github.com/okex/okchain/x/genutil"
 	"github.com/okex/okchain/x/gov"
 	"github.com/okex/okchain/x/gov/keeper"
+	"github.com/okex/okchain/x/margin"
 	"github.com/okex/okchain/x/order"
 	"github.com/okex/okchain/x/params"
 	paramsclient "github.com/okex/okchain/x/params/client"
@@ -62,7 +63,8 @@ var (
 		distr.AppModuleBasic{},
 		gov.NewAppModuleBasic(
 			upgradeClient.ProposalHandler, paramsclient.ProposalHandler,
-			dexClient.DelistProposalHandler,
+			dexClient.DelistProposalHandler, dexClient.MatchingModeProposalHandler,
+			dexClient.PriceBandProposalHandler,
 		),
 		params.AppModuleBasic{},
 		crisis.AppModuleBasic{},
@@ -73,6 +75,7 @@ var (
 		token.AppModuleBasic{},
 		dex.AppModuleBasic{},
 		order.AppModuleBasic{},
+		margin.AppModuleBasic{},
 		backend.AppModuleBasic{},
 		upgrade.AppModuleBasic{},
 		stream.AppModuleBasic{},
@@ -90,6 +93,7 @@ var (
 		order.ModuleName:          nil,
 		backend.ModuleName:        nil,
 		dex.ModuleName:            nil,
+		margin.ModuleName:         nil,
 	}
 )
 
@@ -119,6 +123,7 @@ type ProtocolV0 struct {
 	tokenKeeper    token.Keeper
 	dexKeeper      dex.Keeper
 	orderKeeper    order.Keeper
+	marginKeeper   margin.Keeper
 	protocolKeeper proto.ProtocolKeeper
 	backendKeeper  backend.Keeper
 	streamKeeper   stream.Keeper
@@ -264,6 +269,10 @@ func (p *ProtocolV0) produceKeepers() {
 	orderSubspace := p.paramsKeeper.Subspace(order.DefaultParamspace)
 	upgradeSubspace := p.paramsKeeper.Subspace(upgrade.DefaultParamspace)
 	dexSubspace := p.paramsKeeper.Subspace(dex.DefaultParamspace)
+	marginSubspace := p.paramsKeeper.Subspace(margin.DefaultParamspace)
+	p.paramsKeeper.RegisterParamSet(order.DefaultParamspace, func() params.ValidatableParamSet {
+		return &order.Params{}
+	})
 
 	// 2.add keepers
 	p.accountKeeper = auth.NewAccountKeeper(p.cdc, p.keys[auth.StoreKey], authSubspace, auth.ProtoBaseAccount)
@@ -303,6 +312,9 @@ func (p *ProtocolV0) produceKeepers() {
 		p.cdc, appConfig.BackendConfig.EnableBackend, orderMetrics,
 	)
 
+	p.marginKeeper = margin.NewKeeper(p.cdc, p.keys[margin.StoreKey], marginSubspace, p.supplyKeeper, p.tokenKeeper,
+		p.dexKeeper, &p.orderKeeper)
+
 	p.streamKeeper = stream.NewKeeper(p.orderKeeper, p.tokenKeeper, p.dexKeeper, p.accountKeeper, p.cdc, p.logger,
 		appConfig, streamMetrics)
 
@@ -326,10 +338,14 @@ func (p *ProtocolV0) produceKeepers() {
 	)
 	p.paramsKeeper.SetGovKeeper(p.govKeeper)
 	p.dexKeeper.SetGovKeeper(p.govKeeper)
-	// 4.register the staking hooks
+	// 4.register the staking and order hooks
 	p.stakingKeeper = *stakingKeeper.SetHooks(
 		staking.NewMultiStakingHooks(p.distrKeeper.Hooks(), p.slashingKeeper.Hooks()),
 	)
+	// register the order hooks, the modules observing orders add their hooks here
+	p.orderKeeper = *p.orderKeeper.SetHooks(
+		order.NewMultiOrderHooks(),
+	)
 	p.upgradeKeeper = upgrade.NewKeeper(
 		p.cdc, p.keys[upgrade.StoreKey], p.protocolKeeper, p.stakingKeeper, p.bankKeeper, upgradeSubspace,
 	)
@@ -362,6 +378,7 @@ func (p *ProtocolV0) setManager() {
 		distr.NewAppModule(p.distrKeeper, p.supplyKeeper),
 		gov.NewAppModule(version.ProtocolVersionV0, p.govKeeper, p.supplyKeeper),
 		order.NewAppModule(version.ProtocolVersionV0, p.orderKeeper, p.supplyKeeper),
+		margin.NewAppModule(p.marginKeeper),
 		token.NewAppModule(version.ProtocolVersionV0, p.tokenKeeper, p.supplyKeeper),
 
 		// TODO
@@ -386,6 +403,7 @@ func (p *ProtocolV0) setManager() {
 		crisis.ModuleName,
 		gov.ModuleName,
 		dex.ModuleName,
+		margin.ModuleName,
 		order.ModuleName,
 		staking.ModuleName,
 		backend.ModuleName,
@@ -409,6 +427,7 @@ func (p *ProtocolV0) setManager() {
 		token.ModuleName,
 		dex.ModuleName,
 		order.ModuleName,
+		margin.ModuleName,
 		upgrade.ModuleName,
 	)
 }
@@ -486,6 +505,10 @@ func validateMsgHook(orderKeeper order.Keeper) auth.ValidateMsgHandler {
 				return order.ValidateMsgNewOrders(newCtx, orderKeeper, assertedMsg)
 			case order.MsgCancelOrders:
 				return order.ValidateMsgCancelOrders(newCtx, orderKeeper, assertedMsg)
+			case order.MsgAmendOrder:
+				return order.ValidateMsgAmendOrder(newCtx, orderKeeper, assertedMsg)
+			case order.MsgCancelAllOrders:
+				return order.ValidateMsgCancelAllOrders(newCtx, orderKeeper, assertedMsg)
 			}
 		}
 		return sdk.Result{}
@@ -497,9 +520,10 @@ func isSystemFreeHook(ctx sdk.Context, msgs []sdk.Msg) bool {
 		return true
 	}
 
+	// amending an order charges no order fee, so it pays the system fee to keep the depth book from being churned
 	for _, msg := range msgs {
 		switch msg.(type) {
-		case order.MsgNewOrders, order.MsgCancelOrders:
+		case order.MsgNewOrders, order.MsgCancelOrders, order.MsgCancelAllOrders:
 		default:
 			return false
 		}
@@ -183,4 +183,7 @@ func TestProtocolV0_Hooks(t *testing.T) {
 
 	// condition 3
 	require.False(t, isSystemFreeHook(mockContext, mockMsgs3))
+
+	// amending orders is not free
+	require.False(t, isSystemFreeHook(mockContext, []sdk.Msg{order.MsgAmendOrder{}}))
 }
@@ -13,6 +13,7 @@ import (
 	//distr "github.com/okex/okchain/x/distribution"
 	distr "github.com/okex/okchain/x/distribution"
 	"github.com/okex/okchain/x/gov"
+	"github.com/okex/okchain/x/margin"
 	"github.com/okex/okchain/x/order"
 	"github.com/okex/okchain/x/params"
 
@@ -37,6 +38,7 @@ var (
 		order.OrderStoreKey,
 		upgrade.StoreKey,
 		dex.StoreKey, dex.TokenPairStoreKey,
+		margin.StoreKey,
 	)
 
 	transientStoreKeysMap = sdk.NewTransientStoreKeys(staking.TStoreKey, params.TStoreKey)
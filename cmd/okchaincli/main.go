@@ -16,6 +16,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	authcmd "github.com/cosmos/cosmos-sdk/x/auth/client/cli"
 	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/okex/okchain/x/order/simulator"
 	tokencli "github.com/okex/okchain/x/token/client/cli"
 
 	"github.com/spf13/cobra"
@@ -70,6 +71,8 @@ func main() {
 		queryCmd(cdc),
 		txCmd(cdc),
 		client.LineBreak,
+		simulator.GetCmdSimulate(cdc),
+		client.LineBreak,
 		lcd.ServeCommand(cdc, registerRoutes),
 		client.LineBreak,
 		keys.Commands(),
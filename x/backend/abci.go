@@ -20,6 +20,7 @@ func EndBlocker(ctx sdk.Context, keeper Keeper) {
 		storeDealAndMatchResult(ctx, keeper)
 		storeFeeDetails(keeper)
 		storeTransactions(keeper)
+		storeDepthBookDiffs(keeper)
 		keeper.Flush()
 		keeper.Logger.Debug(fmt.Sprintf("end backend endblocker: block---%d", ctx.BlockHeight()))
 	}
@@ -39,6 +40,12 @@ func storeTransactions(keeper Keeper) {
 	}
 }
 
+func storeDepthBookDiffs(keeper Keeper) {
+	for _, diff := range keeper.OrderKeeper.GetDepthBookDiffs() {
+		keeper.Cache.AddDepthBookDiff(diff)
+	}
+}
+
 func storeDealAndMatchResult(ctx sdk.Context, keeper Keeper) {
 	timestamp := ctx.BlockHeader().Time.Unix()
 	keeper.Orm.MaxBlockTimestamp = timestamp
@@ -141,6 +148,12 @@ func GetNewDealsAndMatchResultsAtEndBlock(ctx sdk.Context, orderKeeper types.Ord
 		for _, record := range matchResult.Deals {
 			order := orderKeeper.GetOrder(ctx, record.OrderID)
 			if quantity, err := strconv.ParseFloat(record.Quantity.String(), 64); err == nil {
+				dealPrice := price
+				if !record.Price.IsNil() {
+					if p, err := strconv.ParseFloat(record.Price.String(), 64); err == nil {
+						dealPrice = p
+					}
+				}
 
 				deal := &types.Deal{
 					BlockHeight: blockHeight,
@@ -148,7 +161,7 @@ func GetNewDealsAndMatchResultsAtEndBlock(ctx sdk.Context, orderKeeper types.Ord
 					Side:        record.Side,
 					Sender:      order.Sender.String(),
 					Product:     product,
-					Price:       price,
+					Price:       dealPrice,
 					Quantity:    quantity,
 					Fee:         record.Fee,
 					Timestamp:   ctx.BlockHeader().Time.Unix(),
@@ -182,6 +195,7 @@ func GetNewOrdersAtEndBlock(ctx sdk.Context, orderKeeper types.OrderKeeper) ([]*
 				FilledAvgPrice: order.FilledAvgPrice.String(),
 				RemainQuantity: order.RemainQuantity.String(),
 				Timestamp:      order.Timestamp,
+				ClientOrderId:  order.ClientOrderID,
 			}
 			orders = append(orders, orderDb)
 		} else {
@@ -209,6 +223,7 @@ func GetUpdatedOrdersAtEndBlock(ctx sdk.Context, orderKeeper types.OrderKeeper)
 				FilledAvgPrice: order.FilledAvgPrice.String(),
 				RemainQuantity: order.RemainQuantity.String(),
 				Timestamp:      order.Timestamp,
+				ClientOrderId:  order.ClientOrderID,
 			}
 			orders = append(orders, orderDb)
 		}
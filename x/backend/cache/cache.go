@@ -1,21 +1,29 @@
 package cache
 
-import "github.com/okex/okchain/x/backend/types"
+import (
+	"github.com/okex/okchain/x/backend/types"
+	orderTypes "github.com/okex/okchain/x/order/types"
+)
+
+// MaxDepthBookDiffs is the max number of the latest depth book diffs kept for a product
+const MaxDepthBookDiffs = 1000
 
 type Cache struct {
 	// Flush at EndBlock
 	Transactions []*types.Transaction
 
 	// persist in memory
-	LatestTicker map[string]*types.Ticker
-	ProductsBuf  []string
+	LatestTicker   map[string]*types.Ticker
+	ProductsBuf    []string
+	DepthBookDiffs map[string][]*orderTypes.DepthBookDiff
 }
 
 func NewCache() *Cache {
 	return &Cache{
-		Transactions: make([]*types.Transaction, 0, 2000),
-		LatestTicker: make(map[string]*types.Ticker),
-		ProductsBuf:  make([]string, 0, 200),
+		Transactions:   make([]*types.Transaction, 0, 2000),
+		LatestTicker:   make(map[string]*types.Ticker),
+		ProductsBuf:    make([]string, 0, 200),
+		DepthBookDiffs: make(map[string][]*orderTypes.DepthBookDiff),
 	}
 }
 
@@ -31,3 +39,23 @@ func (c *Cache) AddTransaction(transaction *types.Transaction) {
 func (c *Cache) GetTransactions() []*types.Transaction {
 	return c.Transactions
 }
+
+// AddDepthBookDiff keeps the diff as the latest one of its product, and drops the oldest one beyond MaxDepthBookDiffs
+func (c *Cache) AddDepthBookDiff(diff *orderTypes.DepthBookDiff) {
+	diffs := append(c.DepthBookDiffs[diff.Product], diff)
+	if len(diffs) > MaxDepthBookDiffs {
+		diffs = diffs[len(diffs)-MaxDepthBookDiffs:]
+	}
+	c.DepthBookDiffs[diff.Product] = diffs
+}
+
+// GetDepthBookDiffs returns the cached diffs of the product following the sequence
+func (c *Cache) GetDepthBookDiffs(product string, sequence int64) []*orderTypes.DepthBookDiff {
+	diffs := c.DepthBookDiffs[product]
+	for i, diff := range diffs {
+		if diff.Sequence > sequence {
+			return diffs[i:]
+		}
+	}
+	return []*orderTypes.DepthBookDiff{}
+}
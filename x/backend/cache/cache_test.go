@@ -5,6 +5,7 @@ import (
 
 	"github.com/okex/okchain/x/backend/types"
 	"github.com/okex/okchain/x/common"
+	orderTypes "github.com/okex/okchain/x/order/types"
 	"github.com/stretchr/testify/require"
 )
 
@@ -33,3 +34,24 @@ func TestCache(t *testing.T) {
 	require.Equal(t, 0, len(cache.GetTransactions()))
 
 }
+
+func TestCacheDepthBookDiffs(t *testing.T) {
+	cache := NewCache()
+	require.Equal(t, 0, len(cache.GetDepthBookDiffs(types.TestTokenPair, 0)))
+
+	for sequence := int64(1); sequence <= MaxDepthBookDiffs+10; sequence++ {
+		cache.AddDepthBookDiff(&orderTypes.DepthBookDiff{Product: types.TestTokenPair, Sequence: sequence})
+	}
+	require.Equal(t, MaxDepthBookDiffs, len(cache.DepthBookDiffs[types.TestTokenPair]))
+
+	// the oldest diffs are dropped
+	diffs := cache.GetDepthBookDiffs(types.TestTokenPair, 0)
+	require.Equal(t, MaxDepthBookDiffs, len(diffs))
+	require.EqualValues(t, 11, diffs[0].Sequence)
+
+	diffs = cache.GetDepthBookDiffs(types.TestTokenPair, MaxDepthBookDiffs+8)
+	require.Equal(t, 2, len(diffs))
+	require.EqualValues(t, MaxDepthBookDiffs+9, diffs[0].Sequence)
+	require.Equal(t, 0, len(cache.GetDepthBookDiffs(types.TestTokenPair, MaxDepthBookDiffs+10)))
+	require.Equal(t, 0, len(cache.GetDepthBookDiffs(common.TestToken, 0)))
+}
@@ -31,6 +31,7 @@ func GetQueryCmd(queryRoute string, cdc *codec.Codec) *cobra.Command {
 		GetCmdOrderList(queryRoute, cdc),
 		GetCmdCandles(queryRoute, cdc),
 		GetCmdTickers(queryRoute, cdc),
+		GetCmdDepthDiffs(queryRoute, cdc),
 		GetCmdTxList(queryRoute, cdc),
 		GetBlockTxHashesCommand(queryRoute, cdc),
 	)
@@ -217,6 +218,41 @@ func GetCmdTickers(queryRoute string, cdc *codec.Codec) *cobra.Command {
 	return cmd
 }
 
+// GetCmdDepthDiffs queries the depth book diffs of a product following a sequence
+func GetCmdDepthDiffs(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "depthdiffs [product]",
+		Short: "get depth book diffs following the sequence",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			sequence, err := cmd.Flags().GetInt64("sequence")
+			if err != nil {
+				return err
+			}
+
+			bz, err := cdc.MarshalJSON(types.NewQueryDepthDiffsParams(args[0], sequence))
+			var out bytes.Buffer
+			if err != nil {
+				return err
+			}
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", queryRoute, types.QueryDepthDiffs), bz)
+			if err != nil {
+				fmt.Printf("failed to get depth diffs: %v\n", err)
+				return nil
+			} else {
+				err = json.Indent(&out, res, "", "  ")
+			}
+
+			fmt.Println(string(out.Bytes()))
+			return nil
+		},
+	}
+	cmd.Flags().Int64("sequence", 0, "sequence of the depth book snapshot")
+	return cmd
+}
+
 // GetCmdFeeDetails queries fee details of a user
 func GetCmdFeeDetails(queryRoute string, cdc *codec.Codec) *cobra.Command {
 	cmd := &cobra.Command{
@@ -18,6 +18,7 @@ func RegisterRoutes(cliCtx context.CLIContext, r *mux.Router) {
 	r.HandleFunc("/candles/{product}", candleHandler(cliCtx)).Methods("GET")
 	r.HandleFunc("/tickers", tickerHandler(cliCtx)).Methods("GET")
 	r.HandleFunc("/tickers/{product}", tickerHandler(cliCtx)).Methods("GET")
+	r.HandleFunc("/depthdiffs/{product}", depthDiffsHandler(cliCtx)).Methods("GET")
 	r.HandleFunc("/matches", matchHandler(cliCtx)).Methods("GET")
 	r.HandleFunc("/deals", dealHandler(cliCtx)).Methods("GET")
 	r.HandleFunc("/fees", feeDetailListHandler(cliCtx)).Methods("GET")
@@ -109,6 +110,35 @@ func tickerHandler(cliCtx context.CLIContext) http.HandlerFunc {
 	}
 }
 
+func depthDiffsHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		product := mux.Vars(r)["product"]
+
+		strSequence := r.URL.Query().Get("sequence")
+		if len(strSequence) == 0 {
+			strSequence = "0"
+		}
+		sequence, err0 := strconv.ParseInt(strSequence, 10, 64)
+		if err0 != nil {
+			common.HandleErrorMsg(w, cliCtx, fmt.Sprintf("parameter sequence %s not correct", strSequence))
+			return
+		}
+
+		bz, err := cliCtx.Codec.MarshalJSON(types.NewQueryDepthDiffsParams(product, sequence))
+		if err != nil {
+			common.HandleErrorMsg(w, cliCtx, err.Error())
+			return
+		}
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/backend/%s", types.QueryDepthDiffs), bz)
+		if err != nil {
+			common.HandleErrorMsg(w, cliCtx, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
 func matchHandler(cliCtx context.CLIContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		product := r.URL.Query().Get("product")
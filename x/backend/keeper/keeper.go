@@ -14,6 +14,7 @@ import (
 	"github.com/okex/okchain/x/backend/config"
 	"github.com/okex/okchain/x/backend/orm"
 	"github.com/okex/okchain/x/backend/types"
+	orderTypes "github.com/okex/okchain/x/order/types"
 	"github.com/okex/okchain/x/token"
 	"github.com/tendermint/tendermint/libs/log"
 )
@@ -116,6 +117,25 @@ func (k Keeper) GetTransactionList(ctx sdk.Context, addr string, txType, startTi
 	return k.Orm.GetTransactionList(addr, txType, startTime, endTime, offset, limit)
 }
 
+// GetDepthBookDiffs returns the depth book diffs of the product following the sequence,
+// which fails if some of them are no longer cached and the depth book has to be rebuilt from a new snapshot
+func (k Keeper) GetDepthBookDiffs(ctx sdk.Context, product string, sequence int64) ([]*orderTypes.DepthBookDiff, error) {
+	latestSequence := k.OrderKeeper.GetDepthBookSequence(ctx, product)
+	if sequence < 0 || sequence > latestSequence {
+		return nil, fmt.Errorf("sequence %d of %s is out of range [0, %d]", sequence, product, latestSequence)
+	}
+	// the cache might run ahead of the committed state
+	diffs := k.Cache.GetDepthBookDiffs(product, sequence)
+	if int64(len(diffs)) > latestSequence-sequence {
+		diffs = diffs[:latestSequence-sequence]
+	}
+	if int64(len(diffs)) != latestSequence-sequence || (len(diffs) > 0 && diffs[0].Sequence != sequence+1) {
+		return nil, fmt.Errorf("diffs of %s following sequence %d are not cached, "+
+			"please query the depth book again", product, sequence)
+	}
+	return diffs, nil
+}
+
 func (k Keeper) GetAllProducts(ctx sdk.Context) []string {
 	products := []string{}
 	tokenPairs := k.dexKeeper.GetTokenPairs(ctx)
@@ -52,6 +52,8 @@ func NewQuerier(keeper Keeper) sdk.Querier {
 			res, err = queryOrderList(ctx, path[1:], req, keeper)
 		case types.QueryTxList:
 			res, err = queryTxList(ctx, path[1:], req, keeper)
+		case types.QueryDepthDiffs:
+			res, err = queryDepthDiffs(ctx, req, keeper)
 		case types.QueryCandleList:
 			if keeper.Config.EnableMktCompute {
 				res, err = queryCandleList(ctx, path[1:], req, keeper)
@@ -141,6 +143,24 @@ func queryDeals(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Ke
 	return bz, nil
 }
 
+func queryDepthDiffs(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	var params types.QueryDepthDiffsParams
+	err := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrUnknownRequest(sdk.AppendMsgToErr("incorrectly formatted request data", err.Error()))
+	}
+
+	diffs, err := keeper.GetDepthBookDiffs(ctx, params.Product, params.Sequence)
+	if err != nil {
+		return nil, sdk.ErrUnknownRequest(err.Error())
+	}
+	bz, err := json.Marshal(common.GetBaseResponse(diffs))
+	if err != nil {
+		return nil, sdk.ErrInternal(err.Error())
+	}
+	return bz, nil
+}
+
 // nolint: unparam
 func queryMatchResults(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
 	var params types.QueryMatchParams
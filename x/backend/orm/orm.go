@@ -1120,16 +1120,17 @@ func (orm *ORM) BatchInsertOrUpdate(newOrders []*types.Order, updatedOrders []*t
 	// 1. Batch Insert Orders.
 	orderVItems := []string{}
 	for _, order := range newOrders {
-		vItem := fmt.Sprintf("('%s','%s','%s','%s','%s','%s','%s','%d','%s','%s','%d')",
+		vItem := fmt.Sprintf("('%s','%s','%s','%s','%s','%s','%s','%d','%s','%s','%d','%s')",
 			order.TxHash, order.OrderId, order.Sender, order.Product, order.Side, order.Price, order.Quantity,
-			order.Status, order.FilledAvgPrice, order.RemainQuantity, order.Timestamp)
+			order.Status, order.FilledAvgPrice, order.RemainQuantity, order.Timestamp, order.ClientOrderId)
 		orderVItems = append(orderVItems, vItem)
 
 	}
 	if len(orderVItems) > 0 {
 		orderValueSQL := strings.Join(orderVItems, ", ")
 		orderSQL := fmt.Sprintf("INSERT INTO `orders` (`tx_hash`,`order_id`,`sender`,`product`,`side`,`price`,"+
-			"`quantity`,`status`,`filled_avg_price`,`remain_quantity`,`timestamp`) VALUES %s", orderValueSQL)
+			"`quantity`,`status`,`filled_avg_price`,`remain_quantity`,`timestamp`,`client_order_id`) VALUES %s",
+			orderValueSQL)
 		ret := trx.Exec(orderSQL)
 		if ret.Error != nil {
 			return resultMap, ret.Error
@@ -580,10 +580,10 @@ func TestSqlite3_FeeDetails(t *testing.T) {
 func testORMOrders(t *testing.T, orm *ORM) {
 
 	orders := []*types.Order{
-		{"hash1", "ID1", "addr1", types.TestTokenPair, types.BuyOrder, "10.0", "1.1", 0, "0", "1.1", 100},
-		{"hash2", "ID2", "addr1", "btc_" + common.NativeToken, types.BuyOrder, "10.0", "1.1", 0, "0", "1.1", 300},
-		{"hash3", "ID3", "addr1", types.TestTokenPair, types.BuyOrder, "10.0", "1.1", 0, "0", "1.1", 200},
-		{"hash4", "ID4", "addr2", types.TestTokenPair, types.BuyOrder, "10.0", "1.1", 0, "0", "1.1", 150},
+		{"hash1", "ID1", "addr1", types.TestTokenPair, types.BuyOrder, "10.0", "1.1", 0, "0", "1.1", 100, ""},
+		{"hash2", "ID2", "addr1", "btc_" + common.NativeToken, types.BuyOrder, "10.0", "1.1", 0, "0", "1.1", 300, ""},
+		{"hash3", "ID3", "addr1", types.TestTokenPair, types.BuyOrder, "10.0", "1.1", 0, "0", "1.1", 200, ""},
+		{"hash4", "ID4", "addr2", types.TestTokenPair, types.BuyOrder, "10.0", "1.1", 0, "0", "1.1", 150, ""},
 	}
 	// Test AddOrders
 	cnt, err := orm.AddOrders(orders)
@@ -611,9 +611,9 @@ func testORMOrders(t *testing.T, orm *ORM) {
 
 	// TestUpdateOrders
 	updateOrders := []*types.Order{
-		{"hash1", "ID1", "addr1", types.TestTokenPair, types.BuyOrder, "10.0", "1.1", 3, "0", "0", 100},
-		{"hash2", "ID2", "addr1", "btc_" + common.NativeToken, types.BuyOrder, "10.0", "1.1", 2, "0", "1.1", 300},
-		{"hash3", "ID3", "addr1", types.TestTokenPair, types.BuyOrder, "10.0", "1.1", 4, "0", "1.1", 200},
+		{"hash1", "ID1", "addr1", types.TestTokenPair, types.BuyOrder, "10.0", "1.1", 3, "0", "0", 100, ""},
+		{"hash2", "ID2", "addr1", "btc_" + common.NativeToken, types.BuyOrder, "10.0", "1.1", 2, "0", "1.1", 300, ""},
+		{"hash3", "ID3", "addr1", types.TestTokenPair, types.BuyOrder, "10.0", "1.1", 4, "0", "1.1", 200, ""},
 	}
 	cnt, err = orm.UpdateOrders(updateOrders)
 	require.Nil(t, err)
@@ -740,12 +740,12 @@ func testORMBatchInsert(t *testing.T, orm *ORM) {
 
 	for i := 0; i < 2000; i++ {
 		oid := fmt.Sprintf("FAKEID-%04d", i)
-		o := types.Order{"hash1", oid, "addr1", types.TestTokenPair, types.BuyOrder, "10.0", "1.1", 0, "1.3", "1.5", 100}
+		o := types.Order{"hash1", oid, "addr1", types.TestTokenPair, types.BuyOrder, "10.0", "1.1", 0, "1.3", "1.5", 100, ""}
 		newOrders = append(newOrders, &o)
 	}
 
 	updatedOrders := []*types.Order{
-		{"hash2", "FAKEID-0002", "addr1", types.TestTokenPair, types.BuyOrder, "10.0", "1.1", 0, "1.4", "1.7", 100},
+		{"hash2", "FAKEID-0002", "addr1", types.TestTokenPair, types.BuyOrder, "10.0", "1.1", 0, "1.4", "1.7", 100, ""},
 	}
 
 	txs := []*types.Transaction{
@@ -848,7 +848,7 @@ func TestORM_CloseDB(t *testing.T) {
 	require.Equal(t, 0, cnt)
 
 	cnt, err = closeORM.AddOrders([]*types.Order{
-		{"hash1", "ID1", "addr1", types.TestTokenPair, types.BuyOrder, "10.0", "1.1", 0, "0", "1.1", 100},
+		{"hash1", "ID1", "addr1", types.TestTokenPair, types.BuyOrder, "10.0", "1.1", 0, "0", "1.1", 100, ""},
 	})
 	require.Error(t, err)
 	require.Equal(t, 0, cnt)
@@ -860,7 +860,7 @@ func TestORM_CloseDB(t *testing.T) {
 	require.Equal(t, 0, cnt)
 
 	cnt, err = closeORM.UpdateOrders([]*types.Order{
-		{"hash1", "ID1", "addr1", types.TestTokenPair, types.BuyOrder, "10.0", "1.1", 0, "0", "1.1", 100},
+		{"hash1", "ID1", "addr1", types.TestTokenPair, types.BuyOrder, "10.0", "1.1", 0, "0", "1.1", 100, ""},
 	})
 	require.Error(t, err)
 	require.Equal(t, 0, cnt)
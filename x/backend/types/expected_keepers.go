@@ -11,11 +11,14 @@ import (
 // expected order keeper
 type OrderKeeper interface {
 	GetOrder(ctx sdk.Context, orderId string) *order.Order
+	GetOrderIDByClientOrderID(ctx sdk.Context, sender sdk.AccAddress, clientOrderID string) string
 	GetUpdatedOrderIDs() []string
 	GetBlockOrderNum(ctx sdk.Context, blockHeight int64) int64
 	GetBlockMatchResult() *ordertypes.BlockMatchResult
 	GetLastPrice(ctx sdk.Context, product string) sdk.Dec
 	GetBestBidAndAsk(ctx sdk.Context, product string) (sdk.Dec, sdk.Dec)
+	GetDepthBookSequence(ctx sdk.Context, product string) int64
+	GetDepthBookDiffs() []*ordertypes.DepthBookDiff
 }
 
 // expected token keeper
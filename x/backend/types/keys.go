@@ -16,6 +16,7 @@ const (
 	QueryTxList       = "txs"
 	QueryCandleList   = "candles"
 	QueryTickerList   = "tickers"
+	QueryDepthDiffs   = "depthdiffs"
 
 	// v2
 	QueryTickerListV2   = "tickerListV2"
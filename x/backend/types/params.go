@@ -94,6 +94,19 @@ type QueryTickerParams struct {
 	Sort    bool   `json:"sort"`
 }
 
+// QueryDepthDiffsParams queries the depth book diffs of the product following the sequence
+type QueryDepthDiffsParams struct {
+	Product  string `json:"product"`
+	Sequence int64  `json:"sequence"`
+}
+
+func NewQueryDepthDiffsParams(product string, sequence int64) QueryDepthDiffsParams {
+	return QueryDepthDiffsParams{
+		Product:  product,
+		Sequence: sequence,
+	}
+}
+
 type QueryOrderListParams struct {
 	Address    string
 	Product    string
@@ -78,7 +78,13 @@ func buildTransactionNew(msg orderTypes.MsgNewOrders, txHash string, ctx sdk.Con
 }
 
 func buildTransactionCancel(msg orderTypes.MsgCancelOrders, txHash string, ctx sdk.Context, orderKeeper OrderKeeper, timestamp int64) *Transaction {
-	order := orderKeeper.GetOrder(ctx, msg.OrderIDs[0])
+	var orderID string
+	if len(msg.OrderIDs) > 0 {
+		orderID = msg.OrderIDs[0]
+	} else {
+		orderID = orderKeeper.GetOrderIDByClientOrderID(ctx, msg.Sender, msg.ClientOrderIDs[0])
+	}
+	order := orderKeeper.GetOrder(ctx, orderID)
 	if order == nil {
 		return nil
 	}
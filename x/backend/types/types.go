@@ -93,6 +93,7 @@ type Order struct {
 	FilledAvgPrice string `gorm:"type:varchar(40)" json:"filled_avg_price" v2:"filled_avg_price"`
 	RemainQuantity string `gorm:"type:varchar(40)" json:"remain_quantity" v2:"remain_quantity"`
 	Timestamp      int64  `gorm:"index;" json:"timestamp" v2:"timestamp"`
+	ClientOrderId  string `gorm:"index;type:varchar(40)" json:"client_order_id" v2:"client_order_id"`
 }
 
 type Transaction struct {
@@ -23,6 +23,13 @@ const (
 	DefaultMaxPriceDigitSize    = types.DefaultMaxPriceDigitSize
 	DefaultMaxQuantityDigitSize = types.DefaultMaxQuantityDigitSize
 
+	MatchingModePeriodicAuction   = types.MatchingModePeriodicAuction
+	MatchingModeContinuousAuction = types.MatchingModeContinuousAuction
+	DefaultMatchingMode           = types.DefaultMatchingMode
+
+	PriceBandActionReject = types.PriceBandActionReject
+	PriceBandActionHalt   = types.PriceBandActionHalt
+
 	AuthFeeCollector = auth.FeeCollectorName
 )
 
@@ -42,9 +49,11 @@ type (
 	MsgDeposit           = types.MsgDeposit
 	MsgWithdraw          = types.MsgWithdraw
 	MsgTransferOwnership = types.MsgTransferOwnership
+	MsgSetMatchingMode   = types.MsgSetMatchingMode
 
 	//
 	TokenPair     = types.TokenPair
+	PriceBand     = types.PriceBand
 	Params        = types.Params
 	WithdrawInfo  = types.WithdrawInfo
 	WithdrawInfos = types.WithdrawInfos
@@ -65,7 +74,11 @@ var (
 	NewMsgDeposit  = types.NewMsgDeposit
 	NewMsgWithdraw = types.NewMsgWithdraw
 
+	NewMsgSetMatchingMode = types.NewMsgSetMatchingMode
+
 	ErrInvalidProduct      = types.ErrInvalidProduct
 	ErrTokenPairNotFound   = types.ErrTokenPairNotFound
 	ErrDelistOwnerNotMatch = types.ErrDelistOwnerNotMatch
+	ErrInvalidMatchingMode = types.ErrInvalidMatchingMode
+	ErrInvalidPriceBand    = types.ErrInvalidPriceBand
 )
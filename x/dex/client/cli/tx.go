@@ -48,6 +48,7 @@ func GetTxCmd(cdc *codec.Codec) *cobra.Command {
 		GetCmdWithdraw(cdc),
 		GetCmdTransferOwnership(cdc),
 		GetMultiSignsCmd(cdc),
+		GetCmdSetMatchingMode(cdc),
 	)...)
 
 	return txCmd
@@ -300,6 +301,32 @@ func GetMultiSignsCmd(cdc *codec.Codec) *cobra.Command {
 	return cmd
 }
 
+// GetCmdSetMatchingMode is the CLI command for switching the matching engine of a product
+func GetCmdSetMatchingMode(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-matching-mode [product] [matching-mode]",
+		Args:  cobra.ExactArgs(2),
+		Short: "switch the matching engine of a product",
+		Long: strings.TrimSpace(fmt.Sprintf(`Switch the matching engine of a product by its owner:
+
+$ okchaincli tx dex set-matching-mode mytoken_okt %s --from mykey
+
+The 'matching-mode' is one of '%s' and '%s'.
+`, types.MatchingModeContinuousAuction, types.MatchingModePeriodicAuction, types.MatchingModeContinuousAuction)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			msg := types.NewMsgSetMatchingMode(cliCtx.GetFromAddress(), args[0], args[1])
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
 //GetCmdSubmitDelistProposal implememts a command handler for submitting a dex delist proposal transaction
 func GetCmdSubmitDelistProposal(cdc *codec.Codec) *cobra.Command {
 	return &cobra.Command{
@@ -351,3 +378,111 @@ Where proposal.json contains:
 	}
 
 }
+
+// GetCmdSubmitMatchingModeProposal implements a command handler for submitting a dex matching mode proposal transaction
+func GetCmdSubmitMatchingModeProposal(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "matching-mode-proposal [proposal-file]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Submit a dex matching mode proposal",
+		Long: strings.TrimSpace(
+			fmt.Sprintf(`Submit a dex matching mode proposal along with an initial deposit.
+The proposal details must be supplied via a JSON file.
+
+Example:
+$ %s tx gov submit-proposal matching-mode-proposal <path/to/proposal.json> --from=<key_or_address>
+
+Where proposal.json contains:
+
+{
+ "title": "match xxx_%s continuously",
+ "description": "switch the matching engine of the product",
+ "product": "xxx_%s",
+ "matching_mode": "%s",
+ "deposit": [
+   {
+     "denom": "%s",
+     "amount": "100"
+   }
+ ]
+}
+`, version.ClientName, sdk.DefaultBondDenom, sdk.DefaultBondDenom, types.MatchingModeContinuousAuction, sdk.DefaultBondDenom,
+			)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			proposal, err := dexUtils.ParseMatchingModeProposalJSON(cdc, args[0])
+			if err != nil {
+				return err
+			}
+
+			from := cliCtx.GetFromAddress()
+			content := types.NewMatchingModeProposal(proposal.Title, proposal.Description, from, proposal.Product, proposal.MatchingMode)
+
+			msg := gov.NewMsgSubmitProposal(content, proposal.Deposit, from)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+// GetCmdSubmitPriceBandProposal implements a command handler for submitting a dex price band proposal transaction
+func GetCmdSubmitPriceBandProposal(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "price-band-proposal [proposal-file]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Submit a dex price band proposal",
+		Long: strings.TrimSpace(
+			fmt.Sprintf(`Submit a dex price band proposal along with an initial deposit.
+The proposal details must be supplied via a JSON file, a null price_band removes the price band of the product.
+
+Example:
+$ %s tx gov submit-proposal price-band-proposal <path/to/proposal.json> --from=<key_or_address>
+
+Where proposal.json contains:
+
+{
+ "title": "price band of xxx_%s",
+ "description": "halt xxx_%s for 100 blocks when it trades 10%% away from its 50 blocks average price",
+ "product": "xxx_%s",
+ "price_band": {
+   "max_deviation": "0.1",
+   "twap_blocks": "50",
+   "action": "%s",
+   "halt_blocks": "100"
+ },
+ "deposit": [
+   {
+     "denom": "%s",
+     "amount": "100"
+   }
+ ]
+}
+`, version.ClientName, sdk.DefaultBondDenom, sdk.DefaultBondDenom, sdk.DefaultBondDenom, types.PriceBandActionHalt,
+				sdk.DefaultBondDenom,
+			)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			proposal, err := dexUtils.ParsePriceBandProposalJSON(cdc, args[0])
+			if err != nil {
+				return err
+			}
+
+			from := cliCtx.GetFromAddress()
+			content := types.NewPriceBandProposal(proposal.Title, proposal.Description, from, proposal.Product, proposal.PriceBand)
+
+			msg := gov.NewMsgSubmitProposal(content, proposal.Deposit, from)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
@@ -8,5 +8,7 @@ import (
 
 // param change proposal handler
 var (
-	DelistProposalHandler = govclient.NewProposalHandler(cli.GetCmdSubmitDelistProposal, rest.DelistProposalRESTHandler)
+	DelistProposalHandler       = govclient.NewProposalHandler(cli.GetCmdSubmitDelistProposal, rest.DelistProposalRESTHandler)
+	MatchingModeProposalHandler = govclient.NewProposalHandler(cli.GetCmdSubmitMatchingModeProposal, rest.MatchingModeProposalRESTHandler)
+	PriceBandProposalHandler    = govclient.NewProposalHandler(cli.GetCmdSubmitPriceBandProposal, rest.PriceBandProposalRESTHandler)
 )
@@ -141,3 +141,13 @@ func matchOrderHandler(cliContext context.CLIContext) func(http.ResponseWriter,
 func DelistProposalRESTHandler(context.CLIContext) govRest.ProposalRESTHandler {
 	return govRest.ProposalRESTHandler{}
 }
+
+// TODO: finish the rest handler of MatchingMode
+func MatchingModeProposalRESTHandler(context.CLIContext) govRest.ProposalRESTHandler {
+	return govRest.ProposalRESTHandler{}
+}
+
+// TODO: finish the rest handler of PriceBand
+func PriceBandProposalRESTHandler(context.CLIContext) govRest.ProposalRESTHandler {
+	return govRest.ProposalRESTHandler{}
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/dex/types"
 )
 
 // DelistProposalJSON defines a DelistProposal with a deposit used
@@ -30,3 +32,51 @@ func ParseDelistProposalJSON(cdc *codec.Codec, proposalFilePath string) (proposa
 
 	return proposal, nil
 }
+
+// MatchingModeProposalJSON defines a MatchingModeProposal with a deposit used
+// to parse matching mode proposals from a JSON file.
+type MatchingModeProposalJSON struct {
+	Title        string         `json:"title" yaml:"title"`
+	Description  string         `json:"description" yaml:"description"`
+	Proposer     sdk.AccAddress `json:"proposer" yaml:"proposer"`
+	Product      string         `json:"product" yaml:"product"`
+	MatchingMode string         `json:"matching_mode" yaml:"matching_mode"`
+	Deposit      sdk.DecCoins   `json:"deposit" yaml:"deposit"`
+}
+
+func ParseMatchingModeProposalJSON(cdc *codec.Codec, proposalFilePath string) (proposal MatchingModeProposalJSON, err error) {
+	contents, err := ioutil.ReadFile(proposalFilePath)
+	if err != nil {
+		return proposal, err
+	}
+
+	if err := cdc.UnmarshalJSON(contents, &proposal); err != nil {
+		return proposal, err
+	}
+
+	return proposal, nil
+}
+
+// PriceBandProposalJSON defines a PriceBandProposal with a deposit used
+// to parse price band proposals from a JSON file.
+type PriceBandProposalJSON struct {
+	Title       string           `json:"title" yaml:"title"`
+	Description string           `json:"description" yaml:"description"`
+	Proposer    sdk.AccAddress   `json:"proposer" yaml:"proposer"`
+	Product     string           `json:"product" yaml:"product"`
+	PriceBand   *types.PriceBand `json:"price_band" yaml:"price_band"`
+	Deposit     sdk.DecCoins     `json:"deposit" yaml:"deposit"`
+}
+
+func ParsePriceBandProposalJSON(cdc *codec.Codec, proposalFilePath string) (proposal PriceBandProposalJSON, err error) {
+	contents, err := ioutil.ReadFile(proposalFilePath)
+	if err != nil {
+		return proposal, err
+	}
+
+	if err := cdc.UnmarshalJSON(contents, &proposal); err != nil {
+		return proposal, err
+	}
+
+	return proposal, nil
+}
@@ -43,6 +43,11 @@ func NewHandler(k IKeeper) sdk.Handler {
 			handlerFun = func() sdk.Result {
 				return handleMsgTransferOwnership(ctx, k, msg, logger)
 			}
+		case MsgSetMatchingMode:
+			name = "handleMsgSetMatchingMode"
+			handlerFun = func() sdk.Result {
+				return handleMsgSetMatchingMode(ctx, k, msg, logger)
+			}
 		default:
 			errMsg := fmt.Sprintf("unrecognized dex message type: %T", msg)
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -73,6 +78,7 @@ func handleMsgList(ctx sdk.Context, keeper IKeeper, msg MsgList, logger log.Logg
 		Delisting:        false,
 		Deposits:         DefaultTokenPairDeposit,
 		BlockHeight:      ctx.BlockHeight(),
+		MatchingMode:     DefaultMatchingMode,
 	}
 
 	// check tokenpair exist
@@ -216,3 +222,30 @@ func handleMsgTransferOwnership(ctx sdk.Context, keeper IKeeper, msg MsgTransfer
 	)
 	return sdk.Result{Events: ctx.EventManager().Events()}
 }
+
+func handleMsgSetMatchingMode(ctx sdk.Context, keeper IKeeper, msg MsgSetMatchingMode, logger log.Logger) sdk.Result {
+	tp := keeper.GetTokenPair(ctx, msg.Product)
+	if tp == nil {
+		return ErrTokenPairNotFound(fmt.Sprintf("%+v", msg)).Result()
+	}
+
+	if !msg.Owner.Equals(tp.Owner) {
+		return sdk.ErrUnauthorized(fmt.Sprintf("%s is not the owner of product(%s)", msg.Owner.String(), msg.Product)).Result()
+	}
+
+	if sdkErr := keeper.SetMatchingMode(ctx, msg.Product, msg.MatchingMode); sdkErr != nil {
+		return sdkErr.Result()
+	}
+
+	logger.Debug(fmt.Sprintf("successfully handleMsgSetMatchingMode: "+
+		"BlockHeight: %d, Msg: %+v", ctx.BlockHeight(), msg))
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, ModuleName),
+			sdk.NewAttribute("matching-mode", msg.MatchingMode),
+		),
+	)
+	return sdk.Result{Events: ctx.EventManager().Events()}
+}
@@ -185,3 +185,36 @@ func TestHandler_handleMsgTransferOwnership(t *testing.T) {
 	spKeeper.behaveEvil = true
 	handlerFunctor(ctx, msgFailedTransferOwnership)
 }
+
+func TestHandler_HandleMsgSetMatchingMode(t *testing.T) {
+	mApp, _, _, mDexKeeper, ctx := getMockTestCaseEvn(t)
+
+	tokenPair := GetBuiltInTokenPair()
+	err := mDexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+	require.EqualValues(t, DefaultMatchingMode, mDexKeeper.Keeper.GetTokenPair(ctx, tokenPair.Name()).GetMatchingMode())
+	handlerFunctor := NewHandler(mApp.dexKeeper)
+
+	// fail case : product is not exist
+	msg := NewMsgSetMatchingMode(tokenPair.Owner, "no-product", MatchingModeContinuousAuction)
+	res := handlerFunctor(ctx, msg)
+	require.False(t, res.Code.IsOK())
+
+	// fail case : sender is not the owner
+	msg = NewMsgSetMatchingMode(mApp.GenesisAccounts[0].GetAddress(), tokenPair.Name(), MatchingModeContinuousAuction)
+	res = handlerFunctor(ctx, msg)
+	require.False(t, res.Code.IsOK())
+
+	// fail case : invalid matching mode
+	msg = NewMsgSetMatchingMode(tokenPair.Owner, tokenPair.Name(), "unknown")
+	require.Error(t, msg.ValidateBasic())
+	res = handlerFunctor(ctx, msg)
+	require.False(t, res.Code.IsOK())
+
+	// successful case
+	msg = NewMsgSetMatchingMode(tokenPair.Owner, tokenPair.Name(), MatchingModeContinuousAuction)
+	require.Nil(t, msg.ValidateBasic())
+	res = handlerFunctor(ctx, msg)
+	require.True(t, res.Code.IsOK())
+	require.EqualValues(t, MatchingModeContinuousAuction, mDexKeeper.Keeper.GetTokenPair(ctx, tokenPair.Name()).MatchingMode)
+}
@@ -45,6 +45,8 @@ type IKeeper interface {
 	GetFeeCollector() string
 	GetCDC() *codec.Codec
 	TransferOwnership(ctx sdk.Context, product string, from sdk.AccAddress, to sdk.AccAddress) sdk.Error
+	SetMatchingMode(ctx sdk.Context, product string, matchingMode string) sdk.Error
+	SetPriceBand(ctx sdk.Context, product string, priceBand *types.PriceBand) sdk.Error
 	LockTokenPair(ctx sdk.Context, product string, lock *ordertypes.ProductLock)
 	LoadProductLocks(ctx sdk.Context) *ordertypes.ProductLockMap
 	SetWithdrawInfo(ctx sdk.Context, withdrawInfo types.WithdrawInfo)
@@ -376,6 +376,43 @@ func (k Keeper) TransferOwnership(ctx sdk.Context, product string, from sdk.AccA
 	return nil
 }
 
+// SetMatchingMode switches the matching engine of the product.
+// The open orders of the product are kept in the depth book and matched by the new engine,
+// a product locked by the periodic auction finishes its execution before it's matched by the new engine.
+func (k Keeper) SetMatchingMode(ctx sdk.Context, product string, matchingMode string) sdk.Error {
+	tokenPair := k.GetTokenPair(ctx, product)
+	if tokenPair == nil {
+		return types.ErrTokenPairNotFound(product)
+	}
+
+	if !types.IsValidMatchingMode(matchingMode) {
+		return types.ErrInvalidMatchingMode(matchingMode)
+	}
+
+	tokenPair.MatchingMode = matchingMode
+	k.UpdateTokenPair(ctx, product, tokenPair)
+	return nil
+}
+
+// SetPriceBand sets the price band of the product, a nil price band removes it.
+// It takes effect from the next order or matching of the product, a halt in progress lasts till its end.
+func (k Keeper) SetPriceBand(ctx sdk.Context, product string, priceBand *types.PriceBand) sdk.Error {
+	tokenPair := k.GetTokenPair(ctx, product)
+	if tokenPair == nil {
+		return types.ErrTokenPairNotFound(product)
+	}
+
+	if priceBand != nil {
+		if err := priceBand.Validate(); err != nil {
+			return types.ErrInvalidPriceBand(err.Error())
+		}
+	}
+
+	tokenPair.PriceBand = priceBand
+	k.UpdateTokenPair(ctx, product, tokenPair)
+	return nil
+}
+
 // GetWithdrawInfo returns withdraw info binding the addr
 func (k Keeper) GetWithdrawInfo(ctx sdk.Context, addr sdk.AccAddress) (withdrawInfo types.WithdrawInfo, ok bool) {
 	bytes := ctx.KVStore(k.storeKey).Get(types.GetWithdrawAddressKey(addr))
@@ -14,7 +14,8 @@ import (
 // implement ProposalHandler
 func (k Keeper) GetMinDeposit(ctx sdk.Context, content gov.Content) (minDeposit sdk.DecCoins) {
 	switch content.(type) {
-	case types.DelistProposal:
+	// matching mode and price band proposals share the governance params of delist proposals
+	case types.DelistProposal, types.MatchingModeProposal, types.PriceBandProposal:
 		minDeposit = k.GetParams(ctx).DelistMinDeposit
 	}
 	return
@@ -22,7 +23,8 @@ func (k Keeper) GetMinDeposit(ctx sdk.Context, content gov.Content) (minDeposit
 
 func (k Keeper) GetMaxDepositPeriod(ctx sdk.Context, content gov.Content) (maxDepositPeriod time.Duration) {
 	switch content.(type) {
-	case types.DelistProposal:
+	// matching mode and price band proposals share the governance params of delist proposals
+	case types.DelistProposal, types.MatchingModeProposal, types.PriceBandProposal:
 		maxDepositPeriod = k.GetParams(ctx).DelistMaxDepositPeriod
 	}
 	return
@@ -30,7 +32,8 @@ func (k Keeper) GetMaxDepositPeriod(ctx sdk.Context, content gov.Content) (maxDe
 
 func (k Keeper) GetVotingPeriod(ctx sdk.Context, content gov.Content) (votingPeriod time.Duration) {
 	switch content.(type) {
-	case types.DelistProposal:
+	// matching mode and price band proposals share the governance params of delist proposals
+	case types.DelistProposal, types.MatchingModeProposal, types.PriceBandProposal:
 		votingPeriod = k.GetParams(ctx).DelistVotingPeriod
 	}
 	return
@@ -48,6 +51,41 @@ func (k Keeper) checkMsgDelistProposal(ctx sdk.Context, delistProposal types.Del
 		return types.ErrInvalidProduct(fmt.Sprintf("failed to submit proposal because the asset with base asset '%s' and quote asset '%s' didn't exist on the Dex", delistProposal.BaseAsset, delistProposal.QuoteAsset))
 	}
 
+	return k.checkProposalInitialDeposit(ctx, proposer, initialDeposit)
+}
+
+// check msg MatchingMode proposal
+func (k Keeper) checkMsgMatchingModeProposal(ctx sdk.Context, proposal types.MatchingModeProposal, proposer sdk.AccAddress, initialDeposit sdk.DecCoins) sdk.Error {
+	// check the proposer of the msg is a validator
+	if !k.stakingKeeper.IsValidator(ctx, proposer) {
+		return gov.ErrInvalidProposer(types.DefaultCodespace, "failed to submit proposal because the proposer of matching mode proposal should be a validator")
+	}
+
+	// check whether the product is in the Dex list
+	if k.GetTokenPair(ctx, proposal.Product) == nil {
+		return types.ErrInvalidProduct(fmt.Sprintf("failed to submit proposal because the product '%s' didn't exist on the Dex", proposal.Product))
+	}
+
+	return k.checkProposalInitialDeposit(ctx, proposer, initialDeposit)
+}
+
+// check msg PriceBand proposal
+func (k Keeper) checkMsgPriceBandProposal(ctx sdk.Context, proposal types.PriceBandProposal, proposer sdk.AccAddress, initialDeposit sdk.DecCoins) sdk.Error {
+	// check the proposer of the msg is a validator
+	if !k.stakingKeeper.IsValidator(ctx, proposer) {
+		return gov.ErrInvalidProposer(types.DefaultCodespace, "failed to submit proposal because the proposer of price band proposal should be a validator")
+	}
+
+	// check whether the product is in the Dex list
+	if k.GetTokenPair(ctx, proposal.Product) == nil {
+		return types.ErrInvalidProduct(fmt.Sprintf("failed to submit proposal because the product '%s' didn't exist on the Dex", proposal.Product))
+	}
+
+	return k.checkProposalInitialDeposit(ctx, proposer, initialDeposit)
+}
+
+// check whether the initial deposit of a dex proposal is enough and affordable
+func (k Keeper) checkProposalInitialDeposit(ctx sdk.Context, proposer sdk.AccAddress, initialDeposit sdk.DecCoins) sdk.Error {
 	// check the initial deposit
 	localMinDeposit := k.GetParams(ctx).DelistMinDeposit.MulDec(sdk.NewDecWithPrec(1, 1))
 	err := common.HasSufficientCoins(proposer, initialDeposit, localMinDeposit)
@@ -68,6 +106,10 @@ func (k Keeper) CheckMsgSubmitProposal(ctx sdk.Context, msg govTypes.MsgSubmitPr
 	switch content := msg.Content.(type) {
 	case types.DelistProposal:
 		sdkErr = k.checkMsgDelistProposal(ctx, content, msg.Proposer, msg.InitialDeposit)
+	case types.MatchingModeProposal:
+		sdkErr = k.checkMsgMatchingModeProposal(ctx, content, msg.Proposer, msg.InitialDeposit)
+	case types.PriceBandProposal:
+		sdkErr = k.checkMsgPriceBandProposal(ctx, content, msg.Proposer, msg.InitialDeposit)
 	default:
 		errContent := fmt.Sprintf("unrecognized dex proposal content type: %T", content)
 		sdkErr = sdk.ErrUnknownRequest(errContent)
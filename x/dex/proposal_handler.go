@@ -14,6 +14,10 @@ func NewProposalHandler(k *Keeper) govTypes.Handler {
 		switch c := proposal.Content.(type) {
 		case types.DelistProposal:
 			return handleDelistProposal(ctx, k, proposal)
+		case types.MatchingModeProposal:
+			return handleMatchingModeProposal(ctx, k, proposal)
+		case types.PriceBandProposal:
+			return handlePriceBandProposal(ctx, k, proposal)
 		default:
 			errMsg := fmt.Sprintf("unrecognized param proposal content type: %s", c)
 			return sdk.ErrUnknownRequest(errMsg)
@@ -56,3 +60,41 @@ func handleDelistProposal(ctx sdk.Context, keeper *Keeper, proposal *govTypes.Pr
 		))
 	return nil
 }
+
+func handleMatchingModeProposal(ctx sdk.Context, keeper *Keeper, proposal *govTypes.Proposal) (err sdk.Error) {
+	p := proposal.Content.(types.MatchingModeProposal)
+	logger := ctx.Logger().With("module", types.ModuleName)
+	logger.Debug("execute MatchingModeProposal begin")
+
+	if err := keeper.SetMatchingMode(ctx, p.Product, p.MatchingMode); err != nil {
+		return err
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute("token-pair-matching-mode", fmt.Sprintf("%s:%s", p.Product, p.MatchingMode)),
+		))
+	return nil
+}
+
+func handlePriceBandProposal(ctx sdk.Context, keeper *Keeper, proposal *govTypes.Proposal) (err sdk.Error) {
+	p := proposal.Content.(types.PriceBandProposal)
+	logger := ctx.Logger().With("module", types.ModuleName)
+	logger.Debug("execute PriceBandProposal begin")
+
+	if err := keeper.SetPriceBand(ctx, p.Product, p.PriceBand); err != nil {
+		return err
+	}
+
+	priceBand := "none"
+	if p.PriceBand != nil {
+		priceBand = p.PriceBand.String()
+	}
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute("token-pair-price-band", fmt.Sprintf("%s:%s", p.Product, priceBand)),
+		))
+	return nil
+}
@@ -65,3 +65,87 @@ func TestProposal_NewProposalHandler(t *testing.T) {
 	require.Error(t, err)
 
 }
+
+func TestProposal_HandleMatchingModeProposal(t *testing.T) {
+	fakeTokenKeeper := newMockTokenKeeper()
+	fakeSupplyKeeper := newMockSupplyKeeper()
+
+	mApp, mDexKeeper, err := newMockApp(fakeTokenKeeper, fakeSupplyKeeper, 10)
+	require.True(t, err == nil)
+
+	mApp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	ctx := mApp.BaseApp.NewContext(false, abci.Header{})
+
+	proposalHandler := NewProposalHandler(mDexKeeper.Keeper)
+	tokenPair := GetBuiltInTokenPair()
+
+	content := types.NewMatchingModeProposal("match xxb_okt continuously", "switch the matching engine",
+		tokenPair.Owner, tokenPair.Name(), types.MatchingModeContinuousAuction)
+	require.Nil(t, content.ValidateBasic())
+	proposal := govTypes.Proposal{Content: content}
+
+	// error case : fail to handle proposal because product(token pair) not exist
+	err = proposalHandler(ctx, &proposal)
+	require.Error(t, err)
+
+	saveErr := mApp.dexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, saveErr)
+
+	// successful case : the matching mode is switched even if the product is locked
+	mDexKeeper.LockTokenPair(ctx, tokenPair.Name(), &ordertypes.ProductLock{})
+	err = proposalHandler(ctx, &proposal)
+	require.Nil(t, err)
+	require.EqualValues(t, types.MatchingModeContinuousAuction, mDexKeeper.Keeper.GetTokenPair(ctx, tokenPair.Name()).MatchingMode)
+
+	// error case : invalid matching mode
+	content.MatchingMode = "unknown"
+	require.Error(t, content.ValidateBasic())
+	err = proposalHandler(ctx, &govTypes.Proposal{Content: content})
+	require.Error(t, err)
+}
+
+func TestProposal_HandlePriceBandProposal(t *testing.T) {
+	fakeTokenKeeper := newMockTokenKeeper()
+	fakeSupplyKeeper := newMockSupplyKeeper()
+
+	mApp, mDexKeeper, err := newMockApp(fakeTokenKeeper, fakeSupplyKeeper, 10)
+	require.True(t, err == nil)
+
+	mApp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	ctx := mApp.BaseApp.NewContext(false, abci.Header{})
+
+	proposalHandler := NewProposalHandler(mDexKeeper.Keeper)
+	tokenPair := GetBuiltInTokenPair()
+
+	priceBand := &types.PriceBand{MaxDeviation: sdk.MustNewDecFromStr("0.1"), TWAPBlocks: 10,
+		Action: types.PriceBandActionHalt, HaltBlocks: 100}
+	content := types.NewPriceBandProposal("band xxb_okt", "halt xxb_okt out of 10% around its twap",
+		tokenPair.Owner, tokenPair.Name(), priceBand)
+	require.Nil(t, content.ValidateBasic())
+	proposal := govTypes.Proposal{Content: content}
+
+	// error case : fail to handle proposal because product(token pair) not exist
+	err = proposalHandler(ctx, &proposal)
+	require.Error(t, err)
+
+	saveErr := mApp.dexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, saveErr)
+
+	// successful case : set the price band
+	err = proposalHandler(ctx, &proposal)
+	require.Nil(t, err)
+	require.EqualValues(t, priceBand, mDexKeeper.Keeper.GetTokenPair(ctx, tokenPair.Name()).PriceBand)
+
+	// error case : invalid price band
+	content.PriceBand = &types.PriceBand{MaxDeviation: sdk.MustNewDecFromStr("0.1"), Action: types.PriceBandActionHalt}
+	require.Error(t, content.ValidateBasic())
+	err = proposalHandler(ctx, &govTypes.Proposal{Content: content})
+	require.Error(t, err)
+
+	// successful case : remove the price band
+	content.PriceBand = nil
+	require.Nil(t, content.ValidateBasic())
+	err = proposalHandler(ctx, &govTypes.Proposal{Content: content})
+	require.Nil(t, err)
+	require.Nil(t, mDexKeeper.Keeper.GetTokenPair(ctx, tokenPair.Name()).PriceBand)
+}
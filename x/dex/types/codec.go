@@ -9,7 +9,10 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgDeposit{}, "okchain/dex/MsgDeposit", nil)
 	cdc.RegisterConcrete(MsgWithdraw{}, "okchain/dex/MsgWithdraw", nil)
 	cdc.RegisterConcrete(MsgTransferOwnership{}, "okchain/dex/MsgTransferTradingPairOwnership", nil)
+	cdc.RegisterConcrete(MsgSetMatchingMode{}, "okchain/dex/MsgSetMatchingMode", nil)
 	cdc.RegisterConcrete(DelistProposal{}, "okchain/dex/DelistProposal", nil)
+	cdc.RegisterConcrete(MatchingModeProposal{}, "okchain/dex/MatchingModeProposal", nil)
+	cdc.RegisterConcrete(PriceBandProposal{}, "okchain/dex/PriceBandProposal", nil)
 
 }
 
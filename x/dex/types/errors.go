@@ -16,6 +16,8 @@ const (
 	CodeInvalidHeight           sdk.CodeType = 5
 	CodeInvalidAsset            sdk.CodeType = 6
 	CodeInvalidCommon           sdk.CodeType = 7
+	CodeInvalidMatchingMode     sdk.CodeType = 8
+	CodeInvalidPriceBand        sdk.CodeType = 9
 )
 
 // CodeType to Message
@@ -27,6 +29,10 @@ func CodeToDefaultMsg(code sdk.CodeType) string {
 		return "tokenpair not found"
 	case CodeDelistOwnerNotMatch:
 		return "tokenpair delistor should be it's owner "
+	case CodeInvalidMatchingMode:
+		return "invalid matching mode"
+	case CodeInvalidPriceBand:
+		return "invalid price band"
 	default:
 		return fmt.Sprintf("unknown code %d", code)
 	}
@@ -47,6 +53,14 @@ func ErrDelistOwnerNotMatch(msg string) sdk.Error {
 	return sdk.NewError(DefaultCodespace, CodeDelistOwnerNotMatch, CodeToDefaultMsg(CodeDelistOwnerNotMatch)+": %s", msg)
 }
 
+func ErrInvalidMatchingMode(msg string) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeInvalidMatchingMode, CodeToDefaultMsg(CodeInvalidMatchingMode)+": %s", msg)
+}
+
+func ErrInvalidPriceBand(msg string) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeInvalidPriceBand, CodeToDefaultMsg(CodeInvalidPriceBand)+": %s", msg)
+}
+
 func ErrInvalidBalanceNotEnough(codespace sdk.CodespaceType, message string) sdk.Error {
 	return sdk.NewError(codespace, CodeInvalidBalanceNotEnough, message)
 }
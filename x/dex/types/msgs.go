@@ -10,6 +10,7 @@ const (
 	TypeMsgDeposit           = "deposit"
 	TypeMsgWithdraw          = "withdraw"
 	TypeMsgTransferOwnership = "transferOwnership"
+	TypeMsgSetMatchingMode   = "setMatchingMode"
 )
 
 type MsgList struct {
@@ -229,3 +230,51 @@ func (msg MsgTransferOwnership) checkMultiSign() bool {
 	toValid := toSignature.VerifyBytes(msg.GetSignBytes(), toSignature.Signature)
 	return toValid
 }
+
+// MsgSetMatchingMode - switch the matching engine of a product by its owner
+type MsgSetMatchingMode struct {
+	Owner        sdk.AccAddress `json:"owner"`
+	Product      string         `json:"product"`
+	MatchingMode string         `json:"matching_mode"`
+}
+
+func NewMsgSetMatchingMode(owner sdk.AccAddress, product, matchingMode string) MsgSetMatchingMode {
+	return MsgSetMatchingMode{
+		Owner:        owner,
+		Product:      product,
+		MatchingMode: matchingMode,
+	}
+}
+
+// Route Implements Msg.
+func (msg MsgSetMatchingMode) Route() string { return RouterKey }
+
+// Type Implements Msg.
+func (msg MsgSetMatchingMode) Type() string { return TypeMsgSetMatchingMode }
+
+// ValidateBasic Implements Msg.
+func (msg MsgSetMatchingMode) ValidateBasic() sdk.Error {
+	if msg.Owner.Empty() {
+		return sdk.ErrInvalidAddress(msg.Owner.String())
+	}
+
+	if msg.Product == "" {
+		return ErrInvalidProduct(msg.Product)
+	}
+
+	if !IsValidMatchingMode(msg.MatchingMode) {
+		return ErrInvalidMatchingMode(msg.MatchingMode)
+	}
+	return nil
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgSetMatchingMode) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners Implements Msg.
+func (msg MsgSetMatchingMode) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Owner}
+}
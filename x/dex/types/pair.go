@@ -9,6 +9,15 @@ import (
 
 var DefaultTokenPairDeposit = sdk.NewDecCoin(sdk.DefaultBondDenom, sdk.NewInt(0))
 
+// matching modes of a token pair
+const (
+	MatchingModePeriodicAuction   = "periodic_auction"
+	MatchingModeContinuousAuction = "continuous_auction"
+
+	// DefaultMatchingMode is also the matching mode of token pairs listed without one
+	DefaultMatchingMode = MatchingModePeriodicAuction
+)
+
 type TokenPair struct {
 	BaseAssetSymbol  string         `json:"base_asset_symbol"`
 	QuoteAssetSymbol string         `json:"quote_asset_symbol"`
@@ -21,12 +30,79 @@ type TokenPair struct {
 	Owner            sdk.AccAddress `json:"owner"`
 	Deposits         sdk.DecCoin    `json:"deposits"`
 	BlockHeight      int64          `json:"block_height"`
+	MatchingMode     string         `json:"matching_mode"`
+	PriceBand        *PriceBand     `json:"price_band,omitempty"` // nil means trading at any price
 }
 
 func (tp *TokenPair) Name() string {
 	return fmt.Sprintf("%s_%s", tp.BaseAssetSymbol, tp.QuoteAssetSymbol)
 }
 
+// GetMatchingMode returns the matching mode of the token pair, the default one if it's not set
+func (tp *TokenPair) GetMatchingMode() string {
+	if tp.MatchingMode == "" {
+		return DefaultMatchingMode
+	}
+	return tp.MatchingMode
+}
+
+// IsValidMatchingMode checks whether the matching mode is supported
+func IsValidMatchingMode(matchingMode string) bool {
+	return matchingMode == MatchingModePeriodicAuction || matchingMode == MatchingModeContinuousAuction
+}
+
+// actions taken on the prices out of the price band of a token pair
+const (
+	PriceBandActionReject = "reject" // orders priced out of the band are rejected
+	PriceBandActionHalt   = "halt"   // matching is halted for a cooling-off period instead of trading out of the band
+)
+
+// PriceBand limits the prices of a token pair to a deviation from its reference price, which is the last price,
+// or the time-weighted average of the last price in the last TWAPBlocks blocks
+type PriceBand struct {
+	MaxDeviation sdk.Dec `json:"max_deviation"` // e.g. 0.1 for prices within 10% of the reference price
+	TWAPBlocks   int64   `json:"twap_blocks"`   // 0 means the last price is the reference price
+	Action       string  `json:"action"`        // reject/halt
+	HaltBlocks   int64   `json:"halt_blocks"`   // cooling-off period of a halt
+}
+
+// Validate checks whether the price band is well-formed
+func (band PriceBand) Validate() error {
+	if band.MaxDeviation.Int == nil || !band.MaxDeviation.IsPositive() {
+		return fmt.Errorf("max deviation should be positive")
+	}
+	if band.TWAPBlocks < 0 {
+		return fmt.Errorf("twap blocks should not be negative, but got %d", band.TWAPBlocks)
+	}
+	switch band.Action {
+	case PriceBandActionReject:
+	case PriceBandActionHalt:
+		if band.HaltBlocks <= 0 {
+			return fmt.Errorf("halt blocks should be positive, but got %d", band.HaltBlocks)
+		}
+	default:
+		return fmt.Errorf("action is expected to be %s or %s, but got %s", PriceBandActionReject,
+			PriceBandActionHalt, band.Action)
+	}
+	return nil
+}
+
+// Contains checks whether the price is within the band around the reference price,
+// every price is if there is no reference price
+func (band PriceBand) Contains(referencePrice, price sdk.Dec) bool {
+	if !referencePrice.IsPositive() {
+		return true
+	}
+	deviation := referencePrice.Mul(band.MaxDeviation)
+	return price.GTE(referencePrice.Sub(deviation)) && price.LTE(referencePrice.Add(deviation))
+}
+
+// String implements the stringer interface.
+func (band PriceBand) String() string {
+	return fmt.Sprintf("MaxDeviation<%s> TWAPBlocks<%d> Action<%s> HaltBlocks<%d>", band.MaxDeviation,
+		band.TWAPBlocks, band.Action, band.HaltBlocks)
+}
+
 // 1. compare deposits
 // 2. compare block height
 // 3. compare name
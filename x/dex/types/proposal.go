@@ -11,11 +11,19 @@ import (
 const (
 	// ProposalTypeDelist defines the type for a Delist proposal
 	ProposalTypeDelist = "Delist"
+	// ProposalTypeMatchingMode defines the type for a MatchingMode proposal
+	ProposalTypeMatchingMode = "MatchingMode"
+	// ProposalTypePriceBand defines the type for a PriceBand proposal
+	ProposalTypePriceBand = "PriceBand"
 )
 
 func init() {
 	govtypes.RegisterProposalType(ProposalTypeDelist)
 	govtypes.RegisterProposalTypeCodec(DelistProposal{}, "okchain/dex/DelistProposal")
+	govtypes.RegisterProposalType(ProposalTypeMatchingMode)
+	govtypes.RegisterProposalTypeCodec(MatchingModeProposal{}, "okchain/dex/MatchingModeProposal")
+	govtypes.RegisterProposalType(ProposalTypePriceBand)
+	govtypes.RegisterProposalTypeCodec(PriceBandProposal{}, "okchain/dex/PriceBandProposal")
 
 }
 
@@ -99,3 +107,175 @@ func (drp DelistProposal) String() string {
 		drp.BaseAsset, drp.QuoteAsset,
 	)
 }
+
+// Assert MatchingModeProposal implements govtypes.Content at compile-time
+var _ govtypes.Content = (*MatchingModeProposal)(nil)
+
+// MatchingModeProposal switches the matching engine of a token pair by governance
+type MatchingModeProposal struct {
+	Title        string         `json:"title" yaml:"title"`
+	Description  string         `json:"description" yaml:"description"`
+	Proposer     sdk.AccAddress `json:"proposer" yaml:"proposer"`
+	Product      string         `json:"product" yaml:"product"`
+	MatchingMode string         `json:"matching_mode" yaml:"matching_mode"`
+}
+
+func NewMatchingModeProposal(title, description string, proposer sdk.AccAddress, product, matchingMode string) MatchingModeProposal {
+	return MatchingModeProposal{
+		Title:        title,
+		Description:  description,
+		Proposer:     proposer,
+		Product:      product,
+		MatchingMode: matchingMode,
+	}
+}
+
+func (mmp MatchingModeProposal) GetTitle() string {
+	return mmp.Title
+}
+
+func (mmp MatchingModeProposal) GetDescription() string {
+	return mmp.Description
+}
+
+func (MatchingModeProposal) ProposalRoute() string {
+	return RouterKey
+}
+
+func (MatchingModeProposal) ProposalType() string {
+	return ProposalTypeMatchingMode
+}
+
+func (mmp MatchingModeProposal) ValidateBasic() sdk.Error {
+	if len(strings.TrimSpace(mmp.Title)) == 0 {
+		return govtypes.ErrInvalidProposalContent(DefaultCodespace, "failed to submit matching mode proposal because title is blank")
+	}
+	if len(mmp.Title) > govtypes.MaxTitleLength {
+		return govtypes.ErrInvalidProposalContent(DefaultCodespace, fmt.Sprintf("failed to submit matching mode proposal because title is longer than max length of %d", govtypes.MaxTitleLength))
+	}
+
+	if len(mmp.Description) == 0 {
+		return govtypes.ErrInvalidProposalContent(DefaultCodespace, "failed to submit matching mode proposal because description is blank")
+	}
+
+	if len(mmp.Description) > govtypes.MaxDescriptionLength {
+		return govtypes.ErrInvalidProposalContent(DefaultCodespace, fmt.Sprintf("failed to submit matching mode proposal because description is longer than max length of %d", govtypes.MaxDescriptionLength))
+	}
+
+	if mmp.Proposer.Empty() {
+		return sdk.ErrInvalidAddress(mmp.Proposer.String())
+	}
+
+	if len(mmp.Product) == 0 {
+		return ErrInvalidProduct(mmp.Product)
+	}
+
+	if !IsValidMatchingMode(mmp.MatchingMode) {
+		return ErrInvalidMatchingMode(mmp.MatchingMode)
+	}
+
+	return nil
+}
+
+func (mmp MatchingModeProposal) String() string {
+	return fmt.Sprintf(`MatchingModeProposal:
+ Title:               %s
+ Description:         %s
+ Type:                %s
+ Proposer:            %s
+ Product:             %s
+ MatchingMode:        %s
+`, mmp.Title, mmp.Description,
+		mmp.ProposalType(), mmp.Proposer,
+		mmp.Product, mmp.MatchingMode,
+	)
+}
+
+// Assert PriceBandProposal implements govtypes.Content at compile-time
+var _ govtypes.Content = (*PriceBandProposal)(nil)
+
+// PriceBandProposal sets the price band of a token pair by governance, a nil price band removes it
+type PriceBandProposal struct {
+	Title       string         `json:"title" yaml:"title"`
+	Description string         `json:"description" yaml:"description"`
+	Proposer    sdk.AccAddress `json:"proposer" yaml:"proposer"`
+	Product     string         `json:"product" yaml:"product"`
+	PriceBand   *PriceBand     `json:"price_band" yaml:"price_band"`
+}
+
+func NewPriceBandProposal(title, description string, proposer sdk.AccAddress, product string, priceBand *PriceBand) PriceBandProposal {
+	return PriceBandProposal{
+		Title:       title,
+		Description: description,
+		Proposer:    proposer,
+		Product:     product,
+		PriceBand:   priceBand,
+	}
+}
+
+func (pbp PriceBandProposal) GetTitle() string {
+	return pbp.Title
+}
+
+func (pbp PriceBandProposal) GetDescription() string {
+	return pbp.Description
+}
+
+func (PriceBandProposal) ProposalRoute() string {
+	return RouterKey
+}
+
+func (PriceBandProposal) ProposalType() string {
+	return ProposalTypePriceBand
+}
+
+func (pbp PriceBandProposal) ValidateBasic() sdk.Error {
+	if len(strings.TrimSpace(pbp.Title)) == 0 {
+		return govtypes.ErrInvalidProposalContent(DefaultCodespace, "failed to submit price band proposal because title is blank")
+	}
+	if len(pbp.Title) > govtypes.MaxTitleLength {
+		return govtypes.ErrInvalidProposalContent(DefaultCodespace, fmt.Sprintf("failed to submit price band proposal because title is longer than max length of %d", govtypes.MaxTitleLength))
+	}
+
+	if len(pbp.Description) == 0 {
+		return govtypes.ErrInvalidProposalContent(DefaultCodespace, "failed to submit price band proposal because description is blank")
+	}
+
+	if len(pbp.Description) > govtypes.MaxDescriptionLength {
+		return govtypes.ErrInvalidProposalContent(DefaultCodespace, fmt.Sprintf("failed to submit price band proposal because description is longer than max length of %d", govtypes.MaxDescriptionLength))
+	}
+
+	if pbp.Proposer.Empty() {
+		return sdk.ErrInvalidAddress(pbp.Proposer.String())
+	}
+
+	if len(pbp.Product) == 0 {
+		return ErrInvalidProduct(pbp.Product)
+	}
+
+	if pbp.PriceBand != nil {
+		if err := pbp.PriceBand.Validate(); err != nil {
+			return ErrInvalidPriceBand(err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (pbp PriceBandProposal) String() string {
+	priceBand := "none"
+	if pbp.PriceBand != nil {
+		priceBand = pbp.PriceBand.String()
+	}
+	return fmt.Sprintf(`PriceBandProposal:
+ Title:               %s
+ Description:         %s
+ Type:                %s
+ Proposer:            %s
+ Product:             %s
+ PriceBand:           %s
+`, pbp.Title, pbp.Description,
+		pbp.ProposalType(), pbp.Proposer,
+		pbp.Product, priceBand,
+	)
+}
@@ -0,0 +1,67 @@
+// nolint
+// aliases generated for the following subdirectories:
+// ALIASGEN: github.com/okex/okchain/x/margin/keeper
+// ALIASGEN: github.com/okex/okchain/x/margin/types
+package margin
+
+import (
+	"github.com/okex/okchain/x/margin/keeper"
+	"github.com/okex/okchain/x/margin/types"
+)
+
+const (
+	ModuleName        = types.ModuleName
+	DefaultCodespace  = types.DefaultCodespace
+	DefaultParamspace = types.DefaultParamspace
+	QuerierRoute      = types.QuerierRoute
+	RouterKey         = types.RouterKey
+	StoreKey          = types.StoreKey
+)
+
+type (
+	// Keepers
+	Keeper       = keeper.Keeper
+	SupplyKeeper = keeper.SupplyKeeper
+	TokenKeeper  = keeper.TokenKeeper
+	DexKeeper    = keeper.DexKeeper
+	OrderKeeper  = keeper.OrderKeeper
+
+	// Messages
+	MsgOpenAccount = types.MsgOpenAccount
+	MsgDeposit     = types.MsgDeposit
+	MsgWithdraw    = types.MsgWithdraw
+	MsgBorrow      = types.MsgBorrow
+	MsgRepay       = types.MsgRepay
+	MsgLend        = types.MsgLend
+	MsgRedeem      = types.MsgRedeem
+	MsgNewOrder    = types.MsgNewOrder
+	MsgCancelOrder = types.MsgCancelOrder
+
+	//
+	Account     = types.Account
+	AccountInfo = types.AccountInfo
+	Pool        = types.Pool
+	LenderShare = types.LenderShare
+	Params      = types.Params
+)
+
+var (
+	ModuleCdc = types.ModuleCdc
+
+	RegisterCodec     = types.RegisterCodec
+	NewQuerier        = keeper.NewQuerier
+	NewKeeper         = keeper.NewKeeper
+	DefaultParams     = types.DefaultParams
+	NewAccount        = types.NewAccount
+	GetAccountAddress = types.GetAccountAddress
+
+	NewMsgOpenAccount = types.NewMsgOpenAccount
+	NewMsgDeposit     = types.NewMsgDeposit
+	NewMsgWithdraw    = types.NewMsgWithdraw
+	NewMsgBorrow      = types.NewMsgBorrow
+	NewMsgRepay       = types.NewMsgRepay
+	NewMsgLend        = types.NewMsgLend
+	NewMsgRedeem      = types.NewMsgRedeem
+	NewMsgNewOrder    = types.NewMsgNewOrder
+	NewMsgCancelOrder = types.NewMsgCancelOrder
+)
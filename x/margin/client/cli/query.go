@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spf13/cobra"
+
+	"github.com/okex/okchain/x/margin/types"
+)
+
+// GetQueryCmd returns the cli query commands for this module
+func GetQueryCmd(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	queryCmd := &cobra.Command{
+		Use:   "margin",
+		Short: "Querying commands for the margin module",
+	}
+
+	queryCmd.AddCommand(client.GetCommands(
+		GetCmdQueryParams(queryRoute, cdc),
+		GetCmdQueryAccount(queryRoute, cdc),
+		GetCmdQueryAccounts(queryRoute, cdc),
+		GetCmdQueryPools(queryRoute, cdc),
+		GetCmdQueryLender(queryRoute, cdc),
+	)...)
+
+	return queryCmd
+}
+
+// GetCmdQueryParams queries the params of the margin module
+func GetCmdQueryParams(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "params",
+		Short: "Query the parameters of the margin module",
+		Long: strings.TrimSpace(`Query the parameters of the margin module:
+
+$ okchaincli query margin params
+`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			route := fmt.Sprintf("custom/%s/%s", queryRoute, types.QueryParameters)
+			bz, _, err := cliCtx.QueryWithData(route, nil)
+			if err != nil {
+				return err
+			}
+
+			var params types.Params
+			cdc.MustUnmarshalJSON(bz, &params)
+			return cliCtx.PrintOutput(params)
+		},
+	}
+}
+
+// GetCmdQueryAccount queries the margin account of an owner on a product
+func GetCmdQueryAccount(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "account [owner-addr] [product]",
+		Short: "Query the margin account of an owner on a product",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			owner, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+			bz, err := cdc.MarshalJSON(types.NewQueryAccountParams(owner, args[1]))
+			if err != nil {
+				return err
+			}
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", queryRoute, types.QueryAccount), bz)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+}
+
+// GetCmdQueryAccounts queries all the margin accounts of an owner
+func GetCmdQueryAccounts(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "accounts [owner-addr]",
+		Short: "Query all the margin accounts of an owner",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return queryAddress(cdc, fmt.Sprintf("custom/%s/%s", queryRoute, types.QueryAccounts), args[0])
+		},
+	}
+}
+
+// GetCmdQueryPools queries all the lending pools
+func GetCmdQueryPools(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "pools",
+		Short: "Query all the lending pools",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", queryRoute, types.QueryPools), nil)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+}
+
+// GetCmdQueryLender queries the shares of a lender in the lending pools
+func GetCmdQueryLender(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "lender [lender-addr]",
+		Short: "Query the shares of a lender in the lending pools",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return queryAddress(cdc, fmt.Sprintf("custom/%s/%s", queryRoute, types.QueryLender), args[0])
+		},
+	}
+}
+
+func queryAddress(cdc *codec.Codec, route, address string) error {
+	cliCtx := context.NewCLIContext().WithCodec(cdc)
+	addr, err := sdk.AccAddressFromBech32(address)
+	if err != nil {
+		return err
+	}
+	bz, err := cdc.MarshalJSON(types.NewQueryAddressParams(addr))
+	if err != nil {
+		return err
+	}
+	res, _, err := cliCtx.QueryWithData(route, bz)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(res))
+	return nil
+}
@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
+	"github.com/spf13/cobra"
+
+	"github.com/okex/okchain/x/margin/types"
+)
+
+// GetTxCmd returns the transaction commands for this module
+func GetTxCmd(cdc *codec.Codec) *cobra.Command {
+	txCmd := &cobra.Command{
+		Use:   "margin",
+		Short: "Margin trading subcommands",
+	}
+
+	txCmd.AddCommand(client.PostCommands(
+		GetCmdOpenAccount(cdc),
+		GetCmdDeposit(cdc),
+		GetCmdWithdraw(cdc),
+		GetCmdBorrow(cdc),
+		GetCmdRepay(cdc),
+		GetCmdLend(cdc),
+		GetCmdRedeem(cdc),
+		GetCmdNewOrder(cdc),
+		GetCmdCancelOrder(cdc),
+	)...)
+
+	return txCmd
+}
+
+func broadcastMsg(cdc *codec.Codec, msg sdk.Msg) error {
+	txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+	cliCtx := context.NewCLIContext().WithCodec(cdc)
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+	return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+}
+
+// GetCmdOpenAccount implements opening a margin account on a product
+func GetCmdOpenAccount(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "open-account [product]",
+		Args:  cobra.ExactArgs(1),
+		Short: "open an isolated margin account on a product",
+		Long: strings.TrimSpace(`Open an isolated margin account on a product:
+
+$ okchaincli tx margin open-account mytoken_okt --from mykey
+
+The 'product' is a trading pair in full name of the tokens: ${base-asset-symbol}_${quote-asset-symbol}, for example 'mytoken_okt'.
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from := context.NewCLIContext().WithCodec(cdc).GetFromAddress()
+			return broadcastMsg(cdc, types.NewMsgOpenAccount(from, args[0]))
+		},
+	}
+}
+
+// GetCmdDeposit implements depositing collateral into a margin account
+func GetCmdDeposit(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "deposit [product] [amount]",
+		Args:  cobra.ExactArgs(2),
+		Short: "deposit collateral into the margin account of a product",
+		Long: strings.TrimSpace(`Deposit collateral into the margin account of a product, the base and the quote tokens of the product are accepted, as well as okt paying the order fees:
+
+$ okchaincli tx margin deposit mytoken_okt 100mytoken,1000okt --from mykey
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from := context.NewCLIContext().WithCodec(cdc).GetFromAddress()
+			amount, err := sdk.ParseDecCoins(args[1])
+			if err != nil {
+				return err
+			}
+			return broadcastMsg(cdc, types.NewMsgDeposit(from, args[0], amount))
+		},
+	}
+}
+
+// GetCmdWithdraw implements withdrawing collateral from a margin account
+func GetCmdWithdraw(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "withdraw [product] [amount]",
+		Args:  cobra.ExactArgs(2),
+		Short: "withdraw collateral from the margin account of a product",
+		Long: strings.TrimSpace(`Withdraw collateral from the margin account of a product:
+
+$ okchaincli tx margin withdraw mytoken_okt 100mytoken --from mykey
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from := context.NewCLIContext().WithCodec(cdc).GetFromAddress()
+			amount, err := sdk.ParseDecCoins(args[1])
+			if err != nil {
+				return err
+			}
+			return broadcastMsg(cdc, types.NewMsgWithdraw(from, args[0], amount))
+		},
+	}
+}
+
+// GetCmdBorrow implements borrowing tokens from a lending pool
+func GetCmdBorrow(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "borrow [product] [amount]",
+		Args:  cobra.ExactArgs(2),
+		Short: "borrow the base or the quote token into the margin account of a product",
+		Long: strings.TrimSpace(`Borrow the base or the quote token from the lending pool into the margin account of a product:
+
+$ okchaincli tx margin borrow mytoken_okt 500okt --from mykey
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from := context.NewCLIContext().WithCodec(cdc).GetFromAddress()
+			amount, err := sdk.ParseDecCoin(args[1])
+			if err != nil {
+				return err
+			}
+			return broadcastMsg(cdc, types.NewMsgBorrow(from, args[0], amount))
+		},
+	}
+}
+
+// GetCmdRepay implements repaying the debts of a margin account
+func GetCmdRepay(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "repay [product] [amount]",
+		Args:  cobra.ExactArgs(2),
+		Short: "repay the debts of the margin account of a product",
+		Long: strings.TrimSpace(`Repay the debts of the margin account of a product with its balances, the interest is paid off first:
+
+$ okchaincli tx margin repay mytoken_okt 500okt --from mykey
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from := context.NewCLIContext().WithCodec(cdc).GetFromAddress()
+			amount, err := sdk.ParseDecCoin(args[1])
+			if err != nil {
+				return err
+			}
+			return broadcastMsg(cdc, types.NewMsgRepay(from, args[0], amount))
+		},
+	}
+}
+
+// GetCmdLend implements lending tokens to a lending pool
+func GetCmdLend(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "lend [amount]",
+		Args:  cobra.ExactArgs(1),
+		Short: "lend tokens to the lending pool of their denom",
+		Long: strings.TrimSpace(`Lend tokens to the lending pool of their denom:
+
+$ okchaincli tx margin lend 1000okt --from mykey
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from := context.NewCLIContext().WithCodec(cdc).GetFromAddress()
+			amount, err := sdk.ParseDecCoin(args[0])
+			if err != nil {
+				return err
+			}
+			return broadcastMsg(cdc, types.NewMsgLend(from, amount))
+		},
+	}
+}
+
+// GetCmdRedeem implements redeeming tokens from a lending pool
+func GetCmdRedeem(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "redeem [amount]",
+		Args:  cobra.ExactArgs(1),
+		Short: "redeem tokens lent to the lending pool of their denom",
+		Long: strings.TrimSpace(`Redeem tokens lent to the lending pool of their denom, together with the interest earned:
+
+$ okchaincli tx margin redeem 1000okt --from mykey
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from := context.NewCLIContext().WithCodec(cdc).GetFromAddress()
+			amount, err := sdk.ParseDecCoin(args[0])
+			if err != nil {
+				return err
+			}
+			return broadcastMsg(cdc, types.NewMsgRedeem(from, amount))
+		},
+	}
+}
+
+// GetCmdNewOrder implements placing an order with a margin account
+func GetCmdNewOrder(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "order [product] [side] [price] [quantity]",
+		Args:  cobra.ExactArgs(4),
+		Short: "place a limit order with the margin account of a product",
+		Long: strings.TrimSpace(`Place a limit order with the margin account of a product:
+
+$ okchaincli tx margin order mytoken_okt BUY 10.0 1.5 --from mykey
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from := context.NewCLIContext().WithCodec(cdc).GetFromAddress()
+			price, err := sdk.NewDecFromStr(args[2])
+			if err != nil {
+				return err
+			}
+			quantity, err := sdk.NewDecFromStr(args[3])
+			if err != nil {
+				return err
+			}
+			return broadcastMsg(cdc, types.NewMsgNewOrder(from, args[0], args[1], price, quantity))
+		},
+	}
+}
+
+// GetCmdCancelOrder implements cancelling an order of a margin account
+func GetCmdCancelOrder(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "cancel [order-id]",
+		Args:  cobra.ExactArgs(1),
+		Short: "cancel an open order placed with a margin account",
+		Long: strings.TrimSpace(`Cancel an open order placed with a margin account:
+
+$ okchaincli tx margin cancel ID0000000010-1 --from mykey
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from := context.NewCLIContext().WithCodec(cdc).GetFromAddress()
+			return broadcastMsg(cdc, types.NewMsgCancelOrder(from, args[0]))
+		},
+	}
+}
@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/rest"
+	"github.com/gorilla/mux"
+
+	"github.com/okex/okchain/x/common"
+	"github.com/okex/okchain/x/margin/types"
+)
+
+// RegisterRoutes - Central function to define routes that get registered by the main application
+func RegisterRoutes(cliCtx context.CLIContext, r *mux.Router) {
+	r.HandleFunc("/margin/params", queryHandler(cliCtx, types.QueryParameters)).Methods("GET")
+	r.HandleFunc("/margin/pools", queryHandler(cliCtx, types.QueryPools)).Methods("GET")
+	r.HandleFunc("/margin/accounts/{address}", addressHandler(cliCtx, types.QueryAccounts)).Methods("GET")
+	r.HandleFunc("/margin/lenders/{address}", addressHandler(cliCtx, types.QueryLender)).Methods("GET")
+}
+
+func queryHandler(cliCtx context.CLIContext, endpoint string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, endpoint), nil)
+		if err != nil {
+			common.HandleErrorMsg(w, cliCtx, err.Error())
+			return
+		}
+		postProcessResponse(w, cliCtx, res)
+	}
+}
+
+func addressHandler(cliCtx context.CLIContext, endpoint string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		addr, err := sdk.AccAddressFromBech32(mux.Vars(r)["address"])
+		if err != nil {
+			common.HandleErrorResponseV2(w, http.StatusBadRequest, common.ErrorInvalidParam)
+			return
+		}
+		bz, err := cliCtx.Codec.MarshalJSON(types.NewQueryAddressParams(addr))
+		if err != nil {
+			common.HandleErrorMsg(w, cliCtx, err.Error())
+			return
+		}
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, endpoint), bz)
+		if err != nil {
+			common.HandleErrorMsg(w, cliCtx, err.Error())
+			return
+		}
+		postProcessResponse(w, cliCtx, res)
+	}
+}
+
+func postProcessResponse(w http.ResponseWriter, cliCtx context.CLIContext, res []byte) {
+	result := common.GetBaseResponse("hello")
+	result2, err := json.Marshal(result)
+	if err != nil {
+		common.HandleErrorMsg(w, cliCtx, err.Error())
+		return
+	}
+	result2 = []byte(strings.Replace(string(result2), "\"hello\"", string(res), 1))
+	rest.PostProcessResponse(w, cliCtx, result2)
+}
@@ -0,0 +1,18 @@
+package margin
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/common/perf"
+	"github.com/okex/okchain/x/margin/types"
+)
+
+// EndBlocker called every block, accrues the interest and liquidates the margin accounts. It runs before the order
+// module, so that the liquidation orders are matched in the same block.
+func EndBlocker(ctx sdk.Context, k Keeper) {
+	seq := perf.GetPerf().OnEndBlockEnter(ctx, types.ModuleName)
+	defer perf.GetPerf().OnEndBlockExit(ctx, types.ModuleName, seq)
+
+	k.AccrueInterest(ctx)
+	k.Liquidate(ctx)
+}
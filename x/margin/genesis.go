@@ -0,0 +1,89 @@
+package margin
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// GenesisState - all margin state that must be provided at genesis
+type GenesisState struct {
+	Params       Params        `json:"params"`
+	Accounts     []*Account    `json:"accounts"`
+	Pools        []*Pool       `json:"pools"`
+	LenderShares []LenderShare `json:"lender_shares"`
+}
+
+// DefaultGenesisState - default GenesisState used by Cosmos Hub
+func DefaultGenesisState() GenesisState {
+	return GenesisState{
+		Params:       *DefaultParams(),
+		Accounts:     nil,
+		Pools:        nil,
+		LenderShares: nil,
+	}
+}
+
+// ValidateGenesis validates the margin genesis parameters
+func ValidateGenesis(data GenesisState) error {
+	if err := data.Params.Validate(); err != nil {
+		return err
+	}
+	for _, account := range data.Accounts {
+		if !account.Address.Equals(GetAccountAddress(account.Owner, account.Product)) {
+			return fmt.Errorf("invalid address of the margin account of %s on %s: %s", account.Owner,
+				account.Product, account.Address)
+		}
+	}
+	for _, pool := range data.Pools {
+		if pool.Principal.Int == nil || pool.Principal.IsNegative() {
+			return fmt.Errorf("invalid principal of the lending pool of %s: %s", pool.Denom, pool.Principal)
+		}
+		if pool.BorrowIndex.Int == nil || pool.BorrowIndex.LT(sdk.OneDec()) ||
+			pool.PrincipalIndex.Int == nil || pool.PrincipalIndex.GT(pool.BorrowIndex) {
+			return fmt.Errorf("invalid borrow index(%s) or principal index(%s) of the lending pool of %s",
+				pool.BorrowIndex, pool.PrincipalIndex, pool.Denom)
+		}
+		if pool.Borrowed.GT(pool.TotalSupply) {
+			return fmt.Errorf("borrowed(%s) is greater than total supply(%s) in the lending pool of %s",
+				pool.Borrowed, pool.TotalSupply, pool.Denom)
+		}
+	}
+	return nil
+}
+
+// InitGenesis initialize default parameters
+// and the keeper's address to pubkey map
+func InitGenesis(ctx sdk.Context, keeper Keeper, data GenesisState) {
+	keeper.SetParams(ctx, data.Params)
+
+	for _, pool := range data.Pools {
+		keeper.SetPool(ctx, pool)
+	}
+	for _, account := range data.Accounts {
+		keeper.SetAccount(ctx, account)
+		if account.Liquidating {
+			keeper.InsertLiquidationQueue(ctx, ctx.BlockHeight(), account)
+		}
+	}
+	for _, lenderShare := range data.LenderShares {
+		keeper.SetLenderShares(ctx, lenderShare.Lender, lenderShare.Denom, lenderShare.Shares)
+	}
+}
+
+// ExportGenesis writes the current store values
+// to a genesis file, which can be imported again
+// with InitGenesis
+func ExportGenesis(ctx sdk.Context, keeper Keeper) (data GenesisState) {
+	var accounts []*Account
+	keeper.IterateAccounts(ctx, func(account *Account) bool {
+		accounts = append(accounts, account)
+		return false
+	})
+	return GenesisState{
+		Params:       keeper.GetParams(ctx),
+		Accounts:     accounts,
+		Pools:        keeper.GetPools(ctx),
+		LenderShares: keeper.GetLenderShareList(ctx, nil),
+	}
+}
@@ -0,0 +1,59 @@
+package margin
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/common"
+	"github.com/okex/okchain/x/margin/keeper"
+	ordertypes "github.com/okex/okchain/x/order/types"
+)
+
+func TestInitExportGenesis(t *testing.T) {
+	testInput := keeper.CreateTestInput(t)
+	ctx, k := testInput.Ctx, testInput.MarginKeeper
+	lender, owner := testInput.TestAddrs[0], testInput.TestAddrs[1]
+
+	account := NewAccount(owner, ordertypes.TestTokenPair)
+	account.Borrowed = sdk.DecCoins{sdk.NewDecCoinFromDec(common.TestToken, sdk.NewDec(5))}
+	account.Interest = sdk.DecCoins{sdk.NewDecCoinFromDec(common.TestToken, sdk.OneDec())}
+	account.BorrowIndexes = sdk.DecCoins{sdk.NewDecCoinFromDec(common.TestToken, sdk.MustNewDecFromStr("1.1"))}
+	pool := &Pool{
+		Denom:          common.TestToken,
+		TotalSupply:    sdk.NewDec(51),
+		TotalShares:    sdk.NewDec(50),
+		Borrowed:       sdk.NewDec(6),
+		Principal:      sdk.NewDec(5),
+		BorrowIndex:    sdk.MustNewDecFromStr("1.1"),
+		PrincipalIndex: sdk.MustNewDecFromStr("1.1"),
+	}
+	params := *DefaultParams()
+	params.BorrowRatio = sdk.NewDec(2)
+	initGenesis := GenesisState{
+		Params:       params,
+		Accounts:     []*Account{account},
+		Pools:        []*Pool{pool},
+		LenderShares: []LenderShare{{Lender: lender, Denom: common.TestToken, Shares: sdk.NewDec(50)}},
+	}
+	require.Nil(t, ValidateGenesis(initGenesis))
+
+	InitGenesis(ctx, k, initGenesis)
+	require.Equal(t, initGenesis, ExportGenesis(ctx, k))
+
+	// invalid genesis
+	account.Address = owner
+	require.NotNil(t, ValidateGenesis(initGenesis))
+	account.Address = GetAccountAddress(owner, ordertypes.TestTokenPair)
+	pool.Borrowed = sdk.NewDec(52)
+	require.NotNil(t, ValidateGenesis(initGenesis))
+	pool.Borrowed = sdk.NewDec(6)
+	pool.BorrowIndex = sdk.ZeroDec()
+	require.NotNil(t, ValidateGenesis(initGenesis))
+	pool.BorrowIndex = sdk.MustNewDecFromStr("1.1")
+	initGenesis.Params.LiquidationRatio = sdk.NewDec(3)
+	require.NotNil(t, ValidateGenesis(initGenesis))
+
+	require.Nil(t, ValidateGenesis(DefaultGenesisState()))
+}
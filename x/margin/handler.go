@@ -0,0 +1,238 @@
+package margin
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/okex/okchain/x/common/perf"
+	"github.com/okex/okchain/x/margin/types"
+)
+
+// NewHandler handles all "margin" type messages.
+func NewHandler(k Keeper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		ctx = ctx.WithEventManager(sdk.NewEventManager())
+		logger := ctx.Logger().With("module", ModuleName)
+
+		var handlerFun func() sdk.Result
+		var name string
+		switch msg := msg.(type) {
+		case MsgOpenAccount:
+			name = "handleMsgOpenAccount"
+			handlerFun = func() sdk.Result {
+				return handleMsgOpenAccount(ctx, k, msg, logger)
+			}
+		case MsgDeposit:
+			name = "handleMsgDeposit"
+			handlerFun = func() sdk.Result {
+				return handleMsgDeposit(ctx, k, msg, logger)
+			}
+		case MsgWithdraw:
+			name = "handleMsgWithdraw"
+			handlerFun = func() sdk.Result {
+				return handleMsgWithdraw(ctx, k, msg, logger)
+			}
+		case MsgBorrow:
+			name = "handleMsgBorrow"
+			handlerFun = func() sdk.Result {
+				return handleMsgBorrow(ctx, k, msg, logger)
+			}
+		case MsgRepay:
+			name = "handleMsgRepay"
+			handlerFun = func() sdk.Result {
+				return handleMsgRepay(ctx, k, msg, logger)
+			}
+		case MsgLend:
+			name = "handleMsgLend"
+			handlerFun = func() sdk.Result {
+				return handleMsgLend(ctx, k, msg, logger)
+			}
+		case MsgRedeem:
+			name = "handleMsgRedeem"
+			handlerFun = func() sdk.Result {
+				return handleMsgRedeem(ctx, k, msg, logger)
+			}
+		case MsgNewOrder:
+			name = "handleMsgNewOrder"
+			handlerFun = func() sdk.Result {
+				return handleMsgNewOrder(ctx, k, msg, logger)
+			}
+		case MsgCancelOrder:
+			name = "handleMsgCancelOrder"
+			handlerFun = func() sdk.Result {
+				return handleMsgCancelOrder(ctx, k, msg, logger)
+			}
+		default:
+			errMsg := fmt.Sprintf("unrecognized margin message type: %T", msg)
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+
+		seq := perf.GetPerf().OnDeliverTxEnter(ctx, ModuleName, name)
+		defer perf.GetPerf().OnDeliverTxExit(ctx, ModuleName, name, seq)
+		return handlerFun()
+	}
+}
+
+func handleMsgOpenAccount(ctx sdk.Context, k Keeper, msg MsgOpenAccount, logger log.Logger) sdk.Result {
+	account, err := k.OpenAccount(ctx, msg.Owner, msg.Product)
+	if err != nil {
+		return err.Result()
+	}
+
+	logger.Debug(fmt.Sprintf("successfully handleMsgOpenAccount: "+
+		"BlockHeight: %d, Msg: %+v", ctx.BlockHeight(), msg))
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, ModuleName),
+			sdk.NewAttribute("address", account.Address.String()),
+		),
+	)
+	return sdk.Result{Events: ctx.EventManager().Events()}
+}
+
+func handleMsgDeposit(ctx sdk.Context, k Keeper, msg MsgDeposit, logger log.Logger) sdk.Result {
+	if err := k.Deposit(ctx, msg.Owner, msg.Product, msg.Amount); err != nil {
+		return err.Result()
+	}
+
+	logger.Debug(fmt.Sprintf("successfully handleMsgDeposit: "+
+		"BlockHeight: %d, Msg: %+v", ctx.BlockHeight(), msg))
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, ModuleName),
+		),
+	)
+	return sdk.Result{Events: ctx.EventManager().Events()}
+}
+
+func handleMsgWithdraw(ctx sdk.Context, k Keeper, msg MsgWithdraw, logger log.Logger) sdk.Result {
+	if err := k.Withdraw(ctx, msg.Owner, msg.Product, msg.Amount); err != nil {
+		return err.Result()
+	}
+
+	logger.Debug(fmt.Sprintf("successfully handleMsgWithdraw: "+
+		"BlockHeight: %d, Msg: %+v", ctx.BlockHeight(), msg))
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, ModuleName),
+		),
+	)
+	return sdk.Result{Events: ctx.EventManager().Events()}
+}
+
+func handleMsgBorrow(ctx sdk.Context, k Keeper, msg MsgBorrow, logger log.Logger) sdk.Result {
+	if err := k.Borrow(ctx, msg.Owner, msg.Product, msg.Amount); err != nil {
+		return err.Result()
+	}
+
+	logger.Debug(fmt.Sprintf("successfully handleMsgBorrow: "+
+		"BlockHeight: %d, Msg: %+v", ctx.BlockHeight(), msg))
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeBorrow,
+			sdk.NewAttribute(types.AttributeKeyOwner, msg.Owner.String()),
+			sdk.NewAttribute(types.AttributeKeyProduct, msg.Product),
+			sdk.NewAttribute(types.AttributeKeyAmount, msg.Amount.String()),
+		),
+	)
+	return sdk.Result{Events: ctx.EventManager().Events()}
+}
+
+func handleMsgRepay(ctx sdk.Context, k Keeper, msg MsgRepay, logger log.Logger) sdk.Result {
+	paid, err := k.Repay(ctx, msg.Owner, msg.Product, msg.Amount)
+	if err != nil {
+		return err.Result()
+	}
+
+	logger.Debug(fmt.Sprintf("successfully handleMsgRepay: "+
+		"BlockHeight: %d, Msg: %+v, Paid: %s", ctx.BlockHeight(), msg, paid))
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeRepay,
+			sdk.NewAttribute(types.AttributeKeyOwner, msg.Owner.String()),
+			sdk.NewAttribute(types.AttributeKeyProduct, msg.Product),
+			sdk.NewAttribute(types.AttributeKeyAmount, paid.String()),
+		),
+	)
+	return sdk.Result{Events: ctx.EventManager().Events()}
+}
+
+func handleMsgLend(ctx sdk.Context, k Keeper, msg MsgLend, logger log.Logger) sdk.Result {
+	if err := k.Lend(ctx, msg.Lender, msg.Amount); err != nil {
+		return err.Result()
+	}
+
+	logger.Debug(fmt.Sprintf("successfully handleMsgLend: "+
+		"BlockHeight: %d, Msg: %+v", ctx.BlockHeight(), msg))
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, ModuleName),
+		),
+	)
+	return sdk.Result{Events: ctx.EventManager().Events()}
+}
+
+func handleMsgRedeem(ctx sdk.Context, k Keeper, msg MsgRedeem, logger log.Logger) sdk.Result {
+	if err := k.Redeem(ctx, msg.Lender, msg.Amount); err != nil {
+		return err.Result()
+	}
+
+	logger.Debug(fmt.Sprintf("successfully handleMsgRedeem: "+
+		"BlockHeight: %d, Msg: %+v", ctx.BlockHeight(), msg))
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, ModuleName),
+		),
+	)
+	return sdk.Result{Events: ctx.EventManager().Events()}
+}
+
+func handleMsgNewOrder(ctx sdk.Context, k Keeper, msg MsgNewOrder, logger log.Logger) sdk.Result {
+	order, err := k.PlaceOrder(ctx, msg.Owner, msg.Product, msg.Side, msg.Price, msg.Quantity)
+	if err != nil {
+		return err.Result()
+	}
+
+	logger.Debug(fmt.Sprintf("successfully handleMsgNewOrder: "+
+		"BlockHeight: %d, Msg: %+v, OrderID: %s", ctx.BlockHeight(), msg, order.OrderID))
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, ModuleName),
+			sdk.NewAttribute("order_id", order.OrderID),
+		),
+	)
+	return sdk.Result{Events: ctx.EventManager().Events()}
+}
+
+func handleMsgCancelOrder(ctx sdk.Context, k Keeper, msg MsgCancelOrder, logger log.Logger) sdk.Result {
+	if err := k.CancelOrder(ctx, msg.Owner, msg.OrderID); err != nil {
+		return err.Result()
+	}
+
+	logger.Debug(fmt.Sprintf("successfully handleMsgCancelOrder: "+
+		"BlockHeight: %d, Msg: %+v", ctx.BlockHeight(), msg))
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, ModuleName),
+		),
+	)
+	return sdk.Result{Events: ctx.EventManager().Events()}
+}
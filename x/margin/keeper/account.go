@@ -0,0 +1,123 @@
+package keeper
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/common"
+	"github.com/okex/okchain/x/margin/types"
+)
+
+// OpenAccount opens an isolated margin account of owner on product
+func (k Keeper) OpenAccount(ctx sdk.Context, owner sdk.AccAddress, product string) (*types.Account, sdk.Error) {
+	if tokenPair := k.dexKeeper.GetTokenPair(ctx, product); tokenPair == nil {
+		return nil, types.ErrInvalidProduct(fmt.Sprintf("trading pair '%s' does not exist", product))
+	}
+	if k.GetAccount(ctx, owner, product) != nil {
+		return nil, types.ErrAccountExists(owner, product)
+	}
+	account := types.NewAccount(owner, product)
+	k.SetAccount(ctx, account)
+	return account, nil
+}
+
+// getActiveAccount returns the margin account of owner on product, which is not being liquidated
+func (k Keeper) getActiveAccount(ctx sdk.Context, owner sdk.AccAddress, product string) (*types.Account, sdk.Error) {
+	account := k.GetAccount(ctx, owner, product)
+	if account == nil {
+		return nil, types.ErrAccountNotFound(owner, product)
+	}
+	if account.Liquidating {
+		return nil, types.ErrAccountLiquidating(owner, product)
+	}
+	return account, nil
+}
+
+// Deposit moves collateral from the owner into the margin account, the base and the quote tokens of the product are
+// accepted, as well as the native token paying the order fees
+func (k Keeper) Deposit(ctx sdk.Context, owner sdk.AccAddress, product string, amount sdk.DecCoins) sdk.Error {
+	account := k.GetAccount(ctx, owner, product)
+	if account == nil {
+		return types.ErrAccountNotFound(owner, product)
+	}
+	base, quote := account.GetBaseQuote()
+	for _, coin := range amount {
+		if coin.Denom != base && coin.Denom != quote && coin.Denom != common.NativeToken {
+			return types.ErrInvalidDenom(fmt.Sprintf("%s is not accepted by the margin account of %s",
+				coin.Denom, product))
+		}
+	}
+	if err := k.tokenKeeper.SendCoinsFromAccountToAccount(ctx, owner, account.Address, amount); err != nil {
+		return sdk.ErrInsufficientCoins(err.Error())
+	}
+	return nil
+}
+
+// Withdraw moves the available collateral of the margin account back to the owner, the collateral ratio must be kept
+// above the borrow ratio if the account has debts
+func (k Keeper) Withdraw(ctx sdk.Context, owner sdk.AccAddress, product string, amount sdk.DecCoins) sdk.Error {
+	account, err := k.getActiveAccount(ctx, owner, product)
+	if err != nil {
+		return err
+	}
+	balances, hasNeg := k.GetAccountBalances(ctx, account).SafeSub(amount)
+	if hasNeg {
+		return sdk.ErrInsufficientCoins(fmt.Sprintf("insufficient balances of the margin account to withdraw %s",
+			amount))
+	}
+	if err := k.checkCollateral(ctx, account, balances, account.Debts()); err != nil {
+		return err
+	}
+	if err := k.tokenKeeper.SendCoinsFromAccountToAccount(ctx, account.Address, owner, amount); err != nil {
+		return sdk.ErrInsufficientCoins(err.Error())
+	}
+	return nil
+}
+
+// GetAccountBalances returns the coins held by a margin account, including the ones locked by its orders
+func (k Keeper) GetAccountBalances(ctx sdk.Context, account *types.Account) sdk.DecCoins {
+	return k.tokenKeeper.GetCoins(ctx, account.Address).Add(k.tokenKeeper.GetLockCoins(ctx, account.Address))
+}
+
+// GetCollateralRatio returns the collateral ratio of a margin account, zero if the account has no debts
+func (k Keeper) GetCollateralRatio(ctx sdk.Context, account *types.Account) sdk.Dec {
+	return k.collateralRatio(ctx, account, k.GetAccountBalances(ctx, account), account.Debts())
+}
+
+// collateralRatio returns the value of the balances divided by the value of the debts, both priced in the quote token
+// at the last price of the product. Only the base and the quote tokens are counted.
+func (k Keeper) collateralRatio(ctx sdk.Context, account *types.Account, balances, debts sdk.DecCoins) sdk.Dec {
+	base, quote := account.GetBaseQuote()
+	price := k.orderKeeper.GetLastPrice(ctx, account.Product)
+	valueOf := func(coins sdk.DecCoins) sdk.Dec {
+		return coins.AmountOf(base).Mul(price).Add(coins.AmountOf(quote))
+	}
+	debtsValue := valueOf(debts)
+	if !debtsValue.IsPositive() {
+		return sdk.ZeroDec()
+	}
+	return valueOf(balances).Quo(debtsValue)
+}
+
+// checkCollateral checks whether a margin account with the balances and the debts meets the borrow ratio
+func (k Keeper) checkCollateral(ctx sdk.Context, account *types.Account, balances, debts sdk.DecCoins) sdk.Error {
+	if debts.IsZero() {
+		return nil
+	}
+	borrowRatio := k.GetParams(ctx).BorrowRatio
+	if ratio := k.collateralRatio(ctx, account, balances, debts); ratio.LT(borrowRatio) {
+		return types.ErrInsufficientCollateral(ratio, borrowRatio)
+	}
+	return nil
+}
+
+// GetAccountInfo returns a margin account with its balances and collateral ratio
+func (k Keeper) GetAccountInfo(ctx sdk.Context, account *types.Account) types.AccountInfo {
+	return types.AccountInfo{
+		Account:         *account,
+		Available:       k.tokenKeeper.GetCoins(ctx, account.Address),
+		Locked:          k.tokenKeeper.GetLockCoins(ctx, account.Address),
+		CollateralRatio: k.GetCollateralRatio(ctx, account),
+	}
+}
@@ -0,0 +1,45 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/supply/exported"
+	"github.com/tendermint/tendermint/libs/log"
+
+	dex "github.com/okex/okchain/x/dex/types"
+	order "github.com/okex/okchain/x/order/types"
+)
+
+// SupplyKeeper defines the expected supply keeper, the coins of the lending pools are held by the module account
+type SupplyKeeper interface {
+	SendCoinsFromAccountToModule(ctx sdk.Context, senderAddr sdk.AccAddress, recipientModule string,
+		amt sdk.Coins) sdk.Error
+	SendCoinsFromModuleToAccount(ctx sdk.Context, senderModule string, recipientAddr sdk.AccAddress,
+		amt sdk.Coins) sdk.Error
+	GetModuleAccount(ctx sdk.Context, moduleName string) exported.ModuleAccountI
+}
+
+// TokenKeeper defines the expected token keeper
+type TokenKeeper interface {
+	TokenExist(ctx sdk.Context, symbol string) bool
+	GetCoins(ctx sdk.Context, addr sdk.AccAddress) sdk.DecCoins
+	GetLockCoins(ctx sdk.Context, addr sdk.AccAddress) sdk.DecCoins
+	SendCoinsFromAccountToAccount(ctx sdk.Context, from, to sdk.AccAddress, amt sdk.DecCoins) error
+}
+
+// DexKeeper defines the expected dex keeper
+type DexKeeper interface {
+	GetTokenPair(ctx sdk.Context, product string) *dex.TokenPair
+}
+
+// OrderKeeper defines the expected order keeper, the margin orders are placed into the spot order book
+type OrderKeeper interface {
+	GetParams(ctx sdk.Context) *order.Params
+	GetLastPrice(ctx sdk.Context, product string) sdk.Dec
+	GetOrder(ctx sdk.Context, orderID string) *order.Order
+	GetSenderOrderIDs(ctx sdk.Context, sender sdk.AccAddress, product string) []string
+	PlaceOrder(ctx sdk.Context, order *order.Order) error
+	CancelOrder(ctx sdk.Context, order *order.Order, logger log.Logger) sdk.DecCoins
+	BurnEarlyCancelledDeposit(ctx sdk.Context, order *order.Order, logger log.Logger)
+	ValidateNewOrder(ctx sdk.Context, product string, price, quantity sdk.Dec) error
+	CheckOrderLimits(ctx sdk.Context, sender sdk.AccAddress, product string, pendingNum int64) sdk.Error
+}
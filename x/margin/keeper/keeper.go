@@ -0,0 +1,189 @@
+package keeper
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/margin/types"
+	"github.com/okex/okchain/x/params"
+)
+
+// Keeper maintains the margin accounts and the lending pools
+type Keeper struct {
+	storeKey      sdk.StoreKey
+	cdc           *codec.Codec
+	paramSubspace params.Subspace
+	supplyKeeper  SupplyKeeper
+	tokenKeeper   TokenKeeper
+	dexKeeper     DexKeeper
+	orderKeeper   OrderKeeper
+}
+
+// NewKeeper creates a new margin keeper
+func NewKeeper(cdc *codec.Codec, storeKey sdk.StoreKey, paramSubspace params.Subspace, supplyKeeper SupplyKeeper,
+	tokenKeeper TokenKeeper, dexKeeper DexKeeper, orderKeeper OrderKeeper) Keeper {
+	return Keeper{
+		storeKey:      storeKey,
+		cdc:           cdc,
+		paramSubspace: paramSubspace.WithKeyTable(types.ParamKeyTable()),
+		supplyKeeper:  supplyKeeper,
+		tokenKeeper:   tokenKeeper,
+		dexKeeper:     dexKeeper,
+		orderKeeper:   orderKeeper,
+	}
+}
+
+// GetCDC returns the codec of the keeper
+func (k Keeper) GetCDC() *codec.Codec {
+	return k.cdc
+}
+
+// GetParams returns the params of the margin module
+func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
+	k.paramSubspace.GetParamSet(ctx, &params)
+	return params
+}
+
+// SetParams sets the params of the margin module
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.paramSubspace.SetParamSet(ctx, &params)
+}
+
+// GetAccount returns the margin account of owner on product, nil if it's not opened
+func (k Keeper) GetAccount(ctx sdk.Context, owner sdk.AccAddress, product string) *types.Account {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GetAccountKey(owner, product))
+	if bz == nil {
+		return nil
+	}
+	var account types.Account
+	k.cdc.MustUnmarshalBinaryBare(bz, &account)
+	k.settleInterest(ctx, &account)
+	return &account
+}
+
+// SetAccount stores a margin account
+func (k Keeper) SetAccount(ctx sdk.Context, account *types.Account) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.GetAccountKey(account.Owner, account.Product), k.cdc.MustMarshalBinaryBare(account))
+}
+
+// GetOwnerAccounts returns all the margin accounts of an owner
+func (k Keeper) GetOwnerAccounts(ctx sdk.Context, owner sdk.AccAddress) []*types.Account {
+	var accounts []*types.Account
+	k.iterateAccounts(ctx, types.GetAccountsPrefix(owner), func(account *types.Account) bool {
+		accounts = append(accounts, account)
+		return false
+	})
+	return accounts
+}
+
+// IterateAccounts iterates all the margin accounts till fn returns true
+func (k Keeper) IterateAccounts(ctx sdk.Context, fn func(account *types.Account) (stop bool)) {
+	k.iterateAccounts(ctx, types.AccountKey, fn)
+}
+
+func (k Keeper) iterateAccounts(ctx sdk.Context, prefix []byte, fn func(account *types.Account) (stop bool)) {
+	store := ctx.KVStore(k.storeKey)
+	iter := sdk.KVStorePrefixIterator(store, prefix)
+	defer iter.Close()
+
+	for ; iter.Valid(); iter.Next() {
+		var account types.Account
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &account)
+		k.settleInterest(ctx, &account)
+		if fn(&account) {
+			break
+		}
+	}
+}
+
+// InsertLiquidationQueue queues a margin account for the liquidation at blockHeight
+func (k Keeper) InsertLiquidationQueue(ctx sdk.Context, blockHeight int64, account *types.Account) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.GetLiquidationQueueKey(blockHeight, account.Owner, account.Product), []byte{})
+}
+
+// getLiquidationQueueKeys returns at most limit keys of the liquidation queue in the order they're queued
+func (k Keeper) getLiquidationQueueKeys(ctx sdk.Context, limit int64) [][]byte {
+	store := ctx.KVStore(k.storeKey)
+	iter := sdk.KVStorePrefixIterator(store, types.LiquidationQueueKey)
+	defer iter.Close()
+
+	var keys [][]byte
+	for ; iter.Valid() && int64(len(keys)) < limit; iter.Next() {
+		keys = append(keys, iter.Key())
+	}
+	return keys
+}
+
+// GetPool returns the lending pool of denom, an empty pool if no one has lent the denom
+func (k Keeper) GetPool(ctx sdk.Context, denom string) *types.Pool {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GetPoolKey(denom))
+	if bz == nil {
+		return types.NewPool(denom)
+	}
+	var pool types.Pool
+	k.cdc.MustUnmarshalBinaryBare(bz, &pool)
+	return &pool
+}
+
+// SetPool stores a lending pool
+func (k Keeper) SetPool(ctx sdk.Context, pool *types.Pool) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.GetPoolKey(pool.Denom), k.cdc.MustMarshalBinaryBare(pool))
+}
+
+// GetPools returns all the lending pools
+func (k Keeper) GetPools(ctx sdk.Context) []*types.Pool {
+	store := ctx.KVStore(k.storeKey)
+	iter := sdk.KVStorePrefixIterator(store, types.PoolKey)
+	defer iter.Close()
+
+	var pools []*types.Pool
+	for ; iter.Valid(); iter.Next() {
+		var pool types.Pool
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &pool)
+		pools = append(pools, &pool)
+	}
+	return pools
+}
+
+// GetLenderShares returns the shares of lender in the lending pool of denom
+func (k Keeper) GetLenderShares(ctx sdk.Context, lender sdk.AccAddress, denom string) sdk.Dec {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GetLenderShareKey(lender, denom))
+	if bz == nil {
+		return sdk.ZeroDec()
+	}
+	var shares sdk.Dec
+	k.cdc.MustUnmarshalBinaryBare(bz, &shares)
+	return shares
+}
+
+// SetLenderShares stores the shares of lender in the lending pool of denom, the zero shares are dropped
+func (k Keeper) SetLenderShares(ctx sdk.Context, lender sdk.AccAddress, denom string, shares sdk.Dec) {
+	store := ctx.KVStore(k.storeKey)
+	if !shares.IsPositive() {
+		store.Delete(types.GetLenderShareKey(lender, denom))
+		return
+	}
+	store.Set(types.GetLenderShareKey(lender, denom), k.cdc.MustMarshalBinaryBare(shares))
+}
+
+// GetLenderShareList returns the shares of a lender in all the lending pools, or of all the lenders if lender is empty
+func (k Keeper) GetLenderShareList(ctx sdk.Context, lender sdk.AccAddress) []types.LenderShare {
+	store := ctx.KVStore(k.storeKey)
+	iter := sdk.KVStorePrefixIterator(store, types.GetLenderSharesPrefix(lender))
+	defer iter.Close()
+
+	var lenderShares []types.LenderShare
+	for ; iter.Valid(); iter.Next() {
+		addr, denom := types.SplitLenderShareKey(iter.Key())
+		var shares sdk.Dec
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &shares)
+		lenderShares = append(lenderShares, types.LenderShare{Lender: addr, Denom: denom, Shares: shares})
+	}
+	return lenderShares
+}
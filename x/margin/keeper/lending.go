@@ -0,0 +1,165 @@
+package keeper
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/margin/types"
+)
+
+// Lend moves coins from the lender into the lending pool of their denom, the lender gets the shares of the pool
+// worth the amount
+func (k Keeper) Lend(ctx sdk.Context, lender sdk.AccAddress, amount sdk.DecCoin) sdk.Error {
+	if !k.tokenKeeper.TokenExist(ctx, amount.Denom) {
+		return types.ErrInvalidDenom(fmt.Sprintf("token %s does not exist", amount.Denom))
+	}
+	if err := k.supplyKeeper.SendCoinsFromAccountToModule(ctx, lender, types.ModuleName,
+		sdk.DecCoins{amount}); err != nil {
+		return err
+	}
+
+	pool := k.GetPool(ctx, amount.Denom)
+	shares := pool.SharesOf(amount.Amount)
+	pool.TotalSupply = pool.TotalSupply.Add(amount.Amount)
+	pool.TotalShares = pool.TotalShares.Add(shares)
+	k.SetPool(ctx, pool)
+	k.SetLenderShares(ctx, lender, amount.Denom, k.GetLenderShares(ctx, lender, amount.Denom).Add(shares))
+	return nil
+}
+
+// Redeem moves coins from the lending pool back to the lender by burning the shares worth the amount, only the coins
+// not borrowed can be redeemed
+func (k Keeper) Redeem(ctx sdk.Context, lender sdk.AccAddress, amount sdk.DecCoin) sdk.Error {
+	pool := k.GetPool(ctx, amount.Denom)
+	lenderShares := k.GetLenderShares(ctx, lender, amount.Denom)
+	if value := pool.ValueOf(lenderShares); amount.Amount.GT(value) {
+		return types.ErrInsufficientShares(fmt.Sprintf("shares of %s are worth %s%s only", lender, value,
+			amount.Denom))
+	}
+	if available := pool.Available(); amount.Amount.GT(available) {
+		return types.ErrInsufficientPool(amount.Denom, available)
+	}
+
+	// the shares worth the amount might be rounded beyond the ones of the lender
+	shares := sdk.MinDec(pool.SharesOf(amount.Amount), lenderShares)
+	pool.TotalSupply = pool.TotalSupply.Sub(amount.Amount)
+	pool.TotalShares = pool.TotalShares.Sub(shares)
+	k.SetPool(ctx, pool)
+	k.SetLenderShares(ctx, lender, amount.Denom, lenderShares.Sub(shares))
+	return k.supplyKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, lender, sdk.DecCoins{amount})
+}
+
+// Borrow lends the base or the quote token of the product from the lending pool to the margin account, the collateral
+// ratio after borrowing must not be less than the borrow ratio
+func (k Keeper) Borrow(ctx sdk.Context, owner sdk.AccAddress, product string, amount sdk.DecCoin) sdk.Error {
+	account, err := k.getActiveAccount(ctx, owner, product)
+	if err != nil {
+		return err
+	}
+	if base, quote := account.GetBaseQuote(); amount.Denom != base && amount.Denom != quote {
+		return types.ErrInvalidDenom(fmt.Sprintf("only %s or %s can be borrowed by the margin account of %s",
+			base, quote, product))
+	}
+	pool := k.GetPool(ctx, amount.Denom)
+	if available := pool.Available(); amount.Amount.GT(available) {
+		return types.ErrInsufficientPool(amount.Denom, available)
+	}
+	borrowed := sdk.DecCoins{amount}
+	balances := k.GetAccountBalances(ctx, account).Add(borrowed)
+	if err := k.checkCollateral(ctx, account, balances, account.Debts().Add(borrowed)); err != nil {
+		return err
+	}
+
+	if err := k.supplyKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, account.Address,
+		borrowed); err != nil {
+		return err
+	}
+	account.Borrowed = account.Borrowed.Add(borrowed)
+	k.settleInterest(ctx, account)
+	k.SetAccount(ctx, account)
+	pool.AddPrincipal(amount.Amount)
+	k.SetPool(ctx, pool)
+	return nil
+}
+
+// Repay pays off the debts of the margin account in the amount's denom with its available balances, at most the
+// debts are paid off. It returns the amount paid off.
+func (k Keeper) Repay(ctx sdk.Context, owner sdk.AccAddress, product string,
+	amount sdk.DecCoin) (sdk.DecCoin, sdk.Error) {
+	account := k.GetAccount(ctx, owner, product)
+	if account == nil {
+		return sdk.DecCoin{}, types.ErrAccountNotFound(owner, product)
+	}
+	if !account.Debts().AmountOf(amount.Denom).IsPositive() {
+		return sdk.DecCoin{}, types.ErrInvalidDenom(fmt.Sprintf("no %s is owed by the margin account", amount.Denom))
+	}
+	return k.repay(ctx, account, amount)
+}
+
+// repay pays off the debts of a margin account and returns the coins back to the lending pool
+func (k Keeper) repay(ctx sdk.Context, account *types.Account, amount sdk.DecCoin) (sdk.DecCoin, sdk.Error) {
+	origin := *account
+	paid := account.Repay(amount)
+	if !paid.IsPositive() {
+		return paid, nil
+	}
+	if err := k.supplyKeeper.SendCoinsFromAccountToModule(ctx, account.Address, types.ModuleName,
+		sdk.DecCoins{paid}); err != nil {
+		*account = origin
+		return paid, err
+	}
+	if !account.HasDebts() {
+		account.Liquidating = false
+	}
+	k.settleInterest(ctx, account)
+	k.SetAccount(ctx, account)
+	pool := k.GetPool(ctx, paid.Denom)
+	principal := origin.Borrowed.AmountOf(paid.Denom).Sub(account.Borrowed.AmountOf(paid.Denom))
+	pool.AddPrincipal(principal.Neg())
+	pool.AddBorrowed(paid.Amount.Sub(principal).Neg())
+	k.SetPool(ctx, pool)
+	return paid, nil
+}
+
+// AccrueInterest grows the borrow indexes of the lending pools by the interest rate of a block, the interest is
+// earned by the lenders. The margin accounts owe the interest on their principal since they last took the indexes.
+func (k Keeper) AccrueInterest(ctx sdk.Context) {
+	rate := k.GetParams(ctx).InterestRatePerBlock
+	if !rate.IsPositive() {
+		return
+	}
+	for _, pool := range k.GetPools(ctx) {
+		if !pool.Principal.IsPositive() {
+			continue
+		}
+		pool.AccrueInterest(rate)
+		k.SetPool(ctx, pool)
+	}
+}
+
+// settleInterest accrues the interest on the principal of a margin account up to the current borrow indexes
+func (k Keeper) settleInterest(ctx sdk.Context, account *types.Account) {
+	account.AccrueInterest(func(denom string) sdk.Dec {
+		return k.GetPool(ctx, denom).BorrowIndex
+	})
+}
+
+// writeOffDebts drops the debts of a margin account which can't be paid off, the loss is taken by the lenders
+func (k Keeper) writeOffDebts(ctx sdk.Context, account *types.Account) sdk.DecCoins {
+	debts := account.Debts()
+	for _, debt := range debts {
+		pool := k.GetPool(ctx, debt.Denom)
+		pool.TotalSupply = pool.TotalSupply.Sub(debt.Amount)
+		principal := account.Borrowed.AmountOf(debt.Denom)
+		pool.AddPrincipal(principal.Neg())
+		pool.AddBorrowed(debt.Amount.Sub(principal).Neg())
+		k.SetPool(ctx, pool)
+	}
+	account.Borrowed = nil
+	account.Interest = nil
+	account.BorrowIndexes = nil
+	account.Liquidating = false
+	k.SetAccount(ctx, account)
+	return debts
+}
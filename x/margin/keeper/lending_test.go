@@ -0,0 +1,187 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/common"
+	"github.com/okex/okchain/x/margin/types"
+	ordertypes "github.com/okex/okchain/x/order/types"
+)
+
+var product = ordertypes.TestTokenPair
+
+func TestKeeper_LendRedeem(t *testing.T) {
+	testInput := CreateTestInput(t)
+	ctx, keeper := testInput.Ctx, testInput.MarginKeeper
+	lender := testInput.TestAddrs[0]
+
+	// unknown token
+	err := keeper.Lend(ctx, lender, sdk.NewDecCoinFromDec("unknown", sdk.OneDec()))
+	require.NotNil(t, err)
+	// insufficient coins
+	err = keeper.Lend(ctx, lender, sdk.NewDecCoinFromDec(common.TestToken, sdk.NewDec(101)))
+	require.NotNil(t, err)
+
+	err = keeper.Lend(ctx, lender, sdk.NewDecCoinFromDec(common.TestToken, sdk.NewDec(50)))
+	require.Nil(t, err)
+	pool := keeper.GetPool(ctx, common.TestToken)
+	require.EqualValues(t, sdk.NewDec(50), pool.TotalSupply)
+	require.EqualValues(t, sdk.NewDec(50), pool.TotalShares)
+	require.EqualValues(t, sdk.NewDec(50), keeper.GetLenderShares(ctx, lender, common.TestToken))
+	require.EqualValues(t, sdk.NewDec(50), testInput.TokenKeeper.GetCoins(ctx, lender).AmountOf(common.TestToken))
+
+	// the interest earned makes the shares worth more
+	pool.TotalSupply = sdk.NewDec(100)
+	keeper.SetPool(ctx, pool)
+	err = keeper.Redeem(ctx, lender, sdk.NewDecCoinFromDec(common.TestToken, sdk.NewDec(101)))
+	require.NotNil(t, err)
+	err = keeper.Redeem(ctx, lender, sdk.NewDecCoinFromDec(common.TestToken, sdk.NewDec(40)))
+	require.Nil(t, err)
+	require.EqualValues(t, sdk.NewDec(30), keeper.GetLenderShares(ctx, lender, common.TestToken))
+	require.EqualValues(t, sdk.NewDec(90), testInput.TokenKeeper.GetCoins(ctx, lender).AmountOf(common.TestToken))
+
+	// the coins borrowed can't be redeemed
+	pool = keeper.GetPool(ctx, common.TestToken)
+	pool.Borrowed = sdk.NewDec(50)
+	keeper.SetPool(ctx, pool)
+	err = keeper.Redeem(ctx, lender, sdk.NewDecCoinFromDec(common.TestToken, sdk.NewDec(20)))
+	require.NotNil(t, err)
+	err = keeper.Redeem(ctx, lender, sdk.NewDecCoinFromDec(common.TestToken, sdk.NewDec(10)))
+	require.Nil(t, err)
+	require.EqualValues(t, sdk.NewDec(25), keeper.GetLenderShares(ctx, lender, common.TestToken))
+
+	lenderShares := keeper.GetLenderShareList(ctx, lender)
+	require.EqualValues(t, 1, len(lenderShares))
+	require.EqualValues(t, common.TestToken, lenderShares[0].Denom)
+}
+
+func TestKeeper_BorrowRepay(t *testing.T) {
+	testInput := CreateTestInput(t)
+	ctx, keeper := testInput.Ctx, testInput.MarginKeeper
+	lender, owner := testInput.TestAddrs[0], testInput.TestAddrs[1]
+
+	err := keeper.Lend(ctx, lender, sdk.NewDecCoinFromDec(common.TestToken, sdk.NewDec(50)))
+	require.Nil(t, err)
+
+	// no margin account
+	err = keeper.Borrow(ctx, owner, product, sdk.NewDecCoinFromDec(common.TestToken, sdk.NewDec(1)))
+	require.NotNil(t, err)
+
+	_, err = keeper.OpenAccount(ctx, owner, product)
+	require.Nil(t, err)
+	_, err = keeper.OpenAccount(ctx, owner, product)
+	require.NotNil(t, err)
+	_, err = keeper.OpenAccount(ctx, owner, "unknown_"+common.NativeToken)
+	require.NotNil(t, err)
+
+	err = keeper.Deposit(ctx, owner, product, sdk.DecCoins{sdk.NewDecCoinFromDec(common.NativeToken,
+		sdk.NewDec(30))})
+	require.Nil(t, err)
+
+	// pool of okt is empty
+	err = keeper.Borrow(ctx, owner, product, sdk.NewDecCoinFromDec(common.NativeToken, sdk.NewDec(1)))
+	require.NotNil(t, err)
+	// collateral ratio (30+5*10)/(5*10) = 1.6
+	err = keeper.Borrow(ctx, owner, product, sdk.NewDecCoinFromDec(common.TestToken, sdk.NewDec(5)))
+	require.Nil(t, err)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.6"), keeper.GetCollateralRatio(ctx,
+		keeper.GetAccount(ctx, owner, product)))
+	// collateral ratio (30+6*10)/(6*10) = 1.5
+	err = keeper.Borrow(ctx, owner, product, sdk.NewDecCoinFromDec(common.TestToken, sdk.NewDec(1)))
+	require.Nil(t, err)
+	// collateral ratio (30+7*10)/(7*10) < 1.5
+	err = keeper.Borrow(ctx, owner, product, sdk.NewDecCoinFromDec(common.TestToken, sdk.NewDec(1)))
+	require.NotNil(t, err)
+	// the collateral ratio would drop below the borrow ratio
+	err = keeper.Withdraw(ctx, owner, product, sdk.DecCoins{sdk.NewDecCoinFromDec(common.NativeToken,
+		sdk.NewDec(1))})
+	require.NotNil(t, err)
+
+	account := keeper.GetAccount(ctx, owner, product)
+	require.EqualValues(t, sdk.NewDec(6), account.Borrowed.AmountOf(common.TestToken))
+	require.EqualValues(t, sdk.NewDec(6), keeper.GetPool(ctx, common.TestToken).Borrowed)
+
+	// interest is paid off first
+	account.AddInterest(sdk.DecCoins{sdk.NewDecCoinFromDec(common.TestToken, sdk.OneDec())})
+	keeper.SetAccount(ctx, account)
+	pool := keeper.GetPool(ctx, common.TestToken)
+	pool.TotalSupply = pool.TotalSupply.Add(sdk.OneDec())
+	pool.Borrowed = pool.Borrowed.Add(sdk.OneDec())
+	keeper.SetPool(ctx, pool)
+	_, err = keeper.Repay(ctx, owner, product, sdk.NewDecCoinFromDec(common.NativeToken, sdk.NewDec(1)))
+	require.NotNil(t, err)
+	paid, err := keeper.Repay(ctx, owner, product, sdk.NewDecCoinFromDec(common.TestToken, sdk.NewDec(2)))
+	require.Nil(t, err)
+	require.EqualValues(t, sdk.NewDec(2), paid.Amount)
+	account = keeper.GetAccount(ctx, owner, product)
+	require.True(t, account.Interest.IsZero())
+	require.EqualValues(t, sdk.NewDec(5), account.Borrowed.AmountOf(common.TestToken))
+
+	// at most the debts are paid off
+	err = keeper.Deposit(ctx, owner, product, sdk.DecCoins{sdk.NewDecCoinFromDec(common.TestToken, sdk.OneDec())})
+	require.Nil(t, err)
+	paid, err = keeper.Repay(ctx, owner, product, sdk.NewDecCoinFromDec(common.TestToken, sdk.NewDec(10)))
+	require.Nil(t, err)
+	require.EqualValues(t, sdk.NewDec(5), paid.Amount)
+	account = keeper.GetAccount(ctx, owner, product)
+	require.False(t, account.HasDebts())
+	require.True(t, keeper.GetPool(ctx, common.TestToken).Borrowed.IsZero())
+	require.EqualValues(t, sdk.NewDec(51), keeper.GetPool(ctx, common.TestToken).TotalSupply)
+
+	// all the collateral can be withdrawn without debts
+	err = keeper.Withdraw(ctx, owner, product, sdk.DecCoins{sdk.NewDecCoinFromDec(common.NativeToken,
+		sdk.NewDec(30))})
+	require.Nil(t, err)
+	require.EqualValues(t, sdk.NewDec(100), testInput.TokenKeeper.GetCoins(ctx, owner).AmountOf(common.NativeToken))
+}
+
+func TestKeeper_AccrueInterest(t *testing.T) {
+	testInput := CreateTestInput(t)
+	ctx, keeper := testInput.Ctx, testInput.MarginKeeper
+	lender, owner := testInput.TestAddrs[0], testInput.TestAddrs[1]
+
+	params := keeper.GetParams(ctx)
+	params.InterestRatePerBlock = sdk.MustNewDecFromStr("0.01")
+	keeper.SetParams(ctx, params)
+
+	err := keeper.Lend(ctx, lender, sdk.NewDecCoinFromDec(common.TestToken, sdk.NewDec(50)))
+	require.Nil(t, err)
+	_, err = keeper.OpenAccount(ctx, owner, product)
+	require.Nil(t, err)
+	err = keeper.Deposit(ctx, owner, product, sdk.DecCoins{sdk.NewDecCoinFromDec(common.NativeToken,
+		sdk.NewDec(100))})
+	require.Nil(t, err)
+	err = keeper.Borrow(ctx, owner, product, sdk.NewDecCoinFromDec(common.TestToken, sdk.NewDec(10)))
+	require.Nil(t, err)
+
+	// simple interest on the principal
+	keeper.AccrueInterest(ctx)
+	keeper.AccrueInterest(ctx)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.02"), keeper.GetPool(ctx, common.TestToken).BorrowIndex)
+	// the margin accounts are not rewritten, their interest is settled with the borrow index as they're read
+	var stored types.Account
+	keeper.cdc.MustUnmarshalBinaryBare(ctx.KVStore(keeper.storeKey).Get(types.GetAccountKey(owner, product)), &stored)
+	require.True(t, stored.Interest.IsZero())
+	account := keeper.GetAccount(ctx, owner, product)
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.2"), account.Interest.AmountOf(common.TestToken))
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.2"), account.Debts().AmountOf(common.TestToken))
+
+	// the interest is earned by the lenders
+	pool := keeper.GetPool(ctx, common.TestToken)
+	require.EqualValues(t, sdk.MustNewDecFromStr("50.2"), pool.TotalSupply)
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.2"), pool.Borrowed)
+	require.EqualValues(t, sdk.MustNewDecFromStr("50.2"), pool.ValueOf(keeper.GetLenderShares(ctx, lender,
+		common.TestToken)))
+
+	// no interest after the debts are paid off
+	err = keeper.Deposit(ctx, owner, product, sdk.DecCoins{sdk.NewDecCoinFromDec(common.TestToken, sdk.OneDec())})
+	require.Nil(t, err)
+	_, err = keeper.Repay(ctx, owner, product, sdk.NewDecCoinFromDec(common.TestToken, sdk.NewDec(11)))
+	require.Nil(t, err)
+	keeper.AccrueInterest(ctx)
+	require.False(t, keeper.GetAccount(ctx, owner, product).HasDebts())
+	require.EqualValues(t, sdk.MustNewDecFromStr("50.2"), keeper.GetPool(ctx, common.TestToken).TotalSupply)
+}
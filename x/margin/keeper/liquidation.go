@@ -0,0 +1,235 @@
+package keeper
+
+import (
+	"fmt"
+	"math/big"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/margin/types"
+	order "github.com/okex/okchain/x/order/types"
+)
+
+// Liquidate queues the margin accounts whose collateral ratio falls below the liquidation ratio, and goes on with at
+// most MaxLiquidationsPerBlock accounts of the liquidation queue. It returns the number of the margin accounts
+// liquidated in this block.
+func (k Keeper) Liquidate(ctx sdk.Context) int {
+	params := k.GetParams(ctx)
+	k.checkCollateralRatios(ctx, params)
+
+	store := ctx.KVStore(k.storeKey)
+	liquidated := make(map[string]bool)
+	for _, key := range k.getLiquidationQueueKeys(ctx, params.MaxLiquidationsPerBlock) {
+		store.Delete(key)
+		owner, product := types.SplitLiquidationQueueKey(key)
+		account := k.GetAccount(ctx, owner, product)
+		// the account is dropped from the queue if its debts have been paid off by the owner
+		if account == nil || !account.Liquidating || liquidated[account.Address.String()] {
+			continue
+		}
+		k.liquidate(ctx, account, params)
+		liquidated[account.Address.String()] = true
+		// the account goes to the end of the queue till its debts are paid off
+		if account.Liquidating {
+			k.InsertLiquidationQueue(ctx, ctx.BlockHeight(), account)
+		}
+	}
+	return len(liquidated)
+}
+
+// checkCollateralRatios checks the collateral ratios of at most LiquidationChecksPerBlock margin accounts from where
+// the checks stopped in the last block, and queues the ones below the liquidation ratio for the liquidation
+func (k Keeper) checkCollateralRatios(ctx sdk.Context, params types.Params) {
+	store := ctx.KVStore(k.storeKey)
+	start, end := types.AccountKey, sdk.PrefixEndBytes(types.AccountKey)
+	cursor := store.Get(types.LiquidationCursorKey)
+	if cursor != nil {
+		start = append(cursor, 0x00)
+	}
+
+	var accounts []*types.Account
+	var lastKey []byte
+	collect := func(start, end []byte) {
+		iter := store.Iterator(start, end)
+		defer iter.Close()
+		for ; iter.Valid() && int64(len(accounts)) < params.LiquidationChecksPerBlock; iter.Next() {
+			var account types.Account
+			k.cdc.MustUnmarshalBinaryBare(iter.Value(), &account)
+			k.settleInterest(ctx, &account)
+			accounts = append(accounts, &account)
+			lastKey = iter.Key()
+		}
+	}
+	collect(start, end)
+	// the checks wrap around to the first accounts
+	if cursor != nil {
+		collect(types.AccountKey, start)
+	}
+	if lastKey == nil {
+		store.Delete(types.LiquidationCursorKey)
+	} else {
+		store.Set(types.LiquidationCursorKey, lastKey)
+	}
+
+	for _, account := range accounts {
+		if account.Liquidating || !account.HasDebts() {
+			continue
+		}
+		ratio := k.GetCollateralRatio(ctx, account)
+		if ratio.GTE(params.LiquidationRatio) {
+			continue
+		}
+		account.Liquidating = true
+		k.SetAccount(ctx, account)
+		k.InsertLiquidationQueue(ctx, ctx.BlockHeight(), account)
+		ctx.EventManager().EmitEvent(sdk.NewEvent(
+			types.EventTypeLiquidate,
+			sdk.NewAttribute(types.AttributeKeyOwner, account.Owner.String()),
+			sdk.NewAttribute(types.AttributeKeyProduct, account.Product),
+			sdk.NewAttribute(types.AttributeKeyCollateralRatio, ratio.String()),
+		))
+	}
+}
+
+// liquidate pays off the debts of a margin account with its balances in the same denoms, and places an IOC order to
+// trade its other balances for the rest of the debts, which are paid off in the following blocks. The debts left
+// without any balances to pay them off are written off.
+func (k Keeper) liquidate(ctx sdk.Context, account *types.Account, params types.Params) {
+	logger := ctx.Logger().With("module", types.ModuleName)
+	k.cancelOpenOrders(ctx, account)
+
+	available := k.tokenKeeper.GetCoins(ctx, account.Address)
+	for _, debt := range account.Debts() {
+		amount := sdk.MinDec(debt.Amount, available.AmountOf(debt.Denom))
+		if !amount.IsPositive() {
+			continue
+		}
+		if _, err := k.repay(ctx, account, sdk.NewDecCoinFromDec(debt.Denom, amount)); err != nil {
+			logger.Error(fmt.Sprintf("failed to repay %s%s of the margin account %s: %s", amount, debt.Denom,
+				account.Address, err))
+		}
+	}
+	if !account.HasDebts() {
+		return
+	}
+
+	liquidationOrder := k.newLiquidationOrder(ctx, account, params)
+	if liquidationOrder == nil {
+		debts := k.writeOffDebts(ctx, account)
+		ctx.EventManager().EmitEvent(sdk.NewEvent(
+			types.EventTypeBadDebt,
+			sdk.NewAttribute(types.AttributeKeyOwner, account.Owner.String()),
+			sdk.NewAttribute(types.AttributeKeyProduct, account.Product),
+			sdk.NewAttribute(types.AttributeKeyAmount, debts.String()),
+		))
+		return
+	}
+	// the order is retried in the next block if the product is not open for trading, or the price is out of the
+	// price band
+	if err := k.orderKeeper.ValidateNewOrder(ctx, account.Product, liquidationOrder.Price,
+		liquidationOrder.Quantity); err != nil {
+		logger.Error(fmt.Sprintf("failed to place the liquidation order of the margin account %s: %s",
+			account.Address, err))
+		return
+	}
+	// the order is placed in a cache context, so that no coins are left locked if it fails, and it's retried in
+	// the next block
+	cacheCtx, write := ctx.CacheContext()
+	if err := k.orderKeeper.PlaceOrder(cacheCtx, liquidationOrder); err != nil {
+		logger.Error(fmt.Sprintf("failed to place the liquidation order of the margin account %s: %s",
+			account.Address, err))
+		return
+	}
+	write()
+	ctx.EventManager().EmitEvents(cacheCtx.EventManager().Events())
+}
+
+// newLiquidationOrder creates an IOC order at the last price with the liquidation slippage, which buys the base token
+// owed with the quote token, or sells the base token for the quote token owed. The fee locked by the order is
+// refunded as the order quits in the block it's placed in, so is the order deposit. It returns nil if the balances of
+// the margin account can't afford the fee and the deposit, or are not enough for the min quantity of the product.
+func (k Keeper) newLiquidationOrder(ctx sdk.Context, account *types.Account, params types.Params) *order.Order {
+	tokenPair := k.dexKeeper.GetTokenPair(ctx, account.Product)
+	if tokenPair == nil {
+		return nil
+	}
+	base, quote := account.GetBaseQuote()
+	lastPrice := k.orderKeeper.GetLastPrice(ctx, account.Product)
+	orderParams := k.orderKeeper.GetParams(ctx)
+	var costs sdk.DecCoins
+	if fee := orderParams.FeePerBlock.Amount.MulInt64(orderParams.OrderExpireBlocks); fee.IsPositive() {
+		costs = costs.Add(sdk.DecCoins{sdk.NewDecCoinFromDec(orderParams.FeePerBlock.Denom, fee)})
+	}
+	if deposit := orderParams.OrderDeposit; deposit.Amount.Int != nil && deposit.IsPositive() {
+		costs = costs.Add(sdk.DecCoins{deposit})
+	}
+	available, hasNeg := k.tokenKeeper.GetCoins(ctx, account.Address).SafeSub(costs)
+	if hasNeg {
+		return nil
+	}
+	debts := account.Debts()
+	// the deal fee is charged on the tokens received, so more are traded for the debts
+	receivedRatio := sdk.OneDec().Sub(sdk.MaxDec(orderParams.MakerFeeRate, orderParams.TakerFeeRate))
+	if !receivedRatio.IsPositive() {
+		return nil
+	}
+
+	var side string
+	var price, quantity sdk.Dec
+	if baseDebt := debts.AmountOf(base); baseDebt.IsPositive() {
+		side = order.BuyOrder
+		price = roundDecimal(lastPrice.Mul(sdk.OneDec().Add(params.LiquidationSlippage)), tokenPair.MaxPriceDigit,
+			true)
+		if !price.IsPositive() {
+			return nil
+		}
+		unit := getQuantityUnit(price, tokenPair.MaxQuantityDigit)
+		quantity = roundToUnit(sdk.MaxDec(baseDebt.Quo(receivedRatio), tokenPair.MinQuantity), unit, true)
+		affordable := roundToUnit(available.AmountOf(quote).QuoTruncate(price), unit, false)
+		quantity = sdk.MinDec(quantity, affordable)
+	} else {
+		side = order.SellOrder
+		price = roundDecimal(lastPrice.Mul(sdk.OneDec().Sub(params.LiquidationSlippage)), tokenPair.MaxPriceDigit,
+			false)
+		if !price.IsPositive() {
+			return nil
+		}
+		unit := getQuantityUnit(price, tokenPair.MaxQuantityDigit)
+		quantity = roundToUnit(sdk.MaxDec(debts.AmountOf(quote).Quo(price).Quo(receivedRatio), tokenPair.MinQuantity),
+			unit, true)
+		quantity = sdk.MinDec(quantity, roundToUnit(available.AmountOf(base), unit, false))
+	}
+	if !quantity.IsPositive() || quantity.LT(tokenPair.MinQuantity) {
+		return nil
+	}
+
+	liquidationOrder := k.newOrder(ctx, account, side, price, quantity, orderParams.FeePerBlock)
+	liquidationOrder.TimeInForce = order.TimeInForceIOC
+	return liquidationOrder
+}
+
+// roundDecimal rounds a decimal down or up to the digits
+func roundDecimal(d sdk.Dec, digits int64, up bool) sdk.Dec {
+	return roundToUnit(d, sdk.NewDecWithPrec(1, digits), up)
+}
+
+// getQuantityUnit returns the min step of the quantity of an order at the price, the quantity is within the digits
+// and its amount at the price is within the precision of decimals
+func getQuantityUnit(price sdk.Dec, digits int64) sdk.Dec {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(sdk.Precision), nil)
+	// price*quantity keeps the precision if quantity is a multiple of scale/gcd(price, scale) in the scaled integers
+	priceStep := new(big.Int).Quo(scale, new(big.Int).GCD(nil, nil, price.Int, scale))
+	digitsStep := new(big.Int).Exp(big.NewInt(10), big.NewInt(sdk.Precision-digits), nil)
+	lcm := new(big.Int).Mul(priceStep, digitsStep)
+	lcm.Quo(lcm, new(big.Int).GCD(nil, nil, priceStep, digitsStep))
+	return sdk.NewDecFromBigIntWithPrec(lcm, sdk.Precision)
+}
+
+// roundToUnit rounds a decimal down or up to a multiple of the unit
+func roundToUnit(d, unit sdk.Dec, up bool) sdk.Dec {
+	rounded := d.QuoTruncate(unit).TruncateDec().Mul(unit)
+	if up && rounded.LT(d) {
+		rounded = rounded.Add(unit)
+	}
+	return rounded
+}
@@ -0,0 +1,203 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/common"
+	"github.com/okex/okchain/x/margin/types"
+	"github.com/okex/okchain/x/order"
+	orderkeeper "github.com/okex/okchain/x/order/keeper"
+	ordertypes "github.com/okex/okchain/x/order/types"
+)
+
+// openBorrowedAccount opens the margin account of owner, which spends all the xxb it borrowed and keeps a
+// collateral ratio of 2 at the init price
+func openBorrowedAccount(t *testing.T, testInput TestInput, lender, owner sdk.AccAddress) {
+	ctx, keeper := testInput.Ctx, testInput.MarginKeeper
+	err := keeper.Lend(ctx, lender, sdk.NewDecCoinFromDec(common.TestToken, sdk.NewDec(50)))
+	require.Nil(t, err)
+	_, err = keeper.OpenAccount(ctx, owner, product)
+	require.Nil(t, err)
+	err = keeper.Deposit(ctx, owner, product, sdk.DecCoins{sdk.NewDecCoinFromDec(common.NativeToken,
+		sdk.NewDec(100))})
+	require.Nil(t, err)
+	err = keeper.Borrow(ctx, owner, product, sdk.NewDecCoinFromDec(common.TestToken, sdk.NewDec(5)))
+	require.Nil(t, err)
+	err = keeper.Withdraw(ctx, owner, product, sdk.DecCoins{sdk.NewDecCoinFromDec(common.TestToken,
+		sdk.NewDec(5))})
+	require.Nil(t, err)
+	require.EqualValues(t, sdk.NewDec(2), keeper.GetCollateralRatio(ctx, keeper.GetAccount(ctx, owner, product)))
+}
+
+func TestKeeper_Liquidate(t *testing.T) {
+	testInput := CreateTestInputWithBalance(t, 3, 100)
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	keeper, orderKeeper := testInput.MarginKeeper, testInput.OrderKeeper
+	lender, owner, trader := testInput.TestAddrs[0], testInput.TestAddrs[1], testInput.TestAddrs[2]
+	testInput.Ctx = ctx
+	orderKeeper.ResetCache(ctx)
+	openBorrowedAccount(t, testInput, lender, owner)
+
+	// the collateral ratio is above the liquidation ratio
+	require.EqualValues(t, 0, keeper.Liquidate(ctx))
+
+	params := keeper.GetParams(ctx)
+	params.LiquidationRatio = sdk.NewDec(3)
+	keeper.SetParams(ctx, params)
+
+	// no liquidation order is placed while the product is halted
+	orderKeeper.SetProductLock(ctx, product, &ordertypes.ProductLock{BlockHeight: 9, HaltEndHeight: 12})
+	require.EqualValues(t, 1, keeper.Liquidate(ctx))
+	require.True(t, keeper.GetAccount(ctx, owner, product).Liquidating)
+	require.EqualValues(t, 0, len(orderKeeper.GetSenderOrderIDs(ctx, keeper.GetAccount(ctx, owner,
+		product).Address, product)))
+	orderKeeper.UnlockProduct(ctx, product)
+	require.EqualValues(t, 1, keeper.Liquidate(ctx))
+
+	// an IOC order buys the xxb owed and the deal fee at the last price with the slippage
+	account := keeper.GetAccount(ctx, owner, product)
+	require.True(t, account.Liquidating)
+	orderIDs := orderKeeper.GetSenderOrderIDs(ctx, account.Address, product)
+	require.EqualValues(t, 1, len(orderIDs))
+	liquidationOrder := orderKeeper.GetOrder(ctx, orderIDs[0])
+	require.EqualValues(t, ordertypes.BuyOrder, liquidationOrder.Side)
+	require.EqualValues(t, ordertypes.TimeInForceIOC, liquidationOrder.TimeInForce)
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.5"), liquidationOrder.Price)
+	// the quantity is rounded up to keep the amount 10.5*quantity within the precision
+	require.EqualValues(t, sdk.MustNewDecFromStr("5.00500502"), liquidationOrder.Quantity)
+
+	// the margin account being liquidated can't borrow or place orders
+	err := keeper.Borrow(ctx, owner, product, sdk.NewDecCoinFromDec(common.TestToken, sdk.NewDec(1)))
+	require.NotNil(t, err)
+	_, err = keeper.PlaceOrder(ctx, owner, product, ordertypes.BuyOrder, sdk.NewDec(10), sdk.NewDec(1))
+	require.NotNil(t, err)
+
+	sellOrder := ordertypes.MockOrder("", product, ordertypes.SellOrder, "10", "6")
+	sellOrder.Sender = trader
+	require.Nil(t, orderKeeper.PlaceOrder(ctx, sellOrder))
+	order.EndBlocker(ctx, orderKeeper)
+	require.EqualValues(t, ordertypes.OrderStatusFilled, orderKeeper.GetOrder(ctx, orderIDs[0]).Status)
+
+	// the xxb bought pays off the debts in the next block
+	ctx = ctx.WithBlockHeight(11)
+	orderKeeper.ResetCache(ctx)
+	require.EqualValues(t, 1, keeper.Liquidate(ctx))
+	account = keeper.GetAccount(ctx, owner, product)
+	require.False(t, account.HasDebts())
+	require.False(t, account.Liquidating)
+	require.True(t, keeper.GetPool(ctx, common.TestToken).Borrowed.IsZero())
+	require.EqualValues(t, 0, keeper.Liquidate(ctx))
+}
+
+func TestKeeper_LiquidateBadDebt(t *testing.T) {
+	testInput := CreateTestInput(t)
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	keeper, orderKeeper := testInput.MarginKeeper, testInput.OrderKeeper
+	lender, owner := testInput.TestAddrs[0], testInput.TestAddrs[1]
+	testInput.Ctx = ctx
+	orderKeeper.ResetCache(ctx)
+	openBorrowedAccount(t, testInput, lender, owner)
+
+	// the okt of the margin account can't afford the min quantity
+	tokenPair := testInput.DexKeeper.GetTokenPair(ctx, product)
+	tokenPair.MinQuantity = sdk.NewDec(10)
+	testInput.DexKeeper.UpdateTokenPair(ctx, product, tokenPair)
+
+	params := keeper.GetParams(ctx)
+	params.LiquidationRatio = sdk.NewDec(3)
+	keeper.SetParams(ctx, params)
+	require.EqualValues(t, 1, keeper.Liquidate(ctx))
+
+	// the debts are written off and the loss is taken by the lenders
+	account := keeper.GetAccount(ctx, owner, product)
+	require.False(t, account.HasDebts())
+	require.False(t, account.Liquidating)
+	require.EqualValues(t, 0, len(orderKeeper.GetSenderOrderIDs(ctx, account.Address, product)))
+	pool := keeper.GetPool(ctx, common.TestToken)
+	require.True(t, pool.Borrowed.IsZero())
+	require.EqualValues(t, sdk.NewDec(45), pool.TotalSupply)
+	require.EqualValues(t, sdk.NewDec(45), pool.ValueOf(keeper.GetLenderShares(ctx, lender, common.TestToken)))
+}
+
+func TestKeeper_LiquidateUnaffordableOrder(t *testing.T) {
+	testInput := CreateTestInput(t)
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	keeper, orderKeeper := testInput.MarginKeeper, testInput.OrderKeeper
+	lender, owner := testInput.TestAddrs[0], testInput.TestAddrs[1]
+	testInput.Ctx = ctx
+	orderKeeper.ResetCache(ctx)
+	openBorrowedAccount(t, testInput, lender, owner)
+
+	// the okt of the margin account can't afford the fee and the deposit of the liquidation order
+	orderParams := *orderKeeper.GetParams(ctx)
+	orderParams.OrderDeposit = sdk.NewDecCoinFromDec(common.NativeToken, sdk.NewDec(100))
+	orderKeeper.SetParams(ctx, &orderParams)
+
+	params := keeper.GetParams(ctx)
+	params.LiquidationRatio = sdk.NewDec(3)
+	keeper.SetParams(ctx, params)
+	require.EqualValues(t, 1, keeper.Liquidate(ctx))
+
+	// the debts are written off, and no coins are left locked without an order
+	account := keeper.GetAccount(ctx, owner, product)
+	require.False(t, account.HasDebts())
+	require.EqualValues(t, 0, len(orderKeeper.GetSenderOrderIDs(ctx, account.Address, product)))
+	require.True(t, testInput.TokenKeeper.GetLockCoins(ctx, account.Address).IsZero())
+	_, broken := orderkeeper.LockedCoinsInvariant(orderKeeper)(ctx)
+	require.False(t, broken)
+}
+
+func TestKeeper_LiquidationQueue(t *testing.T) {
+	testInput := CreateTestInputWithBalance(t, 3, 100)
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	keeper, orderKeeper := testInput.MarginKeeper, testInput.OrderKeeper
+	lender, owners := testInput.TestAddrs[0], testInput.TestAddrs[1:]
+	testInput.Ctx = ctx
+	orderKeeper.ResetCache(ctx)
+	for _, owner := range owners {
+		openBorrowedAccount(t, testInput, lender, owner)
+	}
+
+	// the liquidation orders can't be placed while the product is halted, so the accounts stay in the queue
+	orderKeeper.SetProductLock(ctx, product, &ordertypes.ProductLock{BlockHeight: 9, HaltEndHeight: 12})
+	params := keeper.GetParams(ctx)
+	params.LiquidationRatio = sdk.NewDec(3)
+	params.LiquidationChecksPerBlock = 1
+	params.MaxLiquidationsPerBlock = 1
+	keeper.SetParams(ctx, params)
+
+	// one account is checked in a block, the checks go on from where they stopped
+	require.EqualValues(t, 1, keeper.Liquidate(ctx))
+	liquidatingNum := func() int {
+		num := 0
+		keeper.IterateAccounts(ctx, func(account *types.Account) bool {
+			if account.Liquidating {
+				num++
+			}
+			return false
+		})
+		return num
+	}
+	require.EqualValues(t, 1, liquidatingNum())
+	require.EqualValues(t, 1, keeper.Liquidate(ctx))
+	require.EqualValues(t, 2, liquidatingNum())
+	require.EqualValues(t, 2, len(keeper.getLiquidationQueueKeys(ctx, 10)))
+
+	// at most MaxLiquidationsPerBlock accounts are liquidated in a block
+	params.MaxLiquidationsPerBlock = 2
+	keeper.SetParams(ctx, params)
+	require.EqualValues(t, 2, keeper.Liquidate(ctx))
+
+	// the account whose debts are paid off by the owner is dropped from the queue
+	err := keeper.Deposit(ctx, owners[0], product, sdk.DecCoins{sdk.NewDecCoinFromDec(common.TestToken,
+		sdk.NewDec(5))})
+	require.Nil(t, err)
+	_, err = keeper.Repay(ctx, owners[0], product, sdk.NewDecCoinFromDec(common.TestToken, sdk.NewDec(5)))
+	require.Nil(t, err)
+	require.EqualValues(t, 1, keeper.Liquidate(ctx))
+	require.EqualValues(t, 1, liquidatingNum())
+	require.EqualValues(t, 1, len(keeper.getLiquidationQueueKeys(ctx, 10)))
+}
@@ -0,0 +1,89 @@
+package keeper
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+
+	"github.com/okex/okchain/x/margin/types"
+	order "github.com/okex/okchain/x/order/types"
+)
+
+// PlaceOrder places a limit order into the spot order book with the margin account as its sender, so that the coins
+// of the margin account are locked by the order
+func (k Keeper) PlaceOrder(ctx sdk.Context, owner sdk.AccAddress, product, side string,
+	price, quantity sdk.Dec) (*order.Order, sdk.Error) {
+	account, err := k.getActiveAccount(ctx, owner, product)
+	if err != nil {
+		return nil, err
+	}
+	if err := k.checkOrder(ctx, account, price, quantity); err != nil {
+		return nil, err
+	}
+	if err := k.orderKeeper.CheckOrderLimits(ctx, account.Address, product, 0); err != nil {
+		return nil, err
+	}
+
+	newOrder := k.newOrder(ctx, account, side, price, quantity, k.orderKeeper.GetParams(ctx).FeePerBlock)
+	if err := k.orderKeeper.PlaceOrder(ctx, newOrder); err != nil {
+		return nil, sdk.ErrInsufficientCoins(err.Error())
+	}
+	return newOrder, nil
+}
+
+// checkOrder checks a new order of a margin account with the validation of the spot orders, so that the product is
+// open for trading, and the price and the quantity fit the product and its price band
+func (k Keeper) checkOrder(ctx sdk.Context, account *types.Account, price, quantity sdk.Dec) sdk.Error {
+	if err := k.orderKeeper.ValidateNewOrder(ctx, account.Product, price, quantity); err != nil {
+		return types.ErrInvalidOrder(err.Error())
+	}
+	return nil
+}
+
+// newOrder creates a new order of a margin account
+func (k Keeper) newOrder(ctx sdk.Context, account *types.Account, side string, price, quantity sdk.Dec,
+	feePerBlock sdk.DecCoin) *order.Order {
+	orderParams := k.orderKeeper.GetParams(ctx)
+	newOrder := order.NewOrder(
+		fmt.Sprintf("%X", tmhash.Sum(ctx.TxBytes())),
+		account.Address,
+		account.Product,
+		side,
+		price,
+		quantity,
+		ctx.BlockHeader().Time.Unix(),
+		orderParams.OrderExpireBlocks,
+		feePerBlock,
+	)
+	newOrder.SelfTradePrevention = orderParams.SelfTradePrevention
+	return newOrder
+}
+
+// CancelOrder cancels an open order placed with a margin account of owner
+func (k Keeper) CancelOrder(ctx sdk.Context, owner sdk.AccAddress, orderID string) sdk.Error {
+	o := k.orderKeeper.GetOrder(ctx, orderID)
+	if o == nil {
+		return types.ErrInvalidOrder(fmt.Sprintf("order(%s) does not exist", orderID))
+	}
+	if account := k.GetAccount(ctx, owner, o.Product); account == nil || !o.Sender.Equals(account.Address) {
+		return sdk.ErrUnauthorized(fmt.Sprintf("order(%s) is not placed with the margin accounts of %s",
+			orderID, owner))
+	}
+	if o.Status != order.OrderStatusOpen && o.Status != order.OrderStatusUntriggered {
+		return types.ErrInvalidOrder(fmt.Sprintf("order(%s) is not open", orderID))
+	}
+	k.orderKeeper.BurnEarlyCancelledDeposit(ctx, o, ctx.Logger())
+	k.orderKeeper.CancelOrder(ctx, o, ctx.Logger())
+	return nil
+}
+
+// cancelOpenOrders cancels all the open orders of a margin account. The deposits of the orders are always refunded,
+// since the cancellation is forced by the liquidation rather than requested by the owner.
+func (k Keeper) cancelOpenOrders(ctx sdk.Context, account *types.Account) {
+	for _, orderID := range k.orderKeeper.GetSenderOrderIDs(ctx, account.Address, account.Product) {
+		if o := k.orderKeeper.GetOrder(ctx, orderID); o != nil {
+			k.orderKeeper.CancelOrder(ctx, o, ctx.Logger())
+		}
+	}
+}
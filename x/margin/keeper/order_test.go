@@ -0,0 +1,81 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/common"
+	dex "github.com/okex/okchain/x/dex/types"
+	ordertypes "github.com/okex/okchain/x/order/types"
+)
+
+func TestKeeper_PlaceCancelOrder(t *testing.T) {
+	testInput := CreateTestInput(t)
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	keeper, orderKeeper := testInput.MarginKeeper, testInput.OrderKeeper
+	owner, other := testInput.TestAddrs[0], testInput.TestAddrs[1]
+	orderKeeper.ResetCache(ctx)
+
+	account, err := keeper.OpenAccount(ctx, owner, product)
+	require.Nil(t, err)
+	err = keeper.Deposit(ctx, owner, product, sdk.DecCoins{sdk.NewDecCoinFromDec(common.NativeToken,
+		sdk.NewDec(20))})
+	require.Nil(t, err)
+
+	// invalid orders
+	_, err = keeper.PlaceOrder(ctx, other, product, ordertypes.BuyOrder, sdk.NewDec(10), sdk.NewDec(1))
+	require.NotNil(t, err)
+	_, err = keeper.PlaceOrder(ctx, owner, "unknown_"+common.NativeToken, ordertypes.BuyOrder, sdk.NewDec(10),
+		sdk.NewDec(1))
+	require.NotNil(t, err)
+	_, err = keeper.PlaceOrder(ctx, owner, product, ordertypes.BuyOrder, sdk.NewDec(10), sdk.NewDec(3))
+	require.NotNil(t, err)
+	// the price is out of the price band around the last price 10
+	require.Nil(t, testInput.DexKeeper.SetPriceBand(ctx, product, &dex.PriceBand{
+		MaxDeviation: sdk.MustNewDecFromStr("0.1"), Action: dex.PriceBandActionReject}))
+	_, err = keeper.PlaceOrder(ctx, owner, product, ordertypes.BuyOrder, sdk.NewDec(12), sdk.NewDec(1))
+	require.NotNil(t, err)
+	// the product is halted
+	orderKeeper.SetProductLock(ctx, product, &ordertypes.ProductLock{BlockHeight: 9, HaltEndHeight: 12})
+	_, err = keeper.PlaceOrder(ctx, owner, product, ordertypes.BuyOrder, sdk.NewDec(10), sdk.NewDec(1))
+	require.NotNil(t, err)
+	orderKeeper.UnlockProduct(ctx, product)
+
+	// the coins of the margin account are locked by the order
+	newOrder, err := keeper.PlaceOrder(ctx, owner, product, ordertypes.BuyOrder, sdk.NewDec(10), sdk.NewDec(1))
+	require.Nil(t, err)
+	require.EqualValues(t, account.Address, newOrder.Sender)
+	require.EqualValues(t, sdk.NewDec(10), testInput.TokenKeeper.GetLockCoins(ctx, account.Address).AmountOf(
+		common.NativeToken))
+	// the order fee 0.000001*259200 is charged
+	require.EqualValues(t, sdk.MustNewDecFromStr("19.7408"), keeper.GetAccountBalances(ctx, account).AmountOf(
+		common.NativeToken))
+
+	// only the owner can cancel the order
+	err = keeper.CancelOrder(ctx, other, newOrder.OrderID)
+	require.NotNil(t, err)
+	err = keeper.CancelOrder(ctx, owner, newOrder.OrderID)
+	require.Nil(t, err)
+	require.EqualValues(t, ordertypes.OrderStatusCancelled, orderKeeper.GetOrder(ctx, newOrder.OrderID).Status)
+	require.True(t, testInput.TokenKeeper.GetLockCoins(ctx, account.Address).IsZero())
+	err = keeper.CancelOrder(ctx, owner, newOrder.OrderID)
+	require.NotNil(t, err)
+
+	// the deposit of an order cancelled early by the owner is burned
+	orderParams := *orderKeeper.GetParams(ctx)
+	orderParams.OrderDeposit = sdk.NewDecCoinFromDec(common.NativeToken, sdk.OneDec())
+	orderParams.DepositLockBlocks = 5
+	orderKeeper.SetParams(ctx, &orderParams)
+	balance := keeper.GetAccountBalances(ctx, account).AmountOf(common.NativeToken)
+	newOrder, err = keeper.PlaceOrder(ctx, owner, product, ordertypes.BuyOrder, sdk.NewDec(10), sdk.NewDec(1))
+	require.Nil(t, err)
+	require.NotNil(t, newOrder.Deposit)
+	err = keeper.CancelOrder(ctx, owner, newOrder.OrderID)
+	require.Nil(t, err)
+	require.Nil(t, orderKeeper.GetOrder(ctx, newOrder.OrderID).Deposit)
+	require.EqualValues(t, balance.Sub(sdk.OneDec()), keeper.GetAccountBalances(ctx, account).AmountOf(
+		common.NativeToken))
+	require.True(t, testInput.TokenKeeper.GetLockCoins(ctx, account.Address).IsZero())
+}
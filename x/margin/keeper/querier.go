@@ -0,0 +1,109 @@
+package keeper
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/okex/okchain/x/margin/types"
+)
+
+// NewQuerier is the module level router for state queries
+func NewQuerier(keeper Keeper) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) (res []byte, err sdk.Error) {
+		switch path[0] {
+		case types.QueryParameters:
+			return queryParams(ctx, keeper)
+		case types.QueryAccount:
+			return queryAccount(ctx, req, keeper)
+		case types.QueryAccounts:
+			return queryAccounts(ctx, req, keeper)
+		case types.QueryPools:
+			return queryPools(ctx, keeper)
+		case types.QueryLender:
+			return queryLender(ctx, req, keeper)
+		default:
+			return nil, sdk.ErrUnknownRequest("unknown margin query endpoint")
+		}
+	}
+}
+
+func queryParams(ctx sdk.Context, keeper Keeper) ([]byte, sdk.Error) {
+	bz, err := codec.MarshalJSONIndent(keeper.GetCDC(), keeper.GetParams(ctx))
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+	return bz, nil
+}
+
+func queryAccount(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	var params types.QueryAccountParams
+	if err := keeper.GetCDC().UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, sdk.ErrUnknownRequest(sdk.AppendMsgToErr("incorrectly formatted request data", err.Error()))
+	}
+	account := keeper.GetAccount(ctx, params.Owner, params.Product)
+	if account == nil {
+		return nil, types.ErrAccountNotFound(params.Owner, params.Product)
+	}
+	bz, err := codec.MarshalJSONIndent(keeper.GetCDC(), keeper.GetAccountInfo(ctx, account))
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+	return bz, nil
+}
+
+func queryAccounts(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	var params types.QueryAddressParams
+	if err := keeper.GetCDC().UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, sdk.ErrUnknownRequest(sdk.AppendMsgToErr("incorrectly formatted request data", err.Error()))
+	}
+	if params.Address.Empty() {
+		return nil, sdk.ErrInvalidAddress(fmt.Sprintf("invalid address：%s", params.Address))
+	}
+	accounts := keeper.GetOwnerAccounts(ctx, params.Address)
+	infos := make([]types.AccountInfo, 0, len(accounts))
+	for _, account := range accounts {
+		infos = append(infos, keeper.GetAccountInfo(ctx, account))
+	}
+	bz, err := codec.MarshalJSONIndent(keeper.GetCDC(), infos)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+	return bz, nil
+}
+
+func queryPools(ctx sdk.Context, keeper Keeper) ([]byte, sdk.Error) {
+	pools := keeper.GetPools(ctx)
+	if pools == nil {
+		pools = []*types.Pool{}
+	}
+	bz, err := codec.MarshalJSONIndent(keeper.GetCDC(), pools)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+	return bz, nil
+}
+
+func queryLender(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	var params types.QueryAddressParams
+	if err := keeper.GetCDC().UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, sdk.ErrUnknownRequest(sdk.AppendMsgToErr("incorrectly formatted request data", err.Error()))
+	}
+	if params.Address.Empty() {
+		return nil, sdk.ErrInvalidAddress(fmt.Sprintf("invalid address：%s", params.Address))
+	}
+	lenderShares := keeper.GetLenderShareList(ctx, params.Address)
+	for i := range lenderShares {
+		lenderShares[i].Value = keeper.GetPool(ctx, lenderShares[i].Denom).ValueOf(lenderShares[i].Shares)
+	}
+	if lenderShares == nil {
+		lenderShares = []types.LenderShare{}
+	}
+	bz, err := codec.MarshalJSONIndent(keeper.GetCDC(), lenderShares)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+	return bz, nil
+}
@@ -0,0 +1,179 @@
+package keeper
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/supply"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/libs/log"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/okex/okchain/x/common"
+	"github.com/okex/okchain/x/common/monitor"
+	"github.com/okex/okchain/x/dex"
+	"github.com/okex/okchain/x/margin/types"
+	orderkeeper "github.com/okex/okchain/x/order/keeper"
+	ordertypes "github.com/okex/okchain/x/order/types"
+	"github.com/okex/okchain/x/params"
+	"github.com/okex/okchain/x/token"
+	tokentypes "github.com/okex/okchain/x/token/types"
+)
+
+type TestInput struct {
+	Ctx       sdk.Context
+	Cdc       *codec.Codec
+	TestAddrs []sdk.AccAddress
+
+	MarginKeeper  Keeper
+	OrderKeeper   orderkeeper.Keeper
+	TokenKeeper   token.Keeper
+	AccountKeeper auth.AccountKeeper
+	SupplyKeeper  supply.Keeper
+	DexKeeper     dex.Keeper
+}
+
+// create a codec used only for testing
+func MakeTestCodec() *codec.Codec {
+	var cdc = codec.New()
+	bank.RegisterCodec(cdc)
+	auth.RegisterCodec(cdc)
+	supply.RegisterCodec(cdc)
+	sdk.RegisterCodec(cdc)
+	dex.RegisterCodec(cdc)
+	codec.RegisterCrypto(cdc)
+
+	ordertypes.RegisterCodec(cdc) // order
+	token.RegisterCodec(cdc)      // token
+	types.RegisterCodec(cdc)      // margin
+	return cdc
+}
+
+// CreateTestInputWithBalance creates the keepers with the built-in token pair, and numAddrs accounts holding
+// initQuantity of both its base and quote tokens
+func CreateTestInputWithBalance(t *testing.T, numAddrs, initQuantity int64) TestInput {
+
+	db := dbm.NewMemDB()
+
+	keyAcc := sdk.NewKVStoreKey(auth.StoreKey)
+	keySupply := sdk.NewKVStoreKey(supply.StoreKey)
+	keyParams := sdk.NewKVStoreKey(params.StoreKey)
+	tkeyParams := sdk.NewTransientStoreKey(params.TStoreKey)
+
+	// order module
+	keyOrder := sdk.NewKVStoreKey(ordertypes.OrderStoreKey)
+
+	// token module
+	keyToken := sdk.NewKVStoreKey(token.StoreKey)
+	keyLock := sdk.NewKVStoreKey(token.KeyLock)
+
+	// dex module
+	storeKey := sdk.NewKVStoreKey(dex.StoreKey)
+	keyTokenPair := sdk.NewKVStoreKey(dex.TokenPairStoreKey)
+
+	// margin module
+	keyMargin := sdk.NewKVStoreKey(types.StoreKey)
+
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyAcc, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keySupply, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyParams, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(tkeyParams, sdk.StoreTypeTransient, db)
+
+	ms.MountStoreWithDB(keyOrder, sdk.StoreTypeIAVL, db)
+
+	ms.MountStoreWithDB(keyToken, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyLock, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(storeKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyTokenPair, sdk.StoreTypeIAVL, db)
+
+	ms.MountStoreWithDB(keyMargin, sdk.StoreTypeIAVL, db)
+
+	err := ms.LoadLatestVersion()
+	require.Nil(t, err)
+
+	ctx := sdk.NewContext(ms, abci.Header{Time: time.Unix(0, 0)}, false, log.NewTMLogger(os.Stdout))
+	cdc := MakeTestCodec()
+
+	feeCollectorAcc := supply.NewEmptyModuleAccount(auth.FeeCollectorName)
+
+	blacklistedAddrs := make(map[string]bool)
+	blacklistedAddrs[feeCollectorAcc.String()] = true
+
+	paramsKeeper := params.NewKeeper(cdc, keyParams, tkeyParams, params.DefaultCodespace)
+	accountKeeper := auth.NewAccountKeeper(cdc, keyAcc,
+		paramsKeeper.Subspace(auth.DefaultParamspace), auth.ProtoBaseAccount)
+	bankKeeper := bank.NewBaseKeeper(accountKeeper, paramsKeeper.Subspace(bank.DefaultParamspace),
+		bank.DefaultCodespace, blacklistedAddrs)
+	maccPerms := map[string][]string{
+		auth.FeeCollectorName: nil,
+		token.ModuleName:      {supply.Minter, supply.Burner},
+		types.ModuleName:      nil,
+	}
+	supplyKeeper := supply.NewKeeper(cdc, keySupply, accountKeeper, bankKeeper, maccPerms)
+	supplyKeeper.SetSupply(ctx, supply.NewSupply(sdk.Coins{}))
+
+	// set module accounts
+	supplyKeeper.SetModuleAccount(ctx, feeCollectorAcc)
+
+	// token keeper
+	tokenKeeper := token.NewKeeper(bankKeeper, paramsKeeper,
+		paramsKeeper.Subspace(token.DefaultParamspace), auth.FeeCollectorName, supplyKeeper,
+		keyToken, keyLock, cdc, true)
+
+	// dex keeper
+	paramsSubspace := paramsKeeper.Subspace(dex.DefaultParamspace)
+	dexKeeper := dex.NewKeeper(auth.FeeCollectorName, supplyKeeper, paramsSubspace, tokenKeeper, nil, bankKeeper,
+		storeKey, keyTokenPair, cdc)
+
+	// order keeper
+	orderKeeper := orderkeeper.NewKeeper(tokenKeeper, supplyKeeper, paramsKeeper, dexKeeper,
+		paramsKeeper.Subspace(ordertypes.DefaultParamspace), auth.FeeCollectorName, keyOrder,
+		cdc, true, monitor.NopOrderMetrics())
+
+	defaultOrderParams := ordertypes.DefaultParams()
+	orderKeeper.SetParams(ctx, &defaultOrderParams)
+
+	// margin keeper
+	marginKeeper := NewKeeper(cdc, keyMargin, paramsKeeper.Subspace(types.DefaultParamspace), supplyKeeper,
+		tokenKeeper, dexKeeper, &orderKeeper)
+	marginKeeper.SetParams(ctx, *types.DefaultParams())
+
+	// init tokens and the token pair
+	for _, symbol := range []string{common.NativeToken, common.TestToken} {
+		tokenKeeper.NewToken(ctx, tokentypes.Token{Symbol: symbol, OriginalSymbol: symbol, WholeName: symbol})
+	}
+	require.Nil(t, dexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair()))
+
+	// init account tokens
+	initCoins, err := sdk.ParseDecCoins(fmt.Sprintf("%d%s,%d%s",
+		initQuantity, common.NativeToken, initQuantity, common.TestToken))
+	require.Nil(t, err)
+
+	var testAddrs []sdk.AccAddress
+	for i := int64(0); i < numAddrs; i++ {
+		pk := ed25519.GenPrivKey().PubKey()
+		addr := sdk.AccAddress(pk.Address())
+		testAddrs = append(testAddrs, addr)
+		err := supplyKeeper.MintCoins(ctx, token.ModuleName, initCoins)
+		require.Nil(t, err)
+		err = supplyKeeper.SendCoinsFromModuleToAccount(ctx, token.ModuleName, addr, initCoins)
+		require.Nil(t, err)
+	}
+
+	return TestInput{ctx, cdc, testAddrs, marginKeeper, orderKeeper, tokenKeeper, accountKeeper, supplyKeeper,
+		dexKeeper}
+}
+
+func CreateTestInput(t *testing.T) TestInput {
+	return CreateTestInputWithBalance(t, 2, 100)
+}
@@ -0,0 +1,111 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Account is an isolated margin account of an owner on a product. The balances are held by its own address, so that
+// the orders placed with it lock the coins of the margin account only.
+type Account struct {
+	Owner       sdk.AccAddress `json:"owner"`
+	Product     string         `json:"product"`
+	Address     sdk.AccAddress `json:"address"`
+	Borrowed    sdk.DecCoins   `json:"borrowed"`    // principal borrowed from the lending pools
+	Interest    sdk.DecCoins   `json:"interest"`    // interest accrued and not paid yet
+	Liquidating bool           `json:"liquidating"` // the debts are being paid off by the liquidation
+	// borrow indexes of the lending pools when the interest was last accrued on the principal
+	BorrowIndexes sdk.DecCoins `json:"borrow_indexes"`
+}
+
+// NewAccount creates a new margin account of owner on product
+func NewAccount(owner sdk.AccAddress, product string) *Account {
+	return &Account{
+		Owner:   owner,
+		Product: product,
+		Address: GetAccountAddress(owner, product),
+	}
+}
+
+// Debts returns the borrowed principal plus the accrued interest
+func (a *Account) Debts() sdk.DecCoins {
+	return a.Borrowed.Add(a.Interest)
+}
+
+// HasDebts returns whether the account owes anything to the lending pools
+func (a *Account) HasDebts() bool {
+	return !a.Debts().IsZero()
+}
+
+// AddInterest accrues interest on the account
+func (a *Account) AddInterest(interest sdk.DecCoins) {
+	a.Interest = a.Interest.Add(interest)
+}
+
+// AccrueInterest accrues the interest on the principal since the borrow indexes were last taken, and takes the
+// current borrow indexes of the principal's denoms
+func (a *Account) AccrueInterest(getBorrowIndex func(denom string) sdk.Dec) {
+	var indexes sdk.DecCoins
+	for _, principal := range a.Borrowed {
+		index := getBorrowIndex(principal.Denom)
+		// the principal borrowed without an index accrues no interest till the index is taken
+		if last := a.BorrowIndexes.AmountOf(principal.Denom); last.IsPositive() && index.GT(last) {
+			interest := principal.Amount.Mul(index.Sub(last))
+			if interest.IsPositive() {
+				a.AddInterest(sdk.DecCoins{sdk.NewDecCoinFromDec(principal.Denom, interest)})
+			}
+		}
+		indexes = append(indexes, sdk.NewDecCoinFromDec(principal.Denom, index))
+	}
+	a.BorrowIndexes = indexes
+}
+
+// Repay pays off the interest of the amount's denom first and then the principal. It returns the amount paid off.
+func (a *Account) Repay(amount sdk.DecCoin) sdk.DecCoin {
+	paid := sdk.NewDecCoinFromDec(amount.Denom, sdk.ZeroDec())
+	for _, debts := range []*sdk.DecCoins{&a.Interest, &a.Borrowed} {
+		owed := debts.AmountOf(amount.Denom)
+		pay := sdk.MinDec(owed, amount.Amount.Sub(paid.Amount))
+		if !pay.IsPositive() {
+			continue
+		}
+		*debts = debts.Sub(sdk.DecCoins{sdk.NewDecCoinFromDec(amount.Denom, pay)})
+		paid.Amount = paid.Amount.Add(pay)
+	}
+	return paid
+}
+
+// GetBaseQuote returns the base and the quote tokens of the product of the account
+func (a *Account) GetBaseQuote() (string, string) {
+	return SplitProduct(a.Product)
+}
+
+// String implements the stringer interface
+func (a Account) String() string {
+	return fmt.Sprintf(`Margin Account:
+  Owner:       %s
+  Product:     %s
+  Address:     %s
+  Borrowed:    %s
+  Interest:    %s
+  Liquidating: %t`, a.Owner, a.Product, a.Address, a.Borrowed, a.Interest, a.Liquidating)
+}
+
+// SplitProduct splits a product into its base and quote tokens
+func SplitProduct(product string) (string, string) {
+	symbols := strings.Split(product, "_")
+	if len(symbols) != 2 {
+		return "", ""
+	}
+	return symbols[0], symbols[1]
+}
+
+// AccountInfo is an account with its balances and collateral ratio, which is returned by the queriers
+type AccountInfo struct {
+	Account
+	Available       sdk.DecCoins `json:"available"`
+	Locked          sdk.DecCoins `json:"locked"`
+	CollateralRatio sdk.Dec      `json:"collateral_ratio"` // zero if the account has no debts
+}
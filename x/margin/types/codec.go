@@ -0,0 +1,26 @@
+package types
+
+import "github.com/cosmos/cosmos-sdk/codec"
+
+// RegisterCodec registers concrete types on the Amino codec
+func RegisterCodec(cdc *codec.Codec) {
+	cdc.RegisterConcrete(MsgOpenAccount{}, "okchain/margin/MsgOpenAccount", nil)
+	cdc.RegisterConcrete(MsgDeposit{}, "okchain/margin/MsgDeposit", nil)
+	cdc.RegisterConcrete(MsgWithdraw{}, "okchain/margin/MsgWithdraw", nil)
+	cdc.RegisterConcrete(MsgBorrow{}, "okchain/margin/MsgBorrow", nil)
+	cdc.RegisterConcrete(MsgRepay{}, "okchain/margin/MsgRepay", nil)
+	cdc.RegisterConcrete(MsgLend{}, "okchain/margin/MsgLend", nil)
+	cdc.RegisterConcrete(MsgRedeem{}, "okchain/margin/MsgRedeem", nil)
+	cdc.RegisterConcrete(MsgNewOrder{}, "okchain/margin/MsgNewOrder", nil)
+	cdc.RegisterConcrete(MsgCancelOrder{}, "okchain/margin/MsgCancelOrder", nil)
+}
+
+// ModuleCdc represents generic sealed codec to be used throughout this module
+var ModuleCdc *codec.Codec
+
+func init() {
+	ModuleCdc = codec.New()
+	RegisterCodec(ModuleCdc)
+	codec.RegisterCrypto(ModuleCdc)
+	ModuleCdc.Seal()
+}
@@ -0,0 +1,90 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// const CodeType
+const (
+	CodeInvalidProduct         sdk.CodeType = 1
+	CodeAccountExists          sdk.CodeType = 2
+	CodeAccountNotFound        sdk.CodeType = 3
+	CodeAccountLiquidating     sdk.CodeType = 4
+	CodeInvalidDenom           sdk.CodeType = 5
+	CodeInsufficientCollateral sdk.CodeType = 6
+	CodeInsufficientPool       sdk.CodeType = 7
+	CodeInsufficientShares     sdk.CodeType = 8
+	CodeInvalidOrder           sdk.CodeType = 9
+)
+
+// CodeType to Message
+func CodeToDefaultMsg(code sdk.CodeType) string {
+	switch code {
+	case CodeInvalidProduct:
+		return "invalid product"
+	case CodeAccountExists:
+		return "margin account already exists"
+	case CodeAccountNotFound:
+		return "margin account not found"
+	case CodeAccountLiquidating:
+		return "margin account is being liquidated"
+	case CodeInvalidDenom:
+		return "invalid denom"
+	case CodeInsufficientCollateral:
+		return "insufficient collateral"
+	case CodeInsufficientPool:
+		return "insufficient coins in the lending pool"
+	case CodeInsufficientShares:
+		return "insufficient shares in the lending pool"
+	case CodeInvalidOrder:
+		return "invalid order"
+	default:
+		return fmt.Sprintf("unknown code %d", code)
+	}
+}
+
+// SDK Errors Functor
+// All error raised in this module is kept here.
+func ErrInvalidProduct(msg string) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeInvalidProduct, CodeToDefaultMsg(CodeInvalidProduct)+": %s", msg)
+}
+
+func ErrAccountExists(owner sdk.AccAddress, product string) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeAccountExists, CodeToDefaultMsg(CodeAccountExists)+": %s %s",
+		owner, product)
+}
+
+func ErrAccountNotFound(owner sdk.AccAddress, product string) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeAccountNotFound, CodeToDefaultMsg(CodeAccountNotFound)+": %s %s",
+		owner, product)
+}
+
+func ErrAccountLiquidating(owner sdk.AccAddress, product string) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeAccountLiquidating,
+		CodeToDefaultMsg(CodeAccountLiquidating)+": %s %s", owner, product)
+}
+
+func ErrInvalidDenom(msg string) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeInvalidDenom, CodeToDefaultMsg(CodeInvalidDenom)+": %s", msg)
+}
+
+func ErrInsufficientCollateral(ratio, required sdk.Dec) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeInsufficientCollateral,
+		CodeToDefaultMsg(CodeInsufficientCollateral)+": collateral ratio(%s) would be less than %s", ratio, required)
+}
+
+func ErrInsufficientPool(denom string, available sdk.Dec) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeInsufficientPool, CodeToDefaultMsg(CodeInsufficientPool)+": %s%s",
+		available, denom)
+}
+
+func ErrInsufficientShares(msg string) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeInsufficientShares, CodeToDefaultMsg(CodeInsufficientShares)+": %s",
+		msg)
+}
+
+func ErrInvalidOrder(msg string) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeInvalidOrder, CodeToDefaultMsg(CodeInvalidOrder)+": %s", msg)
+}
@@ -0,0 +1,14 @@
+package types
+
+// margin module event types
+const (
+	EventTypeBorrow    = "margin_borrow"
+	EventTypeRepay     = "margin_repay"
+	EventTypeLiquidate = "margin_liquidate"
+	EventTypeBadDebt   = "margin_bad_debt"
+
+	AttributeKeyOwner           = "owner"
+	AttributeKeyProduct         = "product"
+	AttributeKeyAmount          = "amount"
+	AttributeKeyCollateralRatio = "collateral_ratio"
+)
@@ -0,0 +1,86 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/crypto"
+)
+
+const (
+	// ModuleName is the name of the margin module
+	ModuleName        = "margin"
+	DefaultParamspace = ModuleName
+	DefaultCodespace  = ModuleName
+
+	// QuerierRoute is the querier route for the margin module
+	QuerierRoute = ModuleName
+
+	// RouterKey is the msg router key for the margin module
+	RouterKey = ModuleName
+
+	// StoreKey is the string store representation
+	StoreKey = ModuleName
+
+	QueryParameters = "params"
+	QueryAccount    = "account"
+	QueryAccounts   = "accounts"
+	QueryPools      = "pools"
+	QueryLender     = "lender"
+)
+
+var (
+	AccountKey     = []byte{0x01} // prefix of the margin accounts, keyed by the owner and the product
+	PoolKey        = []byte{0x02} // prefix of the lending pools, keyed by the denom
+	LenderShareKey = []byte{0x03} // prefix of the shares of the lenders, keyed by the lender and the denom
+	// prefix of the margin accounts being liquidated, keyed by the height they're queued at, the owner and the product
+	LiquidationQueueKey = []byte{0x04}
+	// the key of the margin account the collateral checks stopped at in the last block
+	LiquidationCursorKey = []byte{0x05}
+)
+
+// GetAccountAddress returns the address holding the balances of the isolated margin account of owner on product
+func GetAccountAddress(owner sdk.AccAddress, product string) sdk.AccAddress {
+	bz := append([]byte(ModuleName), owner.Bytes()...)
+	return sdk.AccAddress(crypto.AddressHash(append(bz, []byte(product)...)))
+}
+
+// GetAccountsPrefix returns the prefix of the margin accounts of an owner
+func GetAccountsPrefix(owner sdk.AccAddress) []byte {
+	return append(AccountKey, owner.Bytes()...)
+}
+
+// GetAccountKey returns the store key of the margin account of owner on product
+func GetAccountKey(owner sdk.AccAddress, product string) []byte {
+	return append(GetAccountsPrefix(owner), []byte(product)...)
+}
+
+// GetPoolKey returns the store key of the lending pool of denom
+func GetPoolKey(denom string) []byte {
+	return append(PoolKey, []byte(denom)...)
+}
+
+// GetLenderSharesPrefix returns the prefix of the shares of a lender
+func GetLenderSharesPrefix(lender sdk.AccAddress) []byte {
+	return append(LenderShareKey, lender.Bytes()...)
+}
+
+// GetLenderShareKey returns the store key of the shares of lender in the lending pool of denom
+func GetLenderShareKey(lender sdk.AccAddress, denom string) []byte {
+	return append(GetLenderSharesPrefix(lender), []byte(denom)...)
+}
+
+// SplitLenderShareKey splits a lender share key into the lender and the denom
+func SplitLenderShareKey(key []byte) (sdk.AccAddress, string) {
+	return sdk.AccAddress(key[1 : 1+sdk.AddrLen]), string(key[1+sdk.AddrLen:])
+}
+
+// GetLiquidationQueueKey returns the key of a margin account queued for the liquidation at blockHeight
+func GetLiquidationQueueKey(blockHeight int64, owner sdk.AccAddress, product string) []byte {
+	key := append(LiquidationQueueKey, sdk.Uint64ToBigEndian(uint64(blockHeight))...)
+	return append(append(key, owner.Bytes()...), []byte(product)...)
+}
+
+// SplitLiquidationQueueKey splits a liquidation queue key into the owner and the product of the margin account
+func SplitLiquidationQueueKey(key []byte) (sdk.AccAddress, string) {
+	start := len(LiquidationQueueKey) + 8
+	return sdk.AccAddress(key[start : start+sdk.AddrLen]), string(key[start+sdk.AddrLen:])
+}
@@ -0,0 +1,349 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	ordertypes "github.com/okex/okchain/x/order/types"
+)
+
+// nolint
+const (
+	TypeMsgOpenAccount = "open_account"
+	TypeMsgDeposit     = "deposit"
+	TypeMsgWithdraw    = "withdraw"
+	TypeMsgBorrow      = "borrow"
+	TypeMsgRepay       = "repay"
+	TypeMsgLend        = "lend"
+	TypeMsgRedeem      = "redeem"
+	TypeMsgNewOrder    = "new_order"
+	TypeMsgCancelOrder = "cancel_order"
+)
+
+func validateOwnerProduct(owner sdk.AccAddress, product string) sdk.Error {
+	if owner.Empty() {
+		return sdk.ErrInvalidAddress(owner.String())
+	}
+	if base, quote := SplitProduct(product); base == "" || quote == "" {
+		return ErrInvalidProduct(product)
+	}
+	return nil
+}
+
+func validateAmount(amount sdk.DecCoin) sdk.Error {
+	if !amount.IsValid() || !amount.IsPositive() {
+		return sdk.ErrInvalidCoins(amount.String())
+	}
+	return nil
+}
+
+// MsgOpenAccount opens an isolated margin account on a product
+type MsgOpenAccount struct {
+	Owner   sdk.AccAddress `json:"owner"`
+	Product string         `json:"product"`
+}
+
+func NewMsgOpenAccount(owner sdk.AccAddress, product string) MsgOpenAccount {
+	return MsgOpenAccount{Owner: owner, Product: product}
+}
+
+// nolint
+func (msg MsgOpenAccount) Route() string { return RouterKey }
+func (msg MsgOpenAccount) Type() string  { return TypeMsgOpenAccount }
+
+// ValidateBasic Implements Msg.
+func (msg MsgOpenAccount) ValidateBasic() sdk.Error {
+	return validateOwnerProduct(msg.Owner, msg.Product)
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgOpenAccount) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgOpenAccount) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Owner}
+}
+
+// MsgDeposit deposits collateral into a margin account
+type MsgDeposit struct {
+	Owner   sdk.AccAddress `json:"owner"`
+	Product string         `json:"product"`
+	Amount  sdk.DecCoins   `json:"amount"`
+}
+
+func NewMsgDeposit(owner sdk.AccAddress, product string, amount sdk.DecCoins) MsgDeposit {
+	return MsgDeposit{Owner: owner, Product: product, Amount: amount}
+}
+
+// nolint
+func (msg MsgDeposit) Route() string { return RouterKey }
+func (msg MsgDeposit) Type() string  { return TypeMsgDeposit }
+
+// ValidateBasic Implements Msg.
+func (msg MsgDeposit) ValidateBasic() sdk.Error {
+	if err := validateOwnerProduct(msg.Owner, msg.Product); err != nil {
+		return err
+	}
+	if !msg.Amount.IsValid() {
+		return sdk.ErrInvalidCoins(msg.Amount.String())
+	}
+	return nil
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgDeposit) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgDeposit) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Owner}
+}
+
+// MsgWithdraw withdraws collateral from a margin account
+type MsgWithdraw struct {
+	Owner   sdk.AccAddress `json:"owner"`
+	Product string         `json:"product"`
+	Amount  sdk.DecCoins   `json:"amount"`
+}
+
+func NewMsgWithdraw(owner sdk.AccAddress, product string, amount sdk.DecCoins) MsgWithdraw {
+	return MsgWithdraw{Owner: owner, Product: product, Amount: amount}
+}
+
+// nolint
+func (msg MsgWithdraw) Route() string { return RouterKey }
+func (msg MsgWithdraw) Type() string  { return TypeMsgWithdraw }
+
+// ValidateBasic Implements Msg.
+func (msg MsgWithdraw) ValidateBasic() sdk.Error {
+	if err := validateOwnerProduct(msg.Owner, msg.Product); err != nil {
+		return err
+	}
+	if !msg.Amount.IsValid() {
+		return sdk.ErrInvalidCoins(msg.Amount.String())
+	}
+	return nil
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgWithdraw) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgWithdraw) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Owner}
+}
+
+// MsgBorrow borrows the base or the quote token of the product of a margin account from the lending pool
+type MsgBorrow struct {
+	Owner   sdk.AccAddress `json:"owner"`
+	Product string         `json:"product"`
+	Amount  sdk.DecCoin    `json:"amount"`
+}
+
+func NewMsgBorrow(owner sdk.AccAddress, product string, amount sdk.DecCoin) MsgBorrow {
+	return MsgBorrow{Owner: owner, Product: product, Amount: amount}
+}
+
+// nolint
+func (msg MsgBorrow) Route() string { return RouterKey }
+func (msg MsgBorrow) Type() string  { return TypeMsgBorrow }
+
+// ValidateBasic Implements Msg.
+func (msg MsgBorrow) ValidateBasic() sdk.Error {
+	if err := validateOwnerProduct(msg.Owner, msg.Product); err != nil {
+		return err
+	}
+	return validateAmount(msg.Amount)
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgBorrow) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgBorrow) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Owner}
+}
+
+// MsgRepay repays the debts of a margin account with its balances, the interest is paid off first
+type MsgRepay struct {
+	Owner   sdk.AccAddress `json:"owner"`
+	Product string         `json:"product"`
+	Amount  sdk.DecCoin    `json:"amount"`
+}
+
+func NewMsgRepay(owner sdk.AccAddress, product string, amount sdk.DecCoin) MsgRepay {
+	return MsgRepay{Owner: owner, Product: product, Amount: amount}
+}
+
+// nolint
+func (msg MsgRepay) Route() string { return RouterKey }
+func (msg MsgRepay) Type() string  { return TypeMsgRepay }
+
+// ValidateBasic Implements Msg.
+func (msg MsgRepay) ValidateBasic() sdk.Error {
+	if err := validateOwnerProduct(msg.Owner, msg.Product); err != nil {
+		return err
+	}
+	return validateAmount(msg.Amount)
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgRepay) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgRepay) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Owner}
+}
+
+// MsgLend lends coins to the lending pool of their denom
+type MsgLend struct {
+	Lender sdk.AccAddress `json:"lender"`
+	Amount sdk.DecCoin    `json:"amount"`
+}
+
+func NewMsgLend(lender sdk.AccAddress, amount sdk.DecCoin) MsgLend {
+	return MsgLend{Lender: lender, Amount: amount}
+}
+
+// nolint
+func (msg MsgLend) Route() string { return RouterKey }
+func (msg MsgLend) Type() string  { return TypeMsgLend }
+
+// ValidateBasic Implements Msg.
+func (msg MsgLend) ValidateBasic() sdk.Error {
+	if msg.Lender.Empty() {
+		return sdk.ErrInvalidAddress(msg.Lender.String())
+	}
+	return validateAmount(msg.Amount)
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgLend) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgLend) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Lender}
+}
+
+// MsgRedeem redeems the coins lent to a lending pool together with the interest earned
+type MsgRedeem struct {
+	Lender sdk.AccAddress `json:"lender"`
+	Amount sdk.DecCoin    `json:"amount"`
+}
+
+func NewMsgRedeem(lender sdk.AccAddress, amount sdk.DecCoin) MsgRedeem {
+	return MsgRedeem{Lender: lender, Amount: amount}
+}
+
+// nolint
+func (msg MsgRedeem) Route() string { return RouterKey }
+func (msg MsgRedeem) Type() string  { return TypeMsgRedeem }
+
+// ValidateBasic Implements Msg.
+func (msg MsgRedeem) ValidateBasic() sdk.Error {
+	if msg.Lender.Empty() {
+		return sdk.ErrInvalidAddress(msg.Lender.String())
+	}
+	return validateAmount(msg.Amount)
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgRedeem) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgRedeem) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Lender}
+}
+
+// MsgNewOrder places a limit order with the balances of a margin account
+type MsgNewOrder struct {
+	Owner    sdk.AccAddress `json:"owner"`
+	Product  string         `json:"product"`
+	Side     string         `json:"side"`
+	Price    sdk.Dec        `json:"price"`
+	Quantity sdk.Dec        `json:"quantity"`
+}
+
+func NewMsgNewOrder(owner sdk.AccAddress, product, side string, price, quantity sdk.Dec) MsgNewOrder {
+	return MsgNewOrder{Owner: owner, Product: product, Side: side, Price: price, Quantity: quantity}
+}
+
+// nolint
+func (msg MsgNewOrder) Route() string { return RouterKey }
+func (msg MsgNewOrder) Type() string  { return TypeMsgNewOrder }
+
+// ValidateBasic Implements Msg.
+func (msg MsgNewOrder) ValidateBasic() sdk.Error {
+	if err := validateOwnerProduct(msg.Owner, msg.Product); err != nil {
+		return err
+	}
+	if msg.Side != ordertypes.BuyOrder && msg.Side != ordertypes.SellOrder {
+		return ErrInvalidOrder(fmt.Sprintf("side is expected to be \"BUY\" or \"SELL\", but got \"%s\"", msg.Side))
+	}
+	if msg.Price.Int == nil || !msg.Price.IsPositive() {
+		return ErrInvalidOrder(fmt.Sprintf("price(%v) must be positive", msg.Price))
+	}
+	if msg.Quantity.Int == nil || !msg.Quantity.IsPositive() {
+		return ErrInvalidOrder(fmt.Sprintf("quantity(%v) must be positive", msg.Quantity))
+	}
+	return nil
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgNewOrder) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgNewOrder) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Owner}
+}
+
+// MsgCancelOrder cancels an open order placed with a margin account
+type MsgCancelOrder struct {
+	Owner   sdk.AccAddress `json:"owner"`
+	OrderID string         `json:"order_id"`
+}
+
+func NewMsgCancelOrder(owner sdk.AccAddress, orderID string) MsgCancelOrder {
+	return MsgCancelOrder{Owner: owner, OrderID: orderID}
+}
+
+// nolint
+func (msg MsgCancelOrder) Route() string { return RouterKey }
+func (msg MsgCancelOrder) Type() string  { return TypeMsgCancelOrder }
+
+// ValidateBasic Implements Msg.
+func (msg MsgCancelOrder) ValidateBasic() sdk.Error {
+	if msg.Owner.Empty() {
+		return sdk.ErrInvalidAddress(msg.Owner.String())
+	}
+	if msg.OrderID == "" {
+		return ErrInvalidOrder("order id is empty")
+	}
+	return nil
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgCancelOrder) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgCancelOrder) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Owner}
+}
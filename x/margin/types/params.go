@@ -0,0 +1,114 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/params/subspace"
+	"github.com/okex/okchain/x/params"
+)
+
+const (
+	DefaultInterestRatePerBlock = "0.00000002" // about 10% a year with 5s blocks
+	DefaultBorrowRatio          = "1.5"
+	DefaultLiquidationRatio     = "1.1"
+	DefaultLiquidationSlippage  = "0.05"
+
+	DefaultLiquidationChecksPerBlock = 1000
+	DefaultMaxLiquidationsPerBlock   = 100
+)
+
+var (
+	KeyInterestRatePerBlock = []byte("InterestRatePerBlock")
+	KeyBorrowRatio          = []byte("BorrowRatio")
+	KeyLiquidationRatio     = []byte("LiquidationRatio")
+	KeyLiquidationSlippage  = []byte("LiquidationSlippage")
+
+	KeyLiquidationChecksPerBlock = []byte("LiquidationChecksPerBlock")
+	KeyMaxLiquidationsPerBlock   = []byte("MaxLiquidationsPerBlock")
+)
+
+// Params defines the parameters of the margin module, the collateral ratio of a margin account is the value of its
+// balances divided by the value of its debts, both priced in the quote token at the last price of the product
+type Params struct {
+	// interest accrued on the borrowed principal per block
+	InterestRatePerBlock sdk.Dec `json:"interest_rate_per_block"`
+	// minimum collateral ratio after borrowing or withdrawing collateral
+	BorrowRatio sdk.Dec `json:"borrow_ratio"`
+	// collateral ratio below which a margin account is liquidated
+	LiquidationRatio sdk.Dec `json:"liquidation_ratio"`
+	// how far from the last price the liquidation orders are placed
+	LiquidationSlippage sdk.Dec `json:"liquidation_slippage"`
+	// max number of margin accounts whose collateral ratios are checked in a block, the checks go on from where the
+	// last block stopped
+	LiquidationChecksPerBlock int64 `json:"liquidation_checks_per_block"`
+	// max number of margin accounts liquidated in a block, the others wait in the liquidation queue
+	MaxLiquidationsPerBlock int64 `json:"max_liquidations_per_block"`
+}
+
+func (p *Params) ParamSetPairs() subspace.ParamSetPairs {
+	return params.ParamSetPairs{
+		{Key: KeyInterestRatePerBlock, Value: &p.InterestRatePerBlock},
+		{Key: KeyBorrowRatio, Value: &p.BorrowRatio},
+		{Key: KeyLiquidationRatio, Value: &p.LiquidationRatio},
+		{Key: KeyLiquidationSlippage, Value: &p.LiquidationSlippage},
+		{Key: KeyLiquidationChecksPerBlock, Value: &p.LiquidationChecksPerBlock},
+		{Key: KeyMaxLiquidationsPerBlock, Value: &p.MaxLiquidationsPerBlock},
+	}
+}
+
+// ParamKeyTable for margin module
+func ParamKeyTable() params.KeyTable {
+	return params.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// DefaultParams returns a default set of parameters.
+func DefaultParams() *Params {
+	return &Params{
+		InterestRatePerBlock: sdk.MustNewDecFromStr(DefaultInterestRatePerBlock),
+		BorrowRatio:          sdk.MustNewDecFromStr(DefaultBorrowRatio),
+		LiquidationRatio:     sdk.MustNewDecFromStr(DefaultLiquidationRatio),
+		LiquidationSlippage:  sdk.MustNewDecFromStr(DefaultLiquidationSlippage),
+
+		LiquidationChecksPerBlock: DefaultLiquidationChecksPerBlock,
+		MaxLiquidationsPerBlock:   DefaultMaxLiquidationsPerBlock,
+	}
+}
+
+// Validate checks the parameters
+func (p Params) Validate() error {
+	if p.InterestRatePerBlock.IsNegative() {
+		return fmt.Errorf("interest rate per block should not be negative: %s", p.InterestRatePerBlock)
+	}
+	if p.LiquidationRatio.LTE(sdk.OneDec()) {
+		return fmt.Errorf("liquidation ratio should be greater than 1: %s", p.LiquidationRatio)
+	}
+	if p.BorrowRatio.LT(p.LiquidationRatio) {
+		return fmt.Errorf("borrow ratio(%s) should not be less than liquidation ratio(%s)", p.BorrowRatio,
+			p.LiquidationRatio)
+	}
+	if p.LiquidationSlippage.IsNegative() || p.LiquidationSlippage.GTE(sdk.OneDec()) {
+		return fmt.Errorf("liquidation slippage should be in [0, 1): %s", p.LiquidationSlippage)
+	}
+	if p.LiquidationChecksPerBlock <= 0 {
+		return fmt.Errorf("liquidation checks per block should be positive: %d", p.LiquidationChecksPerBlock)
+	}
+	if p.MaxLiquidationsPerBlock <= 0 {
+		return fmt.Errorf("max liquidations per block should be positive: %d", p.MaxLiquidationsPerBlock)
+	}
+	return nil
+}
+
+// String implements the stringer interface.
+func (p Params) String() string {
+	var sb strings.Builder
+	sb.WriteString("Params: \n")
+	sb.WriteString(fmt.Sprintf("InterestRatePerBlock:%s\n", p.InterestRatePerBlock))
+	sb.WriteString(fmt.Sprintf("BorrowRatio:%s\n", p.BorrowRatio))
+	sb.WriteString(fmt.Sprintf("LiquidationRatio:%s\n", p.LiquidationRatio))
+	sb.WriteString(fmt.Sprintf("LiquidationSlippage:%s\n", p.LiquidationSlippage))
+	sb.WriteString(fmt.Sprintf("LiquidationChecksPerBlock:%d\n", p.LiquidationChecksPerBlock))
+	sb.WriteString(fmt.Sprintf("MaxLiquidationsPerBlock:%d\n", p.MaxLiquidationsPerBlock))
+	return sb.String()
+}
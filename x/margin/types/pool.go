@@ -0,0 +1,99 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Pool is the lending pool of a denom funded by the lenders. The coins lent out and the interest owed by the
+// borrowers are counted in the total supply, which is shared by the lenders in proportion to their shares.
+// The borrow index is the cumulative interest per unit of principal, a margin account owes the interest of its
+// principal times the growth of the index since it was last taken by the account.
+type Pool struct {
+	Denom       string  `json:"denom"`
+	TotalSupply sdk.Dec `json:"total_supply"` // the coins lent by the lenders plus the interest earned
+	TotalShares sdk.Dec `json:"total_shares"`
+	Borrowed    sdk.Dec `json:"borrowed"`  // the coins owed by the borrowers, including the accrued interest
+	Principal   sdk.Dec `json:"principal"` // the principal owed by the borrowers, excluding the interest
+	BorrowIndex sdk.Dec `json:"borrow_index"`
+	// the borrow index when the principal last changed, the interest accrued since then is rounded as a whole
+	PrincipalIndex sdk.Dec `json:"principal_index"`
+}
+
+// NewPool creates an empty lending pool of denom
+func NewPool(denom string) *Pool {
+	return &Pool{
+		Denom:          denom,
+		TotalSupply:    sdk.ZeroDec(),
+		TotalShares:    sdk.ZeroDec(),
+		Borrowed:       sdk.ZeroDec(),
+		Principal:      sdk.ZeroDec(),
+		BorrowIndex:    sdk.OneDec(),
+		PrincipalIndex: sdk.OneDec(),
+	}
+}
+
+// AccrueInterest grows the borrow index by the interest rate of a block, and accrues the interest on the principal,
+// which is earned by the lenders. It returns the interest accrued.
+func (p *Pool) AccrueInterest(rate sdk.Dec) sdk.Dec {
+	accrued := p.Principal.Mul(p.BorrowIndex.Sub(p.PrincipalIndex))
+	p.BorrowIndex = p.BorrowIndex.Add(rate)
+	interest := p.Principal.Mul(p.BorrowIndex.Sub(p.PrincipalIndex)).Sub(accrued)
+	p.TotalSupply = p.TotalSupply.Add(interest)
+	p.Borrowed = p.Borrowed.Add(interest)
+	return interest
+}
+
+// AddPrincipal changes the principal owed by the borrowers, the debts are changed by the same amount
+func (p *Pool) AddPrincipal(amount sdk.Dec) {
+	p.Principal = sdk.MaxDec(p.Principal.Add(amount), sdk.ZeroDec())
+	p.PrincipalIndex = p.BorrowIndex
+	p.AddBorrowed(amount)
+}
+
+// AddBorrowed changes the coins owed by the borrowers. The interest of the margin accounts is rounded one by one,
+// so the debts never go below zero with the rounding errors.
+func (p *Pool) AddBorrowed(amount sdk.Dec) {
+	p.Borrowed = sdk.MaxDec(p.Borrowed.Add(amount), sdk.ZeroDec())
+}
+
+// Available returns the coins of the pool which can be borrowed or redeemed
+func (p *Pool) Available() sdk.Dec {
+	return p.TotalSupply.Sub(p.Borrowed)
+}
+
+// SharesOf returns the shares worth amount in the pool
+func (p *Pool) SharesOf(amount sdk.Dec) sdk.Dec {
+	if p.TotalShares.IsZero() || p.TotalSupply.IsZero() {
+		return amount
+	}
+	return amount.Mul(p.TotalShares).Quo(p.TotalSupply)
+}
+
+// ValueOf returns the amount the shares are worth in the pool
+func (p *Pool) ValueOf(shares sdk.Dec) sdk.Dec {
+	if p.TotalShares.IsZero() {
+		return sdk.ZeroDec()
+	}
+	return shares.Mul(p.TotalSupply).QuoTruncate(p.TotalShares)
+}
+
+// String implements the stringer interface
+func (p Pool) String() string {
+	return fmt.Sprintf(`Lending Pool:
+  Denom:        %s
+  Total Supply: %s
+  Total Shares: %s
+  Borrowed:     %s
+  Principal:    %s
+  Borrow Index: %s`, p.Denom, p.TotalSupply, p.TotalShares, p.Borrowed, p.Principal, p.BorrowIndex)
+}
+
+// LenderShare is the shares of a lender in the lending pool of a denom
+type LenderShare struct {
+	Lender sdk.AccAddress `json:"lender"`
+	Denom  string         `json:"denom"`
+	Shares sdk.Dec        `json:"shares"`
+	Value  sdk.Dec        `json:"value"` // the amount the shares are worth, only filled in by the queriers
+}
@@ -0,0 +1,26 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// QueryAccountParams is the params of querying the margin account of an owner on a product
+type QueryAccountParams struct {
+	Owner   sdk.AccAddress `json:"owner"`
+	Product string         `json:"product"`
+}
+
+// NewQueryAccountParams creates a new instance of QueryAccountParams
+func NewQueryAccountParams(owner sdk.AccAddress, product string) QueryAccountParams {
+	return QueryAccountParams{Owner: owner, Product: product}
+}
+
+// QueryAddressParams is the params of querying the margin accounts of an owner or the shares of a lender
+type QueryAddressParams struct {
+	Address sdk.AccAddress `json:"address"`
+}
+
+// NewQueryAddressParams creates a new instance of QueryAddressParams
+func NewQueryAddressParams(addr sdk.AccAddress) QueryAddressParams {
+	return QueryAddressParams{Address: addr}
+}
@@ -19,26 +19,31 @@ const (
 // nolint
 // types aliases
 type (
-	Keeper          = keeper.Keeper
-	Order           = types.Order
-	DepthBook       = types.DepthBook
-	MatchResult     = types.MatchResult
-	Deal            = types.Deal
-	Params          = types.Params
-	MsgNewOrder     = types.MsgNewOrder
-	MsgCancelOrder  = types.MsgCancelOrder
-	MsgNewOrders    = types.MsgNewOrders
-	MsgCancelOrders = types.MsgCancelOrders
+	Keeper             = keeper.Keeper
+	Order              = types.Order
+	DepthBook          = types.DepthBook
+	MatchResult        = types.MatchResult
+	Deal               = types.Deal
+	Params             = types.Params
+	MsgNewOrder        = types.MsgNewOrder
+	MsgCancelOrder     = types.MsgCancelOrder
+	MsgNewOrders       = types.MsgNewOrders
+	MsgCancelOrders    = types.MsgCancelOrders
+	MsgAmendOrder      = types.MsgAmendOrder
+	MsgCancelAllOrders = types.MsgCancelAllOrders
 )
 
 // nolint
 // functions aliases
 var (
-	RegisterCodec     = types.RegisterCodec
-	DefaultParams     = types.DefaultParams
-	NewMsgNewOrder    = types.NewMsgNewOrder
-	NewMsgCancelOrder = types.NewMsgCancelOrder
-	NewKeeper         = keeper.NewKeeper
-	NewQuerier        = keeper.NewQuerier
-	FormatOrderIDsKey = types.FormatOrderIDsKey
+	RegisterCodec         = types.RegisterCodec
+	DefaultParams         = types.DefaultParams
+	NewMsgNewOrder        = types.NewMsgNewOrder
+	NewMsgCancelOrder     = types.NewMsgCancelOrder
+	NewMsgAmendOrder      = types.NewMsgAmendOrder
+	NewMsgCancelAllOrders = types.NewMsgCancelAllOrders
+	NewKeeper             = keeper.NewKeeper
+	NewQuerier            = keeper.NewQuerier
+	FormatOrderIDsKey     = types.FormatOrderIDsKey
+	NewMultiOrderHooks    = types.NewMultiOrderHooks
 )
@@ -24,7 +24,10 @@ func GetQueryCmd(queryRoute string, cdc *codec.Codec) *cobra.Command {
 
 	queryCmd.AddCommand(client.GetCommands(
 		GetCmdQueryOrder(queryRoute, cdc),
+		GetCmdQueryClientOrder(queryRoute, cdc),
+		GetCmdQueryOpenOrders(queryRoute, cdc),
 		GetCmdDepthBook(queryRoute, cdc),
+		GetCmdDepthDiff(queryRoute, cdc),
 		GetCmdQueryStore(queryRoute, cdc),
 		GetCmdQueryParams(queryRoute, cdc),
 	)...)
@@ -56,6 +59,62 @@ func GetCmdQueryOrder(queryRoute string, cdc *codec.Codec) *cobra.Command {
 	}
 }
 
+// GetCmdQueryClientOrder queries order info by the sender and its client order id
+func GetCmdQueryClientOrder(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "client-detail [sender] [client-order-id]",
+		Short: "Query an order by the client order id",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			res, _, err := cliCtx.QueryWithData(
+				fmt.Sprintf("custom/%s/%s/%s/%s", queryRoute, types.QueryClientOrder, args[0], args[1]),
+				nil)
+			if err != nil {
+				fmt.Printf("order does not exist - %s \n", args[1])
+				return nil
+			}
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+}
+
+// GetCmdQueryOpenOrders queries the open orders of an address
+func GetCmdQueryOpenOrders(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "open-orders [address]",
+		Short: "Query the open orders of an address",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			addr := args[0]
+			params := keeper.NewQueryOpenOrdersParams(addr, viper.GetString("product"), viper.GetInt("page"),
+				viper.GetInt("per-page"))
+			bz, err := cdc.MarshalJSON(params)
+			if err != nil {
+				return err
+			}
+
+			res, _, err := cliCtx.QueryWithData(
+				fmt.Sprintf("custom/%s/%s", queryRoute, types.QueryOpenOrders),
+				bz)
+			if err != nil {
+				fmt.Printf("get open orders of %s failed: %v\n", addr, err.Error())
+				return nil
+			}
+
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+	cmd.Flags().String("product", "", "filter orders by product")
+	cmd.Flags().Int("page", keeper.DefaultPage, "page num")
+	cmd.Flags().Int("per-page", keeper.DefaultPerPage, "items per page")
+	return cmd
+}
+
 // GetCmdDepthBook queries order book about a product
 func GetCmdDepthBook(queryRoute string, cdc *codec.Codec) *cobra.Command {
 	cmd := &cobra.Command{
@@ -64,6 +123,7 @@ func GetCmdDepthBook(queryRoute string, cdc *codec.Codec) *cobra.Command {
 		Long: strings.TrimSpace(`Query the depth book of a trading pair:
 
 $ okchaincli query depthbook mytoken_okt
+$ okchaincli query depthbook mytoken_okt --tick-size 0.1
 
 The 'product' is a trading pair in full name of the tokens: ${base-asset-symbol}_${quote-asset-symbol}, for example 'mytoken_okt'.
 `),
@@ -72,7 +132,8 @@ The 'product' is a trading pair in full name of the tokens: ${base-asset-symbol}
 			cliCtx := context.NewCLIContext().WithCodec(cdc)
 			product := args[0]
 			size := viper.GetInt("size")
-			params := keeper.NewQueryDepthBookParams(product, size)
+			tickSize := viper.GetString("tick-size")
+			params := keeper.NewQueryDepthBookParams(product, size, tickSize)
 			bz, err := cdc.MarshalJSON(params)
 			if err != nil {
 				return err
@@ -91,9 +152,38 @@ The 'product' is a trading pair in full name of the tokens: ${base-asset-symbol}
 		},
 	}
 	cmd.Flags().Int("size", keeper.DefaultBookSize, "depth book single-side size")
+	cmd.Flags().String("tick-size", "", "aggregate the depth book into price levels of the tick size, e.g. 0.01")
 	return cmd
 }
 
+// GetCmdDepthDiff queries the latest diff of the depth book of a product
+func GetCmdDepthDiff(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "depthdiff [product]",
+		Short: "Query the latest diff of the depth book of a trading pair",
+		Long: strings.TrimSpace(`Query the price levels of the depth book of a trading pair changed in the latest block it changes:
+
+$ okchaincli query order depthdiff mytoken_okt
+
+A price level with zero quantities is removed from the depth book. The sequence of the diff increases by one with every diff,
+so the depth book is rebuilt by applying the following diffs to the depth book of the same sequence.
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s/%s", queryRoute, types.QueryDepthDiff, args[0]),
+				nil)
+			if err != nil {
+				fmt.Printf("get depth diff of %s failed: %v\n", args[0], err.Error())
+				return nil
+			}
+
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+}
+
 // GetCmdQueryStore queries store statistic
 func GetCmdQueryStore(queryRoute string, cdc *codec.Codec) *cobra.Command {
 	cmd := &cobra.Command{
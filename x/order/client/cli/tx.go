@@ -25,6 +25,8 @@ func GetTxCmd(cdc *codec.Codec) *cobra.Command {
 	txCmd.AddCommand(client.PostCommands(
 		GetCmdNewOrder(cdc),
 		GetCmdCancelOrder(cdc),
+		GetCmdAmendOrder(cdc),
+		GetCmdCancelAllOrders(cdc),
 	)...)
 
 	return txCmd
@@ -36,11 +38,22 @@ func GetCmdNewOrder(cdc *codec.Codec) *cobra.Command {
 	var side string
 	var price string
 	var quantity string
+	var timeInForce string
+	var triggerType string
+	var triggerPrice string
+	var clientOrderID string
+	var orderType string
+	var maxSlippage string
+	var displayQuantity string
+	var selfTradePrevention string
+	var nativeFee bool
+	var atomic bool
 	cmd := &cobra.Command{
 		Use:   "new",
 		Short: "place a new order",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(product) == 0 || len(side) == 0 || len(price) == 0 || len(quantity) == 0 {
+			// the price of a market order is optional with a max slippage
+			if len(product) == 0 || len(side) == 0 || (len(price) == 0 && len(orderType) == 0) || len(quantity) == 0 {
 				return errors.New("invalid param format")
 			}
 			if len(args) > 0 {
@@ -52,7 +65,8 @@ func GetCmdNewOrder(cdc *codec.Codec) *cobra.Command {
 				return errors.New("invalid param counts")
 			}
 
-			err := handleNewOrder(cdc, product, side, price, quantity)
+			err := handleNewOrder(cdc, product, side, price, quantity, timeInForce, triggerType, triggerPrice,
+				clientOrderID, orderType, maxSlippage, displayQuantity, selfTradePrevention, nativeFee, atomic)
 			return err
 
 		},
@@ -60,16 +74,31 @@ func GetCmdNewOrder(cdc *codec.Codec) *cobra.Command {
 
 	cmd.Flags().StringVarP(&product, "product", "", "", "Trading pair in full name of the tokens: ${baseAssetSymbol}_${quoteAssetSymbol}, for example \"mycoin_okt\".")
 	cmd.Flags().StringVarP(&side, "side", "s", "", "BUY or SELL (default \"SELL\")")
-	cmd.Flags().StringVarP(&price, "price", "p", "", "The price of the order")
-	cmd.Flags().StringVarP(&quantity, "quantity", "q", "", "The quantity of the order")
+	cmd.Flags().StringVarP(&price, "price", "p", "", "The price of the order, or the worst acceptable price of a market order")
+	cmd.Flags().StringVarP(&quantity, "quantity", "q", "", "The quantity of the order, or the amount of quote token to spend for a market buy order")
+	cmd.Flags().StringVarP(&timeInForce, "time-in-force", "", "", "GTC, IOC, FOK or POST_ONLY (default \"GTC\")")
+	cmd.Flags().StringVarP(&triggerType, "trigger-type", "", "", "STOP_LIMIT or TAKE_PROFIT for a trigger order")
+	cmd.Flags().StringVarP(&triggerPrice, "trigger-price", "", "", "The trigger price of a trigger order")
+	cmd.Flags().StringVarP(&clientOrderID, "client-order-id", "", "", "Your own id of the order, unique among your open orders")
+	cmd.Flags().StringVarP(&orderType, "order-type", "", "", "LIMIT or MARKET (default \"LIMIT\")")
+	cmd.Flags().StringVarP(&maxSlippage, "max-slippage", "", "", "The max slippage of a market order from the best bid/ask instead of a price, e.g. 0.05")
+	cmd.Flags().StringVarP(&displayQuantity, "display-quantity", "", "", "The quantity displayed in the depth book at a time for an iceberg order")
+	cmd.Flags().StringVarP(&selfTradePrevention, "self-trade-prevention", "", "", "NONE, CANCEL_NEWEST, CANCEL_OLDEST, CANCEL_BOTH or DECREMENT_AND_CANCEL (default in params)")
+	cmd.Flags().BoolVarP(&nativeFee, "native-fee", "", false, "Pay the deal fees in the native token at a discount")
+	cmd.Flags().BoolVarP(&atomic, "atomic", "", false, "Place all the orders or none of them if any fails")
 	return cmd
 }
 
-func handleNewOrder(cdc *codec.Codec, product string, side string, price string, quantity string) error {
+func handleNewOrder(cdc *codec.Codec, product string, side string, price string, quantity string,
+	timeInForce string, triggerType string, triggerPrice string, clientOrderID string, orderType string,
+	maxSlippage string, displayQuantity string, selfTradePrevention string, nativeFee bool, atomic bool) error {
 	var items []types.OrderItem
 	productArr := strings.Split(product, ",")
 	sideArr := strings.Split(side, ",")
-	priceArr := strings.Split(price, ",")
+	priceArr := make([]string, len(productArr))
+	if len(price) > 0 {
+		priceArr = strings.Split(price, ",")
+	}
 	quantityArr := strings.Split(quantity, ",")
 	if len(productArr) != len(sideArr) {
 		return errors.New("invalid param side counts")
@@ -83,45 +112,137 @@ func handleNewOrder(cdc *codec.Codec, product string, side string, price string,
 		return errors.New("invalid param quantity counts")
 	}
 
+	timeInForceArr := make([]string, len(productArr))
+	if len(timeInForce) > 0 {
+		timeInForceArr = strings.Split(timeInForce, ",")
+		if len(productArr) != len(timeInForceArr) {
+			return errors.New("invalid param time-in-force counts")
+		}
+	}
+	triggerTypeArr := make([]string, len(productArr))
+	triggerPriceArr := make([]string, len(productArr))
+	if len(triggerType) > 0 {
+		triggerTypeArr = strings.Split(triggerType, ",")
+		triggerPriceArr = strings.Split(triggerPrice, ",")
+		if len(productArr) != len(triggerTypeArr) || len(productArr) != len(triggerPriceArr) {
+			return errors.New("invalid param trigger-type or trigger-price counts")
+		}
+	}
+	clientOrderIDArr := make([]string, len(productArr))
+	if len(clientOrderID) > 0 {
+		clientOrderIDArr = strings.Split(clientOrderID, ",")
+		if len(productArr) != len(clientOrderIDArr) {
+			return errors.New("invalid param client-order-id counts")
+		}
+	}
+
+	orderTypeArr := make([]string, len(productArr))
+	maxSlippageArr := make([]string, len(productArr))
+	if len(orderType) > 0 {
+		orderTypeArr = strings.Split(orderType, ",")
+		if len(productArr) != len(orderTypeArr) {
+			return errors.New("invalid param order-type counts")
+		}
+	}
+	if len(maxSlippage) > 0 {
+		maxSlippageArr = strings.Split(maxSlippage, ",")
+		if len(productArr) != len(maxSlippageArr) {
+			return errors.New("invalid param max-slippage counts")
+		}
+	}
+	displayQuantityArr := make([]string, len(productArr))
+	if len(displayQuantity) > 0 {
+		displayQuantityArr = strings.Split(displayQuantity, ",")
+		if len(productArr) != len(displayQuantityArr) {
+			return errors.New("invalid param display-quantity counts")
+		}
+	}
+	selfTradePreventionArr := make([]string, len(productArr))
+	if len(selfTradePrevention) > 0 {
+		selfTradePreventionArr = strings.Split(selfTradePrevention, ",")
+		if len(productArr) != len(selfTradePreventionArr) {
+			return errors.New("invalid param self-trade-prevention counts")
+		}
+	}
+
 	for i := 0; i < len(productArr); i++ {
 		product := productArr[i]
 		side := sideArr[i]
-		price, err := sdk.NewDecFromStr(priceArr[i])
-		if err != nil {
-			return errors.New(err.Error())
+		price := sdk.ZeroDec()
+		var err error
+		if len(priceArr[i]) > 0 {
+			if price, err = sdk.NewDecFromStr(priceArr[i]); err != nil {
+				return errors.New(err.Error())
+			}
 		}
 		quantity, err := sdk.NewDecFromStr(quantityArr[i])
 		if err != nil {
 			return errors.New(err.Error())
 		}
-		items = append(items, types.OrderItem{
+		item := types.OrderItem{
 			Product:  product,
 			Side:     side,
 			Price:    price,
 			Quantity: quantity,
-		})
+
+			TimeInForce:   timeInForceArr[i],
+			TriggerType:   triggerTypeArr[i],
+			ClientOrderID: clientOrderIDArr[i],
+			OrderType:     orderTypeArr[i],
+
+			SelfTradePrevention: selfTradePreventionArr[i],
+			NativeFee:           nativeFee,
+		}
+		if len(triggerTypeArr[i]) > 0 {
+			if item.TriggerPrice, err = sdk.NewDecFromStr(triggerPriceArr[i]); err != nil {
+				return errors.New(err.Error())
+			}
+		}
+		if len(maxSlippageArr[i]) > 0 {
+			if item.MaxSlippage, err = sdk.NewDecFromStr(maxSlippageArr[i]); err != nil {
+				return errors.New(err.Error())
+			}
+		}
+		if len(displayQuantityArr[i]) > 0 {
+			if item.DisplayQuantity, err = sdk.NewDecFromStr(displayQuantityArr[i]); err != nil {
+				return errors.New(err.Error())
+			}
+		}
+		items = append(items, item)
 	}
 
 	txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
 	cliCtx := context.NewCLIContext().WithCodec(cdc)
 
 	msg := types.NewMsgNewOrders(cliCtx.GetFromAddress(), items)
+	msg.Atomic = atomic
 	err := utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
 	return err
 }
 
 func GetCmdCancelOrder(cdc *codec.Codec) *cobra.Command {
-	return &cobra.Command{
+	var clientOrderID string
+	cmd := &cobra.Command{
 		Use:   "cancel [order-id]",
 		Short: "cancel order",
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.RangeArgs(0, 1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			orderIDs := strings.Split(args[0], ",")
+			var orderIDs, clientOrderIDs []string
+			if len(args) > 0 {
+				orderIDs = strings.Split(args[0], ",")
+			}
+			if len(clientOrderID) > 0 {
+				clientOrderIDs = strings.Split(clientOrderID, ",")
+			}
+			if len(orderIDs) == 0 && len(clientOrderIDs) == 0 {
+				return errors.New("either order-id or client-order-id is required")
+			}
 
 			txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
 			cliCtx := context.NewCLIContext().WithCodec(cdc)
 
 			msg := types.NewMsgCancelOrders(cliCtx.GetFromAddress(), orderIDs)
+			msg.ClientOrderIDs = clientOrderIDs
 			err := utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
 			if err != nil {
 				fmt.Println(err)
@@ -129,4 +250,52 @@ func GetCmdCancelOrder(cdc *codec.Codec) *cobra.Command {
 			return err
 		},
 	}
+
+	cmd.Flags().StringVarP(&clientOrderID, "client-order-id", "", "", "Cancel the open orders by your own ids")
+	return cmd
+}
+
+func GetCmdAmendOrder(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "amend [order-id] [price] [quantity]",
+		Short: "amend the price and the total quantity of an open order",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			price, err := sdk.NewDecFromStr(args[1])
+			if err != nil {
+				return errors.New(err.Error())
+			}
+			quantity, err := sdk.NewDecFromStr(args[2])
+			if err != nil {
+				return errors.New(err.Error())
+			}
+
+			txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			msg := types.NewMsgAmendOrder(cliCtx.GetFromAddress(), args[0], price, quantity)
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+func GetCmdCancelAllOrders(cdc *codec.Codec) *cobra.Command {
+	var product string
+	var side string
+	cmd := &cobra.Command{
+		Use:   "cancel-all",
+		Short: "cancel all the open orders, optionally of a trading pair and a side",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			msg := types.NewMsgCancelAllOrders(cliCtx.GetFromAddress(), product, side)
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd.Flags().StringVarP(&product, "product", "", "", "Only cancel the orders of the trading pair if set")
+	cmd.Flags().StringVarP(&side, "side", "s", "", "Only cancel the orders of BUY or SELL side if set")
+	return cmd
 }
@@ -19,7 +19,10 @@ import (
 // RegisterRoutes - Central function to define routes that get registered by the main application
 func RegisterRoutes(cliCtx context.CLIContext, r *mux.Router) {
 	r.HandleFunc("/order/depthbook", orderBookHandler(cliCtx)).Methods("GET")
+	r.HandleFunc("/order/depthdiff/{product}", depthDiffHandler(cliCtx)).Methods("GET")
 	r.HandleFunc("/order/{orderID}", orderDetailHandler(cliCtx)).Methods("GET")
+	r.HandleFunc("/order/client/{sender}/{clientOrderID}", clientOrderDetailHandler(cliCtx)).Methods("GET")
+	r.HandleFunc("/order/open/{address}", openOrdersHandler(cliCtx)).Methods("GET")
 }
 
 func orderDetailHandler(cliCtx context.CLIContext) http.HandlerFunc {
@@ -45,10 +48,99 @@ func orderDetailHandler(cliCtx context.CLIContext) http.HandlerFunc {
 	}
 }
 
+func depthDiffHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		product := mux.Vars(r)["product"]
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/order/%s/%s", types.QueryDepthDiff, product), nil)
+		if err != nil {
+			common.HandleErrorMsg(w, cliCtx, err.Error())
+			return
+		}
+
+		diff := &types.DepthBookDiff{}
+		codec.Cdc.MustUnmarshalJSON(res, diff)
+		response := common.GetBaseResponse(diff)
+		resBytes, err2 := json.Marshal(response)
+		if err2 != nil {
+			common.HandleErrorMsg(w, cliCtx, err2.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cliCtx, resBytes)
+	}
+}
+
+func clientOrderDetailHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		sender := vars["sender"]
+		clientOrderID := vars["clientOrderID"]
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/order/clientorder/%s/%s", sender, clientOrderID),
+			nil)
+		if err != nil {
+			common.HandleErrorMsg(w, cliCtx, err.Error())
+			return
+		}
+
+		order2 := &types.Order{}
+		codec.Cdc.MustUnmarshalJSON(res, order2)
+		response := common.GetBaseResponse(order2)
+		resBytes, err2 := json.Marshal(response)
+		if err2 != nil {
+			common.HandleErrorMsg(w, cliCtx, err2.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cliCtx, resBytes)
+	}
+}
+
+func openOrdersHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		addr := mux.Vars(r)["address"]
+		product := r.URL.Query().Get("product")
+		pageStr := r.URL.Query().Get("page")
+		perPageStr := r.URL.Query().Get("per_page")
+
+		var page, perPage int
+		var err error
+		if pageStr != "" {
+			page, err = strconv.Atoi(pageStr)
+			if err != nil {
+				common.HandleErrorMsg(w, cliCtx, err.Error())
+				return
+			}
+		}
+		if perPageStr != "" {
+			perPage, err = strconv.Atoi(perPageStr)
+			if err != nil {
+				common.HandleErrorMsg(w, cliCtx, err.Error())
+				return
+			}
+		}
+
+		params := keeper.NewQueryOpenOrdersParams(addr, product, page, perPage)
+		bz, err := cliCtx.Codec.MarshalJSON(params)
+		if err != nil {
+			common.HandleErrorMsg(w, cliCtx, err.Error())
+			return
+		}
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/order/%s", types.QueryOpenOrders), bz)
+		if err != nil {
+			common.HandleErrorMsg(w, cliCtx, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
 func orderBookHandler(cliCtx context.CLIContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		product := r.URL.Query().Get("product")
 		sizeStr := r.URL.Query().Get("size")
+		tickSize := r.URL.Query().Get("tick_size")
 		// validate request
 		if product == "" {
 			common.HandleErrorMsg(w, cliCtx, "Bad request: product is empty")
@@ -67,7 +159,7 @@ func orderBookHandler(cliCtx context.CLIContext) http.HandlerFunc {
 			common.HandleErrorMsg(w, cliCtx, "Bad request: size is invalid")
 			return
 		}
-		params := keeper.NewQueryDepthBookParams(product, size)
+		params := keeper.NewQueryDepthBookParams(product, size, tickSize)
 		bz, err := cliCtx.Codec.MarshalJSON(params)
 		if err != nil {
 			common.HandleErrorMsg(w, cliCtx, err.Error())
@@ -60,6 +60,9 @@ type BookParam struct {
 	// Required: true
 	// in: query
 	Product string `json:"product"`
+	// aggregate the depth book into price levels of the tick size
+	// in: query
+	TickSize string `json:"tick_size"`
 }
 
 // Order depth book
@@ -27,6 +27,7 @@ func depthBookHandlerV2(cliCtx context.CLIContext) http.HandlerFunc {
 		vars := mux.Vars(r)
 		product := vars["instrument_id"]
 		sizeStr := r.URL.Query().Get("size")
+		tickSize := r.URL.Query().Get("tick_size")
 
 		// validate request
 		if product == "" {
@@ -47,7 +48,7 @@ func depthBookHandlerV2(cliCtx context.CLIContext) http.HandlerFunc {
 			return
 		}
 
-		params := keeper.NewQueryDepthBookParams(product, size)
+		params := keeper.NewQueryDepthBookParams(product, size, tickSize)
 		req, err := cliCtx.Codec.MarshalJSON(params)
 		if err != nil {
 			common.HandleErrorResponseV2(w, http.StatusBadRequest, common.ErrorInvalidParam)
@@ -2,8 +2,10 @@ package order
 
 import (
 	"fmt"
+	"sort"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/libs/log"
 
 	"github.com/okex/okchain/x/common/perf"
 	"github.com/okex/okchain/x/order/keeper"
@@ -12,14 +14,26 @@ import (
 )
 
 // EndBlocker called every block
-// 1. execute matching engine
-// 2. flush cache
+// 1. cancel orders of delisted products
+// 2. expire orders
+// 3. prune the orders closed for ClosedOrderRetentionBlocks blocks
+// 4. drop the traded volumes out of the rolling window of fee tiers
+// 5. execute matching engine
+// 6. record the last prices referred to by the price bands of products
+// 7. trigger orders whose trigger price has been crossed by the last price
+// 8. flush cache
 func EndBlocker(ctx sdk.Context, keeper keeper.Keeper) {
 
 	seq := perf.GetPerf().OnEndBlockEnter(ctx, types.ModuleName)
 	defer perf.GetPerf().OnEndBlockExit(ctx, types.ModuleName, seq)
 
-	match.GetEngine().Run(ctx, keeper)
+	cleanupOrdersWhoseTokenPairHaveBeenDelisted(ctx, keeper)
+	expireOrders(ctx, keeper)
+	keeper.PruneClosedOrders(ctx)
+	keeper.DropExpiredTradedVolumes(ctx)
+	match.Run(ctx, keeper)
+	keeper.RecordPriceHistory(ctx)
+	triggerOrders(ctx, keeper)
 
 	// flush cache at the end
 	keeper.Cache2Disk(ctx)
@@ -38,3 +52,208 @@ func EndBlocker(ctx sdk.Context, keeper keeper.Keeper) {
 
 	perf.GetPerf().EnqueueMsg(msg)
 }
+
+// cleanupOrdersWhoseTokenPairHaveBeenDelisted cancels all the open orders of products which no longer exist
+func cleanupOrdersWhoseTokenPairHaveBeenDelisted(ctx sdk.Context, k keeper.Keeper) {
+	logger := ctx.Logger().With("module", "order")
+	products := k.GetProductsFromDepthBookMap()
+	sort.Strings(products)
+	for _, product := range products {
+		if k.GetDexKeeper().GetTokenPair(ctx, product) != nil {
+			continue
+		}
+
+		var orderIDs []string
+		book := k.GetDepthBookCopy(product)
+		for _, item := range book.Items {
+			if item.BuyQuantity.IsPositive() {
+				key := types.FormatOrderIDsKey(product, item.Price, types.BuyOrder)
+				orderIDs = append(orderIDs, k.GetProductPriceOrderIDs(key)...)
+			}
+			if item.SellQuantity.IsPositive() {
+				key := types.FormatOrderIDsKey(product, item.Price, types.SellOrder)
+				orderIDs = append(orderIDs, k.GetProductPriceOrderIDs(key)...)
+			}
+		}
+		sort.Strings(orderIDs)
+
+		for _, orderID := range orderIDs {
+			order := k.GetOrder(ctx, orderID)
+			k.CancelOrder(ctx, order, logger)
+			logger.Info(fmt.Sprintf("order(%s) is cancelled since product(%s) has been delisted", orderID, product))
+		}
+	}
+}
+
+// expireOrders expires the open orders which have lived for OrderExpireBlocks blocks, and drops the closed orders
+// placed at the same height. The expired orders are dropped in the next block.
+// At most MaxExpireOrdersPerBlock orders are handled in a block, the rest are continued in the following blocks
+// from the last expired block height, which also makes the sweep catch up after a restart.
+// Expiring the orders of a product locked by an execution would break the execution, so they are deferred and
+// expired first once the execution is completed, while halted products are not executing.
+func expireOrders(ctx sdk.Context, k keeper.Keeper) {
+	logger := ctx.Logger().With("module", "order")
+	remainNum := types.MaxExpireOrdersPerBlock
+	remainNum -= dropExpiredOrders(ctx, k)
+
+	deferredOrderIDs, handledNum := expireDeferredOrders(ctx, k, remainNum, logger)
+	remainNum -= handledNum
+
+	blockHeight := ctx.BlockHeight()
+	lastExpiredHeight := k.GetLastExpiredBlockHeight(ctx)
+	if lastExpiredHeight == 0 {
+		lastExpiredHeight = blockHeight - 1
+	}
+	for height := lastExpiredHeight + 1; height <= blockHeight; height++ {
+		placedHeights := k.GetExpireBlockHeight(ctx, height)
+		sort.Slice(placedHeights, func(i, j int) bool { return placedHeights[i] < placedHeights[j] })
+		for i, placedHeight := range placedHeights {
+			// genesis might register a height more than once
+			if i > 0 && placedHeights[i-1] == placedHeight {
+				continue
+			}
+			handledNum, finished := expireBlockOrders(ctx, k, placedHeight, remainNum, &deferredOrderIDs, logger)
+			remainNum -= handledNum
+			if !finished {
+				k.SetExpireBlockHeight(ctx, height, placedHeights[i:])
+				k.SetLastExpiredBlockHeight(ctx, height-1)
+				k.SetDeferredExpireOrderIDs(ctx, deferredOrderIDs)
+				return
+			}
+			k.DropBlockOrderNum(ctx, placedHeight)
+		}
+		k.DropExpireBlockHeight(ctx, height)
+	}
+	k.SetLastExpiredBlockHeight(ctx, blockHeight)
+	k.SetDeferredExpireOrderIDs(ctx, deferredOrderIDs)
+}
+
+// expireDeferredOrders expires or drops at most limit deferred orders whose products are no longer executing,
+// and returns the ids of the orders still deferred
+func expireDeferredOrders(ctx sdk.Context, k keeper.Keeper, limit int,
+	logger log.Logger) (deferredOrderIDs []string, handledNum int) {
+
+	for _, orderID := range k.GetDeferredExpireOrderIDs(ctx) {
+		order := k.GetOrder(ctx, orderID)
+		if order == nil {
+			continue
+		}
+		if handledNum >= limit || k.IsProductExecuting(order.Product) {
+			deferredOrderIDs = append(deferredOrderIDs, orderID)
+			continue
+		}
+		expireOrDropOrder(ctx, k, order, logger)
+		handledNum++
+	}
+	return deferredOrderIDs, handledNum
+}
+
+// expireBlockOrders expires or drops at most limit orders placed at blockHeight, and tells whether all the orders of
+// the height have been handled. The orders of executing products are appended to deferredOrderIDs instead.
+func expireBlockOrders(ctx sdk.Context, k keeper.Keeper, blockHeight int64, limit int, deferredOrderIDs *[]string,
+	logger log.Logger) (handledNum int, finished bool) {
+
+	if limit <= 0 {
+		return 0, false
+	}
+	// the deferred orders are still in the store, one more id tells whether there are orders left
+	deferred := make(map[string]bool, len(*deferredOrderIDs))
+	for _, orderID := range *deferredOrderIDs {
+		deferred[orderID] = true
+	}
+	for _, orderID := range k.GetBlockOrderIDs(ctx, blockHeight, limit+1+len(deferred)) {
+		if deferred[orderID] {
+			continue
+		}
+		if handledNum == limit {
+			return handledNum, false
+		}
+		handledNum++
+
+		order := k.GetOrder(ctx, orderID)
+		if (order.Status == types.OrderStatusOpen || order.Status == types.OrderStatusUntriggered) &&
+			k.IsProductExecuting(order.Product) {
+			*deferredOrderIDs = append(*deferredOrderIDs, orderID)
+			continue
+		}
+		expireOrDropOrder(ctx, k, order, logger)
+	}
+	return handledNum, true
+}
+
+// expireOrDropOrder expires the order if it is still open, or drops it if it has been closed
+func expireOrDropOrder(ctx sdk.Context, k keeper.Keeper, order *types.Order, logger log.Logger) {
+	if order.Status == types.OrderStatusOpen || order.Status == types.OrderStatusUntriggered {
+		k.ExpireOrder(ctx, order, logger)
+		logger.Info(fmt.Sprintf("order(%s) is expired", order.OrderID))
+	} else {
+		k.DropOrder(ctx, order.OrderID)
+		k.GetDiskCache().DecreaseStoreOrderNum(1)
+	}
+}
+
+// dropExpiredOrders drops the orders expired in the last block
+func dropExpiredOrders(ctx sdk.Context, k keeper.Keeper) int {
+	droppedNum := 0
+	for _, orderID := range k.GetLastClosedOrderIDs(ctx) {
+		order := k.GetOrder(ctx, orderID)
+		if order == nil ||
+			(order.Status != types.OrderStatusExpired && order.Status != types.OrderStatusPartialFilledExpired) {
+			continue
+		}
+		k.DropOrder(ctx, orderID)
+		k.GetDiskCache().DecreaseStoreOrderNum(1)
+		droppedNum++
+	}
+	return droppedNum
+}
+
+// triggerOrders moves the trigger orders whose condition is met by the last price into the depth book.
+// Only the products whose last price has changed in this block, which have new trigger orders, or whose crossed
+// trigger orders were left by the limit of the last block are checked. At most MaxTriggerOrdersPerBlock orders are
+// triggered in a block. The triggered orders are matched in the next block before the orders placed in it.
+func triggerOrders(ctx sdk.Context, k keeper.Keeper) {
+	logger := ctx.Logger().With("module", "order")
+	products := k.GetPendingTriggerProducts(ctx)
+	for product := range k.GetBlockMatchResult().ResultMap {
+		products = append(products, product)
+	}
+	blockHeight := ctx.BlockHeight()
+	orderNum := k.GetBlockOrderNum(ctx, blockHeight)
+	for i := int64(1); i <= orderNum; i++ {
+		order := k.GetOrder(ctx, types.FormatOrderID(blockHeight, i))
+		if order != nil && order.Status == types.OrderStatusUntriggered {
+			products = append(products, order.Product)
+		}
+	}
+	sort.Strings(products)
+
+	remainNum := types.MaxTriggerOrdersPerBlock
+	var triggeredOrderIDs, pendingProducts []string
+	for i, product := range products {
+		if i > 0 && products[i-1] == product {
+			continue
+		}
+		if k.GetDexKeeper().GetTokenPair(ctx, product) == nil {
+			continue
+		}
+		// the last price of a locked product is not final until its execution is completed
+		if k.IsProductLocked(product) || remainNum == 0 {
+			pendingProducts = append(pendingProducts, product)
+			continue
+		}
+		lastPrice := k.GetLastPrice(ctx, product)
+		orderIDs := k.GetCrossedTriggerOrderIDs(ctx, product, lastPrice, remainNum)
+		for _, orderID := range orderIDs {
+			k.ActivateTriggerOrder(ctx, k.GetOrder(ctx, orderID))
+			triggeredOrderIDs = append(triggeredOrderIDs, orderID)
+			logger.Info(fmt.Sprintf("order(%s) is triggered at price %s", orderID, lastPrice))
+		}
+		remainNum -= len(orderIDs)
+		if remainNum == 0 {
+			pendingProducts = append(pendingProducts, product)
+		}
+	}
+	k.SetTriggeredOrderIDs(ctx, triggeredOrderIDs)
+	k.SetPendingTriggerProducts(ctx, pendingProducts)
+}
@@ -1,6 +1,7 @@
 package order
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/cosmos/cosmos-sdk/x/supply"
@@ -228,7 +229,7 @@ func TestEndBlockerPeriodicMatchBusyProduct(t *testing.T) {
 
 	// check block match result
 	result = k.GetBlockMatchResult()
-	require.EqualValues(t, 10, result.ResultMap[types.TestTokenPair].BlockHeight)
+	require.EqualValues(t, 11, result.ResultMap[types.TestTokenPair].BlockHeight)
 	require.EqualValues(t, sdk.MustNewDecFromStr("10.0"), result.ResultMap[types.TestTokenPair].Price)
 	require.EqualValues(t, sdk.MustNewDecFromStr("1.0"), result.ResultMap[types.TestTokenPair].Quantity)
 	require.EqualValues(t, 1, len(result.ResultMap[types.TestTokenPair].Deals))
@@ -351,10 +352,11 @@ func TestEndBlockerExpireOrdersBusyProduct(t *testing.T) {
 	k.SetProductLock(ctx, types.TestTokenPair, lock)
 	EndBlocker(ctx, k)
 
-	// check order
+	// the order of the executing product is deferred, while the sweep goes on
 	order := k.GetOrder(ctx, orders[0].OrderID)
 	require.EqualValues(t, types.OrderStatusOpen, order.Status)
-	require.EqualValues(t, 9+feeParams.OrderExpireBlocks, k.GetLastExpiredBlockHeight(ctx))
+	require.EqualValues(t, 10+feeParams.OrderExpireBlocks, k.GetLastExpiredBlockHeight(ctx))
+	require.EqualValues(t, []string{orders[0].OrderID}, k.GetDeferredExpireOrderIDs(ctx))
 
 	// call EndBlocker at 86400 + 11, unlock product
 	ctx = mapp.BaseApp.NewContext(false, abci.Header{}).
@@ -366,6 +368,7 @@ func TestEndBlockerExpireOrdersBusyProduct(t *testing.T) {
 	order = k.GetOrder(ctx, orders[0].OrderID)
 	require.EqualValues(t, types.OrderStatusExpired, order.Status)
 	require.EqualValues(t, 11+feeParams.OrderExpireBlocks, k.GetLastExpiredBlockHeight(ctx))
+	require.EqualValues(t, 0, len(k.GetDeferredExpireOrderIDs(ctx)))
 }
 
 func TestEndBlockerExpireOrders(t *testing.T) {
@@ -476,6 +479,53 @@ func TestEndBlockerExpireOrders(t *testing.T) {
 	require.EqualValues(t, "0.51840000"+common.NativeToken, collectedFees.String())
 }
 
+func TestExpireBlockOrdersLimit(t *testing.T) {
+	mapp, addrKeysSlice := getMockApp(t, 1)
+	k := mapp.orderKeeper
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(10)
+	mapp.supplyKeeper.SetSupply(ctx, supply.NewSupply(mapp.TotalCoinsSupply))
+	feeParams := types.DefaultParams()
+	mapp.orderKeeper.SetParams(ctx, &feeParams)
+	err := mapp.dexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair())
+	require.Nil(t, err)
+
+	var orders []*types.Order
+	for i := 0; i < 3; i++ {
+		order := types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0")
+		order.Sender = addrKeysSlice[0].Address
+		require.NoError(t, k.PlaceOrder(ctx, order))
+		orders = append(orders, order)
+	}
+	require.EqualValues(t, []int64{10}, k.GetExpireBlockHeight(ctx, 10+feeParams.OrderExpireBlocks))
+
+	// the orders are expired in several rounds
+	ctx = ctx.WithBlockHeight(10 + feeParams.OrderExpireBlocks)
+	logger := ctx.Logger()
+	var deferredOrderIDs []string
+	handledNum, finished := expireBlockOrders(ctx, k, 10, 2, &deferredOrderIDs, logger)
+	require.EqualValues(t, 2, handledNum)
+	require.False(t, finished)
+	require.EqualValues(t, types.OrderStatusExpired, k.GetOrder(ctx, orders[0].OrderID).Status)
+	require.EqualValues(t, types.OrderStatusOpen, k.GetOrder(ctx, orders[2].OrderID).Status)
+
+	// expired orders left behind are dropped
+	handledNum, finished = expireBlockOrders(ctx, k, 10, 2, &deferredOrderIDs, logger)
+	require.EqualValues(t, 2, handledNum)
+	require.False(t, finished)
+	require.Nil(t, k.GetOrder(ctx, orders[0].OrderID))
+
+	handledNum, finished = expireBlockOrders(ctx, k, 10, 2, &deferredOrderIDs, logger)
+	require.EqualValues(t, 1, handledNum)
+	require.True(t, finished)
+	require.EqualValues(t, types.OrderStatusExpired, k.GetOrder(ctx, orders[2].OrderID).Status)
+
+	// no order is handled when the limit of this block is used up
+	handledNum, finished = expireBlockOrders(ctx, k, 10, 0, &deferredOrderIDs, logger)
+	require.EqualValues(t, 0, handledNum)
+	require.False(t, finished)
+}
+
 func TestEndBlockerCleanupOrdersWhoseTokenPairHaveBeenDelisted(t *testing.T) {
 	mapp, addrKeysSlice := getMockApp(t, 2)
 	k := mapp.orderKeeper
@@ -547,3 +597,192 @@ func TestEndBlockerCleanupOrdersWhoseTokenPairHaveBeenDelisted(t *testing.T) {
 	collectedFees := feeCollector.GetCoins()
 	require.EqualValues(t, "", collectedFees.String())
 }
+
+func TestEndBlockerTriggerOrders(t *testing.T) {
+	mapp, addrKeysSlice := getMockApp(t, 3)
+	k := mapp.orderKeeper
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+
+	var startHeight int64 = 10
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(startHeight)
+	mapp.supplyKeeper.SetSupply(ctx, supply.NewSupply(mapp.TotalCoinsSupply))
+
+	feeParams := types.DefaultParams()
+	mapp.orderKeeper.SetParams(ctx, &feeParams)
+
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := mapp.dexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	placeOrders := func(orders ...*types.Order) {
+		for _, order := range orders {
+			require.NoError(t, k.PlaceOrder(ctx, order))
+		}
+	}
+	newTriggerOrder := func(side, price, triggerType, triggerPrice string) *types.Order {
+		order := types.MockOrder("", types.TestTokenPair, side, price, "1.0")
+		order.Trigger = &types.OrderTrigger{Type: triggerType, Price: sdk.MustNewDecFromStr(triggerPrice)}
+		return order
+	}
+
+	// the stop-limit buy order and the take-profit sell order stay out of the depth book
+	triggerOrders := []*types.Order{
+		newTriggerOrder(types.BuyOrder, "11.0", types.TriggerTypeStopLimit, "10.5"),
+		newTriggerOrder(types.SellOrder, "12.0", types.TriggerTypeTakeProfit, "12.0"),
+	}
+	triggerOrders[0].Sender = addrKeysSlice[0].Address
+	triggerOrders[1].Sender = addrKeysSlice[1].Address
+	orders := []*types.Order{
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "11.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+	}
+	orders[0].Sender = addrKeysSlice[1].Address
+	orders[1].Sender = addrKeysSlice[1].Address
+	orders[2].Sender = addrKeysSlice[2].Address
+	placeOrders(append(triggerOrders, orders...)...)
+	EndBlocker(ctx, k)
+
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.0"), k.GetLastPrice(ctx, types.TestTokenPair))
+	require.EqualValues(t, types.OrderStatusUntriggered, k.GetOrder(ctx, triggerOrders[0].OrderID).Status)
+	require.EqualValues(t, 2, len(k.GetProductTriggerOrderIDs(ctx, types.TestTokenPair)))
+	depthBook := k.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 1, len(depthBook.Items))
+	// coins of the trigger order are locked: 100 - 11 - 0.2592
+	acc0 := mapp.AccountKeeper.GetAccount(ctx, addrKeysSlice[0].Address)
+	require.EqualValues(t, sdk.MustNewDecFromStr("88.7408"), acc0.GetCoins().AmountOf(common.NativeToken))
+
+	// the last price rises to 11 and triggers the stop-limit buy order
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	ctx = mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(startHeight + 1)
+	order := types.MockOrder("", types.TestTokenPair, types.BuyOrder, "11.0", "0.5")
+	order.Sender = addrKeysSlice[2].Address
+	placeOrders(order)
+	EndBlocker(ctx, k)
+
+	require.EqualValues(t, sdk.MustNewDecFromStr("11.0"), k.GetLastPrice(ctx, types.TestTokenPair))
+	require.EqualValues(t, types.OrderStatusOpen, k.GetOrder(ctx, triggerOrders[0].OrderID).Status)
+	require.EqualValues(t, []string{triggerOrders[0].OrderID}, k.GetTriggeredOrderIDs(ctx))
+	require.EqualValues(t, []string{triggerOrders[1].OrderID}, k.GetProductTriggerOrderIDs(ctx, types.TestTokenPair))
+
+	// the triggered order is matched in the next block
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	ctx = mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(startHeight + 2)
+	EndBlocker(ctx, k)
+
+	triggeredOrder := k.GetOrder(ctx, triggerOrders[0].OrderID)
+	require.EqualValues(t, types.OrderStatusOpen, triggeredOrder.Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.5"), triggeredOrder.RemainQuantity)
+	require.EqualValues(t, 0, len(k.GetTriggeredOrderIDs(ctx)))
+
+	// the untriggered order can be cancelled
+	k.CancelOrder(ctx, triggerOrders[1], ctx.Logger())
+	require.EqualValues(t, types.OrderStatusCancelled, k.GetOrder(ctx, triggerOrders[1].OrderID).Status)
+	require.EqualValues(t, 0, len(k.GetProductTriggerOrderIDs(ctx, types.TestTokenPair)))
+}
+
+type mockOrderHooks struct {
+	events []string
+}
+
+func (h *mockOrderHooks) AfterOrderPlaced(ctx sdk.Context, order *types.Order) {
+	h.events = append(h.events, fmt.Sprintf("placed %s", order.OrderID))
+}
+
+func (h *mockOrderHooks) AfterOrderFilled(ctx sdk.Context, order *types.Order, price sdk.Dec, deal types.Deal) {
+	h.events = append(h.events, fmt.Sprintf("filled %s %s at %s", order.OrderID, deal.Quantity, price))
+}
+
+func (h *mockOrderHooks) AfterOrderCancelled(ctx sdk.Context, order *types.Order) {
+	h.events = append(h.events, fmt.Sprintf("cancelled %s", order.OrderID))
+}
+
+func (h *mockOrderHooks) AfterOrderExpired(ctx sdk.Context, order *types.Order) {
+	h.events = append(h.events, fmt.Sprintf("expired %s", order.OrderID))
+}
+
+func TestEndBlockerOrderHooks(t *testing.T) {
+	mapp, addrKeysSlice := getMockApp(t, 2)
+	k := mapp.orderKeeper
+	hooks := &mockOrderHooks{}
+	k.SetHooks(NewMultiOrderHooks(hooks))
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(10)
+	mapp.supplyKeeper.SetSupply(ctx, supply.NewSupply(mapp.TotalCoinsSupply))
+	feeParams := types.DefaultParams()
+	k.SetParams(ctx, &feeParams)
+	require.Nil(t, mapp.dexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair()))
+
+	orders := []*types.Order{
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "0.5"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "2.5"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "12.0", "1.0"),
+	}
+	for i, order := range orders {
+		order.Sender = addrKeysSlice[i%2].Address
+		require.NoError(t, k.PlaceOrder(ctx, order))
+	}
+	EndBlocker(ctx, k)
+	k.CancelOrder(ctx, orders[2], ctx.Logger())
+	k.ExpireOrder(ctx, orders[3], ctx.Logger())
+
+	require.EqualValues(t, []string{
+		"placed ID0000000010-1",
+		"placed ID0000000010-2",
+		"placed ID0000000010-3",
+		"placed ID0000000010-4",
+		"filled ID0000000010-1 1.00000000 at 10.00000000",
+		"filled ID0000000010-2 0.50000000 at 10.00000000",
+		"filled ID0000000010-3 0.50000000 at 10.00000000",
+		"cancelled ID0000000010-3",
+		"expired ID0000000010-4",
+	}, hooks.events)
+}
+
+func TestEndBlockerOrderEvents(t *testing.T) {
+	mapp, addrKeysSlice := getMockApp(t, 2)
+	k := mapp.orderKeeper
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(10)
+	mapp.supplyKeeper.SetSupply(ctx, supply.NewSupply(mapp.TotalCoinsSupply))
+	feeParams := types.DefaultParams()
+	feeParams.OrderExpireBlocks = 1
+	k.SetParams(ctx, &feeParams)
+	require.Nil(t, mapp.dexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair()))
+
+	orders := []*types.Order{
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "0.5"),
+	}
+	for i, order := range orders {
+		order.Sender = addrKeysSlice[i].Address
+		require.NoError(t, k.PlaceOrder(ctx, order))
+	}
+
+	// the orders are matched in the EndBlock
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	EndBlocker(ctx, k)
+	eventOrderIDs := make(map[string][]string)
+	for _, event := range ctx.EventManager().Events() {
+		for _, attribute := range event.Attributes {
+			if string(attribute.Key) == types.AttributeKeyOrderID {
+				eventOrderIDs[event.Type] = append(eventOrderIDs[event.Type], string(attribute.Value))
+			}
+		}
+	}
+	require.EqualValues(t, []string{orders[0].OrderID}, eventOrderIDs[types.EventTypePartialFillOrder])
+	require.EqualValues(t, []string{orders[1].OrderID}, eventOrderIDs[types.EventTypeFullFillOrder])
+
+	// the remaining order is expired in the EndBlock
+	ctx = ctx.WithBlockHeight(11).WithEventManager(sdk.NewEventManager())
+	k.ResetCache(ctx)
+	EndBlocker(ctx, k)
+	var expiredOrderIDs []string
+	for _, event := range ctx.EventManager().Events() {
+		if event.Type == types.EventTypeExpireOrder {
+			expiredOrderIDs = append(expiredOrderIDs, string(event.Attributes[0].Value))
+		}
+	}
+	require.EqualValues(t, []string{orders[0].OrderID}, expiredOrderIDs)
+}
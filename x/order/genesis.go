@@ -12,6 +12,11 @@ import (
 type GenesisState struct {
 	Params     types.Params   `json:"params"`
 	OpenOrders []*types.Order `json:"open_orders"`
+	// closed orders retained for ClosedOrderRetentionBlocks blocks, which are retained again from the genesis height
+	ClosedOrders []*types.Order `json:"closed_orders,omitempty"`
+	// volumes traded by accounts in the rolling window of the fee tiers, which the trailing traded volumes are
+	// rebuilt from
+	BlockTradedVolumes []types.BlockTradedVolume `json:"block_traded_volumes,omitempty"`
 }
 
 // DefaultGenesisState - default GenesisState used by Cosmos Hub
@@ -24,6 +29,14 @@ func DefaultGenesisState() GenesisState {
 
 // ValidateGenesis validates the slashing genesis parameters
 func ValidateGenesis(data GenesisState) error {
+	if err := data.Params.Validate(); err != nil {
+		return err
+	}
+	for _, volume := range data.BlockTradedVolumes {
+		if volume.Address.Empty() || !volume.Volume.IsPositive() {
+			return fmt.Errorf("invalid block traded volume: %s", volume)
+		}
+	}
 	return nil
 }
 
@@ -34,25 +47,42 @@ func InitGenesis(ctx sdk.Context, keeper keeper.Keeper, data GenesisState) {
 
 	// reset open order& depth book
 	for _, order := range data.OpenOrders {
-		height := types.GetBlockHeightFromOrderID(order.OrderID)
-
-		futureHeight := height + data.Params.OrderExpireBlocks
-		futureExpireHeightList := keeper.GetExpireBlockHeight(ctx, futureHeight)
-		futureExpireHeightList = append(futureExpireHeightList, height)
-		keeper.SetExpireBlockHeight(ctx, futureHeight, futureExpireHeightList)
-
-		orderNum := keeper.GetBlockOrderNum(ctx, height)
-		keeper.SetBlockOrderNum(ctx, height, orderNum+1)
+		initBlockOrder(ctx, keeper, data.Params, order)
 		keeper.SetOrder(ctx, order.OrderID, order)
+		keeper.InsertSenderOrderID(ctx, order)
 
+		if order.Status == types.OrderStatusUntriggered {
+			keeper.InsertTriggerOrder(ctx, order)
+			continue
+		}
 		// update depth book and orderIDsMap in cache
 		keeper.InsertOrderIntoDepthBook(order)
 	}
-	if len(data.OpenOrders) > 0 {
+	for _, order := range data.ClosedOrders {
+		initBlockOrder(ctx, keeper, data.Params, order)
+		keeper.InsertClosedOrder(ctx, order)
+	}
+	for _, volume := range data.BlockTradedVolumes {
+		keeper.AddBlockTradedVolume(ctx, volume)
+	}
+	if len(data.OpenOrders) > 0 || len(data.ClosedOrders) > 0 {
 		keeper.Cache2Disk(ctx)
 	}
 }
 
+// initBlockOrder counts an order from genesis in the orders of the block it's placed at, which expire together
+func initBlockOrder(ctx sdk.Context, keeper keeper.Keeper, params types.Params, order *types.Order) {
+	height := types.GetBlockHeightFromOrderID(order.OrderID)
+
+	futureHeight := height + params.OrderExpireBlocks
+	futureExpireHeightList := keeper.GetExpireBlockHeight(ctx, futureHeight)
+	futureExpireHeightList = append(futureExpireHeightList, height)
+	keeper.SetExpireBlockHeight(ctx, futureHeight, futureExpireHeightList)
+
+	orderNum := keeper.GetBlockOrderNum(ctx, height)
+	keeper.SetBlockOrderNum(ctx, height, orderNum+1)
+}
+
 // ExportGenesis writes the current store values
 // to a genesis file, which can be imported again
 // with InitGenesis
@@ -84,6 +114,7 @@ func ExportGenesis(ctx sdk.Context, keeper keeper.Keeper) (data GenesisState) {
 				openIDs = append(openIDs, ids...)
 			}
 		}
+		openIDs = append(openIDs, keeper.GetProductTriggerOrderIDs(ctx, product)...)
 
 		for _, orderID := range openIDs {
 			order := keeper.GetOrder(ctx, orderID)
@@ -104,7 +135,9 @@ func ExportGenesis(ctx sdk.Context, keeper keeper.Keeper) (data GenesisState) {
 	}
 
 	return GenesisState{
-		Params:     *params,
-		OpenOrders: openOrders,
+		Params:             *params,
+		OpenOrders:         openOrders,
+		ClosedOrders:       keeper.GetRetainedClosedOrders(ctx),
+		BlockTradedVolumes: keeper.GetBlockTradedVolumes(ctx),
 	}
 }
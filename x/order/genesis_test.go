@@ -78,6 +78,8 @@ func TestExportGenesis(t *testing.T) {
 	require.Equal(t, int64(1), orderKeeper.GetOpenOrderNum(ctx))
 	// 0x20
 	require.Equal(t, int64(1), orderKeeper.GetStoreOrderNum(ctx))
+	// 0x23
+	require.Equal(t, []string{order1.OrderID}, orderKeeper.GetSenderOrderIDs(ctx, order1.Sender, ""))
 
 	exportGenesis := ExportGenesis(ctx, orderKeeper)
 	require.Equal(t, params, exportGenesis.Params)
@@ -85,7 +87,8 @@ func TestExportGenesis(t *testing.T) {
 
 	params.MaxDealsPerBlock = 1
 	params.FeePerBlock = sdk.NewDecCoinFromDec(common.NativeToken, sdk.OneDec())
-	params.TradeFeeRate = sdk.NewDec(1000)
+	params.MakerFeeRate = sdk.NewDec(1000)
+	params.TakerFeeRate = sdk.NewDec(1000)
 	params.OrderExpireBlocks = 3333
 	orderKeeper.SetParams(ctx, &params)
 
@@ -148,3 +151,82 @@ func TestExportGenesis(t *testing.T) {
 	// 0x20
 	require.Equal(t, int64(2), newOrderKeeper.GetStoreOrderNum(newCtx))
 }
+
+func TestExportGenesisClosedOrders(t *testing.T) {
+	testInput := keeper.CreateTestInput(t)
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	orderKeeper := testInput.OrderKeeper
+	require.NoError(t, testInput.DexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair()))
+	params := types.DefaultParams()
+	params.ClosedOrderRetentionBlocks = 10
+	orderKeeper.SetParams(ctx, &params)
+
+	orders := []*types.Order{
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "11.0", "1.0"),
+	}
+	for _, order := range orders {
+		order.Sender = testInput.TestAddrs[0]
+		require.NoError(t, orderKeeper.PlaceOrder(ctx, order))
+	}
+	orderKeeper.CancelOrder(ctx, orders[0], ctx.Logger())
+	orderKeeper.Cache2Disk(ctx)
+
+	exportGenesis := ExportGenesis(ctx.WithBlockHeight(15), orderKeeper)
+	require.Equal(t, []*types.Order{orders[1]}, exportGenesis.OpenOrders)
+	require.Equal(t, []*types.Order{orderKeeper.GetOrder(ctx, orders[0].OrderID)}, exportGenesis.ClosedOrders)
+
+	newTestInput := keeper.CreateTestInput(t)
+	newCtx := newTestInput.Ctx.WithBlockHeight(1)
+	newOrderKeeper := newTestInput.OrderKeeper
+	require.NoError(t, newTestInput.DexKeeper.SaveTokenPair(newCtx, dex.GetBuiltInTokenPair()))
+	InitGenesis(newCtx, newOrderKeeper, exportGenesis)
+	require.Equal(t, exportGenesis.ClosedOrders[0], newOrderKeeper.GetOrder(newCtx, orders[0].OrderID))
+	require.Equal(t, int64(2), newOrderKeeper.GetBlockOrderNum(newCtx, 10))
+	require.Equal(t, int64(1), newOrderKeeper.GetOpenOrderNum(newCtx))
+	require.Equal(t, int64(2), newOrderKeeper.GetStoreOrderNum(newCtx))
+	// the closed orders are retained again from the genesis height
+	require.Equal(t, exportGenesis.ClosedOrders, newOrderKeeper.GetRetainedClosedOrders(newCtx.WithBlockHeight(10)))
+
+	// the closed orders out of the retention are not exported
+	exportGenesis = ExportGenesis(ctx.WithBlockHeight(20), orderKeeper)
+	require.Len(t, exportGenesis.ClosedOrders, 0)
+}
+
+func TestExportGenesisTradedVolumes(t *testing.T) {
+	testInput := keeper.CreateTestInput(t)
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	orderKeeper := testInput.OrderKeeper
+	addr0, addr1 := testInput.TestAddrs[0], testInput.TestAddrs[1]
+
+	volumes := []types.BlockTradedVolume{
+		{BlockHeight: 8, Address: addr0, Volume: sdk.NewDec(3)},
+		{BlockHeight: 9, Address: addr0, Volume: sdk.NewDec(5)},
+		{BlockHeight: 9, Address: addr1, Volume: sdk.NewDec(7)},
+	}
+	for _, volume := range volumes {
+		orderKeeper.AddBlockTradedVolume(ctx, volume)
+	}
+	exportGenesis := ExportGenesis(ctx, orderKeeper)
+	require.Len(t, exportGenesis.BlockTradedVolumes, 3)
+	require.NoError(t, ValidateGenesis(exportGenesis))
+
+	newTestInput := keeper.CreateTestInput(t)
+	newCtx := newTestInput.Ctx.WithBlockHeight(10)
+	newOrderKeeper := newTestInput.OrderKeeper
+	InitGenesis(newCtx, newOrderKeeper, exportGenesis)
+	require.EqualValues(t, sdk.NewDec(8), newOrderKeeper.GetTradedVolume(newCtx, addr0))
+	require.EqualValues(t, sdk.NewDec(7), newOrderKeeper.GetTradedVolume(newCtx, addr1))
+	require.Equal(t, exportGenesis.BlockTradedVolumes, newOrderKeeper.GetBlockTradedVolumes(newCtx))
+
+	// the restored volumes still leave the rolling window at the blocks they were traded at
+	params := newOrderKeeper.GetParams(newCtx)
+	params.VolumeWindowBlocks = 2
+	newOrderKeeper.SetParams(newCtx, params)
+	newOrderKeeper.DropExpiredTradedVolumes(newCtx)
+	require.EqualValues(t, sdk.NewDec(5), newOrderKeeper.GetTradedVolume(newCtx, addr0))
+	require.EqualValues(t, sdk.NewDec(7), newOrderKeeper.GetTradedVolume(newCtx, addr1))
+
+	exportGenesis.BlockTradedVolumes[0].Volume = sdk.ZeroDec()
+	require.Error(t, ValidateGenesis(exportGenesis))
+}
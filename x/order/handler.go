@@ -3,6 +3,7 @@ package order
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/pkg/errors"
@@ -10,6 +11,7 @@ import (
 	"github.com/tendermint/tendermint/libs/log"
 
 	"github.com/okex/okchain/x/common/perf"
+	dex "github.com/okex/okchain/x/dex/types"
 	"github.com/okex/okchain/x/order/keeper"
 	"github.com/okex/okchain/x/order/types"
 )
@@ -32,6 +34,16 @@ func NewOrderHandler(keeper keeper.Keeper) sdk.Handler {
 			handlerFun = func() sdk.Result {
 				return handleMsgCancelOrders(ctx, keeper, msg, logger)
 			}
+		case types.MsgCancelAllOrders:
+			name = "handleMsgCancelAllOrders"
+			handlerFun = func() sdk.Result {
+				return handleMsgCancelAllOrders(ctx, keeper, msg, logger)
+			}
+		case types.MsgAmendOrder:
+			name = "handleMsgAmendOrder"
+			handlerFun = func() sdk.Result {
+				return handleMsgAmendOrder(ctx, keeper, msg, logger)
+			}
 		default:
 			errMsg := fmt.Sprintf("Invalid msg type: %v", msg.Type())
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -58,43 +70,168 @@ func checkOrderNewMsg(ctx sdk.Context, keeper keeper.Keeper, msg types.MsgNewOrd
 		return errors.Errorf("trading pair '%s' is delisting", msg.Product)
 	}
 
+	if msg.ClientOrderID != "" {
+		orderID := keeper.GetOrderIDByClientOrderID(ctx, msg.Sender, msg.ClientOrderID)
+		if order := keeper.GetOrder(ctx, orderID); order != nil &&
+			(order.Status == types.OrderStatusOpen || order.Status == types.OrderStatusUntriggered) {
+			return fmt.Errorf("client order id(%s) is used by the open order(%s)", msg.ClientOrderID, orderID)
+		}
+	}
+
 	priceDigit := tokenPair.MaxPriceDigit
 	quantityDigit := tokenPair.MaxQuantityDigit
-	roundedPrice := msg.Price.RoundDecimal(priceDigit)
-	roundedQuantity := msg.Quantity.RoundDecimal(quantityDigit)
-	if !roundedPrice.Equal(msg.Price) {
-		return fmt.Errorf("price(%v) over accuracy(%d)", msg.Price, priceDigit)
+	if msg.OrderType == types.OrderTypeMarket {
+		// the worst price of a market order is rounded, the amount of a market buy order is checked instead
+		if msg.Side == types.BuyOrder && !msg.Quantity.RoundDecimal(priceDigit).Equal(msg.Quantity) {
+			return fmt.Errorf("amount(%v) over accuracy(%d)", msg.Quantity, priceDigit)
+		}
+		if msg.Side == types.SellOrder && !msg.Quantity.RoundDecimal(quantityDigit).Equal(msg.Quantity) {
+			return fmt.Errorf("quantity(%v) over accuracy(%d)", msg.Quantity, quantityDigit)
+		}
+		price, quantity, err := getMarketOrderPriceAndQuantity(ctx, keeper, msg, tokenPair)
+		if err != nil {
+			return err
+		}
+		if quantity.LT(tokenPair.MinQuantity) {
+			return fmt.Errorf("quantity should be greater than %s", tokenPair.MinQuantity)
+		}
+		return keeper.CheckPriceBand(ctx, msg.Product, price)
 	}
-	if !roundedQuantity.Equal(msg.Quantity) {
-		return fmt.Errorf("quantity(%v) over accuracy(%d)", msg.Quantity, quantityDigit)
+
+	if msg.TriggerType != "" && !msg.TriggerPrice.RoundDecimal(priceDigit).Equal(msg.TriggerPrice) {
+		return fmt.Errorf("trigger price(%v) over accuracy(%d)", msg.TriggerPrice, priceDigit)
+	}
+	if msg.DisplayQuantity.Int != nil && msg.DisplayQuantity.IsPositive() {
+		if !msg.DisplayQuantity.RoundDecimal(quantityDigit).Equal(msg.DisplayQuantity) {
+			return fmt.Errorf("display quantity(%v) over accuracy(%d)", msg.DisplayQuantity, quantityDigit)
+		}
+		if msg.DisplayQuantity.LT(tokenPair.MinQuantity) {
+			return fmt.Errorf("display quantity should be greater than %s", tokenPair.MinQuantity)
+		}
+	}
+	return keeper.ValidateOrderPriceQuantity(ctx, tokenPair, msg.Price, msg.Quantity)
+}
+
+// getMarketOrderPriceAndQuantity returns the worst acceptable price of a market order, which is given by the msg or
+// derived from the max slippage and the best ask/bid, rounded to the price accuracy in favor of the sender.
+// The quantity of a market buy order is what its amount affords at the worst price, which grows as it's filled at
+// better prices.
+func getMarketOrderPriceAndQuantity(ctx sdk.Context, k keeper.Keeper, msg types.MsgNewOrder,
+	tokenPair *dex.TokenPair) (price, quantity sdk.Dec, err error) {
+
+	price = msg.Price
+	if msg.MaxSlippage.Int != nil && msg.MaxSlippage.IsPositive() {
+		bestBid, bestAsk := k.GetBestBidAndAsk(ctx, msg.Product)
+		if msg.Side == types.BuyOrder {
+			price = bestAsk.Mul(sdk.OneDec().Add(msg.MaxSlippage))
+		} else {
+			price = bestBid.Mul(sdk.OneDec().Sub(msg.MaxSlippage))
+		}
+	}
+	price = roundDecimal(price, tokenPair.MaxPriceDigit, msg.Side == types.SellOrder)
+	if !price.IsPositive() {
+		return price, sdk.ZeroDec(), fmt.Errorf("no orders to take in the depth book of %s", msg.Product)
 	}
 
-	if msg.Quantity.LT(tokenPair.MinQuantity) {
-		return fmt.Errorf("quantity should be greater than %s", tokenPair.MinQuantity)
+	quantity = msg.Quantity
+	if msg.Side == types.BuyOrder {
+		quantity = roundDecimal(msg.Quantity.QuoTruncate(price), tokenPair.MaxQuantityDigit, false)
 	}
-	var d int64 = 100000000
-	baseQuantity := msg.Price.Mul(msg.Quantity)
-	if !msg.Price.MulInt64(d).Mul(msg.Quantity).Equal(baseQuantity.MulInt64(d)) {
-		return fmt.Errorf("price(%v) * quantity(%v) over accuracy(%d)", msg.Price, msg.Quantity, priceDigit)
+	return price, quantity, nil
+}
+
+// roundDecimal rounds a decimal down or up to the digits
+func roundDecimal(d sdk.Dec, digits int64, up bool) sdk.Dec {
+	unit := sdk.NewDecWithPrec(1, digits)
+	rounded := d.QuoTruncate(unit).TruncateDec().Mul(unit)
+	if up && rounded.LT(d) {
+		rounded = rounded.Add(unit)
 	}
-	return nil
+	return rounded
 }
 
 func getOrderFromMsg(ctx sdk.Context, k keeper.Keeper, msg types.MsgNewOrder, ratio string) *types.Order {
 	feeParams := k.GetParams(ctx)
 	feePerBlockAmount := feeParams.FeePerBlock.Amount.Mul(sdk.MustNewDecFromStr(ratio))
 	feePerBlock := sdk.NewDecCoinFromDec(feeParams.FeePerBlock.Denom, feePerBlockAmount)
-	return types.NewOrder(
+	price, quantity := msg.Price, msg.Quantity
+	if msg.OrderType == types.OrderTypeMarket {
+		// the order of an invalid msg is never placed
+		price, quantity = sdk.ZeroDec(), sdk.ZeroDec()
+		if tokenPair := k.GetDexKeeper().GetTokenPair(ctx, msg.Product); tokenPair != nil {
+			price, quantity, _ = getMarketOrderPriceAndQuantity(ctx, k, msg, tokenPair)
+		}
+	}
+	order := types.NewOrder(
 		fmt.Sprintf("%X", tmhash.Sum(ctx.TxBytes())),
 		msg.Sender,
 		msg.Product,
 		msg.Side,
-		msg.Price,
-		msg.Quantity,
+		price,
+		quantity,
 		ctx.BlockHeader().Time.Unix(),
 		feeParams.OrderExpireBlocks,
 		feePerBlock,
 	)
+	order.TimeInForce = msg.TimeInForce
+	if msg.DisplayQuantity.Int != nil && msg.DisplayQuantity.IsPositive() {
+		displayQuantity := msg.DisplayQuantity
+		order.DisplayQuantity = &displayQuantity
+	}
+	if msg.OrderType == types.OrderTypeMarket {
+		// a market order never rests in the depth book
+		order.OrderType = msg.OrderType
+		order.TimeInForce = types.TimeInForceIOC
+		if msg.Side == types.BuyOrder {
+			// a market buy order locks and spends its whole amount
+			order.SpendQuoteAmount(msg.Quantity)
+		}
+	}
+	order.ClientOrderID = msg.ClientOrderID
+	order.SelfTradePrevention = msg.SelfTradePrevention
+	if order.SelfTradePrevention == "" {
+		order.SelfTradePrevention = feeParams.SelfTradePrevention
+	}
+	order.NativeFee = msg.NativeFee
+	if msg.TriggerType != "" {
+		order.Trigger = &types.OrderTrigger{Type: msg.TriggerType, Price: msg.TriggerPrice}
+	}
+	return order
+}
+
+// getMsgFromItem returns the msg of a single new order from an order item of MsgNewOrders
+func getMsgFromItem(sender sdk.AccAddress, item types.OrderItem) MsgNewOrder {
+	return MsgNewOrder{
+		Sender:              sender,
+		Product:             item.Product,
+		Side:                item.Side,
+		Price:               item.Price,
+		Quantity:            item.Quantity,
+		TimeInForce:         item.TimeInForce,
+		TriggerType:         item.TriggerType,
+		TriggerPrice:        item.TriggerPrice,
+		ClientOrderID:       item.ClientOrderID,
+		OrderType:           item.OrderType,
+		MaxSlippage:         item.MaxSlippage,
+		DisplayQuantity:     item.DisplayQuantity,
+		SelfTradePrevention: item.SelfTradePrevention,
+		NativeFee:           item.NativeFee,
+	}
+}
+
+// checkNewOrder checks whether a new order can be placed after the pending ones, and returns the code of the failure
+func checkNewOrder(ctx sdk.Context, k Keeper, msg MsgNewOrder, pendingNum int64) (sdk.CodeType, sdk.CodespaceType,
+	error) {
+	if err := checkOrderNewMsg(ctx, k, msg); err != nil {
+		return sdk.CodeUnknownRequest, "", err
+	}
+	if k.IsProductLocked(msg.Product) {
+		return sdk.CodeInternal, "", fmt.Errorf("the trading pair (%s) is locked, please retry later", msg.Product)
+	}
+	if err := k.CheckOrderLimits(ctx, msg.Sender, msg.Product, pendingNum); err != nil {
+		return err.Code(), err.Codespace(), err
+	}
+	return sdk.CodeOK, "", nil
 }
 
 func handleNewOrder(ctx sdk.Context, k Keeper, sender sdk.AccAddress,
@@ -102,31 +239,20 @@ func handleNewOrder(ctx sdk.Context, k Keeper, sender sdk.AccAddress,
 
 	cacheItem := ctx.MultiStore().CacheMultiStore()
 	ctxItem := ctx.WithMultiStore(cacheItem)
-	msg := MsgNewOrder{
-		Sender:   sender,
-		Product:  item.Product,
-		Side:     item.Side,
-		Price:    item.Price,
-		Quantity: item.Quantity,
-	}
+	msg := getMsgFromItem(sender, item)
 	order := getOrderFromMsg(ctxItem, k, msg, ratio)
-	code := sdk.CodeOK
-	err := checkOrderNewMsg(ctxItem, k, msg)
-
-	if err != nil {
-		code = sdk.CodeUnknownRequest
-	} else {
-		if k.IsProductLocked(msg.Product) {
-			code = sdk.CodeInternal
-			err = fmt.Errorf("the trading pair (%s) is locked, please retry later", order.Product)
-		} else if err = k.PlaceOrder(ctxItem, order); err != nil {
+	code, codespace, err := checkNewOrder(ctxItem, k, msg, 0)
+	if err == nil {
+		if err = k.PlaceOrder(ctxItem, order); err != nil {
 			code = sdk.CodeInsufficientCoins
 		}
 	}
 
 	res := types.OrderResult{
-		Code:    code,
-		OrderID: order.OrderID,
+		Code:          code,
+		OrderID:       order.OrderID,
+		ClientOrderID: order.ClientOrderID,
+		Codespace:     codespace,
 	}
 
 	if err == nil {
@@ -145,6 +271,72 @@ func handleNewOrder(ctx sdk.Context, k Keeper, sender sdk.AccAddress,
 	return res, cacheItem, err
 }
 
+// tryNewOrder tries placing a new order of an atomic msg, it only locks the coins and the fee of the order without
+// touching the caches of the keeper. The order is indexed as an open order of its sender, so that the limits are
+// checked against the orders tried before it.
+func tryNewOrder(ctx sdk.Context, k Keeper, sender sdk.AccAddress, item types.OrderItem, ratio string,
+	index int) (*types.Order, sdk.DecCoins, types.OrderResult, error) {
+
+	msg := getMsgFromItem(sender, item)
+	order := getOrderFromMsg(ctx, k, msg, ratio)
+	code, codespace, err := checkNewOrder(ctx, k, msg, int64(index))
+	var fee sdk.DecCoins
+	if err == nil {
+		if fee, err = k.TryPlaceOrder(ctx, order); err != nil {
+			code = sdk.CodeInsufficientCoins
+		}
+	}
+	if err != nil {
+		return nil, nil, types.OrderResult{Code: code, Message: err.Error(), ClientOrderID: order.ClientOrderID,
+			Codespace: codespace}, err
+	}
+	order.OrderID = types.FormatOrderID(ctx.BlockHeight(), k.GetBlockOrderNum(ctx, ctx.BlockHeight())+int64(index)+1)
+	k.InsertSenderOrderID(ctx, order)
+	return order, fee, types.OrderResult{}, nil
+}
+
+// handleAtomicNewOrders places all the orders of an atomic msg or none of them. The coins and the fees of all the
+// orders are locked in a cache store first, if one of them fails, the store is discarded before any order reaches
+// the caches of the keeper, and the failure is reported with the others rolled back.
+func handleAtomicNewOrders(ctx sdk.Context, k Keeper, msg types.MsgNewOrders, ratio string,
+	logger log.Logger) []types.OrderResult {
+
+	cacheBatch := ctx.MultiStore().CacheMultiStore()
+	ctxBatch := ctx.WithMultiStore(cacheBatch)
+	orders := make([]*types.Order, 0, len(msg.OrderItems))
+	fees := make([]sdk.DecCoins, 0, len(msg.OrderItems))
+	for i, item := range msg.OrderItems {
+		order, fee, failure, err := tryNewOrder(ctxBatch, k, msg.Sender, item, ratio, i)
+		if err == nil {
+			orders = append(orders, order)
+			fees = append(fees, fee)
+			continue
+		}
+		rollBackErr := types.ErrAtomicOrdersRolledBack(i)
+		rs := make([]types.OrderResult, 0, len(msg.OrderItems))
+		for j, item := range msg.OrderItems {
+			if j == i {
+				rs = append(rs, failure)
+				continue
+			}
+			rs = append(rs, types.OrderResult{Code: rollBackErr.Code(), Message: rollBackErr.Error(),
+				ClientOrderID: item.ClientOrderID, Codespace: rollBackErr.Codespace()})
+		}
+		return rs
+	}
+
+	rs := make([]types.OrderResult, 0, len(orders))
+	for i, order := range orders {
+		k.PlaceTriedOrder(ctxBatch, order, fees[i])
+		logger.Debug(fmt.Sprintf("BlockHeight<%d>, handler<%s>, the user has created an order {ID:%s}",
+			ctx.BlockHeight(), "handleAtomicNewOrders", order.OrderID))
+		rs = append(rs, types.OrderResult{Code: sdk.CodeOK, OrderID: order.OrderID,
+			ClientOrderID: order.ClientOrderID})
+	}
+	cacheBatch.Write()
+	return rs
+}
+
 func handleMsgNewOrders(ctx sdk.Context, k Keeper, msg types.MsgNewOrders,
 	logger log.Logger) sdk.Result {
 	event := sdk.NewEvent(sdk.EventTypeMessage, sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName))
@@ -154,13 +346,18 @@ func handleMsgNewOrders(ctx sdk.Context, k Keeper, msg types.MsgNewOrders,
 		ratio = "0.8"
 	}
 
-	rs := make([]types.OrderResult, 0, len(msg.OrderItems))
-	for _, item := range msg.OrderItems {
-		res, cacheItem, err := handleNewOrder(ctx, k, msg.Sender, item, ratio, logger)
-		if err == nil {
-			cacheItem.Write()
+	var rs []types.OrderResult
+	if msg.Atomic {
+		rs = handleAtomicNewOrders(ctx, k, msg, ratio, logger)
+	} else {
+		rs = make([]types.OrderResult, 0, len(msg.OrderItems))
+		for _, item := range msg.OrderItems {
+			res, cacheItem, err := handleNewOrder(ctx, k, msg.Sender, item, ratio, logger)
+			if err == nil {
+				cacheItem.Write()
+			}
+			rs = append(rs, res)
 		}
-		rs = append(rs, res)
 	}
 	rss, err := json.Marshal(&rs)
 	if err != nil {
@@ -181,13 +378,7 @@ func ValidateMsgNewOrders(ctx sdk.Context, k keeper.Keeper, msg types.MsgNewOrde
 	}
 
 	for _, item := range msg.OrderItems {
-		msg := MsgNewOrder{
-			Sender:   msg.Sender,
-			Product:  item.Product,
-			Side:     item.Side,
-			Price:    item.Price,
-			Quantity: item.Quantity,
-		}
+		msg := getMsgFromItem(msg.Sender, item)
 		err := checkOrderNewMsg(ctx, k, msg)
 		if err != nil {
 			return sdk.Result{
@@ -202,6 +393,10 @@ func ValidateMsgNewOrders(ctx sdk.Context, k keeper.Keeper, msg types.MsgNewOrde
 			}
 		}
 
+		if err := k.CheckOrderLimits(ctx, msg.Sender, msg.Product, 0); err != nil {
+			return err.Result()
+		}
+
 		order := getOrderFromMsg(ctx, k, msg, ratio)
 		_, err = k.TryPlaceOrder(ctx, order)
 		if err != nil {
@@ -235,6 +430,7 @@ func handleCancelOrder(context sdk.Context, k Keeper, sender sdk.AccAddress, ord
 	} else {
 		// cancel order
 		order := k.GetOrder(ctx, orderID)
+		k.BurnEarlyCancelledDeposit(ctx, order, logger)
 		fee := k.CancelOrder(ctx, order, logger)
 		message = fee.String()
 	}
@@ -263,6 +459,28 @@ func handleMsgCancelOrders(ctx sdk.Context, k Keeper, msg types.MsgCancelOrders,
 			msg.Sender, orderID, orderID))
 
 	}
+	for _, clientOrderID := range msg.ClientOrderIDs {
+		orderID := k.GetOrderIDByClientOrderID(ctx, msg.Sender, clientOrderID)
+		if orderID == "" {
+			cancelRes = append(cancelRes, types.OrderResult{
+				Code:          sdk.CodeUnknownRequest,
+				Message:       fmt.Sprintf("client order id(%s) does not exist", clientOrderID),
+				ClientOrderID: clientOrderID,
+			})
+			continue
+		}
+
+		res, cacheItem := handleCancelOrder(ctx, k, msg.Sender, orderID, logger)
+		res.ClientOrderID = clientOrderID
+		cancelRes = append(cancelRes, res)
+		cacheItem.Write()
+
+		logger.Debug(fmt.Sprintf("BlockHeight<%d>, handler<%s>\n"+
+			"    msg<Sender:%s,ClientID:%s>\n"+
+			"    result<The User have canceled an order {ID:%s} >\n",
+			ctx.BlockHeight(), "handleMsgCancelOrder",
+			msg.Sender, clientOrderID, orderID))
+	}
 	rss, err := json.Marshal(&cancelRes)
 	if err != nil {
 		rss = []byte(fmt.Sprintf("failed to marshal result to JSON: %s", err))
@@ -286,7 +504,7 @@ func validateCancelOrder(ctx sdk.Context, keeper keeper.Keeper, msg types.MsgCan
 			Log:  fmt.Sprintf("order(%s) does not exist or already closed", msg.OrderID),
 		}
 	}
-	if order.Status != types.OrderStatusOpen {
+	if order.Status != types.OrderStatusOpen && order.Status != types.OrderStatusUntriggered {
 		return sdk.Result{
 			Code: sdk.CodeInternal,
 			Log:  fmt.Sprintf("cannot cancel order with status(%d)", order.Status),
@@ -319,6 +537,202 @@ func ValidateMsgCancelOrders(ctx sdk.Context, keeper keeper.Keeper, msg types.Ms
 			return res
 		}
 	}
+	for _, clientOrderID := range msg.ClientOrderIDs {
+		orderID := keeper.GetOrderIDByClientOrderID(ctx, msg.Sender, clientOrderID)
+		if orderID == "" {
+			return sdk.Result{
+				Code: sdk.CodeUnknownRequest,
+				Log:  fmt.Sprintf("client order id(%s) does not exist", clientOrderID),
+			}
+		}
+		res := validateCancelOrder(ctx, keeper, MsgCancelOrder{Sender: msg.Sender, OrderID: orderID})
+		if sdk.CodeOK != res.Code {
+			return res
+		}
+	}
+
+	return sdk.Result{}
+}
+
+func handleMsgCancelAllOrders(ctx sdk.Context, k Keeper, msg types.MsgCancelAllOrders,
+	logger log.Logger) sdk.Result {
+	if res := ValidateMsgCancelAllOrders(ctx, k, msg); !res.IsOK() {
+		return res
+	}
+
+	// a msg cancels as many orders as MsgCancelOrders at most, the rest are left to the next msgs
+	orderIDs := getCancelAllOrderIDs(ctx, k, msg)
+	remainNum := 0
+	if len(orderIDs) > types.MultiCancelOrderItemLimit {
+		remainNum = len(orderIDs) - types.MultiCancelOrderItemLimit
+		orderIDs = orderIDs[:types.MultiCancelOrderItemLimit]
+	}
+
+	cancelRes := []types.OrderResult{}
+	for _, orderID := range orderIDs {
+		res, cacheItem := handleCancelOrder(ctx, k, msg.Sender, orderID, logger)
+		cancelRes = append(cancelRes, res)
+		cacheItem.Write()
+		if res.Code != sdk.CodeOK {
+			continue
+		}
+
+		logger.Debug(fmt.Sprintf("BlockHeight<%d>, handler<%s>\n"+
+			"    msg<Sender:%s,Product:%s,Side:%s>\n"+
+			"    result<The User have canceled an order {ID:%s} >\n",
+			ctx.BlockHeight(), "handleMsgCancelAllOrders",
+			msg.Sender, msg.Product, msg.Side, orderID))
+	}
+	rss, err := json.Marshal(&cancelRes)
+	if err != nil {
+		rss = []byte(fmt.Sprintf("failed to marshal result to JSON: %s", err))
+	}
+
+	event := sdk.NewEvent(sdk.EventTypeMessage, sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName))
+	event = event.AppendAttributes(sdk.NewAttribute("orders", string(rss)),
+		sdk.NewAttribute("remain_orders", strconv.Itoa(remainNum)))
+	ctx.EventManager().EmitEvent(event)
+	result := sdk.Result{
+		Data:   rss,
+		Events: ctx.EventManager().Events(),
+	}
+	if remainNum > 0 {
+		result.Log = fmt.Sprintf("%d orders are left open, send the msg again to cancel them", remainNum)
+	}
+	return result
+}
+
+// getCancelAllOrderIDs returns the ids of the open orders of the sender which match the product and side of the msg
+func getCancelAllOrderIDs(ctx sdk.Context, k keeper.Keeper, msg types.MsgCancelAllOrders) []string {
+	var orderIDs []string
+	for _, orderID := range k.GetSenderOrderIDs(ctx, msg.Sender, msg.Product) {
+		order := k.GetOrder(ctx, orderID)
+		if order == nil || (msg.Side != "" && order.Side != msg.Side) {
+			continue
+		}
+		orderIDs = append(orderIDs, orderID)
+	}
+	return orderIDs
+}
+
+// ValidateMsgCancelAllOrders validates whether the msg of cancelAllOrders is valid.
+func ValidateMsgCancelAllOrders(ctx sdk.Context, k keeper.Keeper, msg types.MsgCancelAllOrders) sdk.Result {
+	if len(getCancelAllOrderIDs(ctx, k, msg)) == 0 {
+		return sdk.Result{
+			Code: sdk.CodeUnknownRequest,
+			Log:  fmt.Sprintf("no open order of %s to cancel", msg.Sender),
+		}
+	}
+	return sdk.Result{}
+}
+
+func handleMsgAmendOrder(ctx sdk.Context, k Keeper, msg types.MsgAmendOrder, logger log.Logger) sdk.Result {
+	if res := ValidateMsgAmendOrder(ctx, k, msg); !res.IsOK() {
+		return res
+	}
+
+	order := k.GetOrder(ctx, msg.OrderID)
+	if err := k.AmendOrder(ctx, order, msg.Price, msg.Quantity); err != nil {
+		return sdk.Result{
+			Code: sdk.CodeInsufficientCoins,
+			Log:  err.Error(),
+		}
+	}
+
+	logger.Debug(fmt.Sprintf("BlockHeight<%d>, handler<%s>\n"+
+		"    msg<Sender:%s,ID:%s,Price:%s,Quantity:%s>\n"+
+		"    result<The User have amended an order {ID:%s,RemainQuantity:%s} >\n",
+		ctx.BlockHeight(), "handleMsgAmendOrder",
+		msg.Sender, msg.OrderID, msg.Price, msg.Quantity,
+		order.OrderID, order.RemainQuantity))
+
+	event := sdk.NewEvent(sdk.EventTypeMessage, sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName))
+	event = event.AppendAttributes(sdk.NewAttribute("order_id", order.OrderID),
+		sdk.NewAttribute("remain_quantity", order.RemainQuantity.String()))
+	ctx.EventManager().EmitEvent(event)
+	return sdk.Result{
+		Events: ctx.EventManager().Events(),
+	}
+}
+
+// ValidateMsgAmendOrder validates whether the msg of amendOrder is valid.
+func ValidateMsgAmendOrder(ctx sdk.Context, k keeper.Keeper, msg types.MsgAmendOrder) sdk.Result {
+	order := k.GetOrder(ctx, msg.OrderID)
+	if order == nil {
+		return sdk.Result{
+			Code: sdk.CodeUnknownRequest,
+			Log:  fmt.Sprintf("order(%s) does not exist or already closed", msg.OrderID),
+		}
+	}
+	if order.Status != types.OrderStatusOpen {
+		return sdk.Result{
+			Code: sdk.CodeInternal,
+			Log:  fmt.Sprintf("cannot amend order with status(%d)", order.Status),
+		}
+	}
+	if !order.Sender.Equals(msg.Sender) {
+		return sdk.Result{
+			Code: sdk.CodeUnauthorized,
+			Log:  fmt.Sprintf("not the owner of order(%v)", msg.OrderID),
+		}
+	}
+	if k.IsProductLocked(order.Product) {
+		return sdk.Result{
+			Code: sdk.CodeInternal,
+			Log:  fmt.Sprintf("the trading pair (%s) is locked, please retry later", order.Product),
+		}
+	}
+	// IOC and FOK orders never rest in the depth book
+	if timeInForce := order.GetTimeInForce(); timeInForce == types.TimeInForceIOC || timeInForce == types.TimeInForceFOK {
+		return sdk.Result{
+			Code: sdk.CodeUnknownRequest,
+			Log:  fmt.Sprintf("cannot amend order with time in force(%s)", timeInForce),
+		}
+	}
+	if order.IsIceberg() {
+		return sdk.Result{
+			Code: sdk.CodeUnknownRequest,
+			Log:  "cannot amend iceberg order",
+		}
+	}
 
+	newOrderMsg := MsgNewOrder{
+		Sender:   msg.Sender,
+		Product:  order.Product,
+		Side:     order.Side,
+		Price:    msg.Price,
+		Quantity: msg.Quantity,
+	}
+	if err := checkOrderNewMsg(ctx, k, newOrderMsg); err != nil {
+		return sdk.Result{
+			Code: sdk.CodeUnknownRequest,
+			Log:  err.Error(),
+		}
+	}
+	if !msg.Quantity.GT(order.Quantity.Sub(order.RemainQuantity)) {
+		return sdk.Result{
+			Code: sdk.CodeUnknownRequest,
+			Log:  fmt.Sprintf("quantity(%s) should be greater than the filled quantity", msg.Quantity),
+		}
+	}
+	if order.GetTimeInForce() == types.TimeInForcePostOnly && crossesDepthBook(k, order, msg.Price) {
+		return sdk.Result{
+			Code: sdk.CodeUnknownRequest,
+			Log:  fmt.Sprintf("post-only order(%s) would be matched at price(%s)", msg.OrderID, msg.Price),
+		}
+	}
 	return sdk.Result{}
 }
+
+// crossesDepthBook checks whether the order at price would be matched with the other side of the depth book
+func crossesDepthBook(k keeper.Keeper, order *types.Order, price sdk.Dec) bool {
+	for _, item := range k.GetDepthBookCopy(order.Product).Items {
+		if order.Side == types.BuyOrder && item.SellQuantity.IsPositive() && item.Price.LTE(price) {
+			return true
+		}
+		if order.Side == types.SellOrder && item.BuyQuantity.IsPositive() && item.Price.GTE(price) {
+			return true
+		}
+	}
+	return false
+}
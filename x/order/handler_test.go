@@ -44,8 +44,15 @@ func TestEventNewOrders(t *testing.T) {
 	msg := types.NewMsgNewOrders(addrKeysSlice[0].Address, orderItems)
 	result := handler(ctx, msg)
 
-	require.EqualValues(t, 2, len(result.Events[4].Attributes))
-
+	// the message event is emitted after the events of the orders
+	require.EqualValues(t, 2, len(result.Events[len(result.Events)-1].Attributes))
+	newOrderEvents := 0
+	for _, event := range result.Events {
+		if event.Type == types.EventTypeNewOrder {
+			newOrderEvents++
+		}
+	}
+	require.EqualValues(t, 2, newOrderEvents)
 }
 
 func TestFeesNewOrders(t *testing.T) {
@@ -138,6 +145,214 @@ func TestHandleMsgNewOrderInvalid(t *testing.T) {
 	require.Equal(t, 0, len(depthBook.Items))
 }
 
+func TestHandleMsgNewOrderPriceBand(t *testing.T) {
+	mapp, addrKeysSlice := getMockApp(t, 1)
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(10)
+	feeParams := types.DefaultParams()
+	mapp.orderKeeper.SetParams(ctx, &feeParams)
+
+	tokenPair := dex.GetBuiltInTokenPair()
+	tokenPair.PriceBand = &dex.PriceBand{MaxDeviation: sdk.MustNewDecFromStr("0.1"), Action: dex.PriceBandActionReject}
+	require.Nil(t, mapp.dexKeeper.SaveTokenPair(ctx, tokenPair))
+	handler := NewOrderHandler(mapp.orderKeeper)
+
+	// the last price is the init price 10.0
+	tests := []struct {
+		side  string
+		price string
+		code  sdk.CodeType
+	}{
+		{types.BuyOrder, "11.0", sdk.CodeOK},
+		{types.BuyOrder, "11.1", sdk.CodeUnknownRequest},
+		{types.SellOrder, "9.0", sdk.CodeOK},
+		{types.SellOrder, "8.9", sdk.CodeUnknownRequest},
+	}
+	for _, test := range tests {
+		msg := types.NewMsgNewOrder(addrKeysSlice[0].Address, types.TestTokenPair, test.side, test.price, "0.1")
+		orderRes := parseOrderResult(handler(ctx, msg))
+		require.EqualValues(t, test.code, orderRes[0].Code)
+	}
+
+	// orders out of a band halting the product are accepted
+	require.Nil(t, mapp.dexKeeper.SetPriceBand(ctx, types.TestTokenPair,
+		&dex.PriceBand{MaxDeviation: sdk.MustNewDecFromStr("0.1"), Action: dex.PriceBandActionHalt, HaltBlocks: 10}))
+	msg := types.NewMsgNewOrder(addrKeysSlice[0].Address, types.TestTokenPair, types.BuyOrder, "11.1", "0.1")
+	orderRes := parseOrderResult(handler(ctx, msg))
+	require.EqualValues(t, sdk.CodeOK, orderRes[0].Code)
+}
+
+func TestHandleMsgNewOrderLimits(t *testing.T) {
+	mapp, addrKeysSlice := getMockApp(t, 1)
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(10)
+	keeper := mapp.orderKeeper
+	feeParams := types.DefaultParams()
+	feeParams.MaxOpenOrdersPerProduct = 2
+	feeParams.MaxNewOrdersPerBlock = 3
+	keeper.SetParams(ctx, &feeParams)
+	keeper.ResetCache(ctx)
+	require.Nil(t, mapp.dexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair()))
+	handler := NewOrderHandler(keeper)
+	sender := addrKeysSlice[0].Address
+
+	newOrder := func() types.OrderResult {
+		msg := types.NewMsgNewOrder(sender, types.TestTokenPair, types.BuyOrder, "10.0", "0.1")
+		return parseOrderResult(handler(ctx, msg))[0]
+	}
+	orderRes := newOrder()
+	require.EqualValues(t, sdk.CodeOK, orderRes.Code)
+	require.EqualValues(t, sdk.CodeOK, newOrder().Code)
+
+	// too many open orders of the product
+	orderRes2 := newOrder()
+	require.EqualValues(t, types.CodeOpenOrderLimitExceeded, orderRes2.Code)
+	require.EqualValues(t, types.DefaultCodespace, orderRes2.Codespace)
+	result := ValidateMsgNewOrders(ctx, keeper,
+		types.NewMsgNewOrder(sender, types.TestTokenPair, types.BuyOrder, "10.0", "0.1"))
+	require.EqualValues(t, types.CodeOpenOrderLimitExceeded, result.Code)
+	require.EqualValues(t, types.DefaultCodespace, result.Codespace)
+
+	// too many new orders in this block
+	result = handler(ctx, types.NewMsgCancelOrder(sender, orderRes.OrderID))
+	require.EqualValues(t, sdk.CodeOK, parseOrderResult(result)[0].Code)
+	require.EqualValues(t, sdk.CodeOK, newOrder().Code)
+	require.EqualValues(t, sdk.CodeOK, parseOrderResult(handler(ctx, types.NewMsgCancelOrder(sender,
+		types.FormatOrderID(10, 2))))[0].Code)
+	orderRes = newOrder()
+	require.EqualValues(t, types.CodeNewOrderLimitExceeded, orderRes.Code)
+	require.EqualValues(t, types.DefaultCodespace, orderRes.Codespace)
+
+	// the limit of new orders is reset in the next block
+	ctx = ctx.WithBlockHeight(11)
+	keeper.ResetCache(ctx)
+	require.EqualValues(t, sdk.CodeOK, newOrder().Code)
+}
+
+func TestHandleMsgCancelOrderDeposit(t *testing.T) {
+	mapp, addrKeysSlice := getMockApp(t, 1)
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(10)
+	keeper := mapp.orderKeeper
+	feeParams := types.DefaultParams()
+	feeParams.OrderDeposit = sdk.NewDecCoinFromDec(common.NativeToken, sdk.OneDec())
+	feeParams.DepositLockBlocks = 5
+	keeper.SetParams(ctx, &feeParams)
+	mapp.supplyKeeper.SetSupply(ctx, supply.NewSupply(mapp.TotalCoinsSupply))
+	require.Nil(t, mapp.dexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair()))
+	handler := NewOrderHandler(keeper)
+	sender := addrKeysSlice[0].Address
+	getBalance := func() sdk.Dec {
+		return mapp.AccountKeeper.GetAccount(ctx, sender).GetCoins().AmountOf(common.NativeToken)
+	}
+
+	tests := []struct {
+		blocks        int64
+		burnedDeposit string
+		cost          string
+	}{
+		// cancelled too early, the deposit is burned
+		{4, "1.00000000" + common.NativeToken, "1.000004"},
+		// the deposit is refunded
+		{5, "", "0.000005"},
+	}
+	for _, test := range tests {
+		balance := getBalance()
+		orderRes := parseOrderResult(handler(ctx, types.NewMsgNewOrder(sender, types.TestTokenPair, types.SellOrder,
+			"10.0", "1.0")))[0]
+		require.EqualValues(t, sdk.CodeOK, orderRes.Code)
+		require.EqualValues(t, common.NativeToken, keeper.GetOrder(ctx, orderRes.OrderID).Deposit.Denom)
+
+		ctx = ctx.WithBlockHeight(ctx.BlockHeight() + test.blocks)
+		result := handler(ctx, types.NewMsgCancelOrder(sender, orderRes.OrderID))
+		require.EqualValues(t, sdk.CodeOK, parseOrderResult(result)[0].Code)
+		order := keeper.GetOrder(ctx, orderRes.OrderID)
+		require.Nil(t, order.Deposit)
+		require.Equal(t, test.burnedDeposit, order.GetExtraInfoWithKey(types.OrderExtraInfoKeyBurnedDeposit))
+		require.Equal(t, balance.Sub(sdk.MustNewDecFromStr(test.cost)), getBalance())
+	}
+	burned := sdk.DecCoins{sdk.NewDecCoinFromDec(common.NativeToken, sdk.OneDec())}
+	require.Equal(t, mapp.TotalCoinsSupply.Sub(burned), mapp.supplyKeeper.GetSupply(ctx).GetTotal())
+}
+
+func TestHandleMsgNewOrdersAtomic(t *testing.T) {
+	mapp, addrKeysSlice := getMockApp(t, 1)
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(10)
+	keeper := mapp.orderKeeper
+	feeParams := types.DefaultParams()
+	feeParams.MaxNewOrdersPerBlock = 3
+	keeper.SetParams(ctx, &feeParams)
+	keeper.ResetCache(ctx)
+	require.Nil(t, mapp.dexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair()))
+	handler := NewOrderHandler(keeper)
+	sender := addrKeysSlice[0].Address
+	coins := mapp.AccountKeeper.GetAccount(ctx, sender).GetCoins()
+
+	newItem := func(side, price, quantity string) types.OrderItem {
+		return types.OrderItem{Product: types.TestTokenPair, Side: side, Price: sdk.MustNewDecFromStr(price),
+			Quantity: sdk.MustNewDecFromStr(quantity)}
+	}
+	tests := []struct {
+		items []types.OrderItem
+		codes []sdk.CodeType
+	}{
+		// the second order can't lock enough coins
+		{
+			[]types.OrderItem{newItem(types.BuyOrder, "10.0", "1.0"), newItem(types.SellOrder, "10.0", "1000"),
+				newItem(types.SellOrder, "11.0", "1.0")},
+			[]sdk.CodeType{types.CodeAtomicOrdersRolledBack, sdk.CodeInsufficientCoins,
+				types.CodeAtomicOrdersRolledBack},
+		},
+		// the coins locked by the first orders are short for the last one
+		{
+			[]types.OrderItem{newItem(types.SellOrder, "10.0", "60"), newItem(types.SellOrder, "11.0", "60")},
+			[]sdk.CodeType{types.CodeAtomicOrdersRolledBack, sdk.CodeInsufficientCoins},
+		},
+		// the orders exceed the limit of new orders per block
+		{
+			[]types.OrderItem{newItem(types.BuyOrder, "10.0", "1.0"), newItem(types.BuyOrder, "9.0", "1.0"),
+				newItem(types.BuyOrder, "8.0", "1.0"), newItem(types.BuyOrder, "7.0", "1.0")},
+			[]sdk.CodeType{types.CodeAtomicOrdersRolledBack, types.CodeAtomicOrdersRolledBack,
+				types.CodeAtomicOrdersRolledBack, types.CodeNewOrderLimitExceeded},
+		},
+	}
+	for _, test := range tests {
+		msg := types.NewMsgNewOrders(sender, test.items)
+		msg.Atomic = true
+		orderRes := parseOrderResult(handler(ctx, msg))
+		require.Equal(t, len(test.codes), len(orderRes))
+		for i, code := range test.codes {
+			require.EqualValues(t, code, orderRes[i].Code)
+			require.Empty(t, orderRes[i].OrderID)
+		}
+		// nothing is placed
+		require.EqualValues(t, 0, keeper.GetBlockOrderNum(ctx, 10))
+		require.Empty(t, keeper.GetDepthBookCopy(types.TestTokenPair).Items)
+		require.Equal(t, coins, mapp.AccountKeeper.GetAccount(ctx, sender).GetCoins())
+	}
+
+	// the orders of a batch can't share a client order id
+	items := []types.OrderItem{newItem(types.BuyOrder, "10.0", "1.0"), newItem(types.BuyOrder, "9.0", "1.0")}
+	items[0].ClientOrderID, items[1].ClientOrderID = "id1", "id1"
+	msg := types.NewMsgNewOrders(sender, items)
+	msg.Atomic = true
+	require.NotNil(t, msg.ValidateBasic())
+
+	// all the orders are placed
+	msg = types.NewMsgNewOrders(sender, tests[2].items[:3])
+	msg.Atomic = true
+	orderRes := parseOrderResult(handler(ctx, msg))
+	for i, res := range orderRes {
+		require.EqualValues(t, sdk.CodeOK, res.Code)
+		require.Equal(t, types.FormatOrderID(10, int64(i+1)), res.OrderID)
+		require.EqualValues(t, types.OrderStatusOpen, keeper.GetOrder(ctx, res.OrderID).Status)
+	}
+	require.EqualValues(t, 3, keeper.GetBlockOrderNum(ctx, 10))
+	require.Equal(t, 3, len(keeper.GetDepthBookCopy(types.TestTokenPair).Items))
+	require.Equal(t, 3, len(keeper.GetSenderOrderIDs(ctx, sender, types.TestTokenPair)))
+}
+
 func TestValidateMsgNewOrder(t *testing.T) {
 	mapp, addrKeysSlice := getMockApp(t, 1)
 	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
@@ -979,3 +1194,328 @@ func handleOrders(t *testing.T, baseasset string, quoteasset string, orders []*t
 	require.NotNil(t, acc1)
 	return acc0.GetCoins()
 }
+
+func TestHandleMsgAmendOrder(t *testing.T) {
+	mapp, addrKeysSlice := getMockApp(t, 2)
+	keeper := mapp.orderKeeper
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+
+	var startHeight int64 = 10
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(startHeight)
+	feeParams := types.DefaultParams()
+	mapp.orderKeeper.SetParams(ctx, &feeParams)
+	tokenPair := dex.GetBuiltInTokenPair()
+	mapp.supplyKeeper.SetSupply(ctx, supply.NewSupply(mapp.TotalCoinsSupply))
+	err := mapp.dexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	// mock orders
+	orders := []*types.Order{
+		types.MockOrder(types.FormatOrderID(startHeight, 1), types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+		types.MockOrder(types.FormatOrderID(startHeight, 2), types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+		types.MockOrder(types.FormatOrderID(startHeight, 3), types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+	}
+	for _, order := range orders {
+		order.Sender = addrKeysSlice[0].Address
+		require.NoError(t, keeper.PlaceOrder(ctx, order))
+	}
+	EndBlocker(ctx, keeper)
+
+	ctx = mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(startHeight + 1)
+	handler := NewOrderHandler(keeper)
+	keeper.ResetCache(ctx)
+	// 100 - 3 * (10 + 0.2592)
+	acc0 := mapp.AccountKeeper.GetAccount(ctx, addrKeysSlice[0].Address)
+	require.EqualValues(t, sdk.MustNewDecFromStr("69.2224"), acc0.GetCoins().AmountOf(common.NativeToken))
+
+	// reducing quantity keeps time priority
+	msg := types.NewMsgAmendOrder(addrKeysSlice[0].Address, orders[0].OrderID, sdk.MustNewDecFromStr("10.0"),
+		sdk.MustNewDecFromStr("0.5"))
+	require.EqualValues(t, sdk.CodeOK, handler(ctx, msg).Code)
+	// increasing quantity resets time priority
+	msg = types.NewMsgAmendOrder(addrKeysSlice[0].Address, orders[1].OrderID, sdk.MustNewDecFromStr("10.0"),
+		sdk.MustNewDecFromStr("2.0"))
+	require.EqualValues(t, sdk.CodeOK, handler(ctx, msg).Code)
+	key := types.FormatOrderIDsKey(types.TestTokenPair, sdk.MustNewDecFromStr("10.0"), types.BuyOrder)
+	require.EqualValues(t, []string{orders[0].OrderID, orders[2].OrderID, orders[1].OrderID},
+		keeper.GetProductPriceOrderIDs(key))
+	// changing price resets time priority
+	msg = types.NewMsgAmendOrder(addrKeysSlice[0].Address, orders[2].OrderID, sdk.MustNewDecFromStr("9.0"),
+		sdk.MustNewDecFromStr("1.0"))
+	require.EqualValues(t, sdk.CodeOK, handler(ctx, msg).Code)
+	require.EqualValues(t, []string{orders[0].OrderID, orders[1].OrderID}, keeper.GetProductPriceOrderIDs(key))
+	key = types.FormatOrderIDsKey(types.TestTokenPair, sdk.MustNewDecFromStr("9.0"), types.BuyOrder)
+	require.EqualValues(t, []string{orders[2].OrderID}, keeper.GetProductPriceOrderIDs(key))
+	amendedOrders := keeper.GetAmendedOrders()
+	require.EqualValues(t, 1, len(amendedOrders))
+	require.EqualValues(t, orders[2].OrderID, amendedOrders[0].OrderID)
+	require.EqualValues(t, keeper.GetBlockOrderNum(ctx, ctx.BlockHeight()), amendedOrders[0].OrderNum)
+
+	// check order
+	order := keeper.GetOrder(ctx, orders[1].OrderID)
+	require.EqualValues(t, types.OrderStatusOpen, order.Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("2.0"), order.RemainQuantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("20.0"), order.RemainLocked)
+	// check depth book
+	depthBook := keeper.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 2, len(depthBook.Items))
+	require.EqualValues(t, sdk.MustNewDecFromStr("2.5"), depthBook.Items[0].BuyQuantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.0"), depthBook.Items[1].BuyQuantity)
+	// check account balance: locked coins are adjusted by -5 + 10 - 1
+	acc0 = mapp.AccountKeeper.GetAccount(ctx, addrKeysSlice[0].Address)
+	require.EqualValues(t, sdk.MustNewDecFromStr("65.2224"), acc0.GetCoins().AmountOf(common.NativeToken))
+
+	// not the owner
+	msg = types.NewMsgAmendOrder(addrKeysSlice[1].Address, orders[0].OrderID, sdk.MustNewDecFromStr("10.0"),
+		sdk.MustNewDecFromStr("1.0"))
+	require.EqualValues(t, sdk.CodeUnauthorized, handler(ctx, msg).Code)
+	// insufficient coins
+	msg = types.NewMsgAmendOrder(addrKeysSlice[0].Address, orders[0].OrderID, sdk.MustNewDecFromStr("10.0"),
+		sdk.MustNewDecFromStr("100.0"))
+	require.EqualValues(t, sdk.CodeInsufficientCoins, handler(ctx, msg).Code)
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.5"), keeper.GetOrder(ctx, orders[0].OrderID).RemainQuantity)
+	// order does not exist
+	msg = types.NewMsgAmendOrder(addrKeysSlice[0].Address, types.FormatOrderID(startHeight, 4),
+		sdk.MustNewDecFromStr("10.0"), sdk.MustNewDecFromStr("1.0"))
+	require.EqualValues(t, sdk.CodeUnknownRequest, handler(ctx, msg).Code)
+}
+
+func TestHandleMsgCancelAllOrders(t *testing.T) {
+	mapp, addrKeysSlice := getMockApp(t, 2)
+	keeper := mapp.orderKeeper
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+
+	var startHeight int64 = 10
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(startHeight)
+	feeParams := types.DefaultParams()
+	mapp.orderKeeper.SetParams(ctx, &feeParams)
+	tokenPair := dex.GetBuiltInTokenPair()
+	mapp.supplyKeeper.SetSupply(ctx, supply.NewSupply(mapp.TotalCoinsSupply))
+	err := mapp.dexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	// mock orders
+	orders := []*types.Order{
+		types.MockOrder(types.FormatOrderID(startHeight, 1), types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+		types.MockOrder(types.FormatOrderID(startHeight, 2), types.TestTokenPair, types.SellOrder, "11.0", "1.0"),
+		types.MockOrder(types.FormatOrderID(startHeight, 3), types.TestTokenPair, types.BuyOrder, "9.0", "1.0"),
+		types.MockOrder(types.FormatOrderID(startHeight, 4), types.TestTokenPair, types.BuyOrder, "9.0", "1.0"),
+	}
+	orders[3].Sender = addrKeysSlice[1].Address
+	for _, order := range orders[:3] {
+		order.Sender = addrKeysSlice[0].Address
+	}
+	for _, order := range orders {
+		require.NoError(t, keeper.PlaceOrder(ctx, order))
+	}
+	EndBlocker(ctx, keeper)
+
+	ctx = mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(startHeight + 1)
+	handler := NewOrderHandler(keeper)
+	keeper.ResetCache(ctx)
+
+	// cancel the buy orders of the sender
+	msg := types.NewMsgCancelAllOrders(addrKeysSlice[0].Address, types.TestTokenPair, types.BuyOrder)
+	result := handler(ctx, msg)
+	require.EqualValues(t, sdk.CodeOK, result.Code)
+	var cancelRes []types.OrderResult
+	require.NoError(t, json.Unmarshal(result.Data, &cancelRes))
+	require.EqualValues(t, 2, len(cancelRes))
+	require.EqualValues(t, orders[0].OrderID, cancelRes[0].OrderID)
+	require.EqualValues(t, orders[2].OrderID, cancelRes[1].OrderID)
+	cancelEvents := 0
+	for _, event := range result.Events {
+		if event.Type == types.EventTypeCancelOrder {
+			cancelEvents++
+		}
+	}
+	require.EqualValues(t, 2, cancelEvents)
+	require.EqualValues(t, types.OrderStatusCancelled, keeper.GetOrder(ctx, orders[0].OrderID).Status)
+	require.EqualValues(t, types.OrderStatusCancelled, keeper.GetOrder(ctx, orders[2].OrderID).Status)
+	require.EqualValues(t, []string{orders[1].OrderID}, keeper.GetSenderOrderIDs(ctx, addrKeysSlice[0].Address, ""))
+
+	// no buy order left
+	result = handler(ctx, msg)
+	require.EqualValues(t, sdk.CodeUnknownRequest, result.Code)
+
+	// cancel all the remaining orders of the sender
+	msg = types.NewMsgCancelAllOrders(addrKeysSlice[0].Address, "", "")
+	result = handler(ctx, msg)
+	require.EqualValues(t, sdk.CodeOK, result.Code)
+	require.EqualValues(t, types.OrderStatusCancelled, keeper.GetOrder(ctx, orders[1].OrderID).Status)
+	require.Nil(t, keeper.GetSenderOrderIDs(ctx, addrKeysSlice[0].Address, ""))
+
+	// orders of other senders are untouched
+	require.EqualValues(t, types.OrderStatusOpen, keeper.GetOrder(ctx, orders[3].OrderID).Status)
+	require.EqualValues(t, []string{orders[3].OrderID}, keeper.GetSenderOrderIDs(ctx, addrKeysSlice[1].Address, ""))
+}
+
+func TestHandleMsgCancelAllOrdersLimit(t *testing.T) {
+	mapp, addrKeysSlice := getMockAppWithBalance(t, 1, 10000)
+	keeper := mapp.orderKeeper
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(10)
+	feeParams := types.DefaultParams()
+	mapp.orderKeeper.SetParams(ctx, &feeParams)
+	mapp.supplyKeeper.SetSupply(ctx, supply.NewSupply(mapp.TotalCoinsSupply))
+	require.Nil(t, mapp.dexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair()))
+
+	for i := 0; i < types.MultiCancelOrderItemLimit+1; i++ {
+		order := types.MockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0")
+		order.Sender = addrKeysSlice[0].Address
+		require.NoError(t, keeper.PlaceOrder(ctx, order))
+	}
+
+	// a msg cancels MultiCancelOrderItemLimit orders at most and reports the orders left open
+	handler := NewOrderHandler(keeper)
+	msg := types.NewMsgCancelAllOrders(addrKeysSlice[0].Address, "", "")
+	result := handler(ctx, msg)
+	require.EqualValues(t, sdk.CodeOK, result.Code)
+	var cancelRes []types.OrderResult
+	require.NoError(t, json.Unmarshal(result.Data, &cancelRes))
+	require.EqualValues(t, types.MultiCancelOrderItemLimit, len(cancelRes))
+	require.Contains(t, result.Log, "1 orders are left open")
+	require.EqualValues(t, 1, len(keeper.GetSenderOrderIDs(ctx, addrKeysSlice[0].Address, "")))
+
+	result = handler(ctx, msg)
+	require.EqualValues(t, sdk.CodeOK, result.Code)
+	require.EqualValues(t, "", result.Log)
+	require.Nil(t, keeper.GetSenderOrderIDs(ctx, addrKeysSlice[0].Address, ""))
+}
+
+func TestHandleMsgNewOrderWithClientOrderID(t *testing.T) {
+	mapp, addrKeysSlice := getMockApp(t, 2)
+	keeper := mapp.orderKeeper
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(10)
+	feeParams := types.DefaultParams()
+	mapp.orderKeeper.SetParams(ctx, &feeParams)
+
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := mapp.dexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	handler := NewOrderHandler(keeper)
+
+	orderItem := types.NewOrderItem(types.TestTokenPair, types.BuyOrder, "10.0", "1.0")
+	orderItem.ClientOrderID = "my-order-1"
+	msg := types.NewMsgNewOrders(addrKeysSlice[0].Address, []types.OrderItem{orderItem})
+	res := parseOrderResult(handler(ctx, msg))
+	require.EqualValues(t, sdk.CodeOK, res[0].Code)
+	require.EqualValues(t, orderItem.ClientOrderID, res[0].ClientOrderID)
+	orderID := res[0].OrderID
+	require.EqualValues(t, orderItem.ClientOrderID, keeper.GetOrder(ctx, orderID).ClientOrderID)
+	require.EqualValues(t, orderID, keeper.GetOrderIDByClientOrderID(ctx, addrKeysSlice[0].Address,
+		orderItem.ClientOrderID))
+
+	// the client order id is used by an open order of the sender
+	require.NotEqual(t, sdk.CodeOK, ValidateMsgNewOrders(ctx, keeper, msg).Code)
+	res = parseOrderResult(handler(ctx, msg))
+	require.EqualValues(t, sdk.CodeUnknownRequest, res[0].Code)
+	// but not by the other senders
+	msg1 := types.NewMsgNewOrders(addrKeysSlice[1].Address, []types.OrderItem{orderItem})
+	res = parseOrderResult(handler(ctx, msg1))
+	require.EqualValues(t, sdk.CodeOK, res[0].Code)
+
+	// cancel the order by the client order id
+	cancelMsg := types.NewMsgCancelOrders(addrKeysSlice[0].Address, nil)
+	cancelMsg.ClientOrderIDs = []string{orderItem.ClientOrderID, "not-exist"}
+	require.NotEqual(t, sdk.CodeOK, ValidateMsgCancelOrders(ctx, keeper, cancelMsg).Code)
+	res = parseOrderResult(handler(ctx, cancelMsg))
+	require.EqualValues(t, 2, len(res))
+	require.EqualValues(t, sdk.CodeOK, res[0].Code)
+	require.EqualValues(t, orderID, res[0].OrderID)
+	require.EqualValues(t, orderItem.ClientOrderID, res[0].ClientOrderID)
+	require.EqualValues(t, sdk.CodeUnknownRequest, res[1].Code)
+	require.EqualValues(t, types.OrderStatusCancelled, keeper.GetOrder(ctx, orderID).Status)
+
+	// the client order id can be reused once the order is closed
+	res = parseOrderResult(handler(ctx, msg))
+	require.EqualValues(t, sdk.CodeOK, res[0].Code)
+	require.EqualValues(t, res[0].OrderID, keeper.GetOrderIDByClientOrderID(ctx, addrKeysSlice[0].Address,
+		orderItem.ClientOrderID))
+}
+
+func TestHandleMsgNewMarketOrder(t *testing.T) {
+	mapp, addrKeysSlice := getMockApp(t, 2)
+	keeper := mapp.orderKeeper
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+
+	var startHeight int64 = 10
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(startHeight)
+	feeParams := types.DefaultParams()
+	mapp.orderKeeper.SetParams(ctx, &feeParams)
+	tokenPair := dex.GetBuiltInTokenPair()
+	mapp.supplyKeeper.SetSupply(ctx, supply.NewSupply(mapp.TotalCoinsSupply))
+	err := mapp.dexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	// resting sell orders
+	orders := []*types.Order{
+		types.MockOrder(types.FormatOrderID(startHeight, 1), types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+		types.MockOrder(types.FormatOrderID(startHeight, 2), types.TestTokenPair, types.SellOrder, "11.0", "0.5"),
+	}
+	for _, order := range orders {
+		order.Sender = addrKeysSlice[1].Address
+		require.NoError(t, keeper.PlaceOrder(ctx, order))
+	}
+	EndBlocker(ctx, keeper)
+
+	ctx = mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(startHeight + 1)
+	handler := NewOrderHandler(keeper)
+	keeper.ResetCache(ctx)
+
+	// a limit buy order, then a market buy order spending 12.0 at the worst price 12.0,
+	// and a market buy order spending 10.5 with the worst price 5% above the best ask
+	limitItem := types.NewOrderItem(types.TestTokenPair, types.BuyOrder, "11.0", "1.0")
+	marketItem := types.NewOrderItem(types.TestTokenPair, types.BuyOrder, "12.0", "12.0")
+	marketItem.OrderType = types.OrderTypeMarket
+	slippageItem := types.NewOrderItem(types.TestTokenPair, types.BuyOrder, "0", "10.5")
+	slippageItem.OrderType = types.OrderTypeMarket
+	slippageItem.MaxSlippage = sdk.MustNewDecFromStr("0.05")
+	var orderIDs []string
+	for _, item := range []types.OrderItem{limitItem, marketItem, slippageItem} {
+		msg := types.NewMsgNewOrders(addrKeysSlice[0].Address, []types.OrderItem{item})
+		validateCtx, _ := ctx.CacheContext()
+		require.EqualValues(t, sdk.CodeOK, ValidateMsgNewOrders(validateCtx, keeper, msg).Code)
+		res := parseOrderResult(handler(ctx, msg))
+		require.EqualValues(t, sdk.CodeOK, res[0].Code)
+		orderIDs = append(orderIDs, res[0].OrderID)
+	}
+	order := keeper.GetOrder(ctx, orderIDs[2])
+	require.EqualValues(t, types.TimeInForceIOC, order.TimeInForce)
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.5"), order.Price)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.0"), order.Quantity)
+	// the whole amount is locked
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.5"), *order.QuoteAmount)
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.5"), order.RemainLocked)
+
+	// no buy orders to take for a market sell order
+	sellItem := types.NewOrderItem(types.TestTokenPair, types.SellOrder, "0", "1.0")
+	sellItem.OrderType = types.OrderTypeMarket
+	sellItem.MaxSlippage = sdk.MustNewDecFromStr("0.05")
+	msg := types.NewMsgNewOrders(addrKeysSlice[1].Address, []types.OrderItem{sellItem})
+	require.EqualValues(t, sdk.CodeUnknownRequest, ValidateMsgNewOrders(ctx, keeper, msg).Code)
+	require.EqualValues(t, sdk.CodeUnknownRequest, parseOrderResult(handler(ctx, msg))[0].Code)
+
+	// the call auction clears at 11.0, and the market order is filled ahead of the limit order at the same price
+	EndBlocker(ctx, keeper)
+	order = keeper.GetOrder(ctx, orderIDs[1])
+	require.EqualValues(t, types.OrderStatusFilled, order.Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("11.0"), order.FilledAvgPrice)
+	require.EqualValues(t, sdk.ZeroDec(), order.RemainLocked)
+	order = keeper.GetOrder(ctx, orderIDs[0])
+	require.EqualValues(t, types.OrderStatusOpen, order.Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.5"), order.RemainQuantity)
+	// the worst price of the other market order is under the clearing price
+	order = keeper.GetOrder(ctx, orderIDs[2])
+	require.EqualValues(t, types.OrderStatusIOCCancelled, order.Status)
+	require.EqualValues(t, sdk.ZeroDec(), order.RemainLocked)
+
+	// only the limit order keeps its quote coins locked
+	depthBook := keeper.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 1, len(depthBook.Items))
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.5"), depthBook.Items[0].BuyQuantity)
+	lockCoins := mapp.tokenKeeper.GetLockCoins(ctx, addrKeysSlice[0].Address)
+	require.EqualValues(t, sdk.MustNewDecFromStr("5.5"), lockCoins.AmountOf(common.NativeToken))
+}
@@ -0,0 +1,56 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/order/types"
+)
+
+// GetDepthBookDiff returns the latest diff of the depth book of the product, nil if its depth book never changes
+func (k Keeper) GetDepthBookDiff(ctx sdk.Context, product string) *types.DepthBookDiff {
+	store := ctx.KVStore(k.orderStoreKey)
+	bz := store.Get(types.GetDepthBookDiffKey(product))
+	if bz == nil {
+		return nil
+	}
+	diff := &types.DepthBookDiff{}
+	k.cdc.MustUnmarshalBinaryBare(bz, diff)
+	return diff
+}
+
+// GetDepthBookSequence returns the sequence of the depth book of the product stored in KVStore
+func (k Keeper) GetDepthBookSequence(ctx sdk.Context, product string) int64 {
+	if diff := k.GetDepthBookDiff(ctx, product); diff != nil {
+		return diff.Sequence
+	}
+	return 0
+}
+
+// GetDepthBookDiffs returns the diffs of the depth books in this block, sorted by product
+func (k Keeper) GetDepthBookDiffs() []*types.DepthBookDiff {
+	return k.cache.getDepthBookDiffs()
+}
+
+// storeDepthBookDiff stores the diff between the depth book of the product in KVStore and depthBook,
+// with the next sequence of the product. Nothing is stored if the depth book is not changed.
+func (k Keeper) storeDepthBookDiff(ctx sdk.Context, product string, depthBook *types.DepthBook) {
+	if depthBook == nil {
+		depthBook = &types.DepthBook{}
+	}
+	items := k.GetDepthBookFromDB(ctx, product).Diff(depthBook)
+	if len(items) == 0 {
+		return
+	}
+
+	diff := &types.DepthBookDiff{
+		Product:     product,
+		Sequence:    k.GetDepthBookSequence(ctx, product) + 1,
+		BlockHeight: ctx.BlockHeight(),
+		Items:       items,
+	}
+	store := ctx.KVStore(k.orderStoreKey)
+	store.Set(types.GetDepthBookDiffKey(product), k.cdc.MustMarshalBinaryBare(diff))
+	if k.enableBackend {
+		k.cache.addDepthBookDiff(diff)
+	}
+}
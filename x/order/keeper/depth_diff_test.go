@@ -0,0 +1,89 @@
+package keeper
+
+import (
+	"sort"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/okex/okchain/x/dex"
+	"github.com/okex/okchain/x/order/types"
+)
+
+func TestDepthBookDiff(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	querier := NewQuerier(keeper)
+	require.Nil(t, testInput.DexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair()))
+	product := types.TestTokenPair
+
+	// a snapshot of the empty depth book
+	require.EqualValues(t, 0, keeper.GetDepthBookSequence(ctx, product))
+	snapshot := keeper.GetDepthBookFromDB(ctx, product)
+
+	keeper.ResetCache(ctx)
+	sellOrder := mockOrder("", product, types.SellOrder, "10.5", "1.0")
+	sellOrder.Sender = testInput.TestAddrs[0]
+	require.Nil(t, keeper.PlaceOrder(ctx, sellOrder))
+	buyOrder := mockOrder("", product, types.BuyOrder, "9.5", "2.0")
+	buyOrder.Sender = testInput.TestAddrs[1]
+	require.Nil(t, keeper.PlaceOrder(ctx, buyOrder))
+	keeper.Cache2Disk(ctx)
+
+	diff := keeper.GetDepthBookDiff(ctx, product)
+	require.EqualValues(t, 1, diff.Sequence)
+	require.EqualValues(t, 10, diff.BlockHeight)
+	require.EqualValues(t, 2, len(diff.Items))
+	require.EqualValues(t, []*types.DepthBookDiff{diff}, keeper.GetDepthBookDiffs())
+
+	// the depth book is not changed by an order placed and cancelled in the same block
+	ctx = ctx.WithBlockHeight(11)
+	keeper.ResetCache(ctx)
+	order := mockOrder("", product, types.BuyOrder, "9.0", "1.0")
+	order.Sender = testInput.TestAddrs[1]
+	require.Nil(t, keeper.PlaceOrder(ctx, order))
+	keeper.CancelOrder(ctx, order, ctx.Logger())
+	keeper.Cache2Disk(ctx)
+	require.EqualValues(t, 1, keeper.GetDepthBookSequence(ctx, product))
+	require.EqualValues(t, 0, len(keeper.GetDepthBookDiffs()))
+
+	ctx = ctx.WithBlockHeight(12)
+	keeper.ResetCache(ctx)
+	keeper.CancelOrder(ctx, sellOrder, ctx.Logger())
+	keeper.Cache2Disk(ctx)
+
+	bz, err := querier(ctx, []string{types.QueryDepthDiff, product}, abci.RequestQuery{})
+	require.Nil(t, err)
+	var latestDiff types.DepthBookDiff
+	keeper.cdc.MustUnmarshalJSON(bz, &latestDiff)
+	require.EqualValues(t, 2, latestDiff.Sequence)
+	require.EqualValues(t, []types.DepthBookItem{
+		{Price: sdk.MustNewDecFromStr("10.5"), BuyQuantity: sdk.ZeroDec(), SellQuantity: sdk.ZeroDec()}},
+		latestDiff.Items)
+
+	// rebuild the depth book from the snapshot and the diffs
+	for _, d := range []*types.DepthBookDiff{diff, &latestDiff} {
+		snapshot = applyDepthBookDiff(snapshot, d)
+	}
+	require.EqualValues(t, keeper.GetDepthBookFromDB(ctx, product).Items, snapshot.Items)
+
+	_, err = querier(ctx, []string{types.QueryDepthDiff, "unknown_okt"}, abci.RequestQuery{})
+	require.NotNil(t, err)
+}
+
+func applyDepthBookDiff(depthBook *types.DepthBook, diff *types.DepthBookDiff) *types.DepthBook {
+	itemMap := make(map[string]types.DepthBookItem)
+	for _, item := range append(depthBook.Items, diff.Items...) {
+		itemMap[item.Price.String()] = item
+	}
+	newBook := &types.DepthBook{}
+	for _, item := range itemMap {
+		newBook.Items = append(newBook.Items, item)
+	}
+	newBook.RemoveEmptyItems()
+	sort.Slice(newBook.Items, func(i, j int) bool { return newBook.Items[i].Price.GT(newBook.Items[j].Price) })
+	return newBook
+}
@@ -165,6 +165,18 @@ func (c *DiskCache) GetNewDepthbookKyes() []string {
 
 // insert a new order into orderIDsMap
 func (c *DiskCache) insertOrder(order *types.Order) {
+	c.insertOrderIntoBook(order)
+	c.addOrder()
+}
+
+// addOrder counts a new order, which might be a trigger order out of the depth book
+func (c *DiskCache) addOrder() {
+	c.openNum++
+	c.storeOrderNum++
+}
+
+// insertOrderIntoBook inserts an order into depthBookMap and orderIDsMap
+func (c *DiskCache) insertOrderIntoBook(order *types.Order) {
 	// 1. update depthBookMap
 	depthBook, ok := c.depthBookMap.data[order.Product]
 	if !ok {
@@ -185,9 +197,6 @@ func (c *DiskCache) insertOrder(order *types.Order) {
 	orderIDs = append(orderIDs, order.OrderID)
 	orderIDsMap.Data[key] = orderIDs
 	c.orderIDsMap.updatedItems[key] = struct{}{}
-
-	c.openNum++
-	c.storeOrderNum++
 }
 
 func (c *DiskCache) closeOrder(orderID string) {
@@ -197,7 +206,28 @@ func (c *DiskCache) closeOrder(orderID string) {
 
 // remove an order from orderIDsMap when order cancelled/expired
 func (c *DiskCache) removeOrder(order *types.Order) {
+	c.removeOrderFromBook(order)
+	c.closeOrder(order.OrderID)
+}
 
+// amendOrder replaces an order in the depth book with its amended version. The amended order keeps its place
+// in the queue of its price if keepPriority, otherwise it's queued behind the other orders at its new price.
+func (c *DiskCache) amendOrder(order, amendedOrder *types.Order, keepPriority bool) {
+	if !keepPriority {
+		c.removeOrderFromBook(order)
+		c.insertOrderIntoBook(amendedOrder)
+		return
+	}
+	depthBook := c.getDepthBook(order.Product)
+	if depthBook != nil {
+		depthBook.RemoveOrder(order)
+		depthBook.InsertOrder(amendedOrder)
+		c.setDepthBook(order.Product, depthBook)
+	}
+}
+
+// removeOrderFromBook removes an order from depthBookMap and orderIDsMap
+func (c *DiskCache) removeOrderFromBook(order *types.Order) {
 	// update depth book map
 	depthBook := c.getDepthBook(order.Product)
 	if depthBook != nil {
@@ -217,6 +247,4 @@ func (c *DiskCache) removeOrder(order *types.Order) {
 			break
 		}
 	}
-
-	c.closeOrder(order.OrderID)
 }
@@ -40,9 +40,13 @@ func (k Keeper) DropExpireBlockHeight(ctx sdk.Context, blockHeight int64) {
 func (k Keeper) SetOrder(ctx sdk.Context, orderID string, order *types.Order) {
 	store := ctx.KVStore(k.orderStoreKey)
 	store.Set(types.GetOrderKey(orderID), k.cdc.MustMarshalBinaryBare(order))
+	k.updateClientOrderIndex(ctx, orderID, order)
 }
 
 func (k Keeper) DropOrder(ctx sdk.Context, orderID string) {
+	if order := k.GetOrder(ctx, orderID); order != nil {
+		k.removeClientOrderIndex(ctx, orderID, order)
+	}
 	store := ctx.KVStore(k.orderStoreKey)
 	store.Delete(types.GetOrderKey(orderID))
 }
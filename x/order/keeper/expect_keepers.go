@@ -6,6 +6,7 @@ import (
 
 	dex "github.com/okex/okchain/x/dex/types"
 	"github.com/okex/okchain/x/order/types"
+	token "github.com/okex/okchain/x/token/types"
 )
 
 // expected token keeper
@@ -22,6 +23,8 @@ type TokenKeeper interface {
 
 	// Fee detail
 	AddFeeDetail(ctx sdk.Context, from string, fee sdk.DecCoins, feeType string)
+
+	GetAllLockCoins(ctx sdk.Context) []token.AccCoins
 }
 
 type SupplyKeeper interface {
@@ -32,6 +35,7 @@ type SupplyKeeper interface {
 	GetModuleAccount(ctx sdk.Context, moduleName string) exported.ModuleAccountI
 	GetModuleAddress(moduleName string) sdk.AccAddress
 	MintCoins(ctx sdk.Context, moduleName string, amt sdk.Coins) sdk.Error
+	BurnCoins(ctx sdk.Context, moduleName string, amt sdk.Coins) sdk.Error
 }
 
 // expected token keeper
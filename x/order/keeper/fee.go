@@ -7,6 +7,7 @@ import (
 
 	"strings"
 
+	"github.com/okex/okchain/x/common"
 	"github.com/okex/okchain/x/order/types"
 )
 
@@ -14,6 +15,7 @@ const MinFee = "0.00000001"
 
 type GetFeeKeeper interface {
 	GetLastPrice(ctx sdk.Context, product string) sdk.Dec
+	GetTradedVolume(ctx sdk.Context, addr sdk.AccAddress) sdk.Dec
 }
 
 // Currently, placing order does not need any fee, so we only support charging okb if necessary
@@ -43,7 +45,9 @@ func GetZeroFee() sdk.DecCoins {
 	return sdk.DecCoins{sdk.ZeroFee()}
 }
 
-func GetDealFee(order *types.Order, fillAmt sdk.Dec, ctx sdk.Context, keeper GetFeeKeeper,
+// GetDealFee returns the fee of a deal charged from the received coins, at the maker or taker fee rate of the fee
+// tier of the order sender
+func GetDealFee(order *types.Order, fillAmt sdk.Dec, isMaker bool, ctx sdk.Context, keeper GetFeeKeeper,
 	feeParams *types.Params) sdk.DecCoins {
 	symbols := strings.Split(order.Product, "_")
 	symbol := symbols[0]
@@ -53,9 +57,49 @@ func GetDealFee(order *types.Order, fillAmt sdk.Dec, ctx sdk.Context, keeper Get
 		quantity = fillAmt.Mul(keeper.GetLastPrice(ctx, order.Product))
 	}
 
-	feeAmt := quantity.Mul(feeParams.TradeFeeRate)
+	feeRate := feeParams.GetDealFeeRate(keeper.GetTradedVolume(ctx, order.Sender), isMaker)
+	feeAmt := quantity.Mul(feeRate)
 	if feeAmt.IsPositive() {
 		return sdk.DecCoins{sdk.NewDecCoinFromDec(symbol, feeAmt)}
 	}
 	return sdk.DecCoins{sdk.NewDecCoinFromDec(symbol, sdk.MustNewDecFromStr(MinFee))}
 }
+
+// GetNativePrice returns the last price of a token in the native token, which is taken from the pair
+// <denom>_okt or inverted from the pair okt_<denom>. It returns zero if neither pair has a price.
+func GetNativePrice(ctx sdk.Context, keeper GetFeeKeeper, denom string) sdk.Dec {
+	if denom == common.NativeToken {
+		return sdk.OneDec()
+	}
+	if price := keeper.GetLastPrice(ctx, denom+"_"+common.NativeToken); price.IsPositive() {
+		return price
+	}
+	if price := keeper.GetLastPrice(ctx, common.NativeToken+"_"+denom); price.IsPositive() {
+		return sdk.OneDec().Quo(price)
+	}
+	return sdk.ZeroDec()
+}
+
+// GetNativeDealFee converts the deal fee into the native token with the last price of the fee coin against it, and
+// applies the native fee discount. It returns false if the fee coin has no such price.
+func GetNativeDealFee(dealFee sdk.DecCoins, ctx sdk.Context, keeper GetFeeKeeper,
+	feeParams *types.Params) (sdk.DecCoins, bool) {
+	feeAmt := sdk.ZeroDec()
+	for _, fee := range dealFee {
+		amount := fee.Amount
+		if fee.Denom != common.NativeToken {
+			price := GetNativePrice(ctx, keeper, fee.Denom)
+			if !price.IsPositive() {
+				return nil, false
+			}
+			amount = amount.Mul(price)
+		}
+		feeAmt = feeAmt.Add(amount)
+	}
+
+	feeAmt = feeAmt.Mul(sdk.OneDec().Sub(feeParams.NativeFeeDiscount))
+	if feeAmt.IsPositive() {
+		return sdk.DecCoins{sdk.NewDecCoinFromDec(common.NativeToken, feeAmt)}, true
+	}
+	return sdk.DecCoins{sdk.NewDecCoinFromDec(common.NativeToken, sdk.MustNewDecFromStr(MinFee))}, true
+}
@@ -13,12 +13,13 @@ import (
 )
 
 type MockGetFeeKeeper struct {
-	coins    sdk.Coins
-	priceMap map[string]sdk.Dec
+	coins     sdk.Coins
+	priceMap  map[string]sdk.Dec
+	volumeMap map[string]sdk.Dec
 }
 
 func NewMockGetFeeKeeper() MockGetFeeKeeper {
-	return MockGetFeeKeeper{sdk.NewCoins(), make(map[string]sdk.Dec)}
+	return MockGetFeeKeeper{sdk.NewCoins(), make(map[string]sdk.Dec), make(map[string]sdk.Dec)}
 }
 
 func (k MockGetFeeKeeper) GetCoins(ctx sdk.Context, addr sdk.AccAddress) sdk.Coins {
@@ -32,6 +33,13 @@ func (k MockGetFeeKeeper) GetLastPrice(ctx sdk.Context, product string) sdk.Dec
 	return sdk.ZeroDec()
 }
 
+func (k MockGetFeeKeeper) GetTradedVolume(ctx sdk.Context, addr sdk.AccAddress) sdk.Dec {
+	if volume, ok := k.volumeMap[addr.String()]; ok {
+		return volume
+	}
+	return sdk.ZeroDec()
+}
+
 func TestGetOrderNewFee(t *testing.T) {
 	order := mockOrder("ID0000001970-1", types.TestTokenPair, types.BuyOrder, "10.0", "1.0")
 	orderExpireBlocks := sdk.NewDec(order.OrderExpireBlocks)
@@ -80,7 +88,7 @@ func TestOrderDealFee(t *testing.T) {
 		Quantity: sdk.MustNewDecFromStr("100.0"),
 	}
 	keeper.priceMap[types.TestTokenPair] = sdk.MustNewDecFromStr("10.0")
-	feeOther := GetDealFee(order, sdk.MustNewDecFromStr("10.0"), ctx, keeper, &feeParams)
+	feeOther := GetDealFee(order, sdk.MustNewDecFromStr("10.0"), false, ctx, keeper, &feeParams)
 	// 10 * 0.001
 	expectFee := sdk.DecCoins{sdk.NewDecCoinFromDec(common.TestToken, sdk.MustNewDecFromStr("0.01"))}
 	require.EqualValues(t, expectFee, feeOther)
@@ -95,7 +103,7 @@ func TestOrderDealFee(t *testing.T) {
 	keeper.priceMap["xxb_yyb"] = sdk.MustNewDecFromStr("20.0")
 	keeper.priceMap["yyb_"+common.NativeToken] = sdk.MustNewDecFromStr("0.6")
 
-	feeOther = GetDealFee(order, sdk.MustNewDecFromStr("100.0"), ctx, keeper, &feeParams)
+	feeOther = GetDealFee(order, sdk.MustNewDecFromStr("100.0"), false, ctx, keeper, &feeParams)
 	// 100 * 0.001
 	expectFee = sdk.DecCoins{sdk.NewDecCoinFromDec(common.TestToken, sdk.MustNewDecFromStr("0.1"))}
 	require.EqualValues(t, expectFee, feeOther)
@@ -107,7 +115,7 @@ func TestOrderDealFee(t *testing.T) {
 		Price:    sdk.MustNewDecFromStr("11.0"),
 		Quantity: sdk.MustNewDecFromStr("100.0"),
 	}
-	feeOther = GetDealFee(order, sdk.MustNewDecFromStr("100.0"), ctx, keeper, &feeParams)
+	feeOther = GetDealFee(order, sdk.MustNewDecFromStr("100.0"), false, ctx, keeper, &feeParams)
 	// 100 * 20 * 0.001
 	expectFee = sdk.DecCoins{sdk.NewDecCoinFromDec("yyb", sdk.MustNewDecFromStr("2.0"))}
 	require.EqualValues(t, expectFee, feeOther)
@@ -119,7 +127,84 @@ func TestOrderDealFee(t *testing.T) {
 		Price:    sdk.MustNewDecFromStr("1.0"),
 		Quantity: sdk.MustNewDecFromStr("0.00000001"),
 	}
-	feeOther = GetDealFee(order, sdk.MustNewDecFromStr("0.00000001"), ctx, keeper, &feeParams)
+	feeOther = GetDealFee(order, sdk.MustNewDecFromStr("0.00000001"), false, ctx, keeper, &feeParams)
 	expectFee = sdk.DecCoins{sdk.NewDecCoinFromDec("xxb", sdk.MustNewDecFromStr("0.00000001"))}
 	require.EqualValues(t, expectFee, feeOther)
 }
+
+func TestOrderDealFeeTiers(t *testing.T) {
+	ctx := sdk.Context{}
+	keeper := NewMockGetFeeKeeper()
+	feeParams := types.DefaultParams()
+	feeParams.MakerFeeRate = sdk.MustNewDecFromStr("0.002")
+	feeParams.TakerFeeRate = sdk.MustNewDecFromStr("0.003")
+	feeParams.FeeTiers = []types.FeeTier{
+		{MinVolume: sdk.NewDec(1000), MakerFeeRate: sdk.MustNewDecFromStr("0.001"),
+			TakerFeeRate: sdk.MustNewDecFromStr("0.002")},
+		{MinVolume: sdk.NewDec(10000), MakerFeeRate: sdk.ZeroDec(), TakerFeeRate: sdk.MustNewDecFromStr("0.001")},
+	}
+	require.Nil(t, feeParams.ValidateFeeTiers())
+
+	order := &types.Order{
+		Sender:   sdk.AccAddress([]byte("addr1_______________")),
+		Product:  types.TestTokenPair,
+		Side:     types.BuyOrder,
+		Price:    sdk.MustNewDecFromStr("10.0"),
+		Quantity: sdk.MustNewDecFromStr("100.0"),
+	}
+	tests := []struct {
+		volume   string
+		isMaker  bool
+		expected string
+	}{
+		{"0", true, "0.2"},
+		{"0", false, "0.3"},
+		{"1000", true, "0.1"},
+		{"9999", false, "0.2"},
+		{"10000", true, "0.00000001"}, // the min fee
+		{"10000", false, "0.1"},
+	}
+	for _, test := range tests {
+		keeper.volumeMap[order.Sender.String()] = sdk.MustNewDecFromStr(test.volume)
+		fee := GetDealFee(order, sdk.MustNewDecFromStr("100.0"), test.isMaker, ctx, keeper, &feeParams)
+		expectFee := sdk.DecCoins{sdk.NewDecCoinFromDec(common.TestToken, sdk.MustNewDecFromStr(test.expected))}
+		require.EqualValues(t, expectFee, fee)
+	}
+
+	feeParams.FeeTiers[1].MinVolume = sdk.NewDec(1000)
+	require.NotNil(t, feeParams.ValidateFeeTiers())
+}
+
+func TestGetNativeDealFee(t *testing.T) {
+	ctx := sdk.Context{}
+	keeper := NewMockGetFeeKeeper()
+	keeper.priceMap[common.TestToken+"_"+common.NativeToken] = sdk.MustNewDecFromStr("2.0")
+	keeper.priceMap[common.NativeToken+"_btc"] = sdk.MustNewDecFromStr("4.0")
+	feeParams := types.DefaultParams()
+	require.Nil(t, feeParams.ValidateFeeTiers())
+
+	tests := []struct {
+		dealFee  sdk.DecCoins
+		expected string
+		ok       bool
+	}{
+		{sdk.DecCoins{sdk.NewDecCoinFromDec(common.TestToken, sdk.MustNewDecFromStr("0.1"))}, "0.15", true},
+		{sdk.DecCoins{sdk.NewDecCoinFromDec(common.NativeToken, sdk.MustNewDecFromStr("0.1"))}, "0.075", true},
+		{sdk.DecCoins{sdk.NewDecCoinFromDec(common.NativeToken, sdk.MustNewDecFromStr(MinFee))}, MinFee, true},
+		// the price is inverted from the pair quoted in the fee coin
+		{sdk.DecCoins{sdk.NewDecCoinFromDec("btc", sdk.MustNewDecFromStr("0.1"))}, "0.01875", true},
+		// no last price against the native token
+		{sdk.DecCoins{sdk.NewDecCoinFromDec("usdk", sdk.MustNewDecFromStr("0.1"))}, "", false},
+	}
+	for _, test := range tests {
+		fee, ok := GetNativeDealFee(test.dealFee, ctx, keeper, &feeParams)
+		require.Equal(t, test.ok, ok)
+		if ok {
+			expectFee := sdk.DecCoins{sdk.NewDecCoinFromDec(common.NativeToken, sdk.MustNewDecFromStr(test.expected))}
+			require.EqualValues(t, expectFee, fee)
+		}
+	}
+
+	feeParams.NativeFeeDiscount = sdk.OneDec()
+	require.NotNil(t, feeParams.ValidateFeeTiers())
+}
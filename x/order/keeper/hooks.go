@@ -0,0 +1,38 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/order/types"
+)
+
+// Implements OrderHooks interface
+var _ types.OrderHooks = Keeper{}
+
+// AfterOrderPlaced - call hook if registered
+func (k Keeper) AfterOrderPlaced(ctx sdk.Context, order *types.Order) {
+	if k.hooks != nil {
+		k.hooks.AfterOrderPlaced(ctx, order)
+	}
+}
+
+// AfterOrderFilled - call hook if registered
+func (k Keeper) AfterOrderFilled(ctx sdk.Context, order *types.Order, price sdk.Dec, deal types.Deal) {
+	if k.hooks != nil {
+		k.hooks.AfterOrderFilled(ctx, order, price, deal)
+	}
+}
+
+// AfterOrderCancelled - call hook if registered
+func (k Keeper) AfterOrderCancelled(ctx sdk.Context, order *types.Order) {
+	if k.hooks != nil {
+		k.hooks.AfterOrderCancelled(ctx, order)
+	}
+}
+
+// AfterOrderExpired - call hook if registered
+func (k Keeper) AfterOrderExpired(ctx sdk.Context, order *types.Order) {
+	if k.hooks != nil {
+		k.hooks.AfterOrderExpired(ctx, order)
+	}
+}
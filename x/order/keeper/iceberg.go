@@ -0,0 +1,32 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/order/types"
+)
+
+// GetRematchProducts returns the products whose depth books were left crossed by the next display slices of
+// iceberg orders after the call auction of the last block, which are matched in this block
+func (k Keeper) GetRematchProducts(ctx sdk.Context) []string {
+	store := ctx.KVStore(k.orderStoreKey)
+	bz := store.Get(types.RematchProductsKey)
+	products := []string{}
+	if bz == nil {
+		return products
+	}
+	k.cdc.MustUnmarshalJSON(bz, &products)
+	return products
+}
+
+// SetRematchProducts records the products to be matched again in the next block
+func (k Keeper) SetRematchProducts(ctx sdk.Context, products []string) {
+	store := ctx.KVStore(k.orderStoreKey)
+	if len(products) == 0 {
+		if store.Has(types.RematchProductsKey) {
+			store.Delete(types.RematchProductsKey)
+		}
+		return
+	}
+	store.Set(types.RematchProductsKey, k.cdc.MustMarshalJSON(products))
+}
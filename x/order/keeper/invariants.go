@@ -0,0 +1,208 @@
+package keeper
+
+import (
+	"fmt"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/order/types"
+	token "github.com/okex/okchain/x/token/types"
+)
+
+// RegisterInvariants registers all order invariants
+func RegisterInvariants(ir sdk.InvariantRegistry, k Keeper) {
+	ir.RegisterRoute(types.ModuleName, "locked-coins",
+		LockedCoinsInvariant(k))
+	ir.RegisterRoute(types.ModuleName, "depth-book",
+		DepthBookInvariant(k))
+	ir.RegisterRoute(types.ModuleName, "order-num",
+		OrderNumInvariant(k))
+}
+
+// LockedCoinsInvariant checks that the locked coins of every account add up to the remaining locked coins of its
+// open orders, untriggered orders included, and that the token module account holds them together with the fees
+// and the deposits locked by the orders
+func LockedCoinsInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		var broken bool
+
+		expectedLocks := make(map[string]sdk.DecCoins)
+		heldCoins := sdk.DecCoins{}
+		k.iterateOrders(ctx, func(order *types.Order) bool {
+			if order.Status != types.OrderStatusOpen && order.Status != types.OrderStatusUntriggered {
+				return false
+			}
+			lockedCoins := order.NeedUnlockCoins()
+			expectedLocks[order.Sender.String()] = expectedLocks[order.Sender.String()].Add(lockedCoins)
+			heldCoins = heldCoins.Add(lockedCoins).Add(GetOrderNewFee(order))
+			if order.Deposit != nil {
+				heldCoins = heldCoins.Add(sdk.DecCoins{*order.Deposit})
+			}
+			return false
+		})
+
+		for _, lock := range k.tokenKeeper.GetAllLockCoins(ctx) {
+			addr := sdk.AccAddress(lock.Acc).String()
+			if !isDecCoinsEqual(lock.Coins, expectedLocks[addr]) {
+				broken = true
+				msg += fmt.Sprintf("\taccount %s locks %s, but its open orders lock %s\n",
+					addr, lock.Coins, expectedLocks[addr])
+			}
+			delete(expectedLocks, addr)
+		}
+		addrs := make([]string, 0, len(expectedLocks))
+		for addr := range expectedLocks {
+			addrs = append(addrs, addr)
+		}
+		sort.Strings(addrs)
+		for _, addr := range addrs {
+			if !expectedLocks[addr].IsZero() {
+				broken = true
+				msg += fmt.Sprintf("\taccount %s locks nothing, but its open orders lock %s\n",
+					addr, expectedLocks[addr])
+			}
+		}
+
+		moduleCoins := k.supplyKeeper.GetModuleAccount(ctx, token.ModuleName).GetCoins()
+		if !moduleCoins.IsAllGTE(heldCoins) {
+			broken = true
+			msg += fmt.Sprintf("\ttoken module account holds %s, less than %s held for the open orders\n",
+				moduleCoins, heldCoins)
+		}
+		return sdk.FormatInvariant(types.ModuleName, "locked coins", msg), broken
+	}
+}
+
+// DepthBookInvariant checks that the buy and sell quantities at every price of the depth books add up to the
+// visible quantities of the open orders there. The products locked by the periodic auction are skipped,
+// for their orders are being matched.
+func DepthBookInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		var broken bool
+
+		expectedBooks := make(map[string]*types.DepthBook)
+		k.iterateOrders(ctx, func(order *types.Order) bool {
+			if order.Status != types.OrderStatusOpen {
+				return false
+			}
+			if _, ok := expectedBooks[order.Product]; !ok {
+				expectedBooks[order.Product] = &types.DepthBook{}
+			}
+			expectedBooks[order.Product].InsertOrder(order)
+			return false
+		})
+
+		products := make([]string, 0, len(expectedBooks))
+		for product := range expectedBooks {
+			products = append(products, product)
+		}
+		for product := range k.diskCache.depthBookMap.data {
+			if _, ok := expectedBooks[product]; !ok {
+				products = append(products, product)
+			}
+		}
+		sort.Strings(products)
+
+		for _, product := range products {
+			if k.IsProductLocked(product) {
+				continue
+			}
+			expectedItems := make(map[string]types.DepthBookItem)
+			if expectedBook := expectedBooks[product]; expectedBook != nil {
+				expectedItems = nonZeroDepthBookItems(expectedBook)
+			}
+			items := nonZeroDepthBookItems(k.GetDepthBookCopy(product))
+			// the prices left in the depth book without open orders
+			for price, item := range items {
+				if _, ok := expectedItems[price]; !ok {
+					expectedItems[price] = types.DepthBookItem{
+						Price: item.Price, BuyQuantity: sdk.ZeroDec(), SellQuantity: sdk.ZeroDec()}
+				}
+			}
+			prices := make([]string, 0, len(expectedItems))
+			for price := range expectedItems {
+				prices = append(prices, price)
+			}
+			sort.Strings(prices)
+			for _, price := range prices {
+				item, ok := items[price]
+				if !ok {
+					item = types.DepthBookItem{BuyQuantity: sdk.ZeroDec(), SellQuantity: sdk.ZeroDec()}
+				}
+				expectedItem := expectedItems[price]
+				if !item.BuyQuantity.Equal(expectedItem.BuyQuantity) ||
+					!item.SellQuantity.Equal(expectedItem.SellQuantity) {
+					broken = true
+					msg += fmt.Sprintf("\tdepth book of %s at price %s has buy quantity %s and sell quantity %s, "+
+						"but its open orders have %s and %s\n", product, price, item.BuyQuantity, item.SellQuantity,
+						expectedItem.BuyQuantity, expectedItem.SellQuantity)
+				}
+			}
+		}
+		return sdk.FormatInvariant(types.ModuleName, "depth book", msg), broken
+	}
+}
+
+// OrderNumInvariant checks that the counters of the open orders and the stored orders match the orders in the store
+func OrderNumInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		var broken bool
+
+		var openNum, storeOrderNum int64
+		k.iterateOrders(ctx, func(order *types.Order) bool {
+			if order.Status == types.OrderStatusOpen || order.Status == types.OrderStatusUntriggered {
+				openNum++
+			}
+			storeOrderNum++
+			return false
+		})
+
+		if k.diskCache.getOpenNum() != openNum {
+			broken = true
+			msg += fmt.Sprintf("\topen order num is %d, but %d orders are open in the store\n",
+				k.diskCache.getOpenNum(), openNum)
+		}
+		if k.diskCache.storeOrderNum != storeOrderNum {
+			broken = true
+			msg += fmt.Sprintf("\tstore order num is %d, but %d orders are in the store\n",
+				k.diskCache.storeOrderNum, storeOrderNum)
+		}
+		return sdk.FormatInvariant(types.ModuleName, "order num", msg), broken
+	}
+}
+
+// nonZeroDepthBookItems returns the items of the depth book with a positive quantity, by price
+func nonZeroDepthBookItems(depthBook *types.DepthBook) map[string]types.DepthBookItem {
+	items := make(map[string]types.DepthBookItem, len(depthBook.Items))
+	for _, item := range depthBook.Items {
+		if !item.BuyQuantity.IsZero() || !item.SellQuantity.IsZero() {
+			items[item.Price.String()] = item
+		}
+	}
+	return items
+}
+
+// iterateOrders iterates over all the orders in the store till the handler returns true
+func (k Keeper) iterateOrders(ctx sdk.Context, handler func(order *types.Order) (stop bool)) {
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, types.OrderKey)
+	defer iter.Close()
+
+	for ; iter.Valid(); iter.Next() {
+		var order types.Order
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &order)
+		if handler(&order) {
+			break
+		}
+	}
+}
+
+// isDecCoinsEqual compares the coins ignoring zero amounts
+func isDecCoinsEqual(coinsA, coinsB sdk.DecCoins) bool {
+	diff, _ := coinsA.SafeSub(coinsB)
+	return diff.IsZero()
+}
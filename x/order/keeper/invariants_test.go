@@ -0,0 +1,72 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/common"
+	"github.com/okex/okchain/x/dex"
+	"github.com/okex/okchain/x/order/types"
+	token "github.com/okex/okchain/x/token/types"
+)
+
+func TestInvariants(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	require.Nil(t, testInput.DexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair()))
+	params := types.DefaultParams()
+	params.OrderDeposit = sdk.NewDecCoinFromDec(common.NativeToken, sdk.MustNewDecFromStr("0.1"))
+	keeper.SetParams(ctx, &params)
+
+	invariants := []sdk.Invariant{LockedCoinsInvariant(keeper), DepthBookInvariant(keeper), OrderNumInvariant(keeper)}
+	requireInvariants := func(expected ...bool) {
+		for i, invariant := range invariants {
+			msg, broken := invariant(ctx)
+			require.Equal(t, expected[i], broken, msg)
+		}
+	}
+
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "2.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "11.0", "1.5"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "12.0", "1.0"),
+	}
+	for i, order := range orders {
+		order.Sender = testInput.TestAddrs[i%2]
+		require.Nil(t, keeper.PlaceOrder(ctx, order))
+	}
+	keeper.CancelOrder(ctx, orders[3], ctx.Logger())
+	requireInvariants(false, false, false)
+
+	// the cached depth book and counters are flushed at the end of the block
+	keeper.Cache2Disk(ctx)
+	ctx = ctx.WithBlockHeight(11)
+	keeper.ResetCache(ctx)
+	requireInvariants(false, false, false)
+
+	// coins locked without an order
+	require.Nil(t, keeper.LockCoins(ctx, testInput.TestAddrs[0], sdk.DecCoins{
+		sdk.NewDecCoinFromDec(common.NativeToken, sdk.OneDec())}, token.LockCoinsTypeQuantity))
+	requireInvariants(true, false, false)
+	keeper.UnlockCoins(ctx, testInput.TestAddrs[0], sdk.DecCoins{
+		sdk.NewDecCoinFromDec(common.NativeToken, sdk.OneDec())}, token.LockCoinsTypeQuantity)
+	requireInvariants(false, false, false)
+
+	// a depth book without the orders
+	depthBook := keeper.GetDepthBookCopy(types.TestTokenPair)
+	keeper.SetDepthBook(types.TestTokenPair, &types.DepthBook{Items: depthBook.Items[1:]})
+	requireInvariants(false, true, false)
+	keeper.SetDepthBook(types.TestTokenPair, depthBook)
+	requireInvariants(false, false, false)
+
+	// counters out of the store
+	keeper.diskCache.setOpenNum(4)
+	requireInvariants(false, false, true)
+	keeper.diskCache.setOpenNum(3)
+	keeper.diskCache.setStoreOrderNum(3)
+	requireInvariants(false, false, true)
+}
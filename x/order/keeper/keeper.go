@@ -34,6 +34,7 @@ type Keeper struct {
 	cdc           *codec.Codec // The wire codec for binary encoding/decoding.
 	enableBackend bool         // whether open backend plugin
 	metric        *monitor.OrderMetric
+	hooks         types.OrderHooks
 
 	// cache data in memory to avoid marshal/unmarshal too frequently
 	// reset cache data in BeginBlock
@@ -67,6 +68,15 @@ func NewKeeper(tokenKeeper TokenKeeper, supplyKeeper SupplyKeeper, paramsKeeper
 	}
 }
 
+// SetHooks sets the order hooks
+func (k *Keeper) SetHooks(oh types.OrderHooks) *Keeper {
+	if k.hooks != nil {
+		panic("cannot set order hooks twice")
+	}
+	k.hooks = oh
+	return k
+}
+
 // Reset cache, called in BeginBlock
 func (k Keeper) ResetCache(ctx sdk.Context) {
 	// Reset cache
@@ -107,13 +117,16 @@ func (k Keeper) Cache2Disk(ctx sdk.Context) {
 	closedOrderIDs := k.diskCache.GetClosedOrderIDs()
 
 	k.SetLastClosedOrderIDs(ctx, closedOrderIDs)
+	k.recordClosedOrders(ctx, closedOrderIDs)
 	k.setOpenOrderNum(ctx, k.diskCache.openNum)
 	k.setStoreOrderNum(ctx, k.diskCache.storeOrderNum)
 
 	// update depth book to KVStore
 	updatedBookKeys := k.diskCache.GetUpdatedDepthbookKeys()
 	for _, key := range updatedBookKeys {
-		k.StoreDepthBook(ctx, key, k.diskCache.getDepthBook(key))
+		depthBook := k.diskCache.getDepthBook(key)
+		k.storeDepthBookDiff(ctx, key, depthBook)
+		k.StoreDepthBook(ctx, key, depthBook)
 	}
 
 	updatedItemKeys := k.diskCache.GetUpdatedOrderIDKeys()
@@ -168,6 +181,19 @@ func (k Keeper) GetExpireBlockHeight(ctx sdk.Context, blockHeight int64) []int64
 	return expireBlockNumbers
 }
 
+// GetBlockOrderIDs returns the ids of at most limit orders placed at blockHeight which are still in the store
+func (k Keeper) GetBlockOrderIDs(ctx sdk.Context, blockHeight int64, limit int) []string {
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, types.GetOrderKey(types.FormatOrderIDPrefix(blockHeight)))
+	defer iter.Close()
+
+	var orderIDs []string
+	for ; iter.Valid() && len(orderIDs) < limit; iter.Next() {
+		orderIDs = append(orderIDs, types.GetKey(iter))
+	}
+	return orderIDs
+}
+
 func (k Keeper) GetOrder(ctx sdk.Context, orderID string) *types.Order {
 	store := ctx.KVStore(k.orderStoreKey)
 	orderInfo := store.Get(types.GetOrderKey(orderID))
@@ -297,6 +323,62 @@ func (k Keeper) GetUpdatedOrderIDs() []string {
 	return k.cache.getUpdatedOrderIDs()
 }
 
+// GetAmendedOrders returns the orders moved to a new price in this block, in the order they were amended
+func (k Keeper) GetAmendedOrders() []AmendedOrder {
+	return k.cache.getAmendedOrders()
+}
+
+// GetPendingTakerOrderIDs returns the ids of the orders left unmatched by the continuous auction when the deals
+// limit of the last block was reached, which are matched first in this block
+func (k Keeper) GetPendingTakerOrderIDs(ctx sdk.Context) []string {
+	store := ctx.KVStore(k.orderStoreKey)
+	bz := store.Get(types.PendingTakerOrderIDsKey)
+	orderIDs := []string{}
+	if bz == nil {
+		return orderIDs
+	}
+	k.cdc.MustUnmarshalJSON(bz, &orderIDs)
+	return orderIDs
+}
+
+// SetPendingTakerOrderIDs records the ids of the orders to be matched first by the continuous auction in the next
+// block
+func (k Keeper) SetPendingTakerOrderIDs(ctx sdk.Context, orderIDs []string) {
+	store := ctx.KVStore(k.orderStoreKey)
+	if len(orderIDs) == 0 {
+		if store.Has(types.PendingTakerOrderIDsKey) {
+			store.Delete(types.PendingTakerOrderIDsKey)
+		}
+		return
+	}
+	store.Set(types.PendingTakerOrderIDsKey, k.cdc.MustMarshalJSON(orderIDs))
+}
+
+// GetDeferredExpireOrderIDs returns the ids of the expired orders which were left open since their products were
+// executing, and are expired as soon as the executions are completed
+func (k Keeper) GetDeferredExpireOrderIDs(ctx sdk.Context) []string {
+	store := ctx.KVStore(k.orderStoreKey)
+	bz := store.Get(types.DeferredExpireOrderIDsKey)
+	orderIDs := []string{}
+	if bz == nil {
+		return orderIDs
+	}
+	k.cdc.MustUnmarshalJSON(bz, &orderIDs)
+	return orderIDs
+}
+
+// SetDeferredExpireOrderIDs records the ids of the orders whose expiry waits for the executions of their products
+func (k Keeper) SetDeferredExpireOrderIDs(ctx sdk.Context, orderIDs []string) {
+	store := ctx.KVStore(k.orderStoreKey)
+	if len(orderIDs) == 0 {
+		if store.Has(types.DeferredExpireOrderIDsKey) {
+			store.Delete(types.DeferredExpireOrderIDsKey)
+		}
+		return
+	}
+	store.Set(types.DeferredExpireOrderIDsKey, k.cdc.MustMarshalJSON(orderIDs))
+}
+
 func (k Keeper) addUpdatedOrderID(orderID string) {
 	if k.enableBackend {
 		k.cache.addUpdatedOrderID(orderID)
@@ -419,6 +501,8 @@ func (k Keeper) SetMetric() {
 	k.metric.PartialFilledNum.Set(float64(k.cache.partialFillNum))
 }
 
+// GetBestBidAndAsk returns the highest buy price and the lowest sell price in the depth book,
+// zero if there is no order on the side
 func (k Keeper) GetBestBidAndAsk(ctx sdk.Context, product string) (sdk.Dec, sdk.Dec) {
 	bestBid := sdk.ZeroDec()
 	bestAsk := sdk.ZeroDec()
@@ -431,7 +515,7 @@ func (k Keeper) GetBestBidAndAsk(ctx sdk.Context, product string) (sdk.Dec, sdk.
 			}
 		}
 		if item.SellQuantity.IsPositive() {
-			if item.Price.LT(bestAsk) {
+			if bestAsk.IsZero() || item.Price.LT(bestAsk) {
 				bestAsk = item.Price
 			}
 		}
@@ -441,13 +525,18 @@ func (k Keeper) GetBestBidAndAsk(ctx sdk.Context, product string) (sdk.Dec, sdk.
 
 func (k Keeper) RemoveOrderFromDepthBook(order *types.Order, feeType string) {
 	k.addUpdatedOrderID(order.OrderID)
-	if feeType == types.FeeTypeOrderCancel {
+	k.increaseQuitNum(feeType)
+
+	k.diskCache.removeOrder(order)
+}
+
+func (k Keeper) increaseQuitNum(feeType string) {
+	if feeType == types.FeeTypeOrderCancel || feeType == types.FeeTypeOrderIOCCancel ||
+		feeType == types.FeeTypeOrderSelfTrade {
 		k.cache.IncreaseCancelNum()
 	} else if feeType == types.FeeTypeOrderExpire {
 		k.cache.IncreaseExpireNum()
 	}
-
-	k.diskCache.removeOrder(order)
 }
 
 func (k Keeper) UpdateOrder(order *types.Order, ctx sdk.Context) {
@@ -456,6 +545,7 @@ func (k Keeper) UpdateOrder(order *types.Order, ctx sdk.Context) {
 	// record updated orderID
 	k.addUpdatedOrderID(order.OrderID)
 	if order.Status == types.OrderStatusFilled {
+		k.removeSenderOrderID(ctx, order)
 		k.diskCache.closeOrder(order.OrderID)
 		k.cache.IncreaseFullFillNum()
 	} else {
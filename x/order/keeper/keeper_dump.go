@@ -50,13 +50,64 @@ func (k Keeper) DumpStore(ctx sdk.Context) {
 
 	dumpKvs(orderStore, types.OrderIDsKey, "OrderIDsKey", &orderIDs, unmarshalJSONHanlder, dumpStringHandler)
 
+	dumpKvs(orderStore, types.TriggerOrderIDsKey, "TriggerOrderIDsKey", nil, nil,
+		func(key string, it sdk.Iterator, v interface{}) {
+			logger.Error(fmt.Sprintf("%s: <%X> -> <%s>", key, it.Key()[1:], it.Value()))
+		})
+
+	dumpSenderOrderIDHandler := func(key string, it sdk.Iterator, v interface{}) {
+		sender := sdk.AccAddress(it.Key()[1 : 1+sdk.AddrLen])
+		logger.Error(fmt.Sprintf("%s: <%s> -> <%s>", key, sender, it.Key()[1+sdk.AddrLen:]))
+	}
+	dumpKvs(orderStore, types.SenderOrderIDKey, "SenderOrderIDKey", nil, nil, dumpSenderOrderIDHandler)
+
+	dumpClientOrderIDHandler := func(key string, it sdk.Iterator, v interface{}) {
+		sender := sdk.AccAddress(it.Key()[1 : 1+sdk.AddrLen])
+		logger.Error(fmt.Sprintf("%s: <%s:%s> -> <%s>", key, sender, it.Key()[1+sdk.AddrLen:], it.Value()))
+	}
+	dumpKvs(orderStore, types.ClientOrderIDKey, "ClientOrderIDKey", nil, nil, dumpClientOrderIDHandler)
+
+	var volume sdk.Dec
+	dumpKvs(orderStore, types.TradedVolumeKey, "TradedVolumeKey", &volume, unmarshalHandler,
+		func(key string, it sdk.Iterator, v interface{}) {
+			logger.Error(fmt.Sprintf("%s: <%s> -> <%v>", key, sdk.AccAddress(it.Key()[1:]), v))
+		})
+	dumpKvs(orderStore, types.BlockTradedVolumeKey, "BlockTradedVolumeKey", &volume, unmarshalHandler,
+		func(key string, it sdk.Iterator, v interface{}) {
+			logger.Error(fmt.Sprintf("%s: <%d:%s> -> <%v>", key, common.BytesToInt64(it.Key()[1:9]),
+				sdk.AccAddress(it.Key()[9:]), v))
+		})
+
+	dumpKvs(orderStore, types.PriceHistoryKey, "PriceHistoryKey", &price, unmarshalHandler,
+		func(key string, it sdk.Iterator, v interface{}) {
+			keyLength := len(it.Key())
+			logger.Error(fmt.Sprintf("%s: <%s%d> -> <%v>", key, it.Key()[1:keyLength-8],
+				common.BytesToInt64(it.Key()[keyLength-8:]), v))
+		})
+
+	var depthBookDiff types.DepthBookDiff
+	dumpKvs(orderStore, types.DepthBookDiffKey, "DepthBookDiffKey", &depthBookDiff, unmarshalHandler, dumpStringHandler)
+
 	var expireBlockNumbers []int64
 	dumpKvs(orderStore, types.ExpireBlockHeightKey, "ExpireBlockHeightKey", &expireBlockNumbers, unmarshalHandler, dumpIntHandler)
 
+	dumpKvs(orderStore, types.ClosedOrderKey, "ClosedOrderKey", nil, nil,
+		func(key string, it sdk.Iterator, v interface{}) {
+			closedHeight, orderID := types.SplitClosedOrderKey(it.Key())
+			logger.Error(fmt.Sprintf("%s: <%d:%s>", key, closedHeight, orderID))
+		})
+
 	dumpKv(orderStore, logger, types.LastExpiredBlockHeightKey, "LastExpiredBlockHeightKey")
 	dumpKv(orderStore, logger, types.OpenOrderNumKey, "OpenOrderNumKey")
 	dumpKv(orderStore, logger, types.StoreOrderNumKey, "StoreOrderNumKey")
+	dumpKv(orderStore, logger, types.LastPrunedBlockHeightKey, "LastPrunedBlockHeightKey")
 	dumpKvJSON(orderStore, k, logger, types.RecentlyClosedOrderIDsKey, "RecentlyClosedOrderIDsKey", &orderIDs)
+	dumpKvJSON(orderStore, k, logger, types.TriggeredOrderIDsKey, "TriggeredOrderIDsKey", &orderIDs)
+	dumpKvJSON(orderStore, k, logger, types.PendingTakerOrderIDsKey, "PendingTakerOrderIDsKey", &orderIDs)
+	dumpKvJSON(orderStore, k, logger, types.DeferredExpireOrderIDsKey, "DeferredExpireOrderIDsKey", &orderIDs)
+	var products []string
+	dumpKvJSON(orderStore, k, logger, types.RematchProductsKey, "RematchProductsKey", &products)
+	dumpKvJSON(orderStore, k, logger, types.PendingTriggerProductsKey, "PendingTriggerProductsKey", &products)
 }
 
 func dumpKvs(orderStore sdk.KVStore, k []byte, key string, v interface{},
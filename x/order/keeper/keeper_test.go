@@ -340,6 +340,20 @@ func TestKeeper_GetBestBidAndAsk(t *testing.T) {
 
 	ask, _ := keeper.GetBestBidAndAsk(ctx, types.TestTokenPair)
 	require.EqualValues(t, sdk.MustNewDecFromStr("0"), ask)
+
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "8", "1"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "9", "1"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "11", "1"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10", "1"),
+	}
+	for _, order := range orders {
+		keeper.InsertOrderIntoDepthBook(order)
+	}
+	keeper.Cache2Disk(ctx)
+	bid, ask := keeper.GetBestBidAndAsk(ctx, types.TestTokenPair)
+	require.EqualValues(t, sdk.MustNewDecFromStr("9"), bid)
+	require.EqualValues(t, sdk.MustNewDecFromStr("10"), ask)
 }
 
 func TestKeeper_InsertOrderIntoDepthBook(t *testing.T) {
@@ -4,10 +4,19 @@ import (
 	"github.com/okex/okchain/x/order/types"
 )
 
+// AmendedOrder is an order moved to a new price in this block, after OrderNum orders had been placed in it
+type AmendedOrder struct {
+	OrderID  string
+	OrderNum int64
+}
+
 type Cache struct {
 	// Reset at BeginBlock
 	updatedOrderIDs  []string
+	amendedOrders    []AmendedOrder // orders moved to a new price in this block
 	blockMatchResult *types.BlockMatchResult
+	depthBookDiffs   []*types.DepthBookDiff
+	newOrderNums     map[string]int64 // new orders placed by every sender in this block
 
 	params *types.Params
 
@@ -21,7 +30,10 @@ type Cache struct {
 func NewCache() *Cache {
 	return &Cache{
 		updatedOrderIDs:  []string{},
+		amendedOrders:    []AmendedOrder{},
 		blockMatchResult: nil,
+		depthBookDiffs:   []*types.DepthBookDiff{},
+		newOrderNums:     make(map[string]int64),
 		params:           nil,
 	}
 }
@@ -29,7 +41,10 @@ func NewCache() *Cache {
 // Reset temporary cache, called at BeginBlock
 func (c *Cache) reset() {
 	c.updatedOrderIDs = []string{}
+	c.amendedOrders = []AmendedOrder{}
 	c.blockMatchResult = &types.BlockMatchResult{}
+	c.depthBookDiffs = []*types.DepthBookDiff{}
+	c.newOrderNums = make(map[string]int64)
 	c.params = nil
 
 	c.cancelNum = 0
@@ -43,11 +58,38 @@ func (c *Cache) addUpdatedOrderID(orderID string) {
 	c.updatedOrderIDs = append(c.updatedOrderIDs, orderID)
 }
 
+// amendedOrders
+func (c *Cache) addAmendedOrder(amendedOrder AmendedOrder) {
+	c.amendedOrders = append(c.amendedOrders, amendedOrder)
+}
+
+func (c *Cache) getAmendedOrders() []AmendedOrder {
+	return c.amendedOrders
+}
+
 // blockMatchResult
 func (c *Cache) setBlockMatchResult(result *types.BlockMatchResult) {
 	c.blockMatchResult = result
 }
 
+// depthBookDiffs
+func (c *Cache) addDepthBookDiff(diff *types.DepthBookDiff) {
+	c.depthBookDiffs = append(c.depthBookDiffs, diff)
+}
+
+func (c *Cache) getDepthBookDiffs() []*types.DepthBookDiff {
+	return c.depthBookDiffs
+}
+
+// newOrderNums
+func (c *Cache) addNewOrderNum(sender string) {
+	c.newOrderNums[sender]++
+}
+
+func (c *Cache) getNewOrderNum(sender string) int64 {
+	return c.newOrderNums[sender]
+}
+
 func (c *Cache) IncreaseExpireNum() int64 {
 	c.expireNum++
 	return c.expireNum
@@ -2,14 +2,56 @@ package keeper
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/tendermint/tendermint/libs/log"
 
+	dex "github.com/okex/okchain/x/dex/types"
 	"github.com/okex/okchain/x/order/types"
 	token "github.com/okex/okchain/x/token/types"
 )
 
+// ValidateNewOrder checks whether a new limit order can be placed into the depth book of the product, which is
+// listed, not delisting, neither locked nor halted, and the price and the quantity of the order fit the product
+func (k Keeper) ValidateNewOrder(ctx sdk.Context, product string, price, quantity sdk.Dec) error {
+	tokenPair := k.dexKeeper.GetTokenPair(ctx, product)
+	if tokenPair == nil {
+		return fmt.Errorf("trading pair '%s' does not exist", product)
+	}
+	isDelisting, err := k.dexKeeper.CheckTokenPairUnderDexDelist(ctx, product)
+	if err != nil {
+		return err
+	}
+	if isDelisting {
+		return fmt.Errorf("trading pair '%s' is delisting", product)
+	}
+	if k.IsProductLocked(product) {
+		return fmt.Errorf("the trading pair (%s) is locked, please retry later", product)
+	}
+	return k.ValidateOrderPriceQuantity(ctx, tokenPair, price, quantity)
+}
+
+// ValidateOrderPriceQuantity checks the accuracy and the min quantity of a new limit order, and its price against
+// the price band of the product
+func (k Keeper) ValidateOrderPriceQuantity(ctx sdk.Context, tokenPair *dex.TokenPair, price, quantity sdk.Dec) error {
+	if !price.RoundDecimal(tokenPair.MaxPriceDigit).Equal(price) {
+		return fmt.Errorf("price(%v) over accuracy(%d)", price, tokenPair.MaxPriceDigit)
+	}
+	if !quantity.RoundDecimal(tokenPair.MaxQuantityDigit).Equal(quantity) {
+		return fmt.Errorf("quantity(%v) over accuracy(%d)", quantity, tokenPair.MaxQuantityDigit)
+	}
+	if quantity.LT(tokenPair.MinQuantity) {
+		return fmt.Errorf("quantity should be greater than %s", tokenPair.MinQuantity)
+	}
+	var d int64 = 100000000
+	if !price.MulInt64(d).Mul(quantity).Equal(price.Mul(quantity).MulInt64(d)) {
+		return fmt.Errorf("price(%v) * quantity(%v) over accuracy(%d)", price, quantity, tokenPair.MaxPriceDigit)
+	}
+	return k.CheckPriceBand(ctx, tokenPair.Name(), price)
+}
+
 // tryPlaceOrder tries to charge fee & lock coins for a new order
 func (k Keeper) TryPlaceOrder(ctx sdk.Context, order *types.Order) (fee sdk.DecCoins, err error) {
 	logger := ctx.Logger().With("module", "order")
@@ -30,6 +72,15 @@ func (k Keeper) TryPlaceOrder(ctx sdk.Context, order *types.Order) (fee sdk.DecC
 		return fee, err
 	}
 
+	// lock the refundable deposit, which is held like the fee
+	deposit := k.GetParams(ctx).OrderDeposit
+	if deposit.Amount.Int != nil && deposit.IsPositive() {
+		if err := k.LockCoins(ctx, order.Sender, sdk.DecCoins{deposit}, token.LockCoinsTypeFee); err != nil {
+			return fee, err
+		}
+		order.Deposit = &deposit
+	}
+
 	return fee, err
 }
 
@@ -38,6 +89,12 @@ func (k Keeper) PlaceOrder(ctx sdk.Context, order *types.Order) error {
 	if err != nil {
 		return err
 	}
+	k.PlaceTriedOrder(ctx, order, fee)
+	return nil
+}
+
+// PlaceTriedOrder places a new order whose coins and fee have been locked by TryPlaceOrder, it never fails
+func (k Keeper) PlaceTriedOrder(ctx sdk.Context, order *types.Order, fee sdk.DecCoins) {
 	order.RecordOrderNewFee(fee)
 	k.AddFeeDetail(ctx, order.Sender, fee, types.FeeTypeOrderNew)
 
@@ -46,10 +103,59 @@ func (k Keeper) PlaceOrder(ctx sdk.Context, order *types.Order) error {
 	order.OrderID = types.FormatOrderID(blockHeight, orderNum+1)
 
 	k.SetBlockOrderNum(ctx, blockHeight, orderNum+1)
+	k.cache.addNewOrderNum(order.Sender.String())
+	if order.Trigger != nil {
+		order.Status = types.OrderStatusUntriggered
+	}
 	k.SetOrder(ctx, order.OrderID, order)
+	if orderNum == 0 {
+		// orders placed in this block expire together
+		expireHeight := blockHeight + k.GetParams(ctx).OrderExpireBlocks
+		k.SetExpireBlockHeight(ctx, expireHeight, append(k.GetExpireBlockHeight(ctx, expireHeight), blockHeight))
+	}
+
+	k.InsertSenderOrderID(ctx, order)
+	if order.Trigger != nil {
+		k.InsertTriggerOrder(ctx, order)
+	} else {
+		// update depth book and orderIDsMap in cache
+		k.InsertOrderIntoDepthBook(order)
+	}
+	emitOrderEvent(ctx, types.EventTypeNewOrder, order, order.Price, order.Quantity, fee)
+	k.AfterOrderPlaced(ctx, order)
+}
+
+// AmendOrder changes the price and the total quantity of an open order in place, the locked coins are adjusted
+// by the delta. The order keeps its time priority if only its remaining quantity is reduced.
+func (k Keeper) AmendOrder(ctx sdk.Context, order *types.Order, price, quantity sdk.Dec) error {
+	oldOrder := *order
+	order.Amend(price, quantity)
+
+	lockedCoins := oldOrder.NeedUnlockCoins()
+	denom := lockedCoins[0].Denom
+	delta := order.RemainLocked.Sub(oldOrder.RemainLocked)
+	if delta.IsPositive() {
+		if err := k.LockCoins(ctx, order.Sender, sdk.DecCoins{sdk.NewDecCoinFromDec(denom, delta)},
+			token.LockCoinsTypeQuantity); err != nil {
+			*order = oldOrder
+			return err
+		}
+	} else if delta.IsNegative() {
+		k.UnlockCoins(ctx, order.Sender, sdk.DecCoins{sdk.NewDecCoinFromDec(denom, delta.Neg())},
+			token.LockCoinsTypeQuantity)
+	}
 
-	// update depth book and orderIDsMap in cache
-	k.InsertOrderIntoDepthBook(order)
+	k.SetOrder(ctx, order.OrderID, order)
+	k.addUpdatedOrderID(order.OrderID)
+	keepPriority := price.Equal(oldOrder.Price) && order.RemainQuantity.LTE(oldOrder.RemainQuantity)
+	k.diskCache.amendOrder(&oldOrder, order, keepPriority)
+	if !price.Equal(oldOrder.Price) {
+		// the order might cross the depth book at its new price
+		k.cache.addAmendedOrder(AmendedOrder{
+			OrderID:  order.OrderID,
+			OrderNum: k.GetBlockOrderNum(ctx, ctx.BlockHeight()),
+		})
+	}
 	return nil
 }
 
@@ -61,12 +167,56 @@ func (k Keeper) CancelOrder(ctx sdk.Context, order *types.Order, logger log.Logg
 	return k.quitOrder(ctx, order, types.FeeTypeOrderCancel, logger)
 }
 
+// CancelIOCOrder cancels the remaining quantity of an IOC order after it has been matched
+func (k Keeper) CancelIOCOrder(ctx sdk.Context, order *types.Order, logger log.Logger) sdk.DecCoins {
+	return k.quitOrder(ctx, order, types.FeeTypeOrderIOCCancel, logger)
+}
+
+// KillFOKOrder kills a FOK order which can't be filled entirely, no fee is charged
+func (k Keeper) KillFOKOrder(ctx sdk.Context, order *types.Order, logger log.Logger) sdk.DecCoins {
+	return k.quitOrder(ctx, order, types.FeeTypeOrderFOKKill, logger)
+}
+
+// RejectPostOnlyOrder rejects a post-only order which would take liquidity, no fee is charged
+func (k Keeper) RejectPostOnlyOrder(ctx sdk.Context, order *types.Order, logger log.Logger) sdk.DecCoins {
+	return k.quitOrder(ctx, order, types.FeeTypeOrderPostOnlyReject, logger)
+}
+
+// CancelSelfTradeOrder cancels an order which would trade with another order of the same sender
+func (k Keeper) CancelSelfTradeOrder(ctx sdk.Context, order *types.Order, logger log.Logger) sdk.DecCoins {
+	return k.quitOrder(ctx, order, types.FeeTypeOrderSelfTrade, logger)
+}
+
+// DecrementOrder reduces the quantity of an open order which would trade with another order of the same sender,
+// the coins locked for quantity are unlocked. The order keeps its time priority.
+func (k Keeper) DecrementOrder(ctx sdk.Context, order *types.Order, quantity sdk.Dec) {
+	oldOrder := *order
+	order.Decrement(quantity)
+
+	lockedCoins := oldOrder.NeedUnlockCoins()
+	k.UnlockCoins(ctx, order.Sender, sdk.DecCoins{sdk.NewDecCoinFromDec(lockedCoins[0].Denom,
+		oldOrder.RemainLocked.Sub(order.RemainLocked))}, token.LockCoinsTypeQuantity)
+
+	k.SetOrder(ctx, order.OrderID, order)
+	k.addUpdatedOrderID(order.OrderID)
+	k.diskCache.amendOrder(&oldOrder, order, true)
+}
+
 func (k Keeper) quitOrder(ctx sdk.Context, order *types.Order, feeType string, logger log.Logger) (fee sdk.DecCoins) {
+	untriggered := order.Status == types.OrderStatusUntriggered
 	switch feeType {
 	case types.FeeTypeOrderCancel:
 		order.Cancel()
 	case types.FeeTypeOrderExpire:
 		order.Expire()
+	case types.FeeTypeOrderIOCCancel:
+		order.CancelIOC()
+	case types.FeeTypeOrderFOKKill:
+		order.KillFOK()
+	case types.FeeTypeOrderPostOnlyReject:
+		order.RejectPostOnly()
+	case types.FeeTypeOrderSelfTrade:
+		order.CancelSelfTrade()
 	default:
 		return
 	}
@@ -74,9 +224,110 @@ func (k Keeper) quitOrder(ctx sdk.Context, order *types.Order, feeType string, l
 	// unlock coins in this order & charge fee
 	needUnlockCoins := order.NeedUnlockCoins()
 	k.UnlockCoins(ctx, order.Sender, needUnlockCoins, token.LockCoinsTypeQuantity)
+	fee = k.chargeOrderFee(ctx, order, feeType, logger)
+	k.refundOrderDeposit(ctx, order)
+
+	order.Unlock()
+	k.SetOrder(ctx, order.OrderID, order)
+	k.removeSenderOrderID(ctx, order)
+
+	// remove order from depth book cache, an untriggered order is not in it
+	if untriggered {
+		k.removeUntriggeredOrder(ctx, order, feeType)
+	} else {
+		k.RemoveOrderFromDepthBook(order, feeType)
+	}
+
+	if feeType == types.FeeTypeOrderExpire {
+		emitOrderEvent(ctx, types.EventTypeExpireOrder, order, order.Price, order.RemainQuantity, fee)
+		k.AfterOrderExpired(ctx, order)
+	} else {
+		emitOrderEvent(ctx, types.EventTypeCancelOrder, order, order.Price, order.RemainQuantity, fee)
+		k.AfterOrderCancelled(ctx, order)
+	}
+	return fee
+}
+
+// FillOrder fills the order with quantity at price: the traded coins are transferred, the deal fee is sent to
+// the product owner at the maker or taker fee rate, in the native token at a discount if the order chooses to,
+// and a fully filled order releases its remaining locked coins.
+// The quote amount is added to the trailing traded volume of the sender after the deal fee is charged.
+// Depth book is not touched, the match engine is supposed to update it.
+func (k Keeper) FillOrder(ctx sdk.Context, order *types.Order, price, quantity sdk.Dec, isMaker bool,
+	logger log.Logger) (dealFee sdk.DecCoins) {
+	order.Fill(price, quantity)
 
+	symbols := strings.Split(order.Product, "_")
+	baseCoins := sdk.DecCoins{sdk.NewDecCoinFromDec(symbols[0], quantity)}
+	quoteCoins := sdk.DecCoins{sdk.NewDecCoinFromDec(symbols[1], price.Mul(quantity))}
+	if order.Side == types.BuyOrder {
+		k.BalanceAccount(ctx, order.Sender, quoteCoins, baseCoins)
+	} else {
+		k.BalanceAccount(ctx, order.Sender, baseCoins, quoteCoins)
+	}
+
+	// GetDealFee values the received quote coins with the last price, which is the match price
+	feeParams := k.GetParams(ctx)
+	dealFee = GetDealFee(order, quantity, isMaker, ctx, k, feeParams)
+	feeType := types.FeeTypeOrderDeal
+	if order.NativeFee {
+		// fall back to the received coins if the fee can't be valued in the native token or the balance is short
+		nativeFee, ok := GetNativeDealFee(dealFee, ctx, k, feeParams)
+		if ok && k.GetCoins(ctx, order.Sender).IsAllGTE(nativeFee) {
+			dealFee, feeType = nativeFee, types.FeeTypeOrderNativeDeal
+		}
+	}
+	if err := k.SendFeesToProductOwner(ctx, dealFee, order.Sender, feeType, order.Product); err != nil {
+		logger.Error(fmt.Sprintf("failed to charge order(%s) deal fee: %v", order.OrderID, err))
+	}
+	order.RecordOrderDealFee(dealFee)
+	k.addTradedVolume(ctx, order.Sender, order.Product, price.Mul(quantity))
+
+	if order.Status == types.OrderStatusFilled {
+		// a buy order filled under its price leaves some quote coins locked
+		if order.RemainLocked.IsPositive() {
+			k.UnlockCoins(ctx, order.Sender, order.NeedUnlockCoins(), token.LockCoinsTypeQuantity)
+		}
+		k.chargeOrderFee(ctx, order, types.FeeTypeOrderDeal, logger)
+		k.refundOrderDeposit(ctx, order)
+		order.Unlock()
+	}
+
+	k.UpdateOrder(order, ctx)
+	if order.Status == types.OrderStatusFilled {
+		emitOrderEvent(ctx, types.EventTypeFullFillOrder, order, price, quantity, dealFee)
+	} else {
+		emitOrderEvent(ctx, types.EventTypePartialFillOrder, order, price, quantity, dealFee)
+	}
+	return dealFee
+}
+
+// emitOrderEvent emits the event of a transition of the order, the price and quantity are those of the deal
+// for a fill, the quantity is the remaining one for a cancel or an expire
+func emitOrderEvent(ctx sdk.Context, eventType string, order *types.Order, price, quantity sdk.Dec,
+	fee sdk.DecCoins) {
+	ctx.EventManager().EmitEvent(sdk.NewEvent(eventType,
+		sdk.NewAttribute(types.AttributeKeyOrderID, order.OrderID),
+		sdk.NewAttribute(types.AttributeKeySender, order.Sender.String()),
+		sdk.NewAttribute(types.AttributeKeyProduct, order.Product),
+		sdk.NewAttribute(types.AttributeKeySide, order.Side),
+		sdk.NewAttribute(types.AttributeKeyPrice, price.String()),
+		sdk.NewAttribute(types.AttributeKeyQuantity, quantity.String()),
+		sdk.NewAttribute(types.AttributeKeyFee, fee.String()),
+		sdk.NewAttribute(types.AttributeKeyStatus, strconv.FormatInt(order.Status, 10)),
+	))
+}
+
+// chargeOrderFee unlocks the fee locked when placing the order, and collects the part of it
+// that the order has cost since then
+func (k Keeper) chargeOrderFee(ctx sdk.Context, order *types.Order, feeType string,
+	logger log.Logger) (fee sdk.DecCoins) {
 	lockedFee := GetOrderNewFee(order)
 	fee = GetOrderCostFee(order, ctx)
+	// killed FOK orders and rejected post-only orders never enter the book, they are free
+	if feeType == types.FeeTypeOrderFOKKill || feeType == types.FeeTypeOrderPostOnlyReject {
+		fee = GetZeroFee()
+	}
 	receiveFee := lockedFee.Sub(fee)
 
 	k.UnlockCoins(ctx, order.Sender, lockedFee, token.LockCoinsTypeFee)
@@ -88,11 +339,5 @@ func (k Keeper) quitOrder(ctx sdk.Context, order *types.Order, feeType string, l
 	if err != nil {
 		logger.Error(fmt.Sprintf("failed to charge order(%s) %s fee: %v", feeType, order.OrderID, err))
 	}
-
-	order.Unlock()
-	k.SetOrder(ctx, order.OrderID, order)
-
-	// remove order from depth book cache
-	k.RemoveOrderFromDepthBook(order, feeType)
 	return fee
 }
@@ -0,0 +1,55 @@
+package keeper
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/okex/okchain/x/order/types"
+	token "github.com/okex/okchain/x/token/types"
+)
+
+// CheckOrderLimits checks whether the sender has reached the limit of new orders in this block, or the limit of
+// open orders of the product. The pending orders are the new orders of the sender about to be placed before this one.
+func (k Keeper) CheckOrderLimits(ctx sdk.Context, sender sdk.AccAddress, product string, pendingNum int64) sdk.Error {
+	params := k.GetParams(ctx)
+	if params.MaxNewOrdersPerBlock > 0 &&
+		k.cache.getNewOrderNum(sender.String())+pendingNum >= params.MaxNewOrdersPerBlock {
+		return types.ErrNewOrderLimitExceeded(sender, params.MaxNewOrdersPerBlock)
+	}
+	if params.MaxOpenOrdersPerProduct > 0 {
+		if _, total := k.getSenderOrderIDs(ctx, sender, product, 0, 0); int64(total) >= params.MaxOpenOrdersPerProduct {
+			return types.ErrOpenOrderLimitExceeded(sender, product, params.MaxOpenOrdersPerProduct)
+		}
+	}
+	return nil
+}
+
+// BurnEarlyCancelledDeposit burns the deposit of an order its sender cancels within DepositLockBlocks blocks
+// after placing it
+func (k Keeper) BurnEarlyCancelledDeposit(ctx sdk.Context, order *types.Order, logger log.Logger) {
+	if order.Deposit == nil {
+		return
+	}
+	blocks := ctx.BlockHeight() - types.GetBlockHeightFromOrderID(order.OrderID)
+	if blocks >= k.GetParams(ctx).DepositLockBlocks {
+		return
+	}
+	deposit := sdk.DecCoins{*order.Deposit}
+	if err := k.supplyKeeper.BurnCoins(ctx, token.ModuleName, deposit); err != nil {
+		logger.Error(fmt.Sprintf("failed to burn order(%s) deposit: %v", order.OrderID, err))
+		return
+	}
+	order.RecordOrderBurnedDeposit(deposit)
+	order.Deposit = nil
+}
+
+// refundOrderDeposit returns the deposit of a closed order to its sender
+func (k Keeper) refundOrderDeposit(ctx sdk.Context, order *types.Order) {
+	if order.Deposit == nil {
+		return
+	}
+	k.UnlockCoins(ctx, order.Sender, sdk.DecCoins{*order.Deposit}, token.LockCoinsTypeFee)
+	order.Deposit = nil
+}
@@ -7,6 +7,7 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
 	"github.com/stretchr/testify/require"
 
 	"github.com/okex/okchain/x/common"
@@ -206,3 +207,108 @@ func TestPlaceOrderAndExpireOrder(t *testing.T) {
 	require.EqualValues(t, 0, keeper.diskCache.openNum)
 	require.EqualValues(t, 1, keeper.cache.expireNum)
 }
+
+func TestQuitOrderByTimeInForce(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+	}
+	for _, order := range orders {
+		order.Sender = testInput.TestAddrs[0]
+		require.Nil(t, keeper.PlaceOrder(ctx, order))
+	}
+
+	// quit the orders a block later, only the IOC order is charged
+	ctx = ctx.WithBlockHeight(11)
+	fee := keeper.CancelIOCOrder(ctx, orders[0], ctx.Logger())
+	require.Equal(t, "0.00000100"+common.NativeToken, fee.String())
+	require.EqualValues(t, types.OrderStatusIOCCancelled, orders[0].Status)
+	fee = keeper.KillFOKOrder(ctx, orders[1], ctx.Logger())
+	require.True(t, fee.IsZero())
+	require.EqualValues(t, types.OrderStatusFOKKilled, orders[1].Status)
+	fee = keeper.RejectPostOnlyOrder(ctx, orders[2], ctx.Logger())
+	require.True(t, fee.IsZero())
+	require.EqualValues(t, types.OrderStatusPostOnlyRejected, orders[2].Status)
+
+	// check account balance
+	acc := testInput.AccountKeeper.GetAccount(ctx, testInput.TestAddrs[0])
+	expectCoins := sdk.DecCoins{
+		sdk.NewDecCoinFromDec(common.NativeToken, sdk.MustNewDecFromStr("99.999999")),
+		sdk.NewDecCoinFromDec(common.TestToken, sdk.MustNewDecFromStr("100")),
+	}
+	require.EqualValues(t, expectCoins.String(), acc.GetCoins().String())
+	// check depth book and metrics
+	require.EqualValues(t, 0, len(keeper.GetDepthBookCopy(types.TestTokenPair).Items))
+	require.EqualValues(t, 3, len(keeper.diskCache.GetClosedOrderIDs()))
+	require.EqualValues(t, 1, keeper.cache.cancelNum)
+}
+
+func TestOrderEvents(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10).WithEventManager(sdk.NewEventManager())
+	require.Nil(t, testInput.DexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair()))
+
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "2.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "12.0", "1.0"),
+	}
+	for _, order := range orders {
+		order.Sender = testInput.TestAddrs[0]
+		require.Nil(t, keeper.PlaceOrder(ctx, order))
+	}
+	price := sdk.MustNewDecFromStr("10.0")
+	dealFee := keeper.FillOrder(ctx, orders[0], price, sdk.OneDec(), true, ctx.Logger())
+	keeper.FillOrder(ctx, orders[1], price, sdk.OneDec(), false, ctx.Logger())
+	cancelFee := keeper.CancelOrder(ctx, orders[0], ctx.Logger())
+	keeper.ExpireOrder(ctx, orders[2], ctx.Logger())
+
+	var eventTypes []string
+	var events []map[string]string
+	for _, event := range ctx.EventManager().Events() {
+		// the transfers of coins are left out
+		if event.Type == bank.EventTypeTransfer || event.Type == sdk.EventTypeMessage {
+			continue
+		}
+		eventTypes = append(eventTypes, event.Type)
+		attributes := make(map[string]string)
+		for _, attribute := range event.Attributes {
+			attributes[string(attribute.Key)] = string(attribute.Value)
+		}
+		events = append(events, attributes)
+	}
+	require.EqualValues(t, []string{types.EventTypeNewOrder, types.EventTypeNewOrder, types.EventTypeNewOrder,
+		types.EventTypePartialFillOrder, types.EventTypeFullFillOrder, types.EventTypeCancelOrder,
+		types.EventTypeExpireOrder}, eventTypes)
+
+	require.EqualValues(t, map[string]string{
+		types.AttributeKeyOrderID:  orders[0].OrderID,
+		types.AttributeKeySender:   testInput.TestAddrs[0].String(),
+		types.AttributeKeyProduct:  types.TestTokenPair,
+		types.AttributeKeySide:     types.BuyOrder,
+		types.AttributeKeyPrice:    "10.00000000",
+		types.AttributeKeyQuantity: "2.00000000",
+		types.AttributeKeyFee:      orders[0].GetExtraInfoWithKey(types.OrderExtraInfoKeyNewFee),
+		types.AttributeKeyStatus:   "0",
+	}, events[0])
+	require.EqualValues(t, "1.00000000", events[3][types.AttributeKeyQuantity])
+	require.EqualValues(t, dealFee.String(), events[3][types.AttributeKeyFee])
+	require.EqualValues(t, "0", events[3][types.AttributeKeyStatus])
+	require.EqualValues(t, "1", events[4][types.AttributeKeyStatus])
+	// the remaining quantity is cancelled
+	require.EqualValues(t, "1.00000000", events[5][types.AttributeKeyQuantity])
+	require.EqualValues(t, cancelFee.String(), events[5][types.AttributeKeyFee])
+	require.EqualValues(t, "4", events[5][types.AttributeKeyStatus])
+	require.EqualValues(t, orders[2].OrderID, events[6][types.AttributeKeyOrderID])
+	require.EqualValues(t, "3", events[6][types.AttributeKeyStatus])
+}
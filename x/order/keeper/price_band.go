@@ -0,0 +1,144 @@
+package keeper
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/common"
+	dex "github.com/okex/okchain/x/dex/types"
+	"github.com/okex/okchain/x/order/types"
+)
+
+// GetPriceBand returns the price band of the product, nil if it has none
+func (k Keeper) GetPriceBand(ctx sdk.Context, product string) *dex.PriceBand {
+	tokenPair := k.dexKeeper.GetTokenPair(ctx, product)
+	if tokenPair == nil {
+		return nil
+	}
+	return tokenPair.PriceBand
+}
+
+// GetReferencePrice returns the reference price of the price band of the product, which is the last price,
+// or the time-weighted average of the last price at the end of the last TWAPBlocks blocks
+func (k Keeper) GetReferencePrice(ctx sdk.Context, product string, band *dex.PriceBand) sdk.Dec {
+	if band.TWAPBlocks <= 0 {
+		return k.GetLastPrice(ctx, product)
+	}
+
+	height := ctx.BlockHeight()
+	windowStart := height - band.TWAPBlocks
+	store := ctx.KVStore(k.orderStoreKey)
+	prefix := types.GetPriceHistoryPrefix(product)
+	iter := store.ReverseIterator(prefix, types.GetPriceHistoryKey(product, height))
+	defer iter.Close()
+
+	// a price lasts from the block it's recorded at till the next record
+	sum := sdk.ZeroDec()
+	end := height
+	var price sdk.Dec
+	for ; iter.Valid() && end > windowStart; iter.Next() {
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &price)
+		start := common.BytesToInt64(iter.Key()[len(prefix):])
+		if start < windowStart {
+			start = windowStart
+		}
+		sum = sum.Add(price.MulInt64(end - start))
+		end = start
+	}
+	if price.Int == nil {
+		return k.GetLastPrice(ctx, product)
+	}
+	// the prices before the earliest record are unknown, which are taken as the earliest one
+	if end > windowStart {
+		sum = sum.Add(price.MulInt64(end - windowStart))
+	}
+	return sum.QuoInt64(band.TWAPBlocks)
+}
+
+// IsPriceOutOfBand checks whether the price is out of the price band of the product, if the band takes the action
+func (k Keeper) IsPriceOutOfBand(ctx sdk.Context, product string, price sdk.Dec, action string) bool {
+	band := k.GetPriceBand(ctx, product)
+	if band == nil || band.Action != action {
+		return false
+	}
+	return !band.Contains(k.GetReferencePrice(ctx, product, band), price)
+}
+
+// CheckPriceBand rejects the order priced out of the price band of the product, if the band rejects such orders
+func (k Keeper) CheckPriceBand(ctx sdk.Context, product string, price sdk.Dec) error {
+	if k.IsPriceOutOfBand(ctx, product, price, dex.PriceBandActionReject) {
+		band := k.GetPriceBand(ctx, product)
+		return fmt.Errorf("price(%v) is out of the price band of %s, which is within %s of the reference price %s",
+			price, product, band.MaxDeviation, k.GetReferencePrice(ctx, product, band))
+	}
+	return nil
+}
+
+// RecordPriceHistory records the last prices of the products whose depth books are updated in this block and
+// whose price bands refer to time-weighted average prices, and drops the records no longer needed by their windows.
+// The last price of a product never changes without updating its depth book.
+func (k Keeper) RecordPriceHistory(ctx sdk.Context) {
+	products := k.diskCache.GetUpdatedDepthbookKeys()
+	height := ctx.BlockHeight()
+	store := ctx.KVStore(k.orderStoreKey)
+	for _, product := range products {
+		band := k.GetPriceBand(ctx, product)
+		if band == nil || band.TWAPBlocks <= 0 {
+			continue
+		}
+		store.Set(types.GetPriceHistoryKey(product, height), k.cdc.MustMarshalBinaryBare(k.GetLastPrice(ctx, product)))
+
+		// the window of the next block starts at height+1-TWAPBlocks, the latest record before it is kept
+		windowEnd := height + 2 - band.TWAPBlocks
+		if windowEnd <= 0 {
+			continue
+		}
+		iter := store.ReverseIterator(types.GetPriceHistoryPrefix(product), types.GetPriceHistoryKey(product, windowEnd))
+		var keys [][]byte
+		for ; iter.Valid(); iter.Next() {
+			keys = append(keys, iter.Key())
+		}
+		iter.Close()
+		for i := 1; i < len(keys); i++ {
+			store.Delete(keys[i])
+		}
+	}
+}
+
+// HaltProduct halts the matching of the product which would trade at price out of its price band.
+// The product is locked till the end of the cooling-off period, so that its depth book is frozen.
+func (k Keeper) HaltProduct(ctx sdk.Context, product string, price sdk.Dec) {
+	band := k.GetPriceBand(ctx, product)
+	if band == nil {
+		return
+	}
+	lock := &types.ProductLock{
+		BlockHeight:   ctx.BlockHeight(),
+		Price:         price,
+		Quantity:      sdk.ZeroDec(),
+		BuyExecuted:   sdk.ZeroDec(),
+		SellExecuted:  sdk.ZeroDec(),
+		HaltEndHeight: ctx.BlockHeight() + band.HaltBlocks,
+	}
+	k.SetProductLock(ctx, product, lock)
+
+	referencePrice := k.GetReferencePrice(ctx, product, band)
+	ctx.EventManager().EmitEvent(sdk.NewEvent(types.EventTypeHaltProduct,
+		sdk.NewAttribute(types.AttributeKeyProduct, product),
+		sdk.NewAttribute(types.AttributeKeyPrice, price.String()),
+		sdk.NewAttribute(types.AttributeKeyReferencePrice, referencePrice.String()),
+		sdk.NewAttribute(types.AttributeKeyHaltEndHeight, fmt.Sprintf("%d", lock.HaltEndHeight)),
+	))
+	ctx.Logger().With("module", "order").Info(fmt.Sprintf("product(%s) is halted till height %d "+
+		"since price %s is out of the band around %s", product, lock.HaltEndHeight, price, referencePrice))
+}
+
+// ResumeProduct unlocks the halted product, whose matching resumes with a call auction
+func (k Keeper) ResumeProduct(ctx sdk.Context, product string) {
+	k.UnlockProduct(ctx, product)
+	ctx.EventManager().EmitEvent(sdk.NewEvent(types.EventTypeResumeProduct,
+		sdk.NewAttribute(types.AttributeKeyProduct, product),
+	))
+	ctx.Logger().With("module", "order").Info(fmt.Sprintf("product(%s) is resumed", product))
+}
@@ -0,0 +1,56 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/dex"
+	"github.com/okex/okchain/x/order/types"
+)
+
+func TestGetReferencePrice(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	tokenPair := dex.GetBuiltInTokenPair()
+	band := &dex.PriceBand{MaxDeviation: sdk.MustNewDecFromStr("0.1"), TWAPBlocks: 4, Action: dex.PriceBandActionReject}
+	tokenPair.PriceBand = band
+	require.Nil(t, testInput.DexKeeper.SaveTokenPair(ctx, tokenPair))
+
+	// no records, the last price is the init price 10.0
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.0"), keeper.GetReferencePrice(ctx, types.TestTokenPair, band))
+
+	recordPrice := func(height int64, price string) {
+		ctx = ctx.WithBlockHeight(height)
+		keeper.SetLastPrice(ctx, types.TestTokenPair, sdk.MustNewDecFromStr(price))
+		keeper.diskCache.setDepthBook(types.TestTokenPair, &types.DepthBook{})
+		keeper.RecordPriceHistory(ctx)
+		keeper.Cache2Disk(ctx)
+	}
+	recordPrice(10, "10.0")
+	recordPrice(12, "14.0")
+
+	// the window of block 13 is [9, 12], and the price before the earliest record is taken as 10.0
+	ctx = ctx.WithBlockHeight(13)
+	require.EqualValues(t, sdk.MustNewDecFromStr("11.0"), keeper.GetReferencePrice(ctx, types.TestTokenPair, band))
+	// the band without a TWAP window refers to the last price
+	require.EqualValues(t, sdk.MustNewDecFromStr("14.0"), keeper.GetReferencePrice(ctx, types.TestTokenPair, &dex.PriceBand{}))
+
+	recordPrice(15, "12.0")
+	// the window of block 16 is [12, 15]
+	ctx = ctx.WithBlockHeight(16)
+	require.EqualValues(t, sdk.MustNewDecFromStr("13.5"), keeper.GetReferencePrice(ctx, types.TestTokenPair, band))
+	require.True(t, keeper.IsPriceOutOfBand(ctx, types.TestTokenPair, sdk.MustNewDecFromStr("15.0"),
+		dex.PriceBandActionReject))
+	require.False(t, keeper.IsPriceOutOfBand(ctx, types.TestTokenPair, sdk.MustNewDecFromStr("14.5"),
+		dex.PriceBandActionReject))
+	require.False(t, keeper.IsPriceOutOfBand(ctx, types.TestTokenPair, sdk.MustNewDecFromStr("15.0"),
+		dex.PriceBandActionHalt))
+
+	// the record at block 10 is dropped, the one at block 12 still covers the window of block 16
+	store := ctx.KVStore(keeper.orderStoreKey)
+	require.Nil(t, store.Get(types.GetPriceHistoryKey(types.TestTokenPair, 10)))
+	require.NotNil(t, store.Get(types.GetPriceHistoryKey(types.TestTokenPair, 12)))
+}
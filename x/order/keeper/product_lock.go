@@ -20,3 +20,13 @@ func (k Keeper) UnlockProduct(ctx sdk.Context, product string) {
 func (k Keeper) AnyProductLocked() bool {
 	return k.dexKeeper.IsAnyProductLocked()
 }
+
+// IsProductExecuting checks whether the product is locked by an execution of the periodic auction,
+// rather than halted by its price band
+func (k Keeper) IsProductExecuting(product string) bool {
+	if !k.dexKeeper.IsTokenPairLocked(product) {
+		return false
+	}
+	lock, ok := k.dexKeeper.GetLockedProductsCopy().Data[product]
+	return ok && !lock.IsHalt()
+}
@@ -0,0 +1,153 @@
+package keeper
+
+import (
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/common"
+	"github.com/okex/okchain/x/order/types"
+)
+
+// recordClosedOrders records the orders closed in this block to be pruned after ClosedOrderRetentionBlocks blocks
+func (k Keeper) recordClosedOrders(ctx sdk.Context, orderIDs []string) {
+	if len(orderIDs) == 0 || k.GetParams(ctx).ClosedOrderRetentionBlocks <= 0 {
+		return
+	}
+	store := ctx.KVStore(k.orderStoreKey)
+	for _, orderID := range orderIDs {
+		store.Set(types.GetClosedOrderKey(ctx.BlockHeight(), orderID), []byte{})
+	}
+}
+
+// InsertClosedOrder stores a closed order from genesis, which is retained for ClosedOrderRetentionBlocks blocks
+// from the genesis height
+func (k Keeper) InsertClosedOrder(ctx sdk.Context, order *types.Order) {
+	k.SetOrder(ctx, order.OrderID, order)
+	k.diskCache.storeOrderNum++
+	k.recordClosedOrders(ctx, []string{order.OrderID})
+}
+
+// GetRetainedClosedOrders returns the closed orders which are still retained, not those due to be pruned
+func (k Keeper) GetRetainedClosedOrders(ctx sdk.Context) []*types.Order {
+	retention := k.GetParams(ctx).ClosedOrderRetentionBlocks
+	if retention <= 0 {
+		return nil
+	}
+	startHeight := ctx.BlockHeight() - retention + 1
+	if startHeight < 0 {
+		startHeight = 0
+	}
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := store.Iterator(types.GetClosedOrdersPrefix(startHeight), sdk.PrefixEndBytes(types.ClosedOrderKey))
+	defer iter.Close()
+
+	var orders []*types.Order
+	for ; iter.Valid(); iter.Next() {
+		_, orderID := types.SplitClosedOrderKey(iter.Key())
+		if order := k.GetOrder(ctx, orderID); order != nil {
+			orders = append(orders, order)
+		}
+	}
+	return orders
+}
+
+// PruneClosedOrders drops the orders closed ClosedOrderRetentionBlocks blocks ago or earlier, together with the order
+// nums and the expire heights of the blocks left without orders. At most MaxPruneOrdersPerBlock closed orders are
+// handled in a block, the rest are continued in the following blocks. It returns the number of the dropped orders.
+func (k Keeper) PruneClosedOrders(ctx sdk.Context) int {
+	params := k.GetParams(ctx)
+	pruneHeight := ctx.BlockHeight() - params.ClosedOrderRetentionBlocks
+	if params.ClosedOrderRetentionBlocks <= 0 || pruneHeight <= 0 {
+		return 0
+	}
+
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := store.Iterator(types.ClosedOrderKey, types.GetClosedOrdersPrefix(pruneHeight+1))
+	var keys [][]byte
+	var orderIDs []string
+	lastPrunedHeight := pruneHeight
+	for ; iter.Valid(); iter.Next() {
+		closedHeight, orderID := types.SplitClosedOrderKey(iter.Key())
+		if len(keys) == types.MaxPruneOrdersPerBlock {
+			lastPrunedHeight = closedHeight - 1
+			break
+		}
+		keys = append(keys, iter.Key())
+		orderIDs = append(orderIDs, orderID)
+	}
+	iter.Close()
+
+	prunedNum := 0
+	var placedHeights []int64
+	for i, orderID := range orderIDs {
+		store.Delete(keys[i])
+		// the expired orders and the closed orders placed with expired ones might have been dropped
+		order := k.GetOrder(ctx, orderID)
+		if order == nil || order.Status == types.OrderStatusOpen || order.Status == types.OrderStatusUntriggered {
+			continue
+		}
+		k.DropOrder(ctx, orderID)
+		k.diskCache.DecreaseStoreOrderNum(1)
+		placedHeights = append(placedHeights, types.GetBlockHeightFromOrderID(orderID))
+		prunedNum++
+	}
+
+	sort.Slice(placedHeights, func(i, j int) bool { return placedHeights[i] < placedHeights[j] })
+	for i, placedHeight := range placedHeights {
+		if (i > 0 && placedHeights[i-1] == placedHeight) || len(k.GetBlockOrderIDs(ctx, placedHeight, 1)) > 0 {
+			continue
+		}
+		k.DropBlockOrderNum(ctx, placedHeight)
+		k.removeExpireBlockHeight(ctx, placedHeight+params.OrderExpireBlocks, placedHeight)
+	}
+	k.setLastPrunedBlockHeight(ctx, lastPrunedHeight)
+	return prunedNum
+}
+
+// removeExpireBlockHeight removes a placement height from the heights whose orders expire at blockHeight
+func (k Keeper) removeExpireBlockHeight(ctx sdk.Context, blockHeight, placedHeight int64) {
+	placedHeights := k.GetExpireBlockHeight(ctx, blockHeight)
+	var remainHeights []int64
+	for _, height := range placedHeights {
+		if height != placedHeight {
+			remainHeights = append(remainHeights, height)
+		}
+	}
+	if len(remainHeights) == len(placedHeights) {
+		return
+	}
+	if len(remainHeights) == 0 {
+		k.DropExpireBlockHeight(ctx, blockHeight)
+		return
+	}
+	k.SetExpireBlockHeight(ctx, blockHeight, remainHeights)
+}
+
+// GetLastPrunedBlockHeight returns the height till which all the closed orders have been pruned
+func (k Keeper) GetLastPrunedBlockHeight(ctx sdk.Context) int64 {
+	store := ctx.KVStore(k.orderStoreKey)
+	numBytes := store.Get(types.LastPrunedBlockHeightKey)
+	if numBytes == nil {
+		return 0
+	}
+	return common.BytesToInt64(numBytes)
+}
+
+func (k Keeper) setLastPrunedBlockHeight(ctx sdk.Context, blockHeight int64) {
+	store := ctx.KVStore(k.orderStoreKey)
+	store.Set(types.LastPrunedBlockHeightKey, common.Int64ToBytes(blockHeight))
+}
+
+// getClosedOrderNum returns the number of the closed orders waiting to be pruned
+func (k Keeper) getClosedOrderNum(ctx sdk.Context) int64 {
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, types.ClosedOrderKey)
+	defer iter.Close()
+
+	var num int64
+	for ; iter.Valid(); iter.Next() {
+		num++
+	}
+	return num
+}
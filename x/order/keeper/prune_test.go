@@ -0,0 +1,90 @@
+package keeper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/dex"
+	"github.com/okex/okchain/x/order/types"
+)
+
+func TestPruneClosedOrders(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	require.Nil(t, testInput.DexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair()))
+	params := types.DefaultParams()
+	params.ClosedOrderRetentionBlocks = 5
+	keeper.SetParams(ctx, &params)
+
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "2.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "11.0", "1.5"),
+	}
+	for _, order := range orders {
+		order.Sender = testInput.TestAddrs[0]
+		require.Nil(t, keeper.PlaceOrder(ctx, order))
+	}
+	keeper.CancelOrder(ctx, orders[0], ctx.Logger())
+	keeper.CancelOrder(ctx, orders[1], ctx.Logger())
+	keeper.Cache2Disk(ctx)
+
+	// the closed orders are retained for 5 blocks
+	ctx = ctx.WithBlockHeight(14)
+	keeper.ResetCache(ctx)
+	require.EqualValues(t, 0, keeper.PruneClosedOrders(ctx))
+	require.Len(t, keeper.GetRetainedClosedOrders(ctx), 2)
+	ss := GetStoreStatistic(ctx, keeper)
+	require.EqualValues(t, 3, ss.StoreOrderNum)
+	require.EqualValues(t, 2, ss.ClosedOrderNum)
+	require.EqualValues(t, 9, ss.LastPrunedBlockHeight)
+
+	ctx = ctx.WithBlockHeight(15)
+	keeper.ResetCache(ctx)
+	require.Len(t, keeper.GetRetainedClosedOrders(ctx), 0)
+	require.EqualValues(t, 2, keeper.PruneClosedOrders(ctx))
+	keeper.Cache2Disk(ctx)
+	require.Nil(t, keeper.GetOrder(ctx, orders[0].OrderID))
+	require.Nil(t, keeper.GetOrder(ctx, orders[1].OrderID))
+	// the order num and the expire height of the block are kept for its open order
+	require.EqualValues(t, 3, keeper.GetBlockOrderNum(ctx, 10))
+	require.EqualValues(t, []int64{10}, keeper.GetExpireBlockHeight(ctx, 10+params.OrderExpireBlocks))
+	ss = GetStoreStatistic(ctx, keeper)
+	require.EqualValues(t, 1, ss.StoreOrderNum)
+	require.EqualValues(t, 0, ss.ClosedOrderNum)
+	require.EqualValues(t, 10, ss.LastPrunedBlockHeight)
+
+	ctx = ctx.WithBlockHeight(20)
+	keeper.ResetCache(ctx)
+	keeper.CancelOrder(ctx, orders[2], ctx.Logger())
+	keeper.Cache2Disk(ctx)
+
+	// the block is dropped with its last order
+	ctx = ctx.WithBlockHeight(25)
+	keeper.ResetCache(ctx)
+	require.EqualValues(t, 1, keeper.PruneClosedOrders(ctx))
+	keeper.Cache2Disk(ctx)
+	require.Nil(t, keeper.GetOrder(ctx, orders[2].OrderID))
+	require.EqualValues(t, 0, keeper.GetBlockOrderNum(ctx, 10))
+	require.Len(t, keeper.GetExpireBlockHeight(ctx, 10+params.OrderExpireBlocks), 0)
+	ss = GetStoreStatistic(ctx, keeper)
+	require.EqualValues(t, 0, ss.StoreOrderNum)
+	require.EqualValues(t, 20, ss.LastPrunedBlockHeight)
+	require.EqualValues(t, 0, ss.ClosedOrderNum)
+
+	// closed orders are kept till they expire without the retention
+	params.ClosedOrderRetentionBlocks = 0
+	keeper.SetParams(ctx, &params)
+	order := mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0")
+	order.Sender = testInput.TestAddrs[0]
+	require.Nil(t, keeper.PlaceOrder(ctx, order))
+	keeper.CancelOrder(ctx, order, ctx.Logger())
+	keeper.Cache2Disk(ctx)
+	ctx = ctx.WithBlockHeight(100)
+	keeper.ResetCache(ctx)
+	require.EqualValues(t, 0, keeper.PruneClosedOrders(ctx))
+	require.NotNil(t, keeper.GetOrder(ctx, order.OrderID))
+	require.EqualValues(t, 0, GetStoreStatistic(ctx, keeper).ClosedOrderNum)
+}
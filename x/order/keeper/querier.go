@@ -1,7 +1,9 @@
 package keeper
 
 import (
+	"encoding/json"
 	"fmt"
+	"math/big"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -13,6 +15,8 @@ import (
 
 const (
 	DefaultBookSize = 200
+	DefaultPage     = 1
+	DefaultPerPage  = 50
 )
 
 // NewQuerier is the module level router for state queries
@@ -21,8 +25,14 @@ func NewQuerier(keeper Keeper) sdk.Querier {
 		switch path[0] {
 		case types.QueryOrderDetail:
 			return queryOrder(ctx, path[1:], req, keeper)
+		case types.QueryClientOrder:
+			return queryClientOrder(ctx, path[1:], req, keeper)
+		case types.QueryOpenOrders:
+			return queryOpenOrders(ctx, req, keeper)
 		case types.QueryDepthBook:
 			return queryDepthBook(ctx, path[1:], req, keeper)
+		case types.QueryDepthDiff:
+			return queryDepthDiff(ctx, path[1:], keeper)
 		case types.QueryStore:
 			return queryStore(ctx, path[1:], req, keeper)
 		case types.QueryParameters:
@@ -47,30 +57,108 @@ func queryOrder(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Ke
 	return bz, nil
 }
 
-type QueryDepthBookParams struct {
+// queryClientOrder queries the latest order of a sender with the client order id
+// nolint: unparam
+func queryClientOrder(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) (res []byte,
+	err sdk.Error) {
+	if len(path) != 2 {
+		return nil, sdk.ErrUnknownRequest("sender and client order id are required")
+	}
+	sender, decodeErr := sdk.AccAddressFromBech32(path[0])
+	if decodeErr != nil {
+		return nil, sdk.ErrInvalidAddress(fmt.Sprintf("invalid address(%s): %v", path[0], decodeErr))
+	}
+	orderID := keeper.GetOrderIDByClientOrderID(ctx, sender, path[1])
+	if orderID == "" {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("client order(%v) does not exist", path[1]))
+	}
+	return queryOrder(ctx, []string{orderID}, req, keeper)
+}
+
+type QueryOpenOrdersParams struct {
+	Address string
 	Product string
-	Size    int
+	Page    int
+	PerPage int
+}
+
+// creates a new instance of QueryOpenOrdersParams
+func NewQueryOpenOrdersParams(addr, product string, page, perPage int) QueryOpenOrdersParams {
+	if page == 0 {
+		page = DefaultPage
+	}
+	if perPage == 0 {
+		perPage = DefaultPerPage
+	}
+	return QueryOpenOrdersParams{
+		Address: addr,
+		Product: product,
+		Page:    page,
+		PerPage: perPage,
+	}
+}
+
+// queryOpenOrders queries a page of the open orders of an address from the store, without the backend
+func queryOpenOrders(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	var params QueryOpenOrdersParams
+	err := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrUnknownRequest(sdk.AppendMsgToErr("incorrectly formatted request data", err.Error()))
+	}
+	sender, err := sdk.AccAddressFromBech32(params.Address)
+	if err != nil {
+		return nil, sdk.ErrUnknownRequest(sdk.AppendMsgToErr("invalid address", err.Error()))
+	}
+	if params.Page <= 0 || params.PerPage <= 0 {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("invalid page(%d) or per page(%d)", params.Page,
+			params.PerPage))
+	}
+
+	offset, limit := common.GetPage(params.Page, params.PerPage)
+	orders, total := keeper.GetSenderOpenOrders(ctx, sender, params.Product, offset, limit)
+
+	var response *common.ListResponse
+	if len(orders) > 0 {
+		response = common.GetListResponse(total, params.Page, params.PerPage, orders)
+	} else {
+		response = common.GetEmptyListResponse(total, params.Page, params.PerPage)
+	}
+	bz, err := json.Marshal(response)
+	if err != nil {
+		return nil, sdk.ErrInternal(err.Error())
+	}
+	return bz, nil
+}
+
+type QueryDepthBookParams struct {
+	Product  string
+	Size     int
+	TickSize string // aggregate the depth book into price levels of the tick size, no aggregation if empty
 }
 
 // creates a new instance of QueryProposalParams
-func NewQueryDepthBookParams(product string, size int) QueryDepthBookParams {
+func NewQueryDepthBookParams(product string, size int, tickSize string) QueryDepthBookParams {
 	if size == 0 {
 		size = DefaultBookSize
 	}
 	return QueryDepthBookParams{
-		Product: product,
-		Size:    size,
+		Product:  product,
+		Size:     size,
+		TickSize: tickSize,
 	}
 }
 
 type BookResItem struct {
-	Price    string `json:"price"`
-	Quantity string `json:"quantity"`
+	Price       string `json:"price"`
+	Quantity    string `json:"quantity"`
+	CumQuantity string `json:"cum_quantity"` // quantity from the best price to this level
+	CumNotional string `json:"cum_notional"` // notional from the best price to this level
 }
 
 type BookRes struct {
-	Asks []BookResItem `json:"asks"`
-	Bids []BookResItem `json:"bids"`
+	Asks     []BookResItem `json:"asks"`
+	Bids     []BookResItem `json:"bids"`
+	Sequence int64         `json:"sequence"` // the depth book is rebuilt by applying the following diffs to it
 }
 
 // nolint: unparam
@@ -86,43 +174,124 @@ func queryDepthBook(ctx sdk.Context, path []string, req abci.RequestQuery, keepe
 	if tokenPair == nil {
 		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("Non-exist product: %s", params.Product))
 	}
+	tickSize, sdkErr := parseTickSize(params.TickSize)
+	if sdkErr != nil {
+		return nil, sdkErr
+	}
 	depthBook := keeper.GetDepthBookFromDB(ctx, params.Product)
+	asks, bids := aggregateDepthBook(depthBook, tickSize, params.Size)
 
-	var asks []BookResItem
-	var bids []BookResItem
-	for _, item := range depthBook.Items {
-		if item.SellQuantity.IsPositive() {
-			asks = append([]BookResItem{{item.Price.String(), item.SellQuantity.String()}}, asks...)
+	bookRes := BookRes{
+		Asks:     asks,
+		Bids:     bids,
+		Sequence: keeper.GetDepthBookSequence(ctx, params.Product),
+	}
+	bz := keeper.cdc.MustMarshalJSON(bookRes)
+	return bz, nil
+}
+
+// queryDepthDiff queries the latest diff of the depth book of a product, whose sequence is 0 if the book never changes
+func queryDepthDiff(ctx sdk.Context, path []string, keeper Keeper) ([]byte, sdk.Error) {
+	if len(path) != 1 {
+		return nil, sdk.ErrUnknownRequest("product is required")
+	}
+	if keeper.GetDexKeeper().GetTokenPair(ctx, path[0]) == nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("Non-exist product: %s", path[0]))
+	}
+	diff := keeper.GetDepthBookDiff(ctx, path[0])
+	if diff == nil {
+		diff = &types.DepthBookDiff{Product: path[0]}
+	}
+	bz := keeper.cdc.MustMarshalJSON(diff)
+	return bz, nil
+}
+
+// parseTickSize parses the tick size of the depth book query, which is nil if not given
+func parseTickSize(tickSizeStr string) (*sdk.Dec, sdk.Error) {
+	if tickSizeStr == "" {
+		return nil, nil
+	}
+	tickSize, err := sdk.NewDecFromStr(tickSizeStr)
+	if err != nil || !tickSize.IsPositive() {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("invalid tick size: %s", tickSizeStr))
+	}
+	return &tickSize, nil
+}
+
+// aggregateDepthBook returns at most size levels of asks and bids from the best prices, with the items aggregated
+// into price levels of the tick size if it's given. Ask prices are rounded up and bid prices are rounded down,
+// so that the quantity at a level is always available at that price or better.
+func aggregateDepthBook(depthBook *types.DepthBook, tickSize *sdk.Dec, size int) (asks, bids []BookResItem) {
+	var askItems, bidItems []types.DepthBookItem
+	for i := len(depthBook.Items) - 1; i >= 0; i-- {
+		if item := depthBook.Items[i]; item.SellQuantity.IsPositive() {
+			askItems = append(askItems, item)
 		}
+	}
+	for _, item := range depthBook.Items {
 		if item.BuyQuantity.IsPositive() {
-			bids = append(bids, BookResItem{item.Price.String(), item.BuyQuantity.String()})
+			bidItems = append(bidItems, item)
 		}
 	}
-	if len(asks) > params.Size {
-		asks = asks[:params.Size]
-	}
-	if len(bids) > params.Size {
-		bids = bids[:params.Size]
+	return aggregateBookItems(askItems, types.SellOrder, tickSize, size),
+		aggregateBookItems(bidItems, types.BuyOrder, tickSize, size)
+}
+
+// aggregateBookItems aggregates the items of a side sorted from the best price into at most size levels
+func aggregateBookItems(items []types.DepthBookItem, side string, tickSize *sdk.Dec, size int) []BookResItem {
+	var levels []BookResItem
+	var levelPrice, levelQuantity sdk.Dec
+	cumQuantity, cumNotional := sdk.ZeroDec(), sdk.ZeroDec()
+	for _, item := range items {
+		quantity := item.BuyQuantity
+		if side == types.SellOrder {
+			quantity = item.SellQuantity
+		}
+		price := item.Price
+		if tickSize != nil {
+			price = roundToTick(price, *tickSize, side == types.SellOrder)
+		}
+
+		if len(levels) == 0 || !price.Equal(levelPrice) {
+			if len(levels) >= size {
+				break
+			}
+			levels = append(levels, BookResItem{})
+			levelPrice, levelQuantity = price, sdk.ZeroDec()
+		}
+		levelQuantity = levelQuantity.Add(quantity)
+		cumQuantity = cumQuantity.Add(quantity)
+		cumNotional = cumNotional.Add(item.Price.Mul(quantity))
+		levels[len(levels)-1] = BookResItem{levelPrice.String(), levelQuantity.String(), cumQuantity.String(),
+			cumNotional.String()}
 	}
+	return levels
+}
 
-	bookRes := BookRes{
-		Asks: asks,
-		Bids: bids,
+// roundToTick rounds the price to a multiple of the tick size, up if roundUp or down otherwise
+func roundToTick(price, tickSize sdk.Dec, roundUp bool) sdk.Dec {
+	ticks, remainder := new(big.Int).QuoRem(price.Int, tickSize.Int, new(big.Int))
+	if roundUp && remainder.Sign() > 0 {
+		ticks.Add(ticks, big.NewInt(1))
 	}
-	bz := keeper.cdc.MustMarshalJSON(bookRes)
-	return bz, nil
+	return tickSize.MulInt(sdk.NewIntFromBigInt(ticks))
 }
 
 type StoreStatistic struct {
 	StoreOrderNum   int64
 	DepthBookNum    map[string]int64
 	BookOrderIDsNum map[string]int64
+	// pruning progress of the closed orders
+	LastPrunedBlockHeight int64
+	ClosedOrderNum        int64 // closed orders waiting to be pruned
 }
 
 func GetStoreStatistic(ctx sdk.Context, keeper Keeper) *StoreStatistic {
 	storeOrderNum := keeper.GetStoreOrderNum(ctx)
 	ss := &StoreStatistic{
-		StoreOrderNum: storeOrderNum,
+		StoreOrderNum:         storeOrderNum,
+		LastPrunedBlockHeight: keeper.GetLastPrunedBlockHeight(ctx),
+		ClosedOrderNum:        keeper.getClosedOrderNum(ctx),
 	}
 
 	depthBookMap := make(map[string]types.DepthBook)
@@ -182,28 +351,17 @@ func queryDepthBookV2(ctx sdk.Context, path []string, req abci.RequestQuery, kee
 		return nil, sdk.ErrUnknownRequest(err.Error())
 	}
 
-	depthBook := keeper.GetDepthBookFromDB(ctx, params.Product)
-
-	var asks []BookResItem
-	var bids []BookResItem
-	for _, item := range depthBook.Items {
-		if item.SellQuantity.IsPositive() {
-			asks = append([]BookResItem{{item.Price.String(), item.SellQuantity.String()}}, asks...)
-		}
-		if item.BuyQuantity.IsPositive() {
-			bids = append(bids, BookResItem{item.Price.String(), item.BuyQuantity.String()})
-		}
-	}
-	if len(asks) > params.Size {
-		asks = asks[:params.Size]
-	}
-	if len(bids) > params.Size {
-		bids = bids[:params.Size]
+	tickSize, sdkErr := parseTickSize(params.TickSize)
+	if sdkErr != nil {
+		return nil, sdkErr
 	}
+	depthBook := keeper.GetDepthBookFromDB(ctx, params.Product)
+	asks, bids := aggregateDepthBook(depthBook, tickSize, params.Size)
 
 	bookRes := BookRes{
-		Asks: asks,
-		Bids: bids,
+		Asks:     asks,
+		Bids:     bids,
+		Sequence: keeper.GetDepthBookSequence(ctx, params.Product),
 	}
 
 	res, err := common.JSONMarshalV2(bookRes)
@@ -1,12 +1,14 @@
 package keeper
 
 import (
+	"encoding/json"
 	"testing"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/stretchr/testify/require"
 	abci "github.com/tendermint/tendermint/abci/types"
 
+	"github.com/okex/okchain/x/common"
 	"github.com/okex/okchain/x/dex"
 	"github.com/okex/okchain/x/order/types"
 )
@@ -39,6 +41,85 @@ func TestQueryOrder(t *testing.T) {
 	require.NotNil(t, err)
 }
 
+func TestQueryClientOrder(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	querier := NewQuerier(keeper)
+
+	orderID1 := "abc"
+	order1 := mockOrder(orderID1, types.TestTokenPair, types.BuyOrder, "0.5", "1.1")
+	order1.Sender = testInput.TestAddrs[0]
+	order1.ClientOrderID = "my-order-1"
+	keeper.SetOrder(ctx, orderID1, order1)
+
+	path := []string{types.QueryClientOrder, order1.Sender.String(), order1.ClientOrderID}
+	BytesOrder, err := querier(ctx, path, abci.RequestQuery{})
+	require.Nil(t, err)
+	order2 := &types.Order{}
+	err2 := keeper.cdc.UnmarshalJSON(BytesOrder, order2)
+	require.Nil(t, err2)
+	require.EqualValues(t, order1.String(), order2.String())
+
+	// client order ids are scoped by sender
+	path = []string{types.QueryClientOrder, testInput.TestAddrs[1].String(), order1.ClientOrderID}
+	_, err = querier(ctx, path, abci.RequestQuery{})
+	require.NotNil(t, err)
+
+	// invalid sender
+	path = []string{types.QueryClientOrder, "invalid", order1.ClientOrderID}
+	_, err = querier(ctx, path, abci.RequestQuery{})
+	require.NotNil(t, err)
+
+	// the client order id is released when the order is dropped
+	keeper.DropOrder(ctx, orderID1)
+	path = []string{types.QueryClientOrder, order1.Sender.String(), order1.ClientOrderID}
+	_, err = querier(ctx, path, abci.RequestQuery{})
+	require.NotNil(t, err)
+}
+
+func TestQueryOpenOrders(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	querier := NewQuerier(keeper)
+
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	for i := 0; i < 3; i++ {
+		order := mockOrder("", types.TestTokenPair, types.BuyOrder, "0.5", "1.1")
+		order.Sender = testInput.TestAddrs[0]
+		require.Nil(t, keeper.PlaceOrder(ctx, order))
+	}
+
+	path := []string{types.QueryOpenOrders}
+	params := NewQueryOpenOrdersParams(testInput.TestAddrs[0].String(), types.TestTokenPair, 2, 2)
+	bz, err := querier(ctx, path, abci.RequestQuery{Data: keeper.cdc.MustMarshalJSON(params)})
+	require.Nil(t, err)
+	var response struct {
+		Data struct {
+			Data      []types.Order    `json:"data"`
+			ParamPage common.ParamPage `json:"param_page"`
+		} `json:"data"`
+	}
+	require.Nil(t, json.Unmarshal(bz, &response))
+	require.EqualValues(t, 3, response.Data.ParamPage.Total)
+	require.EqualValues(t, 1, len(response.Data.Data))
+	require.EqualValues(t, types.FormatOrderID(10, 3), response.Data.Data[0].OrderID)
+
+	// invalid address
+	params = NewQueryOpenOrdersParams("invalid", "", 0, 0)
+	_, err = querier(ctx, path, abci.RequestQuery{Data: keeper.cdc.MustMarshalJSON(params)})
+	require.NotNil(t, err)
+
+	// invalid page
+	params = NewQueryOpenOrdersParams(testInput.TestAddrs[0].String(), "", -1, 0)
+	_, err = querier(ctx, path, abci.RequestQuery{Data: keeper.cdc.MustMarshalJSON(params)})
+	require.NotNil(t, err)
+}
+
 func TestQueryDepthBookV2(t *testing.T) {
 	testInput := CreateTestInput(t)
 	keeper := testInput.OrderKeeper
@@ -65,7 +146,7 @@ func TestQueryDepthBookV2(t *testing.T) {
 
 	// Default query
 	path := []string{types.QueryDepthBookV2}
-	params := NewQueryDepthBookParams(product, 0)
+	params := NewQueryDepthBookParams(product, 0, "")
 	data := keeper.cdc.MustMarshalJSON(params)
 	req := abci.RequestQuery{
 		Data: data,
@@ -114,7 +195,7 @@ func TestQueryDepthBook(t *testing.T) {
 
 	// Default query
 	path := []string{types.QueryDepthBook}
-	params := NewQueryDepthBookParams(product, 0)
+	params := NewQueryDepthBookParams(product, 0, "")
 	data := keeper.cdc.MustMarshalJSON(params)
 	req := abci.RequestQuery{
 		Data: data,
@@ -125,18 +206,22 @@ func TestQueryDepthBook(t *testing.T) {
 	keeper.cdc.MustUnmarshalJSON(bookResBytes, bookRes)
 	expectBookRes := &BookRes{
 		Asks: []BookResItem{
-			{sdk.MustNewDecFromStr("0.5").String(), sdk.MustNewDecFromStr("1.2").String()},
-			{sdk.MustNewDecFromStr("0.6").String(), sdk.MustNewDecFromStr("2.2").String()},
+			{sdk.MustNewDecFromStr("0.5").String(), sdk.MustNewDecFromStr("1.2").String(),
+				sdk.MustNewDecFromStr("1.2").String(), sdk.MustNewDecFromStr("0.6").String()},
+			{sdk.MustNewDecFromStr("0.6").String(), sdk.MustNewDecFromStr("2.2").String(),
+				sdk.MustNewDecFromStr("3.4").String(), sdk.MustNewDecFromStr("1.92").String()},
 		},
 		Bids: []BookResItem{
-			{sdk.MustNewDecFromStr("0.4").String(), sdk.MustNewDecFromStr("1.3").String()},
-			{sdk.MustNewDecFromStr("0.3").String(), sdk.MustNewDecFromStr("2.8").String()},
+			{sdk.MustNewDecFromStr("0.4").String(), sdk.MustNewDecFromStr("1.3").String(),
+				sdk.MustNewDecFromStr("1.3").String(), sdk.MustNewDecFromStr("0.52").String()},
+			{sdk.MustNewDecFromStr("0.3").String(), sdk.MustNewDecFromStr("2.8").String(),
+				sdk.MustNewDecFromStr("4.1").String(), sdk.MustNewDecFromStr("1.36").String()},
 		},
 	}
 	require.EqualValues(t, expectBookRes, bookRes)
 
 	// limit size
-	params = NewQueryDepthBookParams(product, 1)
+	params = NewQueryDepthBookParams(product, 1, "")
 	data = keeper.cdc.MustMarshalJSON(params)
 	req = abci.RequestQuery{
 		Data: data,
@@ -147,14 +232,41 @@ func TestQueryDepthBook(t *testing.T) {
 	keeper.cdc.MustUnmarshalJSON(bookResBytes, bookRes)
 	expectBookRes = &BookRes{
 		Asks: []BookResItem{
-			{sdk.MustNewDecFromStr("0.5").String(), sdk.MustNewDecFromStr("1.2").String()},
+			{sdk.MustNewDecFromStr("0.5").String(), sdk.MustNewDecFromStr("1.2").String(),
+				sdk.MustNewDecFromStr("1.2").String(), sdk.MustNewDecFromStr("0.6").String()},
 		},
 		Bids: []BookResItem{
-			{sdk.MustNewDecFromStr("0.4").String(), sdk.MustNewDecFromStr("1.3").String()},
+			{sdk.MustNewDecFromStr("0.4").String(), sdk.MustNewDecFromStr("1.3").String(),
+				sdk.MustNewDecFromStr("1.3").String(), sdk.MustNewDecFromStr("0.52").String()},
 		},
 	}
 	require.EqualValues(t, expectBookRes, bookRes)
 
+	// aggregate by the tick size, rounding asks up and bids down
+	params = NewQueryDepthBookParams(product, 0, "0.2")
+	bookResBytes, err = querier(ctx, path, abci.RequestQuery{Data: keeper.cdc.MustMarshalJSON(params)})
+	require.Nil(t, err)
+	bookRes = &BookRes{}
+	keeper.cdc.MustUnmarshalJSON(bookResBytes, bookRes)
+	expectBookRes = &BookRes{
+		Asks: []BookResItem{
+			{sdk.MustNewDecFromStr("0.6").String(), sdk.MustNewDecFromStr("3.4").String(),
+				sdk.MustNewDecFromStr("3.4").String(), sdk.MustNewDecFromStr("1.92").String()},
+		},
+		Bids: []BookResItem{
+			{sdk.MustNewDecFromStr("0.4").String(), sdk.MustNewDecFromStr("1.3").String(),
+				sdk.MustNewDecFromStr("1.3").String(), sdk.MustNewDecFromStr("0.52").String()},
+			{sdk.MustNewDecFromStr("0.2").String(), sdk.MustNewDecFromStr("2.8").String(),
+				sdk.MustNewDecFromStr("4.1").String(), sdk.MustNewDecFromStr("1.36").String()},
+		},
+	}
+	require.EqualValues(t, expectBookRes, bookRes)
+
+	// invalid tick size
+	params = NewQueryDepthBookParams(product, 0, "-0.1")
+	_, err = querier(ctx, path, abci.RequestQuery{Data: keeper.cdc.MustMarshalJSON(params)})
+	require.NotNil(t, err)
+
 	// invalid request
 	req = abci.RequestQuery{
 		Data: nil,
@@ -163,7 +275,7 @@ func TestQueryDepthBook(t *testing.T) {
 	require.NotNil(t, err)
 
 	// invalid product
-	params = NewQueryDepthBookParams("invalid_product", 0)
+	params = NewQueryDepthBookParams("invalid_product", 0, "")
 	data = keeper.cdc.MustMarshalJSON(params)
 	req = abci.RequestQuery{
 		Data: data,
@@ -228,7 +340,20 @@ func TestQueryParameters(t *testing.T) {
 		OrderExpireBlocks: 1000,
 		MaxDealsPerBlock:  10000,
 		FeePerBlock:       sdk.NewDecCoinFromDec(types.DefaultFeeDenomPerBlock, sdk.NewDec(1)),
-		TradeFeeRate:      sdk.MustNewDecFromStr("0.001"),
+		MakerFeeRate:      sdk.MustNewDecFromStr("0.001"),
+		TakerFeeRate:      sdk.MustNewDecFromStr("0.001"),
+		FeeTiers: []types.FeeTier{
+			{MinVolume: sdk.NewDec(1000), MakerFeeRate: sdk.ZeroDec(), TakerFeeRate: sdk.MustNewDecFromStr("0.0005")},
+		},
+		VolumeWindowBlocks:      1000,
+		NativeFeeDiscount:       sdk.MustNewDecFromStr("0.25"),
+		SelfTradePrevention:     types.SelfTradePreventionCancelNewest,
+		MaxOpenOrdersPerProduct: 100,
+		MaxNewOrdersPerBlock:    50,
+		OrderDeposit:            sdk.NewDecCoinFromDec(types.DefaultFeeDenomPerBlock, sdk.MustNewDecFromStr("0.1")),
+		DepositLockBlocks:       10,
+
+		ClosedOrderRetentionBlocks: 100,
 	}
 	keeper.SetParams(ctx, params)
 	path := []string{types.QueryParameters}
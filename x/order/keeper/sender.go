@@ -0,0 +1,88 @@
+package keeper
+
+import (
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/order/types"
+)
+
+// GetSenderOrderIDs returns the ids of the open orders of a sender, untriggered orders included.
+// Only the orders of the product are returned if it's set.
+func (k Keeper) GetSenderOrderIDs(ctx sdk.Context, sender sdk.AccAddress, product string) []string {
+	orderIDs, _ := k.getSenderOrderIDs(ctx, sender, product, 0, -1)
+	return orderIDs
+}
+
+// GetSenderOpenOrders returns a page of the open orders of a sender, and the number of all its open orders
+func (k Keeper) GetSenderOpenOrders(ctx sdk.Context, sender sdk.AccAddress, product string,
+	offset, limit int) ([]*types.Order, int) {
+	orderIDs, total := k.getSenderOrderIDs(ctx, sender, product, offset, limit)
+	orders := make([]*types.Order, 0, len(orderIDs))
+	for _, orderID := range orderIDs {
+		if order := k.GetOrder(ctx, orderID); order != nil {
+			orders = append(orders, order)
+		}
+	}
+	return orders, total
+}
+
+// getSenderOrderIDs returns at most limit order ids from offset in the index, a negative limit means no limit
+func (k Keeper) getSenderOrderIDs(ctx sdk.Context, sender sdk.AccAddress, product string,
+	offset, limit int) (orderIDs []string, total int) {
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, types.GetSenderOrderIDsPrefix(sender, product))
+	defer iter.Close()
+
+	for ; iter.Valid(); iter.Next() {
+		if total >= offset && (limit < 0 || len(orderIDs) < limit) {
+			// the key ends with product:orderID
+			key := string(iter.Key())
+			orderIDs = append(orderIDs, key[strings.LastIndex(key, ":")+1:])
+		}
+		total++
+	}
+	return orderIDs, total
+}
+
+// InsertSenderOrderID adds a new order into the index of the open orders of its sender
+func (k Keeper) InsertSenderOrderID(ctx sdk.Context, order *types.Order) {
+	store := ctx.KVStore(k.orderStoreKey)
+	store.Set(types.GetSenderOrderIDKey(order.Sender, order.Product, order.OrderID), []byte{1})
+}
+
+// removeSenderOrderID removes a closed order from the index of the open orders of its sender
+func (k Keeper) removeSenderOrderID(ctx sdk.Context, order *types.Order) {
+	store := ctx.KVStore(k.orderStoreKey)
+	store.Delete(types.GetSenderOrderIDKey(order.Sender, order.Product, order.OrderID))
+}
+
+// GetOrderIDByClientOrderID returns the id of the latest order a sender placed with the client order id,
+// the order might have been closed
+func (k Keeper) GetOrderIDByClientOrderID(ctx sdk.Context, sender sdk.AccAddress, clientOrderID string) string {
+	store := ctx.KVStore(k.orderStoreKey)
+	return string(store.Get(types.GetClientOrderIDKey(sender, clientOrderID)))
+}
+
+// updateClientOrderIndex points the client order id of an order to the order
+func (k Keeper) updateClientOrderIndex(ctx sdk.Context, orderID string, order *types.Order) {
+	if order.ClientOrderID == "" {
+		return
+	}
+	if k.GetOrderIDByClientOrderID(ctx, order.Sender, order.ClientOrderID) != orderID {
+		store := ctx.KVStore(k.orderStoreKey)
+		store.Set(types.GetClientOrderIDKey(order.Sender, order.ClientOrderID), []byte(orderID))
+	}
+}
+
+// removeClientOrderIndex removes the client order id of a dropped order, unless it's been taken by a newer order
+func (k Keeper) removeClientOrderIndex(ctx sdk.Context, orderID string, order *types.Order) {
+	if order.ClientOrderID == "" {
+		return
+	}
+	if k.GetOrderIDByClientOrderID(ctx, order.Sender, order.ClientOrderID) == orderID {
+		store := ctx.KVStore(k.orderStoreKey)
+		store.Delete(types.GetClientOrderIDKey(order.Sender, order.ClientOrderID))
+	}
+}
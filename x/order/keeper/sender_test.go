@@ -0,0 +1,64 @@
+package keeper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/common"
+	"github.com/okex/okchain/x/dex"
+	"github.com/okex/okchain/x/order/types"
+)
+
+func TestSenderOrderIDs(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "11.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "9.0", "1.0"),
+		mockOrder("", "btc_"+common.NativeToken, types.BuyOrder, "9.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "8.0", "1.0"),
+	}
+	orders[0].Sender = testInput.TestAddrs[0]
+	orders[1].Sender = testInput.TestAddrs[1]
+	orders[2].Sender = testInput.TestAddrs[0]
+	orders[2].Trigger = &types.OrderTrigger{Type: types.TriggerTypeStopLimit, Price: orders[2].Price}
+	orders[3].Sender = testInput.TestAddrs[0]
+	orders[4].Sender = testInput.TestAddrs[0]
+	for _, order := range orders {
+		require.NoError(t, keeper.PlaceOrder(ctx, order))
+	}
+
+	// untriggered orders are open too, orders are grouped by product
+	require.EqualValues(t, []string{orders[3].OrderID, orders[0].OrderID, orders[2].OrderID, orders[4].OrderID},
+		keeper.GetSenderOrderIDs(ctx, testInput.TestAddrs[0], ""))
+	require.EqualValues(t, []string{orders[0].OrderID, orders[2].OrderID, orders[4].OrderID},
+		keeper.GetSenderOrderIDs(ctx, testInput.TestAddrs[0], types.TestTokenPair))
+	require.EqualValues(t, []string{orders[1].OrderID}, keeper.GetSenderOrderIDs(ctx, testInput.TestAddrs[1], ""))
+
+	// paginate
+	openOrders, total := keeper.GetSenderOpenOrders(ctx, testInput.TestAddrs[0], types.TestTokenPair, 1, 1)
+	require.EqualValues(t, 3, total)
+	require.EqualValues(t, 1, len(openOrders))
+	require.EqualValues(t, orders[2].OrderID, openOrders[0].OrderID)
+	openOrders, total = keeper.GetSenderOpenOrders(ctx, testInput.TestAddrs[0], "", 3, 10)
+	require.EqualValues(t, 4, total)
+	require.EqualValues(t, 1, len(openOrders))
+	require.EqualValues(t, orders[4].OrderID, openOrders[0].OrderID)
+
+	// closed orders are removed
+	keeper.CancelOrder(ctx, orders[0], ctx.Logger())
+	keeper.ExpireOrder(ctx, orders[2], ctx.Logger())
+	orders[4].Fill(orders[4].Price, orders[4].Quantity)
+	keeper.UpdateOrder(orders[4], ctx)
+	require.EqualValues(t, []string{orders[3].OrderID}, keeper.GetSenderOrderIDs(ctx, testInput.TestAddrs[0], ""))
+	require.Nil(t, keeper.GetSenderOrderIDs(ctx, testInput.TestAddrs[0], types.TestTokenPair))
+	require.EqualValues(t, []string{orders[1].OrderID}, keeper.GetSenderOrderIDs(ctx, testInput.TestAddrs[1], ""))
+}
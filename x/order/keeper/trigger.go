@@ -0,0 +1,126 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/order/types"
+)
+
+// GetProductTriggerOrderIDs returns the ids of all the untriggered orders of a product
+func (k Keeper) GetProductTriggerOrderIDs(ctx sdk.Context, product string) []string {
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, append(types.TriggerOrderIDsKey, []byte(product+":")...))
+	defer iter.Close()
+
+	var orderIDs []string
+	for ; iter.Valid(); iter.Next() {
+		orderIDs = append(orderIDs, string(iter.Value()))
+	}
+	return orderIDs
+}
+
+// GetCrossedTriggerOrderIDs returns the ids of at most limit untriggered orders of a product whose trigger prices
+// have been crossed by the last price. Only the crossed ranges of the trigger index are scanned, from the order
+// waiting for the lowest rising price and then from the one waiting for the highest falling price.
+func (k Keeper) GetCrossedTriggerOrderIDs(ctx sdk.Context, product string, lastPrice sdk.Dec, limit int) []string {
+	store := ctx.KVStore(k.orderStoreKey)
+	var orderIDs []string
+
+	risingIter := store.Iterator(types.GetTriggerOrdersPrefix(product, true),
+		sdk.PrefixEndBytes(types.GetTriggerPriceKey(product, true, lastPrice)))
+	for ; risingIter.Valid() && len(orderIDs) < limit; risingIter.Next() {
+		orderIDs = append(orderIDs, string(risingIter.Value()))
+	}
+	risingIter.Close()
+
+	fallingIter := store.ReverseIterator(types.GetTriggerPriceKey(product, false, lastPrice),
+		sdk.PrefixEndBytes(types.GetTriggerOrdersPrefix(product, false)))
+	for ; fallingIter.Valid() && len(orderIDs) < limit; fallingIter.Next() {
+		orderIDs = append(orderIDs, string(fallingIter.Value()))
+	}
+	fallingIter.Close()
+	return orderIDs
+}
+
+// InsertTriggerOrder keeps a new trigger order in the trigger index until it's triggered
+func (k Keeper) InsertTriggerOrder(ctx sdk.Context, order *types.Order) {
+	store := ctx.KVStore(k.orderStoreKey)
+	store.Set(getTriggerOrderKey(order), []byte(order.OrderID))
+	k.diskCache.addOrder()
+}
+
+func (k Keeper) removeTriggerOrderID(ctx sdk.Context, order *types.Order) {
+	store := ctx.KVStore(k.orderStoreKey)
+	store.Delete(getTriggerOrderKey(order))
+}
+
+func getTriggerOrderKey(order *types.Order) []byte {
+	return types.GetTriggerOrderKey(order.Product, order.Trigger.IsRising(order.Side), order.Trigger.Price,
+		order.OrderID)
+}
+
+// removeUntriggeredOrder removes a cancelled or expired order from the trigger index
+func (k Keeper) removeUntriggeredOrder(ctx sdk.Context, order *types.Order, feeType string) {
+	k.addUpdatedOrderID(order.OrderID)
+	k.increaseQuitNum(feeType)
+	k.removeTriggerOrderID(ctx, order)
+	k.diskCache.closeOrder(order.OrderID)
+}
+
+// ActivateTriggerOrder moves a triggered order from the trigger index into the depth book as a limit order
+func (k Keeper) ActivateTriggerOrder(ctx sdk.Context, order *types.Order) {
+	k.removeTriggerOrderID(ctx, order)
+	order.Activate()
+	k.SetOrder(ctx, order.OrderID, order)
+	k.addUpdatedOrderID(order.OrderID)
+	k.diskCache.insertOrderIntoBook(order)
+}
+
+// GetTriggeredOrderIDs returns the ids of the orders triggered in the last block, which are matched in this block
+func (k Keeper) GetTriggeredOrderIDs(ctx sdk.Context) []string {
+	store := ctx.KVStore(k.orderStoreKey)
+	bz := store.Get(types.TriggeredOrderIDsKey)
+	orderIDs := []string{}
+	if bz == nil {
+		return orderIDs
+	}
+	k.cdc.MustUnmarshalJSON(bz, &orderIDs)
+	return orderIDs
+}
+
+// SetTriggeredOrderIDs records the ids of the orders triggered in this block
+func (k Keeper) SetTriggeredOrderIDs(ctx sdk.Context, orderIDs []string) {
+	store := ctx.KVStore(k.orderStoreKey)
+	if len(orderIDs) == 0 {
+		if store.Has(types.TriggeredOrderIDsKey) {
+			store.Delete(types.TriggeredOrderIDsKey)
+		}
+		return
+	}
+	store.Set(types.TriggeredOrderIDsKey, k.cdc.MustMarshalJSON(orderIDs))
+}
+
+// GetPendingTriggerProducts returns the products whose crossed trigger orders were left untriggered by the limit of
+// the last block, which are checked again in this block
+func (k Keeper) GetPendingTriggerProducts(ctx sdk.Context) []string {
+	store := ctx.KVStore(k.orderStoreKey)
+	bz := store.Get(types.PendingTriggerProductsKey)
+	products := []string{}
+	if bz == nil {
+		return products
+	}
+	k.cdc.MustUnmarshalJSON(bz, &products)
+	return products
+}
+
+// SetPendingTriggerProducts records the products to be checked for the crossed trigger orders in the next block
+func (k Keeper) SetPendingTriggerProducts(ctx sdk.Context, products []string) {
+	store := ctx.KVStore(k.orderStoreKey)
+	if len(products) == 0 {
+		if store.Has(types.PendingTriggerProductsKey) {
+			store.Delete(types.PendingTriggerProductsKey)
+		}
+		return
+	}
+	store.Set(types.PendingTriggerProductsKey, k.cdc.MustMarshalJSON(products))
+}
@@ -0,0 +1,51 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/order/types"
+)
+
+func TestGetCrossedTriggerOrderIDs(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+
+	newTriggerOrder := func(orderID, side, triggerPrice string) *types.Order {
+		order := mockOrder(orderID, types.TestTokenPair, side, "10.0", "1.0")
+		order.Status = types.OrderStatusUntriggered
+		order.Trigger = &types.OrderTrigger{Type: types.TriggerTypeStopLimit, Price: sdk.MustNewDecFromStr(triggerPrice)}
+		return order
+	}
+	// stop-limit buy orders wait for a rising price, and stop-limit sell orders for a falling one
+	orders := []*types.Order{
+		newTriggerOrder(types.FormatOrderID(10, 1), types.BuyOrder, "100.0"),
+		newTriggerOrder(types.FormatOrderID(10, 2), types.BuyOrder, "10.0"),
+		newTriggerOrder(types.FormatOrderID(10, 3), types.BuyOrder, "9.0"),
+		newTriggerOrder(types.FormatOrderID(10, 4), types.BuyOrder, "10.5"),
+		newTriggerOrder(types.FormatOrderID(10, 5), types.SellOrder, "9.0"),
+		newTriggerOrder(types.FormatOrderID(10, 6), types.SellOrder, "11.0"),
+		newTriggerOrder(types.FormatOrderID(10, 7), types.SellOrder, "10.0"),
+	}
+	for _, order := range orders {
+		keeper.InsertTriggerOrder(ctx, order)
+	}
+	require.Len(t, keeper.GetProductTriggerOrderIDs(ctx, types.TestTokenPair), 7)
+
+	// only the crossed orders are returned, ordered by how far their trigger prices have been crossed
+	lastPrice := sdk.MustNewDecFromStr("10.0")
+	require.EqualValues(t, []string{orders[2].OrderID, orders[1].OrderID, orders[5].OrderID, orders[6].OrderID},
+		keeper.GetCrossedTriggerOrderIDs(ctx, types.TestTokenPair, lastPrice, 10))
+	require.EqualValues(t, []string{orders[2].OrderID, orders[1].OrderID, orders[5].OrderID},
+		keeper.GetCrossedTriggerOrderIDs(ctx, types.TestTokenPair, lastPrice, 3))
+	require.EqualValues(t, 0, len(keeper.GetCrossedTriggerOrderIDs(ctx, "unknown_okt", lastPrice, 10)))
+
+	// triggered orders leave the index
+	keeper.ActivateTriggerOrder(ctx, orders[2])
+	require.EqualValues(t, []string{orders[1].OrderID},
+		keeper.GetCrossedTriggerOrderIDs(ctx, types.TestTokenPair, lastPrice, 1))
+	require.Len(t, keeper.GetProductTriggerOrderIDs(ctx, types.TestTokenPair), 6)
+}
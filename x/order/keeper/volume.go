@@ -0,0 +1,110 @@
+package keeper
+
+import (
+	"encoding/binary"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/okex/okchain/x/order/types"
+)
+
+// GetTradedVolume returns the volume an account has traded in the rolling window of the last
+// VolumeWindowBlocks blocks, which is valued in the native token
+func (k Keeper) GetTradedVolume(ctx sdk.Context, addr sdk.AccAddress) sdk.Dec {
+	store := ctx.KVStore(k.orderStoreKey)
+	bz := store.Get(types.GetTradedVolumeKey(addr))
+	if bz == nil {
+		return sdk.ZeroDec()
+	}
+	var volume sdk.Dec
+	k.cdc.MustUnmarshalBinaryBare(bz, &volume)
+	return volume
+}
+
+func (k Keeper) setTradedVolume(ctx sdk.Context, key []byte, volume sdk.Dec) {
+	store := ctx.KVStore(k.orderStoreKey)
+	if !volume.IsPositive() {
+		store.Delete(key)
+		return
+	}
+	store.Set(key, k.cdc.MustMarshalBinaryBare(volume))
+}
+
+// addTradedVolume adds the quote amount of a deal to the trailing traded volume of an account. The amount is
+// valued in the native token with the last price of the quote token, it's not counted if there is no such price.
+func (k Keeper) addTradedVolume(ctx sdk.Context, addr sdk.AccAddress, product string, amount sdk.Dec) {
+	amount = amount.Mul(GetNativePrice(ctx, k, strings.Split(product, "_")[1]))
+	if !amount.IsPositive() {
+		return
+	}
+
+	k.AddBlockTradedVolume(ctx, types.BlockTradedVolume{BlockHeight: ctx.BlockHeight(), Address: addr, Volume: amount})
+}
+
+// GetBlockTradedVolumes returns the volumes traded by accounts at each block of the rolling window
+func (k Keeper) GetBlockTradedVolumes(ctx sdk.Context) (volumes []types.BlockTradedVolume) {
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, types.BlockTradedVolumeKey)
+	defer iter.Close()
+
+	prefixLength := len(types.GetBlockTradedVolumesPrefix(0))
+	for ; iter.Valid(); iter.Next() {
+		key := iter.Key()
+		var volume sdk.Dec
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &volume)
+		volumes = append(volumes, types.BlockTradedVolume{
+			BlockHeight: int64(binary.BigEndian.Uint64(key[len(types.BlockTradedVolumeKey):prefixLength])),
+			Address:     sdk.AccAddress(key[prefixLength:]),
+			Volume:      volume,
+		})
+	}
+	return volumes
+}
+
+// AddBlockTradedVolume adds the volume an account traded at a block to the rolling window, it restores the traded
+// volumes from genesis
+func (k Keeper) AddBlockTradedVolume(ctx sdk.Context, volume types.BlockTradedVolume) {
+	if !volume.Volume.IsPositive() {
+		return
+	}
+	store := ctx.KVStore(k.orderStoreKey)
+	blockKey := types.GetBlockTradedVolumeKey(volume.BlockHeight, volume.Address)
+	blockVolume := volume.Volume
+	if bz := store.Get(blockKey); bz != nil {
+		var prevVolume sdk.Dec
+		k.cdc.MustUnmarshalBinaryBare(bz, &prevVolume)
+		blockVolume = blockVolume.Add(prevVolume)
+	}
+	k.setTradedVolume(ctx, blockKey, blockVolume)
+	k.setTradedVolume(ctx, types.GetTradedVolumeKey(volume.Address),
+		k.GetTradedVolume(ctx, volume.Address).Add(volume.Volume))
+}
+
+// DropExpiredTradedVolumes removes the volumes traded before the rolling window from the trailing traded volumes
+// of accounts
+func (k Keeper) DropExpiredTradedVolumes(ctx sdk.Context) {
+	windowStart := ctx.BlockHeight() - k.GetParams(ctx).VolumeWindowBlocks + 1
+	if windowStart <= 0 {
+		return
+	}
+
+	store := ctx.KVStore(k.orderStoreKey)
+	// the window might have been shortened, so all the volumes before it are dropped
+	iter := store.Iterator(types.BlockTradedVolumeKey, types.GetBlockTradedVolumesPrefix(windowStart))
+	var keys [][]byte
+	var volumes []sdk.Dec
+	for ; iter.Valid(); iter.Next() {
+		var volume sdk.Dec
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &volume)
+		keys = append(keys, iter.Key())
+		volumes = append(volumes, volume)
+	}
+	iter.Close()
+
+	prefixLength := len(types.GetBlockTradedVolumesPrefix(0))
+	for i, key := range keys {
+		addr := sdk.AccAddress(key[prefixLength:])
+		k.setTradedVolume(ctx, types.GetTradedVolumeKey(addr), k.GetTradedVolume(ctx, addr).Sub(volumes[i]))
+		store.Delete(key)
+	}
+}
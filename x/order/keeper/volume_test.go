@@ -0,0 +1,67 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/common"
+	"github.com/okex/okchain/x/dex"
+	"github.com/okex/okchain/x/order/types"
+)
+
+func TestTradedVolume(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	require.Nil(t, testInput.DexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair()))
+	params := keeper.GetParams(ctx)
+	params.VolumeWindowBlocks = 5
+	keeper.SetParams(ctx, params)
+
+	order := mockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "2.0")
+	order.Sender = testInput.TestAddrs[0]
+	require.Nil(t, keeper.PlaceOrder(ctx, order))
+	keeper.FillOrder(ctx, order, sdk.MustNewDecFromStr("10.0"), sdk.MustNewDecFromStr("1.0"), true, ctx.Logger())
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.0"), keeper.GetTradedVolume(ctx, testInput.TestAddrs[0]))
+
+	ctx = ctx.WithBlockHeight(12)
+	keeper.FillOrder(ctx, order, sdk.MustNewDecFromStr("11.0"), sdk.MustNewDecFromStr("1.0"), false, ctx.Logger())
+	require.EqualValues(t, sdk.MustNewDecFromStr("21.0"), keeper.GetTradedVolume(ctx, testInput.TestAddrs[0]))
+	require.True(t, keeper.GetTradedVolume(ctx, testInput.TestAddrs[1]).IsZero())
+
+	// the volume traded at block 10 leaves the window at block 15
+	ctx = ctx.WithBlockHeight(14)
+	keeper.DropExpiredTradedVolumes(ctx)
+	require.EqualValues(t, sdk.MustNewDecFromStr("21.0"), keeper.GetTradedVolume(ctx, testInput.TestAddrs[0]))
+	ctx = ctx.WithBlockHeight(15)
+	keeper.DropExpiredTradedVolumes(ctx)
+	require.EqualValues(t, sdk.MustNewDecFromStr("11.0"), keeper.GetTradedVolume(ctx, testInput.TestAddrs[0]))
+
+	// all the volumes before a shortened window are dropped
+	params.VolumeWindowBlocks = 1
+	keeper.SetParams(ctx, params)
+	keeper.DropExpiredTradedVolumes(ctx)
+	require.True(t, keeper.GetTradedVolume(ctx, testInput.TestAddrs[0]).IsZero())
+	iter := sdk.KVStorePrefixIterator(ctx.KVStore(keeper.orderStoreKey), types.BlockTradedVolumeKey)
+	defer iter.Close()
+	require.False(t, iter.Valid())
+}
+
+func TestTradedVolumeQuoteWithoutNativePrice(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	addr := testInput.TestAddrs[0]
+
+	// the deals are not counted while the quote token has no price against the native token
+	keeper.addTradedVolume(ctx, addr, "xxb_usdk", sdk.MustNewDecFromStr("100.0"))
+	require.True(t, keeper.GetTradedVolume(ctx, addr).IsZero())
+	require.EqualValues(t, 0, len(keeper.GetBlockTradedVolumes(ctx)))
+
+	// they're valued in the native token once it has a price
+	keeper.SetLastPrice(ctx, "usdk_"+common.NativeToken, sdk.MustNewDecFromStr("0.5"))
+	keeper.addTradedVolume(ctx, addr, "xxb_usdk", sdk.MustNewDecFromStr("100.0"))
+	require.EqualValues(t, sdk.MustNewDecFromStr("50.0"), keeper.GetTradedVolume(ctx, addr))
+}
@@ -1,16 +1,28 @@
 package v0_9
 
 import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
 	v08order "github.com/okex/okchain/x/order/legacy/v0_8"
 	"github.com/okex/okchain/x/order/types"
 )
 
 func Migrate(oldGenState v08order.GenesisState) GenesisState {
 	params := types.Params{
-		OrderExpireBlocks: oldGenState.Params.OrderExpireBlocks,
-		MaxDealsPerBlock:  oldGenState.Params.MaxDealsPerBlock,
-		FeePerBlock:       types.DefaultFeePerBlock,
-		TradeFeeRate:      oldGenState.Params.TradeFeeRate,
+		OrderExpireBlocks:       oldGenState.Params.OrderExpireBlocks,
+		MaxDealsPerBlock:        oldGenState.Params.MaxDealsPerBlock,
+		FeePerBlock:             types.DefaultFeePerBlock,
+		MakerFeeRate:            oldGenState.Params.TradeFeeRate,
+		TakerFeeRate:            oldGenState.Params.TradeFeeRate,
+		VolumeWindowBlocks:      types.DefaultVolumeWindowBlocks,
+		NativeFeeDiscount:       sdk.MustNewDecFromStr(types.DefaultNativeFeeDiscount),
+		SelfTradePrevention:     types.DefaultSelfTradePrevention,
+		MaxOpenOrdersPerProduct: types.DefaultMaxOpenOrdersPerProduct,
+		MaxNewOrdersPerBlock:    types.DefaultMaxNewOrdersPerBlock,
+		OrderDeposit:            types.DefaultOrderDeposit,
+		DepositLockBlocks:       types.DefaultDepositLockBlocks,
+
+		ClosedOrderRetentionBlocks: types.DefaultClosedOrderRetentionBlocks,
 	}
 
 	orders := make([]*types.Order, 0, len(oldGenState.OpenOrders))
@@ -1,14 +1,353 @@
 package continuousauction
 
 import (
+	"fmt"
+	"sort"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/libs/log"
 
 	"github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
 )
 
+// CaEngine is the continuous auction match engine
 type CaEngine struct {
 }
 
-func (e *CaEngine) Run(ctx sdk.Context, keeper keeper.Keeper) {
-	// TODO
+// productResult accumulates the deals of a product in this block
+type productResult struct {
+	deals    []types.Deal
+	quantity sdk.Dec
+	amount   sdk.Dec
+}
+
+// Run matches the new orders of the products dispatched to it one by one in the order they entered the depth book,
+// except that the market orders are matched ahead of all the others. A market buy order spends its amount up to its
+// worst price.
+// Each new order takes the resting orders placed before it in price-time priority at the maker's price,
+// and its remaining quantity rests in the depth book, unless its time in force says otherwise.
+// A product is halted before a new order would trade out of its price band, and not matched for the rest of the block.
+// As a MatchResult has only one price, it records the volume weighted average price of the product in this block,
+// while every Deal records the maker price it's filled at.
+// The deals limit of a block is shared with the periodic auction engine, which runs first. The orders left unmatched
+// when it's reached are matched first in the next block.
+func (e *CaEngine) Run(ctx sdk.Context, keeper keeper.Keeper, products []string,
+	blockMatchResult *types.BlockMatchResult) {
+
+	logger := ctx.Logger().With("module", "order")
+	blockHeight := ctx.BlockHeight()
+	productSet := make(map[string]struct{}, len(products))
+	for _, product := range products {
+		productSet[product] = struct{}{}
+	}
+
+	remainDeals := keeper.GetParams(ctx).MaxDealsPerBlock
+	for _, result := range blockMatchResult.ResultMap {
+		remainDeals -= int64(len(result.Deals))
+	}
+
+	// orders left unmatched by the deals limit of the last block, market orders, orders triggered in the last block,
+	// and the other orders placed or moved to a new price in this block are not resting orders until they are
+	// matched, in this order. The orders placed and amended in this block are matched in the order they were placed
+	// or amended, and an order placed and amended in this block is matched when it's amended.
+	amendedOrders := keeper.GetAmendedOrders()
+	amendedOrderIDs := make(map[string]struct{}, len(amendedOrders))
+	for _, amendedOrder := range amendedOrders {
+		amendedOrderIDs[amendedOrder.OrderID] = struct{}{}
+	}
+	orderNum := keeper.GetBlockOrderNum(ctx, blockHeight)
+	var marketOrderIDs, limitOrderIDs []string
+	amendedIdx := 0
+	for i := int64(1); i <= orderNum; i++ {
+		for ; amendedIdx < len(amendedOrders) && amendedOrders[amendedIdx].OrderNum < i; amendedIdx++ {
+			limitOrderIDs = append(limitOrderIDs, amendedOrders[amendedIdx].OrderID)
+		}
+		orderID := types.FormatOrderID(blockHeight, i)
+		if order := keeper.GetOrder(ctx, orderID); order != nil && order.OrderType == types.OrderTypeMarket {
+			marketOrderIDs = append(marketOrderIDs, orderID)
+		} else if _, ok := amendedOrderIDs[orderID]; !ok {
+			limitOrderIDs = append(limitOrderIDs, orderID)
+		}
+	}
+	for ; amendedIdx < len(amendedOrders); amendedIdx++ {
+		limitOrderIDs = append(limitOrderIDs, amendedOrders[amendedIdx].OrderID)
+	}
+	orderIDs := append(keeper.GetPendingTakerOrderIDs(ctx), marketOrderIDs...)
+	orderIDs = append(orderIDs, keeper.GetTriggeredOrderIDs(ctx)...)
+	orderIDs = append(orderIDs, limitOrderIDs...)
+	newOrderIDs := make([]string, 0, len(orderIDs))
+	pendingOrderIDs := make(map[string]struct{}, len(orderIDs))
+	for _, orderID := range orderIDs {
+		if _, ok := pendingOrderIDs[orderID]; !ok {
+			newOrderIDs = append(newOrderIDs, orderID)
+			pendingOrderIDs[orderID] = struct{}{}
+		}
+	}
+
+	results := make(map[string]*productResult)
+	var pendingTakerIDs []string
+	for i, orderID := range newOrderIDs {
+		// a deal of a taker comes with a deal of its maker
+		if remainDeals < 2 {
+			pendingTakerIDs = filterPendingTakers(ctx, keeper, newOrderIDs[i:], productSet)
+			break
+		}
+		delete(pendingOrderIDs, orderID)
+		order := keeper.GetOrder(ctx, orderID)
+		// the order might have been cancelled in this block
+		if order == nil || order.Status != types.OrderStatusOpen {
+			continue
+		}
+		// the product might have been halted by its price band in this block
+		if _, ok := productSet[order.Product]; !ok || keeper.IsProductLocked(order.Product) {
+			continue
+		}
+
+		result, ok := results[order.Product]
+		if !ok {
+			result = &productResult{quantity: sdk.ZeroDec(), amount: sdk.ZeroDec()}
+			results[order.Product] = result
+		}
+		if !checkTimeInForce(ctx, keeper, order, pendingOrderIDs, logger) || !checkPriceBand(ctx, keeper, order) {
+			continue
+		}
+		if limited := matchTakerOrder(ctx, keeper, order, pendingOrderIDs, result, &remainDeals,
+			logger); limited {
+			pendingTakerIDs = filterPendingTakers(ctx, keeper, newOrderIDs[i:], productSet)
+			break
+		}
+		cancelIOCRemain(ctx, keeper, order, logger)
+	}
+	keeper.SetPendingTakerOrderIDs(ctx, pendingTakerIDs)
+
+	for product, result := range results {
+		if len(result.deals) == 0 {
+			continue
+		}
+		blockMatchResult.ResultMap[product] = types.MatchResult{
+			BlockHeight: blockHeight,
+			Price:       result.amount.Quo(result.quantity),
+			Quantity:    result.quantity,
+			Deals:       result.deals,
+		}
+	}
+}
+
+// filterPendingTakers returns the open orders of the products dispatched to the engine
+func filterPendingTakers(ctx sdk.Context, k keeper.Keeper, orderIDs []string,
+	productSet map[string]struct{}) []string {
+	var pendingTakerIDs []string
+	for _, orderID := range orderIDs {
+		order := k.GetOrder(ctx, orderID)
+		if order == nil || order.Status != types.OrderStatusOpen {
+			continue
+		}
+		if _, ok := productSet[order.Product]; ok {
+			pendingTakerIDs = append(pendingTakerIDs, orderID)
+		}
+	}
+	return pendingTakerIDs
+}
+
+// matchTakerOrder fills the taker order with the resting orders on the other side of the depth book.
+// Resting orders of the same sender are handled by the self-trade prevention mode of the taker instead.
+// It returns true if the taker stops matching because remainDeals is used up, which it decreases by the deals made.
+func matchTakerOrder(ctx sdk.Context, k keeper.Keeper, taker *types.Order, pendingOrderIDs map[string]struct{},
+	result *productResult, remainDeals *int64, logger log.Logger) (limited bool) {
+
+	product := taker.Product
+	makerSide := types.SellOrder
+	if taker.Side == types.SellOrder {
+		makerSide = types.BuyOrder
+	}
+
+	book := k.GetDepthBookCopy(product)
+	bookLength := len(book.Items)
+	// the taker order has been inserted into the depth book when it was placed
+	takerIndex := sort.Search(bookLength, func(i int) bool {
+		return taker.Price.GTE(book.Items[i].Price)
+	})
+
+	// the taker takes with its whole remaining quantity, even if only a slice of it is displayed
+	takerVisibleQuantity := taker.VisibleQuantity()
+	quantityDigit := k.GetDexKeeper().GetTokenPair(ctx, product).MaxQuantityDigit
+	preventSelfTrade := taker.GetSelfTradePrevention() != types.SelfTradePreventionNone
+	dealt, takerClosed := false, false
+	for step := 0; step < bookLength && taker.RemainQuantity.IsPositive() && !takerClosed && !limited; step++ {
+		// buy taker takes sell orders from the lowest price, sell taker takes buy orders from the highest price
+		index := step
+		if taker.Side == types.BuyOrder {
+			index = bookLength - 1 - step
+		}
+		item := book.Items[index]
+		if (taker.Side == types.BuyOrder && item.Price.GT(taker.Price)) ||
+			(taker.Side == types.SellOrder && item.Price.LT(taker.Price)) {
+			break
+		}
+		if (makerSide == types.BuyOrder && !item.BuyQuantity.IsPositive()) ||
+			(makerSide == types.SellOrder && !item.SellQuantity.IsPositive()) {
+			continue
+		}
+
+		key := types.FormatOrderIDsKey(product, item.Price, makerSide)
+		makerIDs := append([]string{}, k.GetProductPriceOrderIDs(key)...)
+		doneNum := 0
+		levelQuantity := sdk.ZeroDec()
+		// a market buy taker takes as much as its remaining amount affords at this price
+		takerQuantity := taker.AffordableQuantity(item.Price, quantityDigit)
+		var makerDeals []types.Deal
+		for doneNum < len(makerIDs) {
+			remainQuantity := takerQuantity.Sub(levelQuantity)
+			if !remainQuantity.IsPositive() {
+				break
+			}
+			makerID := makerIDs[doneNum]
+			// orders placed after the taker are queued behind all the resting orders at this price
+			if _, ok := pendingOrderIDs[makerID]; ok {
+				break
+			}
+			// leave room for the deal of the taker at this price level
+			if int64(len(makerDeals))+2 > *remainDeals {
+				limited = true
+				break
+			}
+
+			maker := k.GetOrder(ctx, makerID)
+			if preventSelfTrade && maker.Sender.Equals(taker.Sender) {
+				var makerClosed bool
+				makerClosed, takerClosed = handleSelfTrade(ctx, k, book, index, taker, maker, levelQuantity,
+					quantityDigit, logger)
+				if takerClosed {
+					break
+				}
+				// the taker may be decremented by the self-trade prevention, so it takes less at this price level
+				takerQuantity = taker.AffordableQuantity(item.Price, quantityDigit)
+				if makerClosed {
+					doneNum++
+				}
+				continue
+			}
+			visibleQuantity := maker.VisibleQuantity()
+			fillQuantity := sdk.MinDec(visibleQuantity, remainQuantity)
+			// deal fee of sell orders is valued with the last price
+			k.SetLastPrice(ctx, product, item.Price)
+			dealFee := k.FillOrder(ctx, maker, item.Price, fillQuantity, true, logger)
+			book.Sub(index, fillQuantity, makerSide)
+
+			levelQuantity = levelQuantity.Add(fillQuantity)
+			deal := types.Deal{
+				OrderID:  maker.OrderID,
+				Side:     maker.Side,
+				Price:    item.Price,
+				Quantity: fillQuantity,
+				Fee:      dealFee.String(),
+			}
+			makerDeals = append(makerDeals, deal)
+			k.AfterOrderFilled(ctx, maker, item.Price, deal)
+			if maker.Status != types.OrderStatusFilled && fillQuantity.LT(visibleQuantity) {
+				break
+			}
+			doneNum++
+			if maker.Status != types.OrderStatusFilled {
+				// the display slice of an iceberg order is filled, its next slice is queued behind the others
+				book.Add(index, maker.VisibleQuantity(), makerSide)
+				makerIDs = append(makerIDs, makerID)
+			}
+		}
+		if doneNum > 0 {
+			// orders are filled in time priority, so the filled ones are in front
+			k.SetOrderIDs(key, makerIDs[doneNum:])
+		}
+		if levelQuantity.IsZero() {
+			continue
+		}
+
+		// fill the taker once per price level
+		taker.ResizeToAffordable(item.Price, quantityDigit)
+		dealFee := k.FillOrder(ctx, taker, item.Price, levelQuantity, false, logger)
+		result.deals = append(result.deals, makerDeals...)
+		deal := types.Deal{
+			OrderID:  taker.OrderID,
+			Side:     taker.Side,
+			Price:    item.Price,
+			Quantity: levelQuantity,
+			Fee:      dealFee.String(),
+		}
+		result.deals = append(result.deals, deal)
+		k.AfterOrderFilled(ctx, taker, item.Price, deal)
+		result.quantity = result.quantity.Add(levelQuantity)
+		result.amount = result.amount.Add(item.Price.Mul(levelQuantity))
+		*remainDeals -= int64(len(makerDeals)) + 1
+		dealt = true
+	}
+
+	if dealt {
+		book.Sub(takerIndex, takerVisibleQuantity, taker.Side)
+		book.Add(takerIndex, taker.VisibleQuantity(), taker.Side)
+		if taker.Status == types.OrderStatusFilled {
+			removeOrderID(k, types.FormatOrderIDsKey(product, taker.Price, taker.Side), taker.OrderID)
+		}
+		book.RemoveEmptyItems()
+		k.SetDepthBook(product, book)
+	}
+	// the taker is removed from the depth book after the book copy is saved
+	if takerClosed && taker.Status == types.OrderStatusOpen {
+		k.CancelSelfTradeOrder(ctx, taker, logger)
+		logger.Debug(fmt.Sprintf("order(%s) is cancelled by self-trade prevention", taker.OrderID))
+	}
+	return limited
+}
+
+// handleSelfTrade applies the self-trade prevention mode of the taker to a maker of the same sender, which is
+// the next one to be taken at the price level of index, after levelQuantity has been taken there. The book copy is
+// updated as the keeper updates the depth book for the maker. It returns whether the maker is closed and whether
+// the taker is to be closed.
+func handleSelfTrade(ctx sdk.Context, k keeper.Keeper, book *types.DepthBook, index int, taker, maker *types.Order,
+	levelQuantity sdk.Dec, quantityDigit int64, logger log.Logger) (makerClosed, takerClosed bool) {
+
+	price := book.Items[index].Price
+	remainQuantity := taker.AffordableQuantity(price, quantityDigit).Sub(levelQuantity)
+	visibleQuantity := maker.VisibleQuantity()
+	mode := taker.GetSelfTradePrevention()
+	switch mode {
+	case types.SelfTradePreventionCancelNewest:
+		return false, true
+	case types.SelfTradePreventionCancelOldest, types.SelfTradePreventionCancelBoth:
+		takerClosed = mode == types.SelfTradePreventionCancelBoth
+	case types.SelfTradePreventionDecrementAndCancel:
+		if maker.RemainQuantity.GT(remainQuantity) {
+			k.DecrementOrder(ctx, maker, remainQuantity)
+			book.Sub(index, visibleQuantity, maker.Side)
+			book.Add(index, maker.VisibleQuantity(), maker.Side)
+			logger.Debug(fmt.Sprintf("order(%s) is decremented by self-trade prevention", maker.OrderID))
+			return false, true
+		}
+		// the taker is decremented only if it can still take more than the quantity taken at this price level,
+		// the decrement of a market buy taker costs more at a price better than its worst one
+		decremented := *taker
+		decremented.Decrement(maker.RemainQuantity)
+		if decremented.AffordableQuantity(price, quantityDigit).GT(levelQuantity) {
+			k.DecrementOrder(ctx, taker, maker.RemainQuantity)
+			logger.Debug(fmt.Sprintf("order(%s) is decremented by self-trade prevention", taker.OrderID))
+		} else {
+			takerClosed = true
+		}
+	}
+
+	k.CancelSelfTradeOrder(ctx, maker, logger)
+	book.Sub(index, visibleQuantity, maker.Side)
+	logger.Debug(fmt.Sprintf("order(%s) is cancelled by self-trade prevention", maker.OrderID))
+	return true, takerClosed
+}
+
+func removeOrderID(k keeper.Keeper, key string, orderID string) {
+	orderIDs := k.GetProductPriceOrderIDs(key)
+	remainIDs := make([]string, 0, len(orderIDs))
+	for _, id := range orderIDs {
+		if id != orderID {
+			remainIDs = append(remainIDs, id)
+		}
+	}
+	k.SetOrderIDs(key, remainIDs)
 }
@@ -0,0 +1,397 @@
+package continuousauction
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/common"
+	"github.com/okex/okchain/x/dex"
+	"github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+)
+
+func newBlockMatchResult(ctx sdk.Context) *types.BlockMatchResult {
+	return &types.BlockMatchResult{
+		BlockHeight: ctx.BlockHeight(),
+		ResultMap:   make(map[string]types.MatchResult),
+		TimeStamp:   ctx.BlockHeader().Time.Unix(),
+	}
+}
+
+func TestCaEngineRun(t *testing.T) {
+	testInput := keeper.CreateTestInput(t)
+	k := testInput.OrderKeeper
+	engine := &CaEngine{}
+
+	ctx := testInput.Ctx.WithBlockHeight(9)
+	err := testInput.DexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair())
+	require.Nil(t, err)
+
+	// resting orders
+	restingOrders := []*types.Order{
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "11.0", "1.0"),
+	}
+	for _, order := range restingOrders {
+		order.Sender = testInput.TestAddrs[1]
+		require.NoError(t, k.PlaceOrder(ctx, order))
+	}
+	blockMatchResult := newBlockMatchResult(ctx)
+	engine.Run(ctx, k, []string{types.TestTokenPair}, blockMatchResult)
+	require.EqualValues(t, 0, len(blockMatchResult.ResultMap))
+
+	// taker orders in the next block
+	ctx = testInput.Ctx.WithBlockHeight(10)
+	takerOrders := []*types.Order{
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "11.0", "1.5"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.5", "0.2"),
+	}
+	takerOrders[0].Sender = testInput.TestAddrs[0]
+	takerOrders[1].Sender = testInput.TestAddrs[1]
+	for _, order := range takerOrders {
+		require.NoError(t, k.PlaceOrder(ctx, order))
+	}
+	blockMatchResult = newBlockMatchResult(ctx)
+	engine.Run(ctx, k, []string{types.TestTokenPair}, blockMatchResult)
+
+	// check orders
+	buyOrder := k.GetOrder(ctx, takerOrders[0].OrderID)
+	require.EqualValues(t, types.OrderStatusFilled, buyOrder.Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("15.5").Quo(sdk.MustNewDecFromStr("1.5")), buyOrder.FilledAvgPrice)
+	require.EqualValues(t, types.OrderStatusFilled, k.GetOrder(ctx, restingOrders[0].OrderID).Status)
+	sellOrder := k.GetOrder(ctx, restingOrders[1].OrderID)
+	require.EqualValues(t, types.OrderStatusOpen, sellOrder.Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.5"), sellOrder.RemainQuantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("11.0"), sellOrder.FilledAvgPrice)
+	require.EqualValues(t, types.OrderStatusOpen, k.GetOrder(ctx, takerOrders[1].OrderID).Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("11.0"), k.GetLastPrice(ctx, types.TestTokenPair))
+
+	// check depth book
+	depthBook := k.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 2, len(depthBook.Items))
+	require.EqualValues(t, sdk.MustNewDecFromStr("11.0"), depthBook.Items[0].Price)
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.5"), depthBook.Items[0].SellQuantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.5"), depthBook.Items[1].Price)
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.2"), depthBook.Items[1].SellQuantity)
+	key := types.FormatOrderIDsKey(types.TestTokenPair, sdk.MustNewDecFromStr("11.0"), types.BuyOrder)
+	require.EqualValues(t, 0, len(k.GetProductPriceOrderIDs(key)))
+	key = types.FormatOrderIDsKey(types.TestTokenPair, sdk.MustNewDecFromStr("10.0"), types.SellOrder)
+	require.EqualValues(t, 0, len(k.GetProductPriceOrderIDs(key)))
+
+	// check block match result
+	result := blockMatchResult.ResultMap[types.TestTokenPair]
+	require.EqualValues(t, 10, result.BlockHeight)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.5"), result.Quantity)
+	require.EqualValues(t, buyOrder.FilledAvgPrice, result.Price)
+	require.EqualValues(t, 4, len(result.Deals))
+	require.EqualValues(t, restingOrders[0].OrderID, result.Deals[0].OrderID)
+	require.EqualValues(t, buyOrder.OrderID, result.Deals[1].OrderID)
+	require.EqualValues(t, restingOrders[1].OrderID, result.Deals[2].OrderID)
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.5"), result.Deals[3].Quantity)
+	// every deal is made at the maker price
+	for i, price := range []string{"10.0", "10.0", "11.0", "11.0"} {
+		require.EqualValues(t, sdk.MustNewDecFromStr(price), result.Deals[i].Price)
+	}
+
+	// check account balance: the buyer paid 15.5 instead of the 16.5 locked
+	expectCoins := sdk.DecCoins{
+		sdk.NewDecCoinFromDec(common.NativeToken, sdk.MustNewDecFromStr("84.5")),
+		sdk.NewDecCoinFromDec(common.TestToken, sdk.MustNewDecFromStr("101.4985")), // 100 + 1.5 * (1 - 0.001)
+	}
+	require.EqualValues(t, expectCoins.String(), k.GetCoins(ctx, testInput.TestAddrs[0]).String())
+}
+
+func TestCaEngineRunMaxDealsPerBlock(t *testing.T) {
+	testInput := keeper.CreateTestInput(t)
+	k := testInput.OrderKeeper
+	engine := &CaEngine{}
+	params := k.GetParams(testInput.Ctx)
+	params.MaxDealsPerBlock = 3
+	k.SetParams(testInput.Ctx, params)
+
+	ctx := testInput.Ctx.WithBlockHeight(9)
+	err := testInput.DexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair())
+	require.Nil(t, err)
+
+	restingOrders := []*types.Order{
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "11.0", "1.0"),
+	}
+	for _, order := range restingOrders {
+		order.Sender = testInput.TestAddrs[1]
+		require.NoError(t, k.PlaceOrder(ctx, order))
+	}
+	engine.Run(ctx, k, []string{types.TestTokenPair}, newBlockMatchResult(ctx))
+
+	// the taker stops before the price level which would exceed the deals limit
+	ctx = testInput.Ctx.WithBlockHeight(10)
+	taker := types.MockOrder("", types.TestTokenPair, types.BuyOrder, "11.0", "2.0")
+	taker.Sender = testInput.TestAddrs[0]
+	require.NoError(t, k.PlaceOrder(ctx, taker))
+	blockMatchResult := newBlockMatchResult(ctx)
+	engine.Run(ctx, k, []string{types.TestTokenPair}, blockMatchResult)
+	require.EqualValues(t, 2, len(blockMatchResult.ResultMap[types.TestTokenPair].Deals))
+	require.EqualValues(t, sdk.OneDec(), k.GetOrder(ctx, taker.OrderID).RemainQuantity)
+	require.EqualValues(t, []string{taker.OrderID}, k.GetPendingTakerOrderIDs(ctx))
+
+	// it goes on matching first in the next block
+	ctx = testInput.Ctx.WithBlockHeight(11)
+	blockMatchResult = newBlockMatchResult(ctx)
+	engine.Run(ctx, k, []string{types.TestTokenPair}, blockMatchResult)
+	result := blockMatchResult.ResultMap[types.TestTokenPair]
+	require.EqualValues(t, 2, len(result.Deals))
+	require.EqualValues(t, sdk.MustNewDecFromStr("11.0"), result.Deals[1].Price)
+	require.EqualValues(t, types.OrderStatusFilled, k.GetOrder(ctx, taker.OrderID).Status)
+	require.EqualValues(t, 0, len(k.GetPendingTakerOrderIDs(ctx)))
+}
+
+func TestCaEngineRunAmendedOrder(t *testing.T) {
+	testInput := keeper.CreateTestInput(t)
+	k := testInput.OrderKeeper
+	engine := &CaEngine{}
+
+	ctx := testInput.Ctx.WithBlockHeight(9)
+	err := testInput.DexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair())
+	require.Nil(t, err)
+
+	sellOrder := types.MockOrder("", types.TestTokenPair, types.SellOrder, "11.0", "2.0")
+	sellOrder.Sender = testInput.TestAddrs[1]
+	require.NoError(t, k.PlaceOrder(ctx, sellOrder))
+	amendedOrder := types.MockOrder("", types.TestTokenPair, types.BuyOrder, "9.0", "1.0")
+	amendedOrder.Sender = testInput.TestAddrs[0]
+	require.NoError(t, k.PlaceOrder(ctx, amendedOrder))
+	engine.Run(ctx, k, []string{types.TestTokenPair}, newBlockMatchResult(ctx))
+
+	// the resting order is moved to the crossing price after two new orders are placed
+	ctx = testInput.Ctx.WithBlockHeight(10)
+	k.ResetCache(ctx)
+	takerOrders := []*types.Order{
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "11.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "11.0", "1.0"),
+	}
+	for _, order := range takerOrders {
+		order.Sender = testInput.TestAddrs[0]
+		require.NoError(t, k.PlaceOrder(ctx, order))
+	}
+	err = k.AmendOrder(ctx, k.GetOrder(ctx, amendedOrder.OrderID), sdk.MustNewDecFromStr("11.0"), sdk.OneDec())
+	require.NoError(t, err)
+	engine.Run(ctx, k, []string{types.TestTokenPair}, newBlockMatchResult(ctx))
+
+	// the amended order is matched after the orders placed before the amendment
+	require.EqualValues(t, types.OrderStatusFilled, k.GetOrder(ctx, takerOrders[0].OrderID).Status)
+	require.EqualValues(t, types.OrderStatusFilled, k.GetOrder(ctx, takerOrders[1].OrderID).Status)
+	require.EqualValues(t, types.OrderStatusOpen, k.GetOrder(ctx, amendedOrder.OrderID).Status)
+	require.EqualValues(t, sdk.OneDec(), k.GetOrder(ctx, amendedOrder.OrderID).RemainQuantity)
+}
+
+func TestCaEngineRunMarketOrder(t *testing.T) {
+	testInput := keeper.CreateTestInput(t)
+	k := testInput.OrderKeeper
+	engine := &CaEngine{}
+
+	ctx := testInput.Ctx.WithBlockHeight(9)
+	err := testInput.DexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair())
+	require.Nil(t, err)
+
+	restingOrders := []*types.Order{
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "11.0", "1.0"),
+	}
+	for _, order := range restingOrders {
+		order.Sender = testInput.TestAddrs[1]
+		require.NoError(t, k.PlaceOrder(ctx, order))
+	}
+	engine.Run(ctx, k, []string{types.TestTokenPair}, newBlockMatchResult(ctx))
+
+	// the market order placed after the limit order takes the resting orders first
+	ctx = testInput.Ctx.WithBlockHeight(10)
+	limitOrder := types.MockOrder("", types.TestTokenPair, types.BuyOrder, "11.0", "1.5")
+	marketOrder := types.MockOrder("", types.TestTokenPair, types.BuyOrder, "11.0", "1.5")
+	marketOrder.OrderType = types.OrderTypeMarket
+	marketOrder.TimeInForce = types.TimeInForceIOC
+	for _, order := range []*types.Order{limitOrder, marketOrder} {
+		order.Sender = testInput.TestAddrs[0]
+		require.NoError(t, k.PlaceOrder(ctx, order))
+	}
+	blockMatchResult := newBlockMatchResult(ctx)
+	engine.Run(ctx, k, []string{types.TestTokenPair}, blockMatchResult)
+
+	order := k.GetOrder(ctx, marketOrder.OrderID)
+	require.EqualValues(t, types.OrderStatusFilled, order.Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("15.5").Quo(sdk.MustNewDecFromStr("1.5")), order.FilledAvgPrice)
+	order = k.GetOrder(ctx, limitOrder.OrderID)
+	require.EqualValues(t, types.OrderStatusOpen, order.Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.0"), order.RemainQuantity)
+
+	result := blockMatchResult.ResultMap[types.TestTokenPair]
+	require.EqualValues(t, sdk.MustNewDecFromStr("2.0"), result.Quantity)
+	require.EqualValues(t, marketOrder.OrderID, result.Deals[1].OrderID)
+	depthBook := k.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 1, len(depthBook.Items))
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.0"), depthBook.Items[0].BuyQuantity)
+}
+
+func TestCaEngineRunMarketBuyOrder(t *testing.T) {
+	testInput := keeper.CreateTestInput(t)
+	k := testInput.OrderKeeper
+	engine := &CaEngine{}
+
+	ctx := testInput.Ctx.WithBlockHeight(9)
+	err := testInput.DexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair())
+	require.Nil(t, err)
+
+	restingOrders := []*types.Order{
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "11.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "12.0", "1.0"),
+	}
+	for _, order := range restingOrders {
+		order.Sender = testInput.TestAddrs[1]
+		require.NoError(t, k.PlaceOrder(ctx, order))
+	}
+	engine.Run(ctx, k, []string{types.TestTokenPair}, newBlockMatchResult(ctx))
+
+	newMarketBuyOrder := func(worstPrice, amount string) *types.Order {
+		order := types.MockOrder("", types.TestTokenPair, types.BuyOrder, worstPrice, "1.0")
+		order.OrderType = types.OrderTypeMarket
+		order.TimeInForce = types.TimeInForceIOC
+		order.SpendQuoteAmount(sdk.MustNewDecFromStr(amount))
+		order.Sender = testInput.TestAddrs[0]
+		return order
+	}
+
+	// the amount is spent at the better prices till it's used up
+	ctx = testInput.Ctx.WithBlockHeight(10)
+	marketOrder := newMarketBuyOrder("12.0", "15.5")
+	require.NoError(t, k.PlaceOrder(ctx, marketOrder))
+	require.EqualValues(t, sdk.MustNewDecFromStr("15.5"),
+		testInput.TokenKeeper.GetLockCoins(ctx, marketOrder.Sender).AmountOf(common.NativeToken))
+	engine.Run(ctx, k, []string{types.TestTokenPair}, newBlockMatchResult(ctx))
+
+	order := k.GetOrder(ctx, marketOrder.OrderID)
+	require.EqualValues(t, types.OrderStatusFilled, order.Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.5"), order.Quantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("15.5").Quo(sdk.MustNewDecFromStr("1.5")), order.FilledAvgPrice)
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.5"), k.GetOrder(ctx, restingOrders[1].OrderID).RemainQuantity)
+	require.True(t, testInput.TokenKeeper.GetLockCoins(ctx, marketOrder.Sender).IsZero())
+
+	// the amount left at the worst price is refunded
+	ctx = testInput.Ctx.WithBlockHeight(11)
+	marketOrder = newMarketBuyOrder("11.0", "50.0")
+	require.NoError(t, k.PlaceOrder(ctx, marketOrder))
+	engine.Run(ctx, k, []string{types.TestTokenPair}, newBlockMatchResult(ctx))
+
+	order = k.GetOrder(ctx, marketOrder.OrderID)
+	require.EqualValues(t, types.OrderStatusIOCCancelled, order.Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("11.0"), order.FilledAvgPrice)
+	require.EqualValues(t, types.OrderStatusFilled, k.GetOrder(ctx, restingOrders[1].OrderID).Status)
+	require.EqualValues(t, types.OrderStatusOpen, k.GetOrder(ctx, restingOrders[2].OrderID).Status)
+	require.True(t, testInput.TokenKeeper.GetLockCoins(ctx, marketOrder.Sender).IsZero())
+	depthBook := k.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 1, len(depthBook.Items))
+	require.EqualValues(t, sdk.MustNewDecFromStr("12.0"), depthBook.Items[0].Price)
+}
+
+func TestCaEngineRunIcebergOrder(t *testing.T) {
+	testInput := keeper.CreateTestInput(t)
+	k := testInput.OrderKeeper
+	engine := &CaEngine{}
+
+	ctx := testInput.Ctx.WithBlockHeight(9)
+	err := testInput.DexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair())
+	require.Nil(t, err)
+
+	icebergOrder := types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "3.0")
+	displayQuantity := sdk.MustNewDecFromStr("1.0")
+	icebergOrder.DisplayQuantity = &displayQuantity
+	restingOrder := types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0")
+	for _, order := range []*types.Order{icebergOrder, restingOrder} {
+		order.Sender = testInput.TestAddrs[1]
+		require.NoError(t, k.PlaceOrder(ctx, order))
+	}
+	engine.Run(ctx, k, []string{types.TestTokenPair}, newBlockMatchResult(ctx))
+
+	// only the display slice of the iceberg order is shown in the depth book
+	depthBook := k.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 1, len(depthBook.Items))
+	require.EqualValues(t, sdk.MustNewDecFromStr("2.0"), depthBook.Items[0].SellQuantity)
+
+	// the filled slice is refreshed behind the resting order
+	ctx = testInput.Ctx.WithBlockHeight(10)
+	takerOrder := types.MockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.5")
+	takerOrder.Sender = testInput.TestAddrs[0]
+	require.NoError(t, k.PlaceOrder(ctx, takerOrder))
+	engine.Run(ctx, k, []string{types.TestTokenPair}, newBlockMatchResult(ctx))
+
+	order := k.GetOrder(ctx, takerOrder.OrderID)
+	require.EqualValues(t, types.OrderStatusFilled, order.Status)
+	order = k.GetOrder(ctx, icebergOrder.OrderID)
+	require.EqualValues(t, sdk.MustNewDecFromStr("2.0"), order.RemainQuantity)
+	order = k.GetOrder(ctx, restingOrder.OrderID)
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.5"), order.RemainQuantity)
+
+	key := types.FormatOrderIDsKey(types.TestTokenPair, sdk.MustNewDecFromStr("10.0"), types.SellOrder)
+	require.EqualValues(t, []string{restingOrder.OrderID, icebergOrder.OrderID}, k.GetProductPriceOrderIDs(key))
+	depthBook = k.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 1, len(depthBook.Items))
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.5"), depthBook.Items[0].SellQuantity)
+}
+
+func TestCaEngineRunDecrementAndCancel(t *testing.T) {
+	testInput := keeper.CreateTestInput(t)
+	k := testInput.OrderKeeper
+	engine := &CaEngine{}
+
+	ctx := testInput.Ctx.WithBlockHeight(9)
+	err := testInput.DexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair())
+	require.Nil(t, err)
+
+	// the order of the taker's sender rests between two other orders at the same price
+	restingOrders := []*types.Order{
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "2.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "5.0"),
+	}
+	for i, order := range restingOrders {
+		order.Sender = testInput.TestAddrs[1]
+		if i == 1 {
+			order.Sender = testInput.TestAddrs[0]
+		}
+		require.NoError(t, k.PlaceOrder(ctx, order))
+	}
+	engine.Run(ctx, k, []string{types.TestTokenPair}, newBlockMatchResult(ctx))
+
+	// the taker is decremented by the quantity of the order cancelled, and takes only what's left of it
+	ctx = testInput.Ctx.WithBlockHeight(10)
+	takerOrder := types.MockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "6.0")
+	takerOrder.Sender = testInput.TestAddrs[0]
+	takerOrder.SelfTradePrevention = types.SelfTradePreventionDecrementAndCancel
+	require.NoError(t, k.PlaceOrder(ctx, takerOrder))
+	blockMatchResult := newBlockMatchResult(ctx)
+	engine.Run(ctx, k, []string{types.TestTokenPair}, blockMatchResult)
+
+	taker := k.GetOrder(ctx, takerOrder.OrderID)
+	require.EqualValues(t, types.OrderStatusFilled, taker.Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("4.0"), taker.Quantity)
+	require.True(t, taker.RemainQuantity.IsZero())
+	require.True(t, taker.RemainLocked.IsZero())
+	require.EqualValues(t, types.OrderStatusFilled, k.GetOrder(ctx, restingOrders[0].OrderID).Status)
+	require.EqualValues(t, types.OrderStatusSelfTradeCancelled, k.GetOrder(ctx, restingOrders[1].OrderID).Status)
+	maker := k.GetOrder(ctx, restingOrders[2].OrderID)
+	require.EqualValues(t, types.OrderStatusOpen, maker.Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("2.0"), maker.RemainQuantity)
+
+	result := blockMatchResult.ResultMap[types.TestTokenPair]
+	require.EqualValues(t, sdk.MustNewDecFromStr("4.0"), result.Quantity)
+	require.EqualValues(t, 3, len(result.Deals))
+	require.EqualValues(t, sdk.MustNewDecFromStr("3.0"), result.Deals[1].Quantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("4.0"), result.Deals[2].Quantity)
+	depthBook := k.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 1, len(depthBook.Items))
+	require.EqualValues(t, sdk.MustNewDecFromStr("2.0"), depthBook.Items[0].SellQuantity)
+	require.True(t, testInput.TokenKeeper.GetLockCoins(ctx, takerOrder.Sender).IsZero())
+	_, broken := keeper.LockedCoinsInvariant(k)(ctx)
+	require.False(t, broken)
+}
@@ -0,0 +1,48 @@
+package continuousauction
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	dextypes "github.com/okex/okchain/x/dex/types"
+	"github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+)
+
+// checkPriceBand halts the product of the taker if the taker would trade at a price out of its price band.
+// The prices are estimated with the depth book, from the best price on the other side to the price where the
+// taker would be filled. It returns false if the product has been halted.
+func checkPriceBand(ctx sdk.Context, k keeper.Keeper, taker *types.Order) bool {
+	band := k.GetPriceBand(ctx, taker.Product)
+	if band == nil || band.Action != dextypes.PriceBandActionHalt {
+		return true
+	}
+	referencePrice := k.GetReferencePrice(ctx, taker.Product, band)
+
+	book := k.GetDepthBookCopy(taker.Product)
+	bookLength := len(book.Items)
+	quantity := sdk.ZeroDec()
+	for step := 0; step < bookLength && quantity.LT(taker.RemainQuantity); step++ {
+		index := step
+		if taker.Side == types.BuyOrder {
+			index = bookLength - 1 - step
+		}
+		item := book.Items[index]
+		if (taker.Side == types.BuyOrder && item.Price.GT(taker.Price)) ||
+			(taker.Side == types.SellOrder && item.Price.LT(taker.Price)) {
+			break
+		}
+		levelQuantity := item.SellQuantity
+		if taker.Side == types.SellOrder {
+			levelQuantity = item.BuyQuantity
+		}
+		if !levelQuantity.IsPositive() {
+			continue
+		}
+		if !band.Contains(referencePrice, item.Price) {
+			k.HaltProduct(ctx, taker.Product, item.Price)
+			return false
+		}
+		quantity = quantity.Add(levelQuantity)
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+package continuousauction
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+)
+
+// checkTimeInForce rejects a post-only taker which would take resting orders, and kills a FOK taker which
+// can't be filled entirely by the resting orders. It returns false if the taker has been closed.
+func checkTimeInForce(ctx sdk.Context, k keeper.Keeper, taker *types.Order, pendingOrderIDs map[string]struct{},
+	logger log.Logger) bool {
+
+	switch taker.GetTimeInForce() {
+	case types.TimeInForcePostOnly:
+		if matchableQuantity(ctx, k, taker, pendingOrderIDs).IsPositive() {
+			k.RejectPostOnlyOrder(ctx, taker, logger)
+			logger.Debug(fmt.Sprintf("post-only order(%s) is rejected", taker.OrderID))
+			return false
+		}
+	case types.TimeInForceFOK:
+		if matchableQuantity(ctx, k, taker, pendingOrderIDs).LT(taker.RemainQuantity) {
+			k.KillFOKOrder(ctx, taker, logger)
+			logger.Debug(fmt.Sprintf("FOK order(%s) is killed", taker.OrderID))
+			return false
+		}
+	}
+	return true
+}
+
+// cancelIOCRemain cancels the remaining quantity of an IOC taker after it has been matched
+func cancelIOCRemain(ctx sdk.Context, k keeper.Keeper, taker *types.Order, logger log.Logger) {
+	if taker.GetTimeInForce() == types.TimeInForceIOC && taker.Status == types.OrderStatusOpen {
+		k.CancelIOCOrder(ctx, taker, logger)
+		logger.Debug(fmt.Sprintf("IOC order(%s) is cancelled with remain quantity %s",
+			taker.OrderID, taker.RemainQuantity))
+	}
+}
+
+// matchableQuantity sums the quantity of the resting orders the taker can take, up to its remaining quantity.
+// The resting orders of the same sender are skipped if the taker cancels them, otherwise the taker stops there.
+func matchableQuantity(ctx sdk.Context, k keeper.Keeper, taker *types.Order,
+	pendingOrderIDs map[string]struct{}) sdk.Dec {
+
+	makerSide := types.SellOrder
+	if taker.Side == types.SellOrder {
+		makerSide = types.BuyOrder
+	}
+
+	mode := taker.GetSelfTradePrevention()
+	book := k.GetDepthBookCopy(taker.Product)
+	bookLength := len(book.Items)
+	quantity := sdk.ZeroDec()
+	for step := 0; step < bookLength && quantity.LT(taker.RemainQuantity); step++ {
+		index := step
+		if taker.Side == types.BuyOrder {
+			index = bookLength - 1 - step
+		}
+		item := book.Items[index]
+		if (taker.Side == types.BuyOrder && item.Price.GT(taker.Price)) ||
+			(taker.Side == types.SellOrder && item.Price.LT(taker.Price)) {
+			break
+		}
+
+		key := types.FormatOrderIDsKey(taker.Product, item.Price, makerSide)
+		hiddenQuantity := sdk.ZeroDec()
+		reachable := true
+		for _, makerID := range k.GetProductPriceOrderIDs(key) {
+			if _, ok := pendingOrderIDs[makerID]; ok {
+				reachable = false
+				break
+			}
+			if !quantity.LT(taker.RemainQuantity) {
+				break
+			}
+			maker := k.GetOrder(ctx, makerID)
+			if mode != types.SelfTradePreventionNone && maker.Sender.Equals(taker.Sender) {
+				if mode == types.SelfTradePreventionCancelOldest {
+					continue
+				}
+				return sdk.MinDec(quantity, taker.RemainQuantity)
+			}
+			quantity = quantity.Add(maker.VisibleQuantity())
+			hiddenQuantity = hiddenQuantity.Add(maker.RemainQuantity.Sub(maker.VisibleQuantity()))
+		}
+		// the next slices of iceberg orders are queued behind the orders placed after the taker
+		if reachable {
+			quantity = quantity.Add(hiddenQuantity)
+		}
+	}
+	return sdk.MinDec(quantity, taker.RemainQuantity)
+}
@@ -1,35 +1,151 @@
 package match
 
 import (
-	"sync"
+	"sort"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
+	dextypes "github.com/okex/okchain/x/dex/types"
 	"github.com/okex/okchain/x/order/keeper"
 	"github.com/okex/okchain/x/order/match/continuousauction"
 	"github.com/okex/okchain/x/order/match/periodicauction"
+	"github.com/okex/okchain/x/order/types"
 )
 
-const DefaultAuctionType = "periodicauction"
+var engines = map[string]Engine{
+	dextypes.MatchingModePeriodicAuction:   &periodicauction.PaEngine{},
+	dextypes.MatchingModeContinuousAuction: &continuousauction.CaEngine{},
+}
 
-var (
-	once        sync.Once
-	engine      Engine
-	auctionType = DefaultAuctionType
-)
+// GetEngine returns the engine of the matching mode, the periodic auction one if the mode is unknown
+func GetEngine(matchingMode string) Engine {
+	if engine, ok := engines[matchingMode]; ok {
+		return engine
+	}
+	return engines[dextypes.DefaultMatchingMode]
+}
+
+// Engine matches the orders of the products dispatched to it, and records the deals in blockMatchResult
+type Engine interface {
+	Run(ctx sdk.Context, keeper keeper.Keeper, products []string, blockMatchResult *types.BlockMatchResult)
+}
+
+// Run dispatches every product which might be matched in this block to the engine of its matching mode.
+// A locked product is always dispatched to the periodic auction engine to finish its execution,
+// so that switching the matching mode of a product is safe at any time.
+// A product halted by its price band is not matched till the end of its cooling-off period,
+// then it's resumed and reopens with a call auction of the periodic auction engine.
+// IOC and FOK orders left open by the engines are closed after the matching.
+func Run(ctx sdk.Context, keeper keeper.Keeper) {
+	lockMap := keeper.GetDexKeeper().GetLockedProductsCopy()
+	candidates := keeper.GetDiskCache().GetNewDepthbookKyes()
+	for product := range lockMap.Data {
+		candidates = append(candidates, product)
+	}
+	// orders triggered in the last block entered the depth book after its matching, and the continuous auction
+	// left some orders unmatched when the deals limit of the last block was reached
+	pendingTakerIDs := keeper.GetPendingTakerOrderIDs(ctx)
+	for _, orderID := range append(keeper.GetTriggeredOrderIDs(ctx), pendingTakerIDs...) {
+		if order := keeper.GetOrder(ctx, orderID); order != nil {
+			candidates = append(candidates, order.Product)
+		}
+	}
+	// the depth books left crossed by iceberg orders in the last block
+	candidates = append(candidates, keeper.GetRematchProducts(ctx)...)
+	keeper.SetRematchProducts(ctx, nil)
+	sort.Strings(candidates)
 
-// periodic auction only today
-func GetEngine() Engine {
-	once.Do(func() {
-		if auctionType == DefaultAuctionType {
-			engine = &periodicauction.PaEngine{}
-		} else {
-			engine = &continuousauction.CaEngine{}
+	dispatched := make(map[string][]string)
+	for i, product := range candidates {
+		if i > 0 && candidates[i-1] == product {
+			continue
+		}
+		lock, locked := lockMap.Data[product]
+		if locked && lock.IsHalt() {
+			if ctx.BlockHeight() < lock.HaltEndHeight {
+				continue
+			}
+			keeper.ResumeProduct(ctx, product)
+		}
+		tokenPair := keeper.GetDexKeeper().GetTokenPair(ctx, product)
+		if tokenPair == nil {
+			continue
 		}
-	})
-	return engine
+		matchingMode := tokenPair.GetMatchingMode()
+		if locked {
+			matchingMode = dextypes.MatchingModePeriodicAuction
+		}
+		dispatched[matchingMode] = append(dispatched[matchingMode], product)
+	}
+
+	blockMatchResult := &types.BlockMatchResult{
+		BlockHeight: ctx.BlockHeight(),
+		ResultMap:   make(map[string]types.MatchResult),
+		TimeStamp:   ctx.BlockHeader().Time.Unix(),
+	}
+	for _, matchingMode := range []string{dextypes.MatchingModePeriodicAuction, dextypes.MatchingModeContinuousAuction} {
+		if products := dispatched[matchingMode]; len(products) > 0 {
+			GetEngine(matchingMode).Run(ctx, keeper, products, blockMatchResult)
+		}
+	}
+	// the products of the pending orders are matched by the periodic auction now
+	if len(dispatched[dextypes.MatchingModeContinuousAuction]) == 0 {
+		keeper.SetPendingTakerOrderIDs(ctx, nil)
+	}
+	keeper.SetBlockMatchResult(blockMatchResult)
+
+	// IOC and FOK orders never rest in the depth book after their first matching
+	heights := []int64{ctx.BlockHeight()}
+	for _, orderID := range pendingTakerIDs {
+		heights = append(heights, types.GetBlockHeightFromOrderID(orderID))
+	}
+	for product, lock := range lockMap.Data {
+		if !keeper.IsProductExecuting(product) {
+			heights = append(heights, lock.BlockHeight)
+		}
+	}
+	closeImmediateOrders(ctx, keeper, heights)
 }
 
-type Engine interface {
-	Run(ctx sdk.Context, keeper keeper.Keeper)
+// closeImmediateOrders cancels the open IOC orders and kills the open FOK orders placed at the heights,
+// except those of the products which are still locked by the periodic auction, and those waiting for their first
+// matching by the continuous auction. The orders of halted products are closed too, rather than resting till the
+// end of the cooling-off period.
+func closeImmediateOrders(ctx sdk.Context, keeper keeper.Keeper, heights []int64) {
+	logger := ctx.Logger().With("module", "order")
+	pendingTakerIDs := make(map[string]struct{})
+	for _, orderID := range keeper.GetPendingTakerOrderIDs(ctx) {
+		pendingTakerIDs[orderID] = struct{}{}
+	}
+	executingProducts := make(map[string]bool)
+	isExecuting := func(product string) bool {
+		executing, ok := executingProducts[product]
+		if !ok {
+			executing = keeper.IsProductExecuting(product)
+			executingProducts[product] = executing
+		}
+		return executing
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	for i, height := range heights {
+		if i > 0 && heights[i-1] == height {
+			continue
+		}
+		orderNum := keeper.GetBlockOrderNum(ctx, height)
+		for j := int64(1); j <= orderNum; j++ {
+			order := keeper.GetOrder(ctx, types.FormatOrderID(height, j))
+			if order == nil || order.Status != types.OrderStatusOpen || isExecuting(order.Product) {
+				continue
+			}
+			if _, ok := pendingTakerIDs[order.OrderID]; ok {
+				continue
+			}
+			switch order.GetTimeInForce() {
+			case types.TimeInForceIOC:
+				keeper.CancelIOCOrder(ctx, order, logger)
+			case types.TimeInForceFOK:
+				keeper.KillFOKOrder(ctx, order, logger)
+			}
+		}
+	}
 }
@@ -0,0 +1,305 @@
+package match
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/dex"
+	"github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+)
+
+func runBlock(t *testing.T, testInput keeper.TestInput, height int64, orders ...*types.Order) sdk.Context {
+	ctx := testInput.Ctx.WithBlockHeight(height)
+	k := testInput.OrderKeeper
+	k.ResetCache(ctx)
+	for _, order := range orders {
+		// buy orders and sell orders come from different senders unless the sender is given
+		if order.Sender == nil {
+			order.Sender = testInput.TestAddrs[1]
+			if order.Side == types.BuyOrder {
+				order.Sender = testInput.TestAddrs[0]
+			}
+		}
+		require.NoError(t, k.PlaceOrder(ctx, order))
+	}
+	Run(ctx, k)
+	k.Cache2Disk(ctx)
+	return ctx
+}
+
+func TestRunByMatchingMode(t *testing.T) {
+	testInput := keeper.CreateTestInput(t)
+	k := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(9)
+
+	tokenPair := dex.GetBuiltInTokenPair()
+	tokenPair.MatchingMode = dex.MatchingModeContinuousAuction
+	require.Nil(t, testInput.DexKeeper.SaveTokenPair(ctx, tokenPair))
+
+	// continuous auction: the sell order takes the buy order placed before it at the buy price
+	runBlock(t, testInput, 10,
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "11.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+	)
+	result := k.GetBlockMatchResult().ResultMap[types.TestTokenPair]
+	require.EqualValues(t, sdk.MustNewDecFromStr("11.0"), result.Price)
+	require.EqualValues(t, 2, len(result.Deals))
+
+	// periodic auction: the orders are matched at the clearing price closest to the last price
+	require.Nil(t, testInput.DexKeeper.SetMatchingMode(ctx, types.TestTokenPair, dex.MatchingModePeriodicAuction))
+	runBlock(t, testInput, 11,
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "11.0", "1.0"),
+	)
+	result = k.GetBlockMatchResult().ResultMap[types.TestTokenPair]
+	require.EqualValues(t, sdk.MustNewDecFromStr("11.0"), result.Price)
+	require.EqualValues(t, 2, len(result.Deals))
+
+	// a product locked by the periodic auction finishes its execution after switching mode
+	params := k.GetParams(ctx)
+	params.MaxDealsPerBlock = 2
+	k.SetParams(ctx, params)
+	ctx = runBlock(t, testInput, 12,
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "2.0"),
+	)
+	require.True(t, k.IsProductLocked(types.TestTokenPair))
+	require.Nil(t, testInput.DexKeeper.SetMatchingMode(ctx, types.TestTokenPair, dex.MatchingModeContinuousAuction))
+
+	runBlock(t, testInput, 13)
+	require.False(t, k.IsProductLocked(types.TestTokenPair))
+	result = k.GetBlockMatchResult().ResultMap[types.TestTokenPair]
+	require.EqualValues(t, 13, result.BlockHeight)
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.0"), result.Price)
+	require.EqualValues(t, 1, len(result.Deals))
+	require.EqualValues(t, 0, len(k.GetDepthBookCopy(types.TestTokenPair).Items))
+}
+
+func TestRunTimeInForce(t *testing.T) {
+	testInput := keeper.CreateTestInput(t)
+	k := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(9)
+
+	tokenPair := dex.GetBuiltInTokenPair()
+	tokenPair.MatchingMode = dex.MatchingModeContinuousAuction
+	require.Nil(t, testInput.DexKeeper.SaveTokenPair(ctx, tokenPair))
+
+	newOrder := func(side, price, quantity, timeInForce string) *types.Order {
+		order := types.MockOrder("", types.TestTokenPair, side, price, quantity)
+		order.TimeInForce = timeInForce
+		return order
+	}
+	requireOrder := func(order *types.Order, status int64, remainQuantity string) {
+		order = k.GetOrder(ctx, order.OrderID)
+		require.EqualValues(t, status, order.Status)
+		require.EqualValues(t, sdk.MustNewDecFromStr(remainQuantity), order.RemainQuantity)
+	}
+
+	// continuous auction
+	runBlock(t, testInput, 10,
+		newOrder(types.SellOrder, "10.0", "1.0", ""),
+		newOrder(types.SellOrder, "11.0", "1.0", types.TimeInForceGTC),
+	)
+	orders := []*types.Order{
+		newOrder(types.BuyOrder, "10.0", "1.0", types.TimeInForcePostOnly),
+		newOrder(types.BuyOrder, "11.0", "3.0", types.TimeInForceFOK),
+		newOrder(types.BuyOrder, "10.5", "2.0", types.TimeInForceIOC),
+		newOrder(types.BuyOrder, "9.0", "1.0", types.TimeInForcePostOnly),
+	}
+	ctx = runBlock(t, testInput, 11, orders...)
+	requireOrder(orders[0], types.OrderStatusPostOnlyRejected, "1.0")
+	requireOrder(orders[1], types.OrderStatusFOKKilled, "3.0")
+	requireOrder(orders[2], types.OrderStatusIOCCancelled, "1.0")
+	requireOrder(orders[3], types.OrderStatusOpen, "1.0")
+	book := k.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 2, len(book.Items))
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.0"), book.Items[0].SellQuantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.0"), book.Items[1].BuyQuantity)
+
+	// periodic auction
+	require.Nil(t, testInput.DexKeeper.SetMatchingMode(ctx, types.TestTokenPair, dex.MatchingModePeriodicAuction))
+	orders = []*types.Order{
+		newOrder(types.SellOrder, "9.0", "1.0", types.TimeInForcePostOnly),
+		newOrder(types.BuyOrder, "12.0", "2.0", types.TimeInForceIOC),
+		newOrder(types.BuyOrder, "11.0", "2.0", types.TimeInForceFOK),
+	}
+	ctx = runBlock(t, testInput, 12, orders...)
+	requireOrder(orders[0], types.OrderStatusPostOnlyRejected, "1.0")
+	requireOrder(orders[1], types.OrderStatusIOCCancelled, "1.0")
+	requireOrder(orders[2], types.OrderStatusFOKKilled, "2.0")
+	result := k.GetBlockMatchResult().ResultMap[types.TestTokenPair]
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.0"), result.Quantity)
+	book = k.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 1, len(book.Items))
+	require.EqualValues(t, sdk.MustNewDecFromStr("9.0"), book.Items[0].Price)
+}
+
+func TestRunSelfTradePrevention(t *testing.T) {
+	testInput := keeper.CreateTestInput(t)
+	k := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(9)
+
+	tokenPair := dex.GetBuiltInTokenPair()
+	tokenPair.MatchingMode = dex.MatchingModeContinuousAuction
+	require.Nil(t, testInput.DexKeeper.SaveTokenPair(ctx, tokenPair))
+
+	newOrder := func(sender int, side, price, quantity, mode string) *types.Order {
+		order := types.MockOrder("", types.TestTokenPair, side, price, quantity)
+		order.Sender = testInput.TestAddrs[sender]
+		order.SelfTradePrevention = mode
+		return order
+	}
+	requireOrder := func(order *types.Order, status int64, quantity, remainQuantity string) {
+		order = k.GetOrder(ctx, order.OrderID)
+		require.EqualValues(t, status, order.Status)
+		require.EqualValues(t, sdk.MustNewDecFromStr(quantity), order.Quantity)
+		require.EqualValues(t, sdk.MustNewDecFromStr(remainQuantity), order.RemainQuantity)
+	}
+	requireNoResult := func() {
+		_, ok := k.GetBlockMatchResult().ResultMap[types.TestTokenPair]
+		require.False(t, ok)
+	}
+
+	// continuous auction: the mode of the taker applies
+	restingSell := newOrder(0, types.SellOrder, "10.0", "1.0", "")
+	runBlock(t, testInput, 10, restingSell)
+	cancelNewest := newOrder(0, types.BuyOrder, "10.0", "1.0", types.SelfTradePreventionCancelNewest)
+	ctx = runBlock(t, testInput, 11, cancelNewest)
+	requireOrder(cancelNewest, types.OrderStatusSelfTradeCancelled, "1.0", "1.0")
+	requireOrder(restingSell, types.OrderStatusOpen, "1.0", "1.0")
+	requireNoResult()
+
+	otherSell := newOrder(1, types.SellOrder, "10.0", "1.0", "")
+	cancelOldest := newOrder(0, types.BuyOrder, "10.0", "2.0", types.SelfTradePreventionCancelOldest)
+	ctx = runBlock(t, testInput, 12, otherSell, cancelOldest)
+	requireOrder(restingSell, types.OrderStatusSelfTradeCancelled, "1.0", "1.0")
+	requireOrder(otherSell, types.OrderStatusFilled, "1.0", "0")
+	requireOrder(cancelOldest, types.OrderStatusOpen, "2.0", "1.0")
+	result := k.GetBlockMatchResult().ResultMap[types.TestTokenPair]
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.0"), result.Quantity)
+	require.EqualValues(t, 2, len(result.Deals))
+	require.EqualValues(t, otherSell.OrderID, result.Deals[0].OrderID)
+	require.EqualValues(t, cancelOldest.OrderID, result.Deals[1].OrderID)
+
+	decrement := newOrder(0, types.SellOrder, "10.0", "0.4", types.SelfTradePreventionDecrementAndCancel)
+	ctx = runBlock(t, testInput, 13, decrement)
+	requireOrder(decrement, types.OrderStatusSelfTradeCancelled, "0.4", "0.4")
+	requireOrder(cancelOldest, types.OrderStatusOpen, "1.6", "0.6")
+	requireNoResult()
+	book := k.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 1, len(book.Items))
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.6"), book.Items[0].BuyQuantity)
+
+	cancelBoth := newOrder(0, types.SellOrder, "10.0", "1.0", types.SelfTradePreventionCancelBoth)
+	ctx = runBlock(t, testInput, 14, cancelBoth)
+	requireOrder(cancelBoth, types.OrderStatusSelfTradeCancelled, "1.0", "1.0")
+	requireOrder(cancelOldest, types.OrderStatusSelfTradeCancelled, "1.6", "0.6")
+	requireNoResult()
+	require.EqualValues(t, 0, len(k.GetDepthBookCopy(types.TestTokenPair).Items))
+
+	// an unknown mode is matched as NONE
+	sell := newOrder(0, types.SellOrder, "10.0", "1.0", "")
+	unknown := newOrder(0, types.BuyOrder, "10.0", "1.0", "CANCEL")
+	ctx = runBlock(t, testInput, 15, sell, unknown)
+	requireOrder(sell, types.OrderStatusFilled, "1.0", "0")
+	requireOrder(unknown, types.OrderStatusFilled, "1.0", "0")
+
+	// periodic auction: the mode of the newer order of a crossed pair applies before the call auction
+	require.Nil(t, testInput.DexKeeper.SetMatchingMode(ctx, types.TestTokenPair, dex.MatchingModePeriodicAuction))
+	orders := []*types.Order{
+		newOrder(0, types.SellOrder, "10.0", "1.0", ""),
+		newOrder(1, types.BuyOrder, "10.0", "1.0", ""),
+		newOrder(0, types.BuyOrder, "11.0", "1.0", types.SelfTradePreventionCancelNewest),
+	}
+	ctx = runBlock(t, testInput, 16, orders...)
+	requireOrder(orders[0], types.OrderStatusFilled, "1.0", "0")
+	requireOrder(orders[1], types.OrderStatusFilled, "1.0", "0")
+	requireOrder(orders[2], types.OrderStatusSelfTradeCancelled, "1.0", "1.0")
+	result = k.GetBlockMatchResult().ResultMap[types.TestTokenPair]
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.0"), result.Price)
+	require.EqualValues(t, 2, len(result.Deals))
+	for _, deal := range result.Deals {
+		require.NotEqual(t, orders[2].OrderID, deal.OrderID)
+	}
+}
+
+func TestRunPriceBand(t *testing.T) {
+	testInput := keeper.CreateTestInput(t)
+	k := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(9)
+
+	tokenPair := dex.GetBuiltInTokenPair()
+	tokenPair.PriceBand = &dex.PriceBand{MaxDeviation: sdk.MustNewDecFromStr("0.1"), Action: dex.PriceBandActionHalt,
+		HaltBlocks: 2}
+	require.Nil(t, testInput.DexKeeper.SaveTokenPair(ctx, tokenPair))
+	setMaxDeviation := func(maxDeviation string) {
+		band := *tokenPair.PriceBand
+		band.MaxDeviation = sdk.MustNewDecFromStr(maxDeviation)
+		require.Nil(t, testInput.DexKeeper.SetPriceBand(ctx, types.TestTokenPair, &band))
+	}
+	requireHalted := func(ctx sdk.Context, halted bool) {
+		require.EqualValues(t, halted, k.IsProductLocked(types.TestTokenPair))
+		_, ok := k.GetBlockMatchResult().ResultMap[types.TestTokenPair]
+		require.EqualValues(t, halted, !ok)
+	}
+
+	// periodic auction: the clearing price 12.0 is out of the band around the last price 10.0
+	ctx = runBlock(t, testInput, 10,
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "12.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "12.0", "1.0"),
+	)
+	requireHalted(ctx, true)
+	require.EqualValues(t, 12, testInput.DexKeeper.GetLockedProductsCopy().Data[types.TestTokenPair].HaltEndHeight)
+	// the events of all the blocks are kept by the event manager of the test context
+	require.EqualValues(t, 1, countEvents(ctx, types.EventTypeHaltProduct))
+	require.EqualValues(t, 1, len(k.GetDepthBookCopy(types.TestTokenPair).Items))
+
+	// the IOC orders placed while halted are cancelled instead of resting till the end of the cooling-off period
+	iocOrder := types.MockOrder("", types.TestTokenPair, types.BuyOrder, "11.0", "1.0")
+	iocOrder.TimeInForce = types.TimeInForceIOC
+	ctx = runBlock(t, testInput, 11, iocOrder)
+	requireHalted(ctx, true)
+	require.EqualValues(t, types.OrderStatusIOCCancelled, k.GetOrder(ctx, iocOrder.OrderID).Status)
+
+	// the product reopens with a call auction after the cooling-off period
+	setMaxDeviation("0.5")
+	ctx = runBlock(t, testInput, 12)
+	requireHalted(ctx, false)
+	require.EqualValues(t, 1, countEvents(ctx, types.EventTypeResumeProduct))
+	require.EqualValues(t, sdk.MustNewDecFromStr("12.0"), k.GetLastPrice(ctx, types.TestTokenPair))
+	require.EqualValues(t, 0, len(k.GetDepthBookCopy(types.TestTokenPair).Items))
+
+	// continuous auction: the buy order would take the sell order at 14.0 out of the band around 12.0
+	setMaxDeviation("0.1")
+	require.Nil(t, testInput.DexKeeper.SetMatchingMode(ctx, types.TestTokenPair, dex.MatchingModeContinuousAuction))
+	runBlock(t, testInput, 13,
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "12.5", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "14.0", "1.0"),
+	)
+	buyOrder := types.MockOrder("", types.TestTokenPair, types.BuyOrder, "14.0", "2.0")
+	ctx = runBlock(t, testInput, 14, buyOrder)
+	requireHalted(ctx, true)
+	require.EqualValues(t, 2, countEvents(ctx, types.EventTypeHaltProduct))
+	require.EqualValues(t, types.OrderStatusOpen, k.GetOrder(ctx, buyOrder.OrderID).Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("12.0"), k.GetLastPrice(ctx, types.TestTokenPair))
+
+	setMaxDeviation("0.5")
+	runBlock(t, testInput, 15)
+	ctx = runBlock(t, testInput, 16)
+	requireHalted(ctx, false)
+	require.EqualValues(t, types.OrderStatusFilled, k.GetOrder(ctx, buyOrder.OrderID).Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("14.0"), k.GetLastPrice(ctx, types.TestTokenPair))
+}
+
+func countEvents(ctx sdk.Context, eventType string) (count int) {
+	for _, event := range ctx.EventManager().Events() {
+		if event.Type == eventType {
+			count++
+		}
+	}
+	return count
+}
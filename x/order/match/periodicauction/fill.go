@@ -0,0 +1,195 @@
+package periodicauction
+
+import (
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+)
+
+// executeMatch fills the orders of a product at the price of its lock, until both sides have executed
+// the quantity of the lock or the deals limit of this block is reached.
+// The product keeps locked while the execution is not completed.
+func executeMatch(ctx sdk.Context, k keeper.Keeper, product string, lock *types.ProductLock, marketOrderIDs []string,
+	remainDeals int64, blockMatchResult *types.BlockMatchResult, logger log.Logger) int64 {
+
+	book := k.GetDepthBookCopy(product)
+
+	buyDeals, buyExecuted := fillDepthBook(ctx, k, book, product, types.BuyOrder, lock.Price,
+		lock.Quantity.Sub(lock.BuyExecuted), lock.BlockHeight, marketOrderIDs, remainDeals, logger)
+	lock.BuyExecuted = lock.BuyExecuted.Add(buyExecuted)
+	remainDeals -= int64(len(buyDeals))
+
+	sellDeals, sellExecuted := fillDepthBook(ctx, k, book, product, types.SellOrder, lock.Price,
+		lock.Quantity.Sub(lock.SellExecuted), lock.BlockHeight, marketOrderIDs, remainDeals, logger)
+	lock.SellExecuted = lock.SellExecuted.Add(sellExecuted)
+	remainDeals -= int64(len(sellDeals))
+
+	if len(buyDeals)+len(sellDeals) > 0 {
+		k.SetDepthBook(product, book)
+		// the deals are made in this block, even if the lock is carried over from a previous one
+		blockMatchResult.ResultMap[product] = types.MatchResult{
+			BlockHeight: ctx.BlockHeight(),
+			Price:       lock.Price,
+			Quantity:    lock.Quantity,
+			Deals:       append(buyDeals, sellDeals...),
+		}
+	}
+
+	if lock.BuyExecuted.LT(lock.Quantity) || lock.SellExecuted.LT(lock.Quantity) {
+		k.SetProductLock(ctx, product, lock)
+	} else if k.IsProductLocked(product) {
+		k.UnlockProduct(ctx, product)
+	}
+	return remainDeals
+}
+
+// isCrossed checks whether the highest buy price is not lower than the lowest sell price in the depth book,
+// which happens when the next slices of iceberg orders are displayed after the execution
+func isCrossed(book *types.DepthBook) bool {
+	for i, buyItem := range book.Items {
+		if !buyItem.BuyQuantity.IsPositive() {
+			continue
+		}
+		// items are sorted by price desc
+		for _, sellItem := range book.Items[i:] {
+			if sellItem.SellQuantity.IsPositive() {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// fillDepthBook fills the orders on one side of the depth book which can be executed at price,
+// market orders first and then in price-time priority, until quantity is executed or remainDeals deals are made.
+// The orders placed before the block of the call auction at auctionHeight are charged as makers.
+func fillDepthBook(ctx sdk.Context, k keeper.Keeper, book *types.DepthBook, product, side string,
+	price, quantity sdk.Dec, auctionHeight int64, marketOrderIDs []string, remainDeals int64,
+	logger log.Logger) (deals []types.Deal, executed sdk.Dec) {
+
+	executed = sdk.ZeroDec()
+	if !quantity.IsPositive() || remainDeals <= 0 {
+		return deals, executed
+	}
+
+	bookLength := len(book.Items)
+	for _, orderID := range marketOrderIDs {
+		remainQuantity := quantity.Sub(executed)
+		if !remainQuantity.IsPositive() || int64(len(deals)) >= remainDeals {
+			break
+		}
+		order := k.GetOrder(ctx, orderID)
+		if order.Side != side || order.Status != types.OrderStatusOpen || !canExecute(order.Price, side, price) {
+			continue
+		}
+		// the clearing price is discovered with the quantity a market buy order affords at its worst price, which
+		// its amount always affords at the clearing price
+		fillQuantity := sdk.MinDec(order.RemainQuantity, remainQuantity)
+		dealFee := k.FillOrder(ctx, order, price, fillQuantity, false, logger)
+		// items are sorted by price desc
+		index := sort.Search(bookLength, func(i int) bool {
+			return order.Price.GTE(book.Items[i].Price)
+		})
+		book.Sub(index, fillQuantity, side)
+
+		executed = executed.Add(fillQuantity)
+		deal := types.Deal{
+			OrderID:  order.OrderID,
+			Side:     order.Side,
+			Price:    price,
+			Quantity: fillQuantity,
+			Fee:      dealFee.String(),
+		}
+		deals = append(deals, deal)
+		k.AfterOrderFilled(ctx, order, price, deal)
+		if order.Status == types.OrderStatusFilled {
+			removeOrderID(k, types.FormatOrderIDsKey(product, order.Price, side), orderID)
+		}
+	}
+
+	for step := 0; step < bookLength; step++ {
+		// buy orders are filled from the highest price, sell orders from the lowest price
+		index := step
+		if side == types.SellOrder {
+			index = bookLength - 1 - step
+		}
+		item := book.Items[index]
+		if !canExecute(item.Price, side, price) {
+			break
+		}
+		if (side == types.BuyOrder && !item.BuyQuantity.IsPositive()) ||
+			(side == types.SellOrder && !item.SellQuantity.IsPositive()) {
+			continue
+		}
+
+		key := types.FormatOrderIDsKey(product, item.Price, side)
+		orderIDs := append([]string{}, k.GetProductPriceOrderIDs(key)...)
+		doneNum := 0
+		for doneNum < len(orderIDs) {
+			remainQuantity := quantity.Sub(executed)
+			if !remainQuantity.IsPositive() || int64(len(deals)) >= remainDeals {
+				break
+			}
+			order := k.GetOrder(ctx, orderIDs[doneNum])
+			visibleQuantity := order.VisibleQuantity()
+			fillQuantity := sdk.MinDec(visibleQuantity, remainQuantity)
+			isMaker := types.GetBlockHeightFromOrderID(order.OrderID) < auctionHeight
+			dealFee := k.FillOrder(ctx, order, price, fillQuantity, isMaker, logger)
+			book.Sub(index, fillQuantity, side)
+
+			executed = executed.Add(fillQuantity)
+			deal := types.Deal{
+				OrderID:  order.OrderID,
+				Side:     order.Side,
+				Price:    price,
+				Quantity: fillQuantity,
+				Fee:      dealFee.String(),
+			}
+			deals = append(deals, deal)
+			k.AfterOrderFilled(ctx, order, price, deal)
+			if order.Status != types.OrderStatusFilled && fillQuantity.LT(visibleQuantity) {
+				break
+			}
+			doneNum++
+			if order.Status != types.OrderStatusFilled {
+				// the display slice of an iceberg order is filled, its next slice is queued behind the others
+				book.Add(index, order.VisibleQuantity(), side)
+				orderIDs = append(orderIDs, order.OrderID)
+			}
+		}
+		if doneNum > 0 {
+			// orders are filled in time priority, so the filled ones are in front
+			k.SetOrderIDs(key, orderIDs[doneNum:])
+		}
+		if !quantity.Sub(executed).IsPositive() || int64(len(deals)) >= remainDeals {
+			break
+		}
+	}
+
+	book.RemoveEmptyItems()
+	return deals, executed
+}
+
+// canExecute checks whether an order at orderPrice on the side can be executed at price
+func canExecute(orderPrice sdk.Dec, side string, price sdk.Dec) bool {
+	if side == types.BuyOrder {
+		return orderPrice.GTE(price)
+	}
+	return orderPrice.LTE(price)
+}
+
+func removeOrderID(k keeper.Keeper, key string, orderID string) {
+	orderIDs := k.GetProductPriceOrderIDs(key)
+	remainIDs := make([]string, 0, len(orderIDs))
+	for _, id := range orderIDs {
+		if id != orderID {
+			remainIDs = append(remainIDs, id)
+		}
+	}
+	k.SetOrderIDs(key, remainIDs)
+}
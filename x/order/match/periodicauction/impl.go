@@ -1,15 +1,113 @@
 package periodicauction
 
 import (
+	"sort"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/libs/log"
 
+	dextypes "github.com/okex/okchain/x/dex/types"
 	"github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
 )
 
 // PaEngine is the periodic auction match engine
 type PaEngine struct {
 }
 
-// Run
-func (e *PaEngine) Run(ctx sdk.Context, keeper keeper.Keeper) {
+// Run executes a call auction on every product dispatched to it:
+// 1. continue the execution of products locked in previous blocks
+// 2. cancel or decrement the crossed orders of the same sender by self-trade prevention
+// 3. close the new post-only and FOK orders which break their time in force
+// 4. match the other products at their clearing price, or halt those whose clearing price is out of their price bands
+// Market orders are filled ahead of the limit orders on their side.
+func (e *PaEngine) Run(ctx sdk.Context, keeper keeper.Keeper, products []string,
+	blockMatchResult *types.BlockMatchResult) {
+
+	logger := ctx.Logger().With("module", "order")
+
+	matchOrders(ctx, keeper, products, blockMatchResult, logger)
+}
+
+func matchOrders(ctx sdk.Context, k keeper.Keeper, products []string, blockMatchResult *types.BlockMatchResult,
+	logger log.Logger) {
+
+	remainDeals := k.GetParams(ctx).MaxDealsPerBlock
+
+	// locked products have been matched in previous blocks, finish them first
+	lockMap := k.GetDexKeeper().GetLockedProductsCopy()
+	var lockedProducts, newProducts []string
+	for _, product := range products {
+		if _, ok := lockMap.Data[product]; ok {
+			lockedProducts = append(lockedProducts, product)
+		} else {
+			newProducts = append(newProducts, product)
+		}
+	}
+	sort.Strings(lockedProducts)
+	var rematchProducts []string
+	marketOrderIDs := make(map[int64]map[string][]string)
+	for _, product := range lockedProducts {
+		lock := lockMap.Data[product]
+		if _, ok := marketOrderIDs[lock.BlockHeight]; !ok {
+			marketOrderIDs[lock.BlockHeight] = getMarketOrderIDs(ctx, k, lock.BlockHeight)
+		}
+		remainDeals = executeMatch(ctx, k, product, lock, marketOrderIDs[lock.BlockHeight][product], remainDeals,
+			blockMatchResult, logger)
+		rematchProducts = appendIfCrossed(k, rematchProducts, product)
+	}
+
+	// only new orders can make a depth book crossed
+	sort.Strings(newProducts)
+	k.GetDexKeeper().SortProducts(ctx, newProducts)
+	preventSelfTrades(ctx, k, newProducts, logger)
+	newMarketOrderIDs := getMarketOrderIDs(ctx, k, ctx.BlockHeight())
+	checkTimeInForce(ctx, k, newProducts, newMarketOrderIDs, logger)
+	for _, product := range newProducts {
+		book := k.GetDepthBookCopy(product)
+		price, execution := periodicAuctionMatchPrice(book, k.GetLastPrice(ctx, product))
+		if !execution.IsPositive() {
+			continue
+		}
+		if k.IsPriceOutOfBand(ctx, product, price, dextypes.PriceBandActionHalt) {
+			k.HaltProduct(ctx, product, price)
+			continue
+		}
+		k.SetLastPrice(ctx, product, price)
+
+		lock := &types.ProductLock{
+			BlockHeight:  ctx.BlockHeight(),
+			Price:        price,
+			Quantity:     execution,
+			BuyExecuted:  sdk.ZeroDec(),
+			SellExecuted: sdk.ZeroDec(),
+		}
+		remainDeals = executeMatch(ctx, k, product, lock, newMarketOrderIDs[product], remainDeals, blockMatchResult,
+			logger)
+		rematchProducts = appendIfCrossed(k, rematchProducts, product)
+	}
+	k.SetRematchProducts(ctx, rematchProducts)
+}
+
+// appendIfCrossed appends the product if its execution is completed with a crossed depth book,
+// so that it's matched again in the next block
+func appendIfCrossed(k keeper.Keeper, products []string, product string) []string {
+	if !k.IsProductLocked(product) && isCrossed(k.GetDepthBookCopy(product)) {
+		return append(products, product)
+	}
+	return products
+}
+
+// getMarketOrderIDs returns the ids of the open market orders placed at blockHeight, grouped by product
+func getMarketOrderIDs(ctx sdk.Context, k keeper.Keeper, blockHeight int64) map[string][]string {
+	marketOrderIDs := make(map[string][]string)
+	orderNum := k.GetBlockOrderNum(ctx, blockHeight)
+	for i := int64(1); i <= orderNum; i++ {
+		order := k.GetOrder(ctx, types.FormatOrderID(blockHeight, i))
+		if order == nil || order.Status != types.OrderStatusOpen || order.OrderType != types.OrderTypeMarket {
+			continue
+		}
+		marketOrderIDs[order.Product] = append(marketOrderIDs[order.Product], order.OrderID)
+	}
+	return marketOrderIDs
 }
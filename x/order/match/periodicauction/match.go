@@ -0,0 +1,97 @@
+package periodicauction
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/order/types"
+)
+
+// periodicAuctionMatchPrice calculates the clearing price of a depth book, i.e. the price which
+// maximizes the executed quantity. If several prices have the max execution:
+// 1. choose the prices with the min imbalance between buy and sell quantity
+// 2. if buy quantity exceeds sell quantity at all of them, choose the highest one
+// 3. if sell quantity exceeds buy quantity at all of them, choose the lowest one
+// 4. otherwise, choose the price closest to the reference price
+func periodicAuctionMatchPrice(book *types.DepthBook, refPrice sdk.Dec) (bestPrice sdk.Dec, maxExecution sdk.Dec) {
+	bookLength := len(book.Items)
+	if bookLength == 0 {
+		return refPrice, sdk.ZeroDec()
+	}
+
+	// items are sorted by price desc, buy orders at a price match sell orders at lower or equal prices
+	buyAmountSums := make([]sdk.Dec, bookLength)
+	sellAmountSums := make([]sdk.Dec, bookLength)
+	buyAmountSum := sdk.ZeroDec()
+	for i := 0; i < bookLength; i++ {
+		buyAmountSum = buyAmountSum.Add(book.Items[i].BuyQuantity)
+		buyAmountSums[i] = buyAmountSum
+	}
+	sellAmountSum := sdk.ZeroDec()
+	for i := bookLength - 1; i >= 0; i-- {
+		sellAmountSum = sellAmountSum.Add(book.Items[i].SellQuantity)
+		sellAmountSums[i] = sellAmountSum
+	}
+
+	// 1. find the prices with max execution
+	maxExecution = sdk.ZeroDec()
+	var indexesWithMaxExecution []int
+	for i := 0; i < bookLength; i++ {
+		execution := sdk.MinDec(buyAmountSums[i], sellAmountSums[i])
+		if execution.GT(maxExecution) {
+			maxExecution = execution
+			indexesWithMaxExecution = []int{i}
+		} else if execution.IsPositive() && execution.Equal(maxExecution) {
+			indexesWithMaxExecution = append(indexesWithMaxExecution, i)
+		}
+	}
+	if !maxExecution.IsPositive() {
+		return refPrice, maxExecution
+	}
+	if len(indexesWithMaxExecution) == 1 {
+		return book.Items[indexesWithMaxExecution[0]].Price, maxExecution
+	}
+
+	// 2. keep the prices with min imbalance
+	var indexesWithMinImbalance []int
+	minAbsImbalance := sdk.ZeroDec()
+	for _, index := range indexesWithMaxExecution {
+		absImbalance := buyAmountSums[index].Sub(sellAmountSums[index]).Abs()
+		if len(indexesWithMinImbalance) == 0 || absImbalance.LT(minAbsImbalance) {
+			minAbsImbalance = absImbalance
+			indexesWithMinImbalance = []int{index}
+		} else if absImbalance.Equal(minAbsImbalance) {
+			indexesWithMinImbalance = append(indexesWithMinImbalance, index)
+		}
+	}
+	if len(indexesWithMinImbalance) == 1 {
+		return book.Items[indexesWithMinImbalance[0]].Price, maxExecution
+	}
+
+	// 3. follow the market pressure
+	highestIndex := indexesWithMinImbalance[0]
+	lowestIndex := indexesWithMinImbalance[len(indexesWithMinImbalance)-1]
+	// imbalance never decreases along with the price going down
+	if buyAmountSums[highestIndex].GT(sellAmountSums[highestIndex]) {
+		// buy pressure at the highest candidate, so at all of them
+		return book.Items[highestIndex].Price, maxExecution
+	}
+	if sellAmountSums[lowestIndex].GT(buyAmountSums[lowestIndex]) {
+		// sell pressure at the lowest candidate, so at all of them
+		return book.Items[lowestIndex].Price, maxExecution
+	}
+
+	// 4. no pressure, follow the reference price
+	return bestPriceFromRefPrice(book.Items[lowestIndex].Price, book.Items[highestIndex].Price, refPrice),
+		maxExecution
+}
+
+// bestPriceFromRefPrice limits the reference price to [minPrice, maxPrice]
+func bestPriceFromRefPrice(minPrice, maxPrice, refPrice sdk.Dec) sdk.Dec {
+	if refPrice.LT(minPrice) {
+		return minPrice
+	}
+	if refPrice.GT(maxPrice) {
+		return maxPrice
+	}
+	return refPrice
+}
@@ -0,0 +1,89 @@
+package periodicauction
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/order/types"
+)
+
+func mockDepthBook(orders ...*types.Order) *types.DepthBook {
+	book := &types.DepthBook{}
+	for _, order := range orders {
+		book.InsertOrder(order)
+	}
+	return book
+}
+
+func TestPeriodicAuctionMatchPrice(t *testing.T) {
+	refPrice := sdk.MustNewDecFromStr("10.0")
+
+	// empty book
+	price, execution := periodicAuctionMatchPrice(&types.DepthBook{}, refPrice)
+	require.EqualValues(t, refPrice, price)
+	require.True(t, execution.IsZero())
+
+	// book not crossed
+	book := mockDepthBook(
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "9.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "11.0", "1.0"),
+	)
+	price, execution = periodicAuctionMatchPrice(book, refPrice)
+	require.EqualValues(t, refPrice, price)
+	require.True(t, execution.IsZero())
+
+	// only one price with max execution
+	book = mockDepthBook(
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "12.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "11.0", "2.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "2.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "11.0", "3.0"),
+	)
+	price, execution = periodicAuctionMatchPrice(book, refPrice)
+	require.EqualValues(t, sdk.MustNewDecFromStr("11.0"), price)
+	require.EqualValues(t, sdk.MustNewDecFromStr("3.0"), execution)
+
+	// min imbalance
+	book = mockDepthBook(
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "12.0", "2.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "11.0", "2.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "12.0", "1.0"),
+	)
+	price, execution = periodicAuctionMatchPrice(book, refPrice)
+	require.EqualValues(t, sdk.MustNewDecFromStr("11.0"), price)
+	require.EqualValues(t, sdk.MustNewDecFromStr("2.0"), execution)
+
+	// buy pressure, choose the highest price
+	book = mockDepthBook(
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "12.0", "3.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "11.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "13.0", "1.0"),
+	)
+	price, execution = periodicAuctionMatchPrice(book, refPrice)
+	require.EqualValues(t, sdk.MustNewDecFromStr("12.0"), price)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.0"), execution)
+
+	// sell pressure, choose the lowest price
+	book = mockDepthBook(
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "12.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "11.0", "3.0"),
+	)
+	price, execution = periodicAuctionMatchPrice(book, refPrice)
+	require.EqualValues(t, sdk.MustNewDecFromStr("11.0"), price)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.0"), execution)
+
+	// no pressure, follow the reference price
+	book = mockDepthBook(
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "12.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "9.0", "1.0"),
+	)
+	price, _ = periodicAuctionMatchPrice(book, refPrice)
+	require.EqualValues(t, refPrice, price)
+	price, _ = periodicAuctionMatchPrice(book, sdk.MustNewDecFromStr("8.0"))
+	require.EqualValues(t, sdk.MustNewDecFromStr("9.0"), price)
+	price, _ = periodicAuctionMatchPrice(book, sdk.MustNewDecFromStr("13.0"))
+	require.EqualValues(t, sdk.MustNewDecFromStr("12.0"), price)
+}
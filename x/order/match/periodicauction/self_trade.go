@@ -0,0 +1,116 @@
+package periodicauction
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+)
+
+// preventSelfTrades applies self-trade prevention to the crossed orders of the same sender before the call auction,
+// so that no sender is filled on both sides of it. The mode of the newer order of each crossed pair applies.
+func preventSelfTrades(ctx sdk.Context, k keeper.Keeper, products []string, logger log.Logger) {
+	for _, product := range products {
+		buyOrders, sellOrders := getCrossedOrders(ctx, k, product)
+		sellOrdersMap := make(map[string][]*types.Order)
+		for _, order := range sellOrders {
+			sender := order.Sender.String()
+			sellOrdersMap[sender] = append(sellOrdersMap[sender], order)
+		}
+
+		// buy orders from the highest price, sell orders from the lowest price
+		for _, buyOrder := range buyOrders {
+			for _, sellOrder := range sellOrdersMap[buyOrder.Sender.String()] {
+				if buyOrder.Status != types.OrderStatusOpen || sellOrder.Price.GT(buyOrder.Price) {
+					break
+				}
+				if sellOrder.Status == types.OrderStatusOpen {
+					handleSelfTrade(ctx, k, buyOrder, sellOrder, logger)
+				}
+			}
+		}
+	}
+}
+
+// getCrossedOrders returns the open orders in the crossed part of the depth book in price-time priority
+func getCrossedOrders(ctx sdk.Context, k keeper.Keeper, product string) (buyOrders, sellOrders []*types.Order) {
+	book := k.GetDepthBookCopy(product)
+	bookLength := len(book.Items)
+	// items are sorted by price desc
+	buyEnd := 0
+	for buyEnd < bookLength && !book.Items[buyEnd].BuyQuantity.IsPositive() {
+		buyEnd++
+	}
+	sellEnd := bookLength - 1
+	for sellEnd >= 0 && !book.Items[sellEnd].SellQuantity.IsPositive() {
+		sellEnd--
+	}
+	if buyEnd >= bookLength || sellEnd < 0 || book.Items[buyEnd].Price.LT(book.Items[sellEnd].Price) {
+		return nil, nil
+	}
+	bestBid, bestAsk := book.Items[buyEnd].Price, book.Items[sellEnd].Price
+
+	for i := buyEnd; i < bookLength && book.Items[i].Price.GTE(bestAsk); i++ {
+		key := types.FormatOrderIDsKey(product, book.Items[i].Price, types.BuyOrder)
+		for _, orderID := range k.GetProductPriceOrderIDs(key) {
+			buyOrders = append(buyOrders, k.GetOrder(ctx, orderID))
+		}
+	}
+	for i := sellEnd; i >= 0 && book.Items[i].Price.LTE(bestBid); i-- {
+		key := types.FormatOrderIDsKey(product, book.Items[i].Price, types.SellOrder)
+		for _, orderID := range k.GetProductPriceOrderIDs(key) {
+			sellOrders = append(sellOrders, k.GetOrder(ctx, orderID))
+		}
+	}
+	return buyOrders, sellOrders
+}
+
+// handleSelfTrade applies the self-trade prevention mode of the newer order to a crossed pair of the same sender
+func handleSelfTrade(ctx sdk.Context, k keeper.Keeper, buyOrder, sellOrder *types.Order, logger log.Logger) {
+	newOrder, oldOrder := buyOrder, sellOrder
+	if placedBefore(buyOrder.OrderID, sellOrder.OrderID) {
+		newOrder, oldOrder = sellOrder, buyOrder
+	}
+
+	var closedOrders []*types.Order
+	switch newOrder.GetSelfTradePrevention() {
+	case types.SelfTradePreventionCancelNewest:
+		closedOrders = []*types.Order{newOrder}
+	case types.SelfTradePreventionCancelOldest:
+		closedOrders = []*types.Order{oldOrder}
+	case types.SelfTradePreventionCancelBoth:
+		closedOrders = []*types.Order{newOrder, oldOrder}
+	case types.SelfTradePreventionDecrementAndCancel:
+		largerOrder, smallerOrder := buyOrder, sellOrder
+		if buyOrder.RemainQuantity.LT(sellOrder.RemainQuantity) {
+			largerOrder, smallerOrder = sellOrder, buyOrder
+		}
+		if largerOrder.RemainQuantity.GT(smallerOrder.RemainQuantity) {
+			k.DecrementOrder(ctx, largerOrder, smallerOrder.RemainQuantity)
+			logger.Debug(fmt.Sprintf("order(%s) is decremented by self-trade prevention", largerOrder.OrderID))
+			closedOrders = []*types.Order{smallerOrder}
+		} else {
+			closedOrders = []*types.Order{largerOrder, smallerOrder}
+		}
+	}
+
+	for _, order := range closedOrders {
+		k.CancelSelfTradeOrder(ctx, order, logger)
+		logger.Debug(fmt.Sprintf("order(%s) is cancelled by self-trade prevention", order.OrderID))
+	}
+}
+
+// placedBefore checks whether the order of orderID is placed before the order of otherID
+func placedBefore(orderID, otherID string) bool {
+	var blockHeight, orderNum, otherBlockHeight, otherOrderNum int64
+	format := "ID%d-%d"
+	fmt.Sscanf(orderID, format, &blockHeight, &orderNum)
+	fmt.Sscanf(otherID, format, &otherBlockHeight, &otherOrderNum)
+	if blockHeight != otherBlockHeight {
+		return blockHeight < otherBlockHeight
+	}
+	return orderNum < otherOrderNum
+}
@@ -0,0 +1,158 @@
+package periodicauction
+
+import (
+	"fmt"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+)
+
+// checkTimeInForce closes the new post-only orders which would be filled and the new FOK orders which would not
+// be filled entirely by the call auction of the product. As closing an order changes the clearing price,
+// the auction is simulated again until no more order is closed.
+func checkTimeInForce(ctx sdk.Context, k keeper.Keeper, products []string, marketOrderIDs map[string][]string,
+	logger log.Logger) {
+	watchedOrders := getWatchedOrders(ctx, k, products)
+	for _, product := range products {
+		orders, ok := watchedOrders[product]
+		if !ok {
+			continue
+		}
+		for len(orders) > 0 {
+			book := k.GetDepthBookCopy(product)
+			price, execution := periodicAuctionMatchPrice(book, k.GetLastPrice(ctx, product))
+			if !execution.IsPositive() {
+				break
+			}
+
+			fills := simulateFills(ctx, k, book, product, types.BuyOrder, price, execution, marketOrderIDs[product],
+				orders)
+			for orderID, quantity := range simulateFills(ctx, k, book, product, types.SellOrder, price, execution,
+				marketOrderIDs[product], orders) {
+				fills[orderID] = quantity
+			}
+
+			// close orders in a fixed order to keep the state transition deterministic
+			orderIDs := make([]string, 0, len(orders))
+			for orderID := range orders {
+				orderIDs = append(orderIDs, orderID)
+			}
+			sort.Strings(orderIDs)
+
+			closed := false
+			for _, orderID := range orderIDs {
+				order := orders[orderID]
+				quantity, filled := fills[orderID]
+				if order.GetTimeInForce() == types.TimeInForcePostOnly && filled {
+					k.RejectPostOnlyOrder(ctx, order, logger)
+					logger.Debug(fmt.Sprintf("post-only order(%s) is rejected", orderID))
+				} else if order.GetTimeInForce() == types.TimeInForceFOK &&
+					(!filled || quantity.LT(order.RemainQuantity)) {
+					k.KillFOKOrder(ctx, order, logger)
+					logger.Debug(fmt.Sprintf("FOK order(%s) is killed", orderID))
+				} else {
+					continue
+				}
+				delete(orders, orderID)
+				closed = true
+			}
+			if !closed {
+				break
+			}
+		}
+	}
+}
+
+// getWatchedOrders returns the open post-only and FOK orders placed in this block, grouped by product
+func getWatchedOrders(ctx sdk.Context, k keeper.Keeper, products []string) map[string]map[string]*types.Order {
+	productSet := make(map[string]struct{}, len(products))
+	for _, product := range products {
+		productSet[product] = struct{}{}
+	}
+
+	watchedOrders := make(map[string]map[string]*types.Order)
+	blockHeight := ctx.BlockHeight()
+	orderNum := k.GetBlockOrderNum(ctx, blockHeight)
+	for i := int64(1); i <= orderNum; i++ {
+		order := k.GetOrder(ctx, types.FormatOrderID(blockHeight, i))
+		if order == nil || order.Status != types.OrderStatusOpen {
+			continue
+		}
+		if timeInForce := order.GetTimeInForce(); timeInForce != types.TimeInForcePostOnly &&
+			timeInForce != types.TimeInForceFOK {
+			continue
+		}
+		if _, ok := productSet[order.Product]; !ok {
+			continue
+		}
+		if watchedOrders[order.Product] == nil {
+			watchedOrders[order.Product] = make(map[string]*types.Order)
+		}
+		watchedOrders[order.Product][order.OrderID] = order
+	}
+	return watchedOrders
+}
+
+// simulateFills returns the quantities the watched orders on one side would be filled by executing quantity
+// at price, market orders first and then in price-time priority, as fillDepthBook does
+func simulateFills(ctx sdk.Context, k keeper.Keeper, book *types.DepthBook, product, side string,
+	price, quantity sdk.Dec, marketOrderIDs []string, watchedOrders map[string]*types.Order) map[string]sdk.Dec {
+
+	fills := make(map[string]sdk.Dec)
+	executed := sdk.ZeroDec()
+	marketOrders := make(map[string]struct{}, len(marketOrderIDs))
+	for _, orderID := range marketOrderIDs {
+		order := k.GetOrder(ctx, orderID)
+		if order.Side != side || !canExecute(order.Price, side, price) {
+			continue
+		}
+		marketOrders[orderID] = struct{}{}
+		executed = executed.Add(sdk.MinDec(order.RemainQuantity, quantity.Sub(executed)))
+	}
+	bookLength := len(book.Items)
+	for step := 0; step < bookLength && executed.LT(quantity); step++ {
+		index := step
+		if side == types.SellOrder {
+			index = bookLength - 1 - step
+		}
+		item := book.Items[index]
+		if !canExecute(item.Price, side, price) {
+			break
+		}
+
+		key := types.FormatOrderIDsKey(product, item.Price, side)
+		orderIDs := append([]string{}, k.GetProductPriceOrderIDs(key)...)
+		// the hidden quantities of the iceberg orders whose display slices are filled
+		hiddenQuantities := make(map[string]sdk.Dec)
+		for i := 0; i < len(orderIDs) && executed.LT(quantity); i++ {
+			orderID := orderIDs[i]
+			if _, ok := marketOrders[orderID]; ok {
+				continue
+			}
+			order := k.GetOrder(ctx, orderID)
+			remainQuantity, visibleQuantity := order.RemainQuantity, order.VisibleQuantity()
+			if hiddenQuantity, ok := hiddenQuantities[orderID]; ok {
+				remainQuantity, visibleQuantity = hiddenQuantity, sdk.MinDec(*order.DisplayQuantity, hiddenQuantity)
+			}
+			fillQuantity := sdk.MinDec(visibleQuantity, quantity.Sub(executed))
+			executed = executed.Add(fillQuantity)
+			// the refreshed slices of an iceberg order add up to its fill
+			if _, ok := watchedOrders[orderID]; ok {
+				filled, ok := fills[orderID]
+				if !ok {
+					filled = sdk.ZeroDec()
+				}
+				fills[orderID] = filled.Add(fillQuantity)
+			}
+			if fillQuantity.Equal(visibleQuantity) && remainQuantity.GT(fillQuantity) {
+				hiddenQuantities[orderID] = remainQuantity.Sub(fillQuantity)
+				orderIDs = append(orderIDs, orderID)
+			}
+		}
+	}
+	return fills
+}
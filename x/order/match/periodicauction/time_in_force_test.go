@@ -0,0 +1,38 @@
+package periodicauction
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/dex"
+	"github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+)
+
+func TestSimulateFillsIcebergOrder(t *testing.T) {
+	testInput := keeper.CreateTestInput(t)
+	k := testInput.OrderKeeper
+
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	err := testInput.DexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair())
+	require.Nil(t, err)
+
+	icebergOrder := types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "3.0")
+	displayQuantity := sdk.MustNewDecFromStr("1.0")
+	icebergOrder.DisplayQuantity = &displayQuantity
+	icebergOrder.Sender = testInput.TestAddrs[0]
+	require.NoError(t, k.PlaceOrder(ctx, icebergOrder))
+	restingOrder := types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0")
+	restingOrder.Sender = testInput.TestAddrs[1]
+	require.NoError(t, k.PlaceOrder(ctx, restingOrder))
+
+	// the first slice, the resting order and the refreshed slice of the iceberg order are filled
+	book := k.GetDepthBookCopy(types.TestTokenPair)
+	watchedOrders := map[string]*types.Order{icebergOrder.OrderID: icebergOrder}
+	fills := simulateFills(ctx, k, book, types.TestTokenPair, types.SellOrder,
+		sdk.MustNewDecFromStr("10.0"), sdk.MustNewDecFromStr("3.0"), nil, watchedOrders)
+	require.EqualValues(t, 1, len(fills))
+	require.EqualValues(t, sdk.MustNewDecFromStr("2.0"), fills[icebergOrder.OrderID])
+}
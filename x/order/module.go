@@ -89,7 +89,7 @@ func NewAppModule(v version.ProtocolVersionType, keeper keeper.Keeper, supplyKee
 
 // RegisterInvariants : register invariants
 func (am AppModule) RegisterInvariants(ir sdk.InvariantRegistry) {
-	//RegisterInvariants(ir, am.keeper)
+	keeper.RegisterInvariants(ir, am.keeper)
 }
 
 // Route : module message route name
@@ -0,0 +1,72 @@
+package simulator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/spf13/cobra"
+)
+
+// GetCmdSimulate runs orders through the real match engines offline
+func GetCmdSimulate(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "simulate-orders [input-file] [events-file]",
+		Short: "Simulate the matching of orders offline",
+		Long: strings.TrimSpace(`Simulate the matching of orders with the order handler, the match engines and the keepers
+against an in-memory store, without running a chain:
+
+$ okchaincli simulate-orders input.json events.jsonl
+
+The input file gives the order params, the token pairs, the named accounts with their coins, and the orders placed in
+block 1 as the initial depth book:
+
+{
+  "params": null,
+  "token_pairs": [{"base_asset_symbol": "xxb", "quote_asset_symbol": "okt", "price": "10.0",
+    "max_price_digit": "8", "max_size_digit": "8", "min_trade_size": "0.001"}],
+  "accounts": [{"name": "alice", "coins": [{"denom": "xxb", "amount": "100.0"}]}],
+  "depth_book": [{"sender": "alice", "new_orders": [{"product": "xxb_okt", "side": "SELL", "price": "10.5", "quantity": "1.0"}]}]
+}
+
+Every line of the events file sends new orders or cancels orders of an account in the block at a height after 1,
+in the order of the heights:
+
+{"height": "2", "sender": "bob", "new_orders": [{"product": "xxb_okt", "side": "BUY", "price": "10.5", "quantity": "0.5"}]}
+{"height": "3", "sender": "alice", "cancel_order_ids": ["ID0000000001-1"]}
+
+The msg results, match results and fee details of every block, then the final balances of the accounts,
+are printed a JSON per line. The block time is the height in seconds, so the output is deterministic.
+Integers are quoted in the input as in amino JSON.
+`),
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bz, err := ioutil.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			var input Input
+			if err := cdc.UnmarshalJSON(bz, &input); err != nil {
+				return fmt.Errorf("invalid input file: %v", err)
+			}
+
+			eventsFile, err := os.Open(args[1])
+			if err != nil {
+				return err
+			}
+			defer eventsFile.Close()
+			events, err := ReadEvents(cdc, eventsFile)
+			if err != nil {
+				return err
+			}
+
+			simulator, err := NewSimulator(cdc, &input)
+			if err != nil {
+				return err
+			}
+			return simulator.Run(input.DepthBook, events, cmd.OutOrStdout())
+		},
+	}
+}
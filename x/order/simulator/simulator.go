@@ -0,0 +1,280 @@
+package simulator
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/supply"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/libs/log"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/okex/okchain/x/common/monitor"
+	"github.com/okex/okchain/x/dex"
+	"github.com/okex/okchain/x/order"
+	"github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+	"github.com/okex/okchain/x/params"
+	"github.com/okex/okchain/x/token"
+)
+
+// Input is the initial state of a simulation
+type Input struct {
+	Params     *types.Params    `json:"params"` // the default order params if nil
+	TokenPairs []*dex.TokenPair `json:"token_pairs"`
+	Accounts   []Account        `json:"accounts"`
+	DepthBook  []Event          `json:"depth_book"` // orders placed in block 1, which make the initial depth book
+}
+
+// Account is an account funded with coins, named instead of addressed in the events
+type Account struct {
+	Name  string       `json:"name"`
+	Coins sdk.DecCoins `json:"coins"`
+}
+
+// Event is a line of the event file, which sends new orders or cancels orders in the block at height
+type Event struct {
+	Height         int64             `json:"height"`
+	Sender         string            `json:"sender"`
+	NewOrders      []types.OrderItem `json:"new_orders,omitempty"`
+	Atomic         bool              `json:"atomic,omitempty"` // the new orders are placed all or none
+	CancelOrderIDs []string          `json:"cancel_order_ids,omitempty"`
+}
+
+// MsgResult is the result of the msg of an event
+type MsgResult struct {
+	Sender string `json:"sender"`
+	Code   uint32 `json:"code"`
+	Log    string `json:"log"`
+}
+
+// BlockOutput is the outcome of a block
+type BlockOutput struct {
+	Height       int64                        `json:"height"`
+	MsgResults   []MsgResult                  `json:"msg_results"`
+	MatchResults map[string]types.MatchResult `json:"match_results"`
+	FeeDetails   []*token.FeeDetail           `json:"fee_details"`
+}
+
+// AccountOutput is the final balance of an account
+type AccountOutput struct {
+	Name    string       `json:"name"`
+	Address string       `json:"address"`
+	Coins   sdk.DecCoins `json:"coins"`
+	Locked  sdk.DecCoins `json:"locked"`
+}
+
+// Simulator runs orders through the order handler, the match engines and the keepers against an in-memory store
+type Simulator struct {
+	ctx          sdk.Context
+	orderKeeper  keeper.Keeper
+	tokenKeeper  token.Keeper
+	orderHandler sdk.Handler
+	accounts     []Account
+	addrs        map[string]sdk.AccAddress
+}
+
+// AccAddress returns the deterministic address of the named account
+func AccAddress(name string) sdk.AccAddress {
+	return sdk.AccAddress(crypto.AddressHash([]byte(name)))
+}
+
+// NewSimulator mounts the stores in memory and sets up the token pairs, the accounts and the params of the input
+func NewSimulator(cdc *codec.Codec, input *Input) (*Simulator, error) {
+	db := dbm.NewMemDB()
+	keyAcc := sdk.NewKVStoreKey(auth.StoreKey)
+	keySupply := sdk.NewKVStoreKey(supply.StoreKey)
+	keyParams := sdk.NewKVStoreKey(params.StoreKey)
+	tkeyParams := sdk.NewTransientStoreKey(params.TStoreKey)
+	keyOrder := sdk.NewKVStoreKey(types.OrderStoreKey)
+	keyToken := sdk.NewKVStoreKey(token.StoreKey)
+	keyLock := sdk.NewKVStoreKey(token.KeyLock)
+	keyDex := sdk.NewKVStoreKey(dex.StoreKey)
+	keyTokenPair := sdk.NewKVStoreKey(dex.TokenPairStoreKey)
+
+	ms := store.NewCommitMultiStore(db)
+	for _, key := range []sdk.StoreKey{keyAcc, keySupply, keyParams, keyOrder, keyToken, keyLock, keyDex, keyTokenPair} {
+		ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+	}
+	ms.MountStoreWithDB(tkeyParams, sdk.StoreTypeTransient, db)
+	if err := ms.LoadLatestVersion(); err != nil {
+		return nil, err
+	}
+	ctx := sdk.NewContext(ms, abci.Header{Time: time.Unix(0, 0)}, false, log.NewNopLogger())
+
+	feeCollectorAcc := supply.NewEmptyModuleAccount(auth.FeeCollectorName)
+	blacklistedAddrs := map[string]bool{feeCollectorAcc.String(): true}
+	paramsKeeper := params.NewKeeper(cdc, keyParams, tkeyParams, params.DefaultCodespace)
+	accountKeeper := auth.NewAccountKeeper(cdc, keyAcc, paramsKeeper.Subspace(auth.DefaultParamspace),
+		auth.ProtoBaseAccount)
+	bankKeeper := bank.NewBaseKeeper(accountKeeper, paramsKeeper.Subspace(bank.DefaultParamspace),
+		bank.DefaultCodespace, blacklistedAddrs)
+	maccPerms := map[string][]string{
+		auth.FeeCollectorName: nil,
+		token.ModuleName:      {supply.Minter, supply.Burner},
+	}
+	supplyKeeper := supply.NewKeeper(cdc, keySupply, accountKeeper, bankKeeper, maccPerms)
+	supplyKeeper.SetSupply(ctx, supply.NewSupply(sdk.Coins{}))
+	supplyKeeper.SetModuleAccount(ctx, feeCollectorAcc)
+
+	tokenKeeper := token.NewKeeper(bankKeeper, paramsKeeper, paramsKeeper.Subspace(token.DefaultParamspace),
+		auth.FeeCollectorName, supplyKeeper, keyToken, keyLock, cdc, true)
+	dexKeeper := dex.NewKeeper(auth.FeeCollectorName, supplyKeeper, paramsKeeper.Subspace(dex.DefaultParamspace),
+		tokenKeeper, nil, bankKeeper, keyDex, keyTokenPair, cdc)
+	orderKeeper := keeper.NewKeeper(tokenKeeper, supplyKeeper, paramsKeeper, dexKeeper,
+		paramsKeeper.Subspace(types.DefaultParamspace), auth.FeeCollectorName, keyOrder, cdc, true,
+		monitor.NopOrderMetrics())
+
+	orderParams := types.DefaultParams()
+	if input.Params != nil {
+		orderParams = *input.Params
+	}
+	orderKeeper.SetParams(ctx, &orderParams)
+	for _, tokenPair := range input.TokenPairs {
+		if err := dexKeeper.SaveTokenPair(ctx, tokenPair); err != nil {
+			return nil, fmt.Errorf("failed to save token pair %s: %v", tokenPair.Name(), err)
+		}
+	}
+
+	addrs := make(map[string]sdk.AccAddress, len(input.Accounts))
+	for _, account := range input.Accounts {
+		if _, ok := addrs[account.Name]; ok {
+			return nil, fmt.Errorf("duplicate account %s", account.Name)
+		}
+		addr := AccAddress(account.Name)
+		addrs[account.Name] = addr
+		if err := supplyKeeper.MintCoins(ctx, token.ModuleName, account.Coins); err != nil {
+			return nil, err
+		}
+		if err := supplyKeeper.SendCoinsFromModuleToAccount(ctx, token.ModuleName, addr, account.Coins); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Simulator{
+		ctx:          ctx,
+		orderKeeper:  orderKeeper,
+		tokenKeeper:  tokenKeeper,
+		orderHandler: order.NewOrderHandler(orderKeeper),
+		accounts:     input.Accounts,
+		addrs:        addrs,
+	}, nil
+}
+
+// ReadEvents reads the events from the lines of r, which must be in the order of their heights after block 1
+func ReadEvents(cdc *codec.Codec, r io.Reader) ([]Event, error) {
+	var events []Event
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var event Event
+		if err := cdc.UnmarshalJSON(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("invalid event at line %d: %v", line, err)
+		}
+		if event.Height <= 1 || (len(events) > 0 && event.Height < events[len(events)-1].Height) {
+			return nil, fmt.Errorf("invalid height %d at line %d, heights must increase from 2", event.Height, line)
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+// Run runs the initial depth book in block 1 and the events in their blocks, and writes the output of every block
+// and the final balances to w, a JSON per line
+func (s *Simulator) Run(depthBook, events []Event, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	lastHeight := int64(1)
+	if len(events) > 0 {
+		lastHeight = events[len(events)-1].Height
+	}
+	for height := int64(1); height <= lastHeight; height++ {
+		blockEvents := depthBook
+		if height > 1 {
+			i := sort.Search(len(events), func(i int) bool { return events[i].Height >= height })
+			j := sort.Search(len(events), func(i int) bool { return events[i].Height > height })
+			blockEvents = events[i:j]
+		}
+		output, err := s.runBlock(height, blockEvents)
+		if err != nil {
+			return err
+		}
+		if err := encoder.Encode(output); err != nil {
+			return err
+		}
+	}
+
+	for _, account := range s.accounts {
+		addr := s.addrs[account.Name]
+		output := AccountOutput{
+			Name:    account.Name,
+			Address: addr.String(),
+			Coins:   s.tokenKeeper.GetCoins(s.ctx, addr),
+			Locked:  s.tokenKeeper.GetLockCoins(s.ctx, addr),
+		}
+		if err := encoder.Encode(output); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Simulator) runBlock(height int64, events []Event) (*BlockOutput, error) {
+	s.ctx = s.ctx.WithBlockHeight(height).WithBlockTime(time.Unix(height, 0)).WithEventManager(sdk.NewEventManager())
+	token.BeginBlocker(s.ctx, s.tokenKeeper)
+	order.BeginBlocker(s.ctx, s.orderKeeper)
+
+	output := &BlockOutput{Height: height, MsgResults: []MsgResult{}}
+	for _, event := range events {
+		sender, ok := s.addrs[event.Sender]
+		if !ok {
+			return nil, fmt.Errorf("unknown sender %s at height %d", event.Sender, height)
+		}
+		var msgs []sdk.Msg
+		if len(event.NewOrders) > 0 {
+			msg := types.NewMsgNewOrders(sender, event.NewOrders)
+			msg.Atomic = event.Atomic
+			msgs = append(msgs, msg)
+		}
+		if len(event.CancelOrderIDs) > 0 {
+			msgs = append(msgs, types.NewMsgCancelOrders(sender, event.CancelOrderIDs))
+		}
+		for _, msg := range msgs {
+			output.MsgResults = append(output.MsgResults, s.deliverMsg(event.Sender, msg))
+		}
+	}
+
+	order.EndBlocker(s.ctx, s.orderKeeper)
+	if result := s.orderKeeper.GetBlockMatchResult(); result != nil {
+		output.MatchResults = result.ResultMap
+	}
+	output.FeeDetails = s.tokenKeeper.GetFeeDetailList()
+	return output, nil
+}
+
+// deliverMsg handles the msg in a cached store as a transaction, whose changes are discarded if it fails
+func (s *Simulator) deliverMsg(sender string, msg sdk.Msg) MsgResult {
+	var result sdk.Result
+	if err := msg.ValidateBasic(); err != nil {
+		result = err.Result()
+	} else {
+		msCache := s.ctx.MultiStore().CacheMultiStore()
+		result = s.orderHandler(s.ctx.WithMultiStore(msCache), msg)
+		if result.IsOK() {
+			msCache.Write()
+		}
+	}
+	return MsgResult{Sender: sender, Code: uint32(result.Code), Log: result.Log}
+}
@@ -0,0 +1,89 @@
+package simulator
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/common"
+	"github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+)
+
+const testInput = `{
+  "token_pairs": [{"base_asset_symbol": "xxb", "quote_asset_symbol": "okt", "price": "10.0",
+    "max_price_digit": "8", "max_size_digit": "8", "min_trade_size": "0.001"}],
+  "accounts": [
+    {"name": "alice", "coins": [{"denom": "okt", "amount": "100.0"}, {"denom": "xxb", "amount": "100.0"}]},
+    {"name": "bob", "coins": [{"denom": "okt", "amount": "100.0"}, {"denom": "xxb", "amount": "100.0"}]}
+  ],
+  "depth_book": [{"sender": "alice", "new_orders": [
+    {"product": "xxb_okt", "side": "SELL", "price": "10.5", "quantity": "1.0"},
+    {"product": "xxb_okt", "side": "SELL", "price": "11.0", "quantity": "1.0"}
+  ]}]
+}`
+
+const testEvents = `{"height": "2", "sender": "bob", "new_orders": [{"product": "xxb_okt", "side": "BUY", "price": "10.5", "quantity": "0.5"}]}
+
+{"height": "4", "sender": "alice", "cancel_order_ids": ["ID0000000001-2"]}
+{"height": "4", "sender": "bob", "cancel_order_ids": ["ID0000000001-2"]}
+`
+
+func runSimulation(t *testing.T) []string {
+	cdc := keeper.MakeTestCodec()
+	var input Input
+	require.Nil(t, cdc.UnmarshalJSON([]byte(testInput), &input))
+	events, err := ReadEvents(cdc, strings.NewReader(testEvents))
+	require.Nil(t, err)
+	require.EqualValues(t, 3, len(events))
+
+	simulator, err := NewSimulator(cdc, &input)
+	require.Nil(t, err)
+	var out bytes.Buffer
+	require.Nil(t, simulator.Run(input.DepthBook, events, &out))
+	return strings.Split(strings.TrimSpace(out.String()), "\n")
+}
+
+func TestSimulator(t *testing.T) {
+	lines := runSimulation(t)
+	// 4 blocks and 2 accounts
+	require.EqualValues(t, 6, len(lines))
+	require.EqualValues(t, lines, runSimulation(t))
+
+	var block BlockOutput
+	require.Nil(t, json.Unmarshal([]byte(lines[1]), &block))
+	require.EqualValues(t, 2, block.Height)
+	require.EqualValues(t, 1, len(block.MsgResults))
+	require.EqualValues(t, sdk.CodeOK, block.MsgResults[0].Code)
+	matchResult := block.MatchResults[types.TestTokenPair]
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.5"), matchResult.Price)
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.5"), matchResult.Quantity)
+	require.EqualValues(t, 2, len(matchResult.Deals))
+	require.NotEmpty(t, block.FeeDetails)
+
+	// only the sender cancels its order
+	require.Nil(t, json.Unmarshal([]byte(lines[3]), &block))
+	require.EqualValues(t, 2, len(block.MsgResults))
+	require.EqualValues(t, sdk.CodeOK, block.MsgResults[0].Code)
+	require.NotEqual(t, sdk.CodeOK, block.MsgResults[1].Code)
+
+	var alice AccountOutput
+	require.Nil(t, json.Unmarshal([]byte(lines[4]), &alice))
+	require.EqualValues(t, "alice", alice.Name)
+	require.EqualValues(t, AccAddress("alice").String(), alice.Address)
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.5"), alice.Locked.AmountOf(common.TestToken))
+}
+
+func TestReadEvents(t *testing.T) {
+	cdc := keeper.MakeTestCodec()
+	_, err := ReadEvents(cdc, strings.NewReader(`{"height": "1", "sender": "bob"}`))
+	require.NotNil(t, err)
+	_, err = ReadEvents(cdc, strings.NewReader("{\"height\": \"3\", \"sender\": \"bob\"}\n{\"height\": \"2\", \"sender\": \"bob\"}"))
+	require.NotNil(t, err)
+	_, err = ReadEvents(cdc, strings.NewReader(`{"height": "2"`))
+	require.NotNil(t, err)
+}
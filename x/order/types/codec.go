@@ -8,6 +8,8 @@ import (
 func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgNewOrders{}, "okchain/order/MsgNew", nil)
 	cdc.RegisterConcrete(MsgCancelOrders{}, "okchain/order/MsgCancel", nil)
+	cdc.RegisterConcrete(MsgAmendOrder{}, "okchain/order/MsgAmend", nil)
+	cdc.RegisterConcrete(MsgCancelAllOrders{}, "okchain/order/MsgCancelAll", nil)
 }
 
 // ModuleCdc generic sealed codec to be used throughout this module
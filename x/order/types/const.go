@@ -6,12 +6,90 @@ import (
 )
 
 const (
-	FeeTypeOrderNew     = "new"
-	FeeTypeOrderCancel  = "cancel"
-	FeeTypeOrderExpire  = "expire"
-	FeeTypeOrderDeal    = "deal"
-	FeeTypeOrderReceive = "receive"
-	TestTokenPair       = common.TestToken + "_" + sdk.DefaultBondDenom
-	BuyOrder            = "BUY"
-	SellOrder           = "SELL"
+	FeeTypeOrderNew        = "new"
+	FeeTypeOrderCancel     = "cancel"
+	FeeTypeOrderExpire     = "expire"
+	FeeTypeOrderDeal       = "deal"
+	FeeTypeOrderNativeDeal = "nativeDeal" // deal fees paid in the native token at a discount
+	FeeTypeOrderReceive    = "receive"
+	// orders closed by their time in force or self-trade prevention
+	FeeTypeOrderIOCCancel      = "iocCancel"
+	FeeTypeOrderFOKKill        = "fokKill"
+	FeeTypeOrderPostOnlyReject = "postOnlyReject"
+	FeeTypeOrderSelfTrade      = "selfTrade"
+	TestTokenPair              = common.TestToken + "_" + sdk.DefaultBondDenom
+	BuyOrder                   = "BUY"
+	SellOrder                  = "SELL"
 )
+
+// time in force of orders
+const (
+	TimeInForceGTC      = "GTC"       // good till cancelled or expired
+	TimeInForceIOC      = "IOC"       // immediate or cancel: the remaining quantity is cancelled after matching
+	TimeInForceFOK      = "FOK"       // fill or kill: filled entirely in its first matching, or killed
+	TimeInForcePostOnly = "POST_ONLY" // maker only: rejected if it would be matched when it enters the book
+)
+
+// types of trigger orders, which are dormant until the last price of the product crosses the trigger price
+const (
+	TriggerTypeStopLimit  = "STOP_LIMIT"  // buy when the last price rises to the trigger price, sell when it falls to
+	TriggerTypeTakeProfit = "TAKE_PROFIT" // buy when the last price falls to the trigger price, sell when it rises to
+)
+
+// types of orders
+const (
+	OrderTypeLimit  = "LIMIT"  // rests in the depth book at its price until it's filled, cancelled or expired
+	OrderTypeMarket = "MARKET" // takes the resting orders up to its worst acceptable price, ahead of limit orders
+)
+
+// self-trade prevention modes, which decide what happens when a taker would trade with a maker of the same sender
+const (
+	SelfTradePreventionNone               = "NONE"                 // the orders trade with each other
+	SelfTradePreventionCancelNewest       = "CANCEL_NEWEST"        // the taker is cancelled
+	SelfTradePreventionCancelOldest       = "CANCEL_OLDEST"        // the maker is cancelled, the taker goes on
+	SelfTradePreventionCancelBoth         = "CANCEL_BOTH"          // both orders are cancelled
+	SelfTradePreventionDecrementAndCancel = "DECREMENT_AND_CANCEL" // the smaller one is cancelled, the other one decremented
+)
+
+// MaxClientOrderIDLength is the max length of a client order id, which fits a UUID
+const MaxClientOrderIDLength = 36
+
+// IsValidTimeInForce checks whether the time in force is supported, empty means GTC
+func IsValidTimeInForce(timeInForce string) bool {
+	switch timeInForce {
+	case "", TimeInForceGTC, TimeInForceIOC, TimeInForceFOK, TimeInForcePostOnly:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValidOrderType checks whether the order type is supported, empty means LIMIT
+func IsValidOrderType(orderType string) bool {
+	return orderType == "" || orderType == OrderTypeLimit || orderType == OrderTypeMarket
+}
+
+// IsValidSelfTradePrevention checks whether the self-trade prevention mode is supported, empty means the default
+// mode in params
+func IsValidSelfTradePrevention(mode string) bool {
+	switch mode {
+	case "", SelfTradePreventionNone, SelfTradePreventionCancelNewest, SelfTradePreventionCancelOldest,
+		SelfTradePreventionCancelBoth, SelfTradePreventionDecrementAndCancel:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValidClientOrderID checks whether a client order id only has letters, digits, '-' and '_' within the max length
+func IsValidClientOrderID(clientOrderID string) bool {
+	if len(clientOrderID) == 0 || len(clientOrderID) > MaxClientOrderIDLength {
+		return false
+	}
+	for _, c := range clientOrderID {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
@@ -9,6 +9,7 @@ import (
 type Deal struct {
 	OrderID  string  `json:"order_id"`
 	Side     string  `json:"side"`
+	Price    sdk.Dec `json:"price"` // the continuous auction fills at the maker prices, not at the one of MatchResult
 	Quantity sdk.Dec `json:"quantity"`
 	Fee      string  `json:"fee"`
 }
@@ -36,6 +37,16 @@ type DepthBook struct {
 	Items []DepthBookItem
 }
 
+// DepthBookDiff is the price levels of the depth book of a product changed in a block, and a level with
+// neither buy quantity nor sell quantity is removed from the book. The sequence increases by one with every diff,
+// so that the book is rebuilt by applying the following diffs in sequence to a snapshot of the same sequence.
+type DepthBookDiff struct {
+	Product     string          `json:"product"`
+	Sequence    int64           `json:"sequence"`
+	BlockHeight int64           `json:"block_height"`
+	Items       []DepthBookItem `json:"items"`
+}
+
 // Items in depth book are sorted by price desc
 // insert a new order into depth book
 func (depthBook *DepthBook) InsertOrder(order *Order) {
@@ -46,9 +57,9 @@ func (depthBook *DepthBook) InsertOrder(order *Order) {
 		SellQuantity: sdk.ZeroDec(),
 	}
 	if order.Side == BuyOrder {
-		newItem.BuyQuantity = order.RemainQuantity
+		newItem.BuyQuantity = order.VisibleQuantity()
 	} else {
-		newItem.SellQuantity = order.RemainQuantity
+		newItem.SellQuantity = order.VisibleQuantity()
 	}
 	if bookLength == 0 || order.Price.LT(depthBook.Items[bookLength-1].Price) {
 		depthBook.Items = append(depthBook.Items, newItem)
@@ -63,10 +74,10 @@ func (depthBook *DepthBook) InsertOrder(order *Order) {
 	if order.Price.Equal(depthBook.Items[index].Price) {
 		if order.Side == BuyOrder {
 			depthBook.Items[index].BuyQuantity =
-				depthBook.Items[index].BuyQuantity.Add(order.RemainQuantity)
+				depthBook.Items[index].BuyQuantity.Add(order.VisibleQuantity())
 		} else {
 			depthBook.Items[index].SellQuantity =
-				depthBook.Items[index].SellQuantity.Add(order.RemainQuantity)
+				depthBook.Items[index].SellQuantity.Add(order.VisibleQuantity())
 		}
 	} else { // order.InitPrice > depthBook[index].InitPrice
 		rear := append([]DepthBookItem{newItem}, depthBook.Items[index:]...)
@@ -86,10 +97,10 @@ func (depthBook *DepthBook) RemoveOrder(order *Order) {
 	if index < bookLen && depthBook.Items[index].Price.Equal(order.Price) {
 		if order.Side == BuyOrder {
 			depthBook.Items[index].BuyQuantity =
-				depthBook.Items[index].BuyQuantity.Sub(order.RemainQuantity)
+				depthBook.Items[index].BuyQuantity.Sub(order.VisibleQuantity())
 		} else if order.Side == SellOrder {
 			depthBook.Items[index].SellQuantity =
-				depthBook.Items[index].SellQuantity.Sub(order.RemainQuantity)
+				depthBook.Items[index].SellQuantity.Sub(order.VisibleQuantity())
 		}
 
 		depthBook.RemoveIfEmpty(index)
@@ -104,6 +115,15 @@ func (depthBook *DepthBook) Sub(index int, num sdk.Dec, side string) {
 	}
 }
 
+// Add shows more quantity of the side at the price of index, e.g. the next display slice of an iceberg order
+func (depthBook *DepthBook) Add(index int, num sdk.Dec, side string) {
+	if side == BuyOrder {
+		depthBook.Items[index].BuyQuantity = depthBook.Items[index].BuyQuantity.Add(num)
+	} else if side == SellOrder {
+		depthBook.Items[index].SellQuantity = depthBook.Items[index].SellQuantity.Add(num)
+	}
+}
+
 func (depthBook *DepthBook) RemoveIfEmpty(index int) bool {
 	res := depthBook.Items[index].BuyQuantity.IsZero() && depthBook.Items[index].SellQuantity.IsZero()
 	if res {
@@ -112,6 +132,46 @@ func (depthBook *DepthBook) RemoveIfEmpty(index int) bool {
 	return res
 }
 
+// remove all the items which have neither buy quantity nor sell quantity
+func (depthBook *DepthBook) RemoveEmptyItems() {
+	items := make([]DepthBookItem, 0, len(depthBook.Items))
+	for _, item := range depthBook.Items {
+		if item.BuyQuantity.IsPositive() || item.SellQuantity.IsPositive() {
+			items = append(items, item)
+		}
+	}
+	depthBook.Items = items
+}
+
+// Diff returns the items of newBook different from those of depthBook at the same price, sorted by price desc.
+// The items removed in newBook are returned with zero quantities.
+func (depthBook *DepthBook) Diff(newBook *DepthBook) []DepthBookItem {
+	var diff []DepthBookItem
+	i, j := 0, 0
+	for i < len(depthBook.Items) || j < len(newBook.Items) {
+		switch {
+		case j == len(newBook.Items) || (i < len(depthBook.Items) && depthBook.Items[i].Price.GT(newBook.Items[j].Price)):
+			diff = append(diff, DepthBookItem{
+				Price:        depthBook.Items[i].Price,
+				BuyQuantity:  sdk.ZeroDec(),
+				SellQuantity: sdk.ZeroDec(),
+			})
+			i++
+		case i == len(depthBook.Items) || newBook.Items[j].Price.GT(depthBook.Items[i].Price):
+			diff = append(diff, newBook.Items[j])
+			j++
+		default:
+			if !depthBook.Items[i].BuyQuantity.Equal(newBook.Items[j].BuyQuantity) ||
+				!depthBook.Items[i].SellQuantity.Equal(newBook.Items[j].SellQuantity) {
+				diff = append(diff, newBook.Items[j])
+			}
+			i++
+			j++
+		}
+	}
+	return diff
+}
+
 func (depthBook *DepthBook) Copy() *DepthBook {
 	itemList := make([]DepthBookItem, 0, len(depthBook.Items))
 	itemList = append(itemList, depthBook.Items...)
@@ -96,3 +96,26 @@ func TestCopy(t *testing.T) {
 	require.EqualValues(t, 1, len(depthBook.Items))
 	require.EqualValues(t, sdk.MustNewDecFromStr("0.5"), depthBook.Items[0].Price)
 }
+
+func TestDepthBookDiff(t *testing.T) {
+	depthBook := &DepthBook{}
+	depthBook.InsertOrder(MockOrder("", TestTokenPair, SellOrder, "0.6", "2.0"))
+	depthBook.InsertOrder(MockOrder("", TestTokenPair, SellOrder, "0.5", "1.0"))
+	depthBook.InsertOrder(MockOrder("", TestTokenPair, BuyOrder, "0.4", "1.5"))
+	require.Nil(t, depthBook.Diff(depthBook.Copy()))
+
+	newBook := depthBook.Copy()
+	newBook.InsertOrder(MockOrder("", TestTokenPair, SellOrder, "0.7", "1.0"))
+	newBook.InsertOrder(MockOrder("", TestTokenPair, SellOrder, "0.5", "1.0"))
+	newBook.RemoveOrder(MockOrder("", TestTokenPair, BuyOrder, "0.4", "1.5"))
+	newBook.InsertOrder(MockOrder("", TestTokenPair, BuyOrder, "0.3", "1.0"))
+
+	expectedDiff := []DepthBookItem{
+		{sdk.MustNewDecFromStr("0.7"), sdk.ZeroDec(), sdk.MustNewDecFromStr("1.0")},
+		{sdk.MustNewDecFromStr("0.5"), sdk.ZeroDec(), sdk.MustNewDecFromStr("2.0")},
+		{sdk.MustNewDecFromStr("0.4"), sdk.ZeroDec(), sdk.ZeroDec()},
+		{sdk.MustNewDecFromStr("0.3"), sdk.MustNewDecFromStr("1.0"), sdk.ZeroDec()},
+	}
+	require.EqualValues(t, expectedDiff, depthBook.Diff(newBook))
+	require.EqualValues(t, 4, len(newBook.Diff(depthBook)))
+}
@@ -0,0 +1,47 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// const CodeType
+const (
+	CodeOpenOrderLimitExceeded sdk.CodeType = 1
+	CodeNewOrderLimitExceeded  sdk.CodeType = 2
+	CodeAtomicOrdersRolledBack sdk.CodeType = 3
+)
+
+// CodeToDefaultMsg returns the default message of the code
+func CodeToDefaultMsg(code sdk.CodeType) string {
+	switch code {
+	case CodeOpenOrderLimitExceeded:
+		return "open order limit exceeded"
+	case CodeNewOrderLimitExceeded:
+		return "new order limit exceeded"
+	case CodeAtomicOrdersRolledBack:
+		return "atomic orders rolled back"
+	default:
+		return fmt.Sprintf("unknown code %d", code)
+	}
+}
+
+// ErrOpenOrderLimitExceeded is returned when the sender has reached the limit of open orders of the product
+func ErrOpenOrderLimitExceeded(sender sdk.AccAddress, product string, limit int64) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeOpenOrderLimitExceeded,
+		CodeToDefaultMsg(CodeOpenOrderLimitExceeded)+": %s has %d open orders of %s", sender, limit, product)
+}
+
+// ErrNewOrderLimitExceeded is returned when the sender has placed the max number of new orders in this block
+func ErrNewOrderLimitExceeded(sender sdk.AccAddress, limit int64) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeNewOrderLimitExceeded,
+		CodeToDefaultMsg(CodeNewOrderLimitExceeded)+": %s has placed %d orders in this block", sender, limit)
+}
+
+// ErrAtomicOrdersRolledBack is returned for the orders of an atomic msg which are not placed for the failure of
+// the order at index
+func ErrAtomicOrdersRolledBack(index int) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeAtomicOrdersRolledBack,
+		CodeToDefaultMsg(CodeAtomicOrdersRolledBack)+": order item %d failed", index)
+}
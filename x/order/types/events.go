@@ -0,0 +1,23 @@
+package types
+
+// order module event types
+const (
+	EventTypeNewOrder         = "new_order"
+	EventTypePartialFillOrder = "partial_fill_order"
+	EventTypeFullFillOrder    = "full_fill_order"
+	EventTypeCancelOrder      = "cancel_order"
+	EventTypeExpireOrder      = "expire_order"
+	EventTypeHaltProduct      = "halt_product"
+	EventTypeResumeProduct    = "resume_product"
+
+	AttributeKeyOrderID        = "order_id"
+	AttributeKeySender         = "sender"
+	AttributeKeyFee            = "fee"
+	AttributeKeyProduct        = "product"
+	AttributeKeySide           = "side"
+	AttributeKeyPrice          = "price"
+	AttributeKeyQuantity       = "quantity"
+	AttributeKeyStatus         = "status"
+	AttributeKeyReferencePrice = "reference_price"
+	AttributeKeyHaltEndHeight  = "halt_end_height"
+)
@@ -0,0 +1,54 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// OrderHooks event hooks for the lifecycle of orders (noalias)
+type OrderHooks interface {
+	// Must be called when an order is placed
+	AfterOrderPlaced(ctx sdk.Context, order *Order)
+	// Must be called when an order is filled at price, in part or in full
+	AfterOrderFilled(ctx sdk.Context, order *Order, price sdk.Dec, deal Deal)
+	// Must be called when an order is cancelled by its sender, or closed by the matching rules of its time in force,
+	// self-trade prevention or post-only
+	AfterOrderCancelled(ctx sdk.Context, order *Order)
+	// Must be called when an order is expired
+	AfterOrderExpired(ctx sdk.Context, order *Order)
+}
+
+// MultiOrderHooks combines multiple order hooks, all hook functions are run in array sequence
+type MultiOrderHooks []OrderHooks
+
+// NewMultiOrderHooks creates a new object of MultiOrderHooks
+func NewMultiOrderHooks(hooks ...OrderHooks) MultiOrderHooks {
+	return hooks
+}
+
+// AfterOrderPlaced handles the hooks after the order was placed
+func (h MultiOrderHooks) AfterOrderPlaced(ctx sdk.Context, order *Order) {
+	for i := range h {
+		h[i].AfterOrderPlaced(ctx, order)
+	}
+}
+
+// AfterOrderFilled handles the hooks after the order was filled
+func (h MultiOrderHooks) AfterOrderFilled(ctx sdk.Context, order *Order, price sdk.Dec, deal Deal) {
+	for i := range h {
+		h[i].AfterOrderFilled(ctx, order, price, deal)
+	}
+}
+
+// AfterOrderCancelled handles the hooks after the order was cancelled
+func (h MultiOrderHooks) AfterOrderCancelled(ctx sdk.Context, order *Order) {
+	for i := range h {
+		h[i].AfterOrderCancelled(ctx, order)
+	}
+}
+
+// AfterOrderExpired handles the hooks after the order was expired
+func (h MultiOrderHooks) AfterOrderExpired(ctx sdk.Context, order *Order) {
+	for i := range h {
+		h[i].AfterOrderExpired(ctx, order)
+	}
+}
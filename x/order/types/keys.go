@@ -1,6 +1,7 @@
 package types
 
 import (
+	"encoding/binary"
 	"fmt"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -24,6 +25,9 @@ const (
 	QueryParameters  = "params"
 	QueryStore       = "store"
 	QueryDepthBookV2 = "depthbookV2"
+	QueryClientOrder = "clientorder"
+	QueryOpenOrders  = "openorders"
+	QueryDepthDiff   = "depthdiff"
 
 	OrderStoreKey = ModuleName
 )
@@ -38,12 +42,26 @@ var (
 	PriceKey             = []byte{0x14}
 	ExpireBlockHeightKey = []byte{0x15}
 	OrderNumPerBlockKey  = []byte{0x16}
+	TriggerOrderIDsKey   = []byte{0x21}
+	SenderOrderIDKey     = []byte{0x23}
+	ClientOrderIDKey     = []byte{0x24}
+	TradedVolumeKey      = []byte{0x26}
+	BlockTradedVolumeKey = []byte{0x27}
+	PriceHistoryKey      = []byte{0x28}
+	DepthBookDiffKey     = []byte{0x29}
+	ClosedOrderKey       = []byte{0x2A}
 
 	// none iterator keys
 	RecentlyClosedOrderIDsKey = []byte{0x17}
 	LastExpiredBlockHeightKey = []byte{0x18}
 	OpenOrderNumKey           = []byte{0x19}
 	StoreOrderNumKey          = []byte{0x20}
+	TriggeredOrderIDsKey      = []byte{0x22}
+	RematchProductsKey        = []byte{0x25}
+	LastPrunedBlockHeightKey  = []byte{0x2B}
+	PendingTakerOrderIDsKey   = []byte{0x2C}
+	DeferredExpireOrderIDsKey = []byte{0x2D}
+	PendingTriggerProductsKey = []byte{0x2E}
 )
 
 func GetOrderKey(key string) []byte {
@@ -70,10 +88,91 @@ func GetExpireBlockHeightKey(blockHeight int64) []byte {
 	return append(ExpireBlockHeightKey, sdk.Uint64ToBigEndian(uint64(blockHeight))...)
 }
 
+// GetTriggerOrdersPrefix returns the prefix of the keys of the trigger orders of a product waiting for a rising
+// or a falling price, which are ordered by their trigger prices
+func GetTriggerOrdersPrefix(product string, rising bool) []byte {
+	direction := byte('0')
+	if rising {
+		direction = '1'
+	}
+	return append(append(TriggerOrderIDsKey, []byte(product+":")...), direction)
+}
+
+// GetTriggerPriceKey returns the prefix of the keys of the trigger orders with the same trigger price
+func GetTriggerPriceKey(product string, rising bool, triggerPrice sdk.Dec) []byte {
+	// the length of the big-endian bytes goes first to keep the positive prices in order
+	priceBytes := triggerPrice.Int.Bytes()
+	return append(append(GetTriggerOrdersPrefix(product, rising), byte(len(priceBytes))), priceBytes...)
+}
+
+// GetTriggerOrderKey returns the key of a trigger order in the trigger index
+func GetTriggerOrderKey(product string, rising bool, triggerPrice sdk.Dec, orderID string) []byte {
+	return append(GetTriggerPriceKey(product, rising, triggerPrice), []byte(orderID)...)
+}
+
+// GetSenderOrderIDsPrefix returns the prefix of the keys of the open orders of a sender, the open orders of
+// a product are further prefixed by the product if it's set
+func GetSenderOrderIDsPrefix(sender sdk.AccAddress, product string) []byte {
+	prefix := append(SenderOrderIDKey, sender.Bytes()...)
+	if product == "" {
+		return prefix
+	}
+	return append(prefix, []byte(product+":")...)
+}
+
+func GetSenderOrderIDKey(sender sdk.AccAddress, product, orderID string) []byte {
+	return append(GetSenderOrderIDsPrefix(sender, product), []byte(orderID)...)
+}
+
+func GetClientOrderIDKey(sender sdk.AccAddress, clientOrderID string) []byte {
+	return append(append(ClientOrderIDKey, sender.Bytes()...), []byte(clientOrderID)...)
+}
+
+func GetTradedVolumeKey(addr sdk.AccAddress) []byte {
+	return append(TradedVolumeKey, addr.Bytes()...)
+}
+
+// GetBlockTradedVolumesPrefix returns the prefix of the keys of the volumes traded by accounts at blockHeight
+func GetBlockTradedVolumesPrefix(blockHeight int64) []byte {
+	return append(BlockTradedVolumeKey, sdk.Uint64ToBigEndian(uint64(blockHeight))...)
+}
+
+func GetBlockTradedVolumeKey(blockHeight int64, addr sdk.AccAddress) []byte {
+	return append(GetBlockTradedVolumesPrefix(blockHeight), addr.Bytes()...)
+}
+
+// GetPriceHistoryPrefix returns the prefix of the keys of the last prices of a product at the end of blocks
+func GetPriceHistoryPrefix(product string) []byte {
+	return append(PriceHistoryKey, []byte(product+":")...)
+}
+
+func GetPriceHistoryKey(product string, blockHeight int64) []byte {
+	return append(GetPriceHistoryPrefix(product), sdk.Uint64ToBigEndian(uint64(blockHeight))...)
+}
+
 func FormatOrderIDsKey(product string, price sdk.Dec, side string) string {
 	return fmt.Sprintf("%v:%v:%v", product, price.String(), side)
 }
 
+func GetDepthBookDiffKey(product string) []byte {
+	return append(DepthBookDiffKey, []byte(product)...)
+}
+
+// GetClosedOrdersPrefix returns the prefix of the keys of the orders closed at blockHeight
+func GetClosedOrdersPrefix(blockHeight int64) []byte {
+	return append(ClosedOrderKey, sdk.Uint64ToBigEndian(uint64(blockHeight))...)
+}
+
+func GetClosedOrderKey(blockHeight int64, orderID string) []byte {
+	return append(GetClosedOrdersPrefix(blockHeight), []byte(orderID)...)
+}
+
+// SplitClosedOrderKey returns the height an order is closed at and its id from its key
+func SplitClosedOrderKey(key []byte) (blockHeight int64, orderID string) {
+	prefixLen := len(ClosedOrderKey)
+	return int64(binary.BigEndian.Uint64(key[prefixLen : prefixLen+8])), string(key[prefixLen+8:])
+}
+
 func GetKey(it sdk.Iterator) string {
 	return string(it.Key()[1:])
 }
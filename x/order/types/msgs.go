@@ -20,6 +20,26 @@ type MsgNewOrder struct {
 	Side     string         `json:"side"`     // BUY/SELL
 	Price    sdk.Dec        `json:"price"`    // price of the order
 	Quantity sdk.Dec        `json:"quantity"` // quantity of the order
+
+	TimeInForce  string  `json:"time_in_force,omitempty"` // GTC/IOC/FOK/POST_ONLY, empty means GTC
+	TriggerType  string  `json:"trigger_type,omitempty"`  // STOP_LIMIT/TAKE_PROFIT, empty means a normal order
+	TriggerPrice sdk.Dec `json:"trigger_price,omitempty"` // trigger price of a trigger order
+
+	ClientOrderID string `json:"client_order_id,omitempty"` // optional id given by the sender
+
+	// a market order takes Price as its worst acceptable price, or derives it from MaxSlippage,
+	// and a market buy order spends at most Quantity of the quote token
+	OrderType   string  `json:"order_type,omitempty"`   // LIMIT/MARKET, empty means LIMIT
+	MaxSlippage sdk.Dec `json:"max_slippage,omitempty"` // max slippage from the best bid/ask, e.g. 0.05 for 5%
+
+	// an iceberg order only displays a slice of DisplayQuantity in the depth book at a time
+	DisplayQuantity sdk.Dec `json:"display_quantity,omitempty"`
+
+	// NONE/CANCEL_NEWEST/CANCEL_OLDEST/CANCEL_BOTH/DECREMENT_AND_CANCEL, empty means the default mode in params
+	SelfTradePrevention string `json:"self_trade_prevention,omitempty"`
+
+	// pays the deal fees in the native token at the discount in params, instead of in the received coins
+	NativeFee bool `json:"native_fee,omitempty"`
 }
 
 // NewMsgNewOrder is a constructor function for MsgNewOrder
@@ -53,10 +73,11 @@ func NewMsgCancelOrder(sender sdk.AccAddress, orderID string) MsgCancelOrders {
 	return msgCancelOrder
 }
 
-//********************MsgNewOrders*************
+// ********************MsgNewOrders*************
 type MsgNewOrders struct {
 	Sender     sdk.AccAddress `json:"sender"` // order maker address
 	OrderItems []OrderItem    `json:"order_items"`
+	Atomic     bool           `json:"atomic,omitempty"` // all the orders are placed or none of them
 }
 
 type OrderItem struct {
@@ -64,6 +85,26 @@ type OrderItem struct {
 	Side     string  `json:"side"`     // BUY/SELL
 	Price    sdk.Dec `json:"price"`    // price of the order
 	Quantity sdk.Dec `json:"quantity"` // quantity of the order
+
+	TimeInForce  string  `json:"time_in_force,omitempty"` // GTC/IOC/FOK/POST_ONLY, empty means GTC
+	TriggerType  string  `json:"trigger_type,omitempty"`  // STOP_LIMIT/TAKE_PROFIT, empty means a normal order
+	TriggerPrice sdk.Dec `json:"trigger_price,omitempty"` // trigger price of a trigger order
+
+	ClientOrderID string `json:"client_order_id,omitempty"` // optional id given by the sender
+
+	// a market order takes Price as its worst acceptable price, or derives it from MaxSlippage,
+	// and a market buy order spends at most Quantity of the quote token
+	OrderType   string  `json:"order_type,omitempty"`   // LIMIT/MARKET, empty means LIMIT
+	MaxSlippage sdk.Dec `json:"max_slippage,omitempty"` // max slippage from the best bid/ask, e.g. 0.05 for 5%
+
+	// an iceberg order only displays a slice of DisplayQuantity in the depth book at a time
+	DisplayQuantity sdk.Dec `json:"display_quantity,omitempty"`
+
+	// NONE/CANCEL_NEWEST/CANCEL_OLDEST/CANCEL_BOTH/DECREMENT_AND_CANCEL, empty means the default mode in params
+	SelfTradePrevention string `json:"self_trade_prevention,omitempty"`
+
+	// pays the deal fees in the native token at the discount in params, instead of in the received coins
+	NativeFee bool `json:"native_fee,omitempty"`
 }
 
 func NewOrderItem(product string, side string, price string,
@@ -116,11 +157,117 @@ func (msg MsgNewOrders) ValidateBasic() sdk.Error {
 			return sdk.ErrUnknownRequest(
 				fmt.Sprintf("Side is expected to be \"BUY\" or \"SELL\", but got \"%s\"", item.Side))
 		}
-		if !(item.Price.IsPositive() && item.Quantity.IsPositive()) {
+		if !item.Quantity.IsPositive() || !(item.OrderType == OrderTypeMarket || item.Price.IsPositive()) {
+			return sdk.ErrUnknownRequest("Price/Quantity must be positive")
+		}
+		if !IsValidTimeInForce(item.TimeInForce) {
+			return sdk.ErrUnknownRequest(fmt.Sprintf("TimeInForce is expected to be one of \"%s\", \"%s\", \"%s\" "+
+				"and \"%s\", but got \"%s\"", TimeInForceGTC, TimeInForceIOC, TimeInForceFOK, TimeInForcePostOnly,
+				item.TimeInForce))
+		}
+		if !IsValidSelfTradePrevention(item.SelfTradePrevention) {
+			return sdk.ErrUnknownRequest(fmt.Sprintf("SelfTradePrevention is expected to be one of \"%s\", \"%s\", "+
+				"\"%s\", \"%s\" and \"%s\", but got \"%s\"", SelfTradePreventionNone, SelfTradePreventionCancelNewest,
+				SelfTradePreventionCancelOldest, SelfTradePreventionCancelBoth, SelfTradePreventionDecrementAndCancel,
+				item.SelfTradePrevention))
+		}
+		if err := validateTrigger(item); err != nil {
+			return err
+		}
+		if err := validateOrderType(item); err != nil {
+			return err
+		}
+		if err := validateIceberg(item); err != nil {
+			return err
+		}
+		if item.ClientOrderID != "" && !IsValidClientOrderID(item.ClientOrderID) {
+			return sdk.ErrUnknownRequest(fmt.Sprintf("ClientOrderID should only contain letters, digits, '-' "+
+				"and '_', and be no longer than %d, but got \"%s\"", MaxClientOrderIDLength, item.ClientOrderID))
+		}
+	}
+	if hasDuplicatedID(getClientOrderIDs(msg.OrderItems)) {
+		return sdk.ErrUnknownRequest("Duplicated client order ids detected")
+	}
+
+	return nil
+}
+
+func getClientOrderIDs(items []OrderItem) []string {
+	var clientOrderIDs []string
+	for _, item := range items {
+		if item.ClientOrderID != "" {
+			clientOrderIDs = append(clientOrderIDs, item.ClientOrderID)
+		}
+	}
+	return clientOrderIDs
+}
+
+// validateTrigger checks the trigger condition of a trigger order, the trigger price of a normal order is unset
+func validateTrigger(item OrderItem) sdk.Error {
+	hasTriggerPrice := item.TriggerPrice.Int != nil && !item.TriggerPrice.IsZero()
+	switch item.TriggerType {
+	case "":
+		if hasTriggerPrice {
+			return sdk.ErrUnknownRequest("TriggerPrice is only allowed for trigger orders")
+		}
+	case TriggerTypeStopLimit, TriggerTypeTakeProfit:
+		if !hasTriggerPrice || !item.TriggerPrice.IsPositive() {
+			return sdk.ErrUnknownRequest("TriggerPrice must be positive")
+		}
+		if item.TimeInForce != "" && item.TimeInForce != TimeInForceGTC {
+			return sdk.ErrUnknownRequest("trigger orders only support TimeInForce GTC")
+		}
+	default:
+		return sdk.ErrUnknownRequest(fmt.Sprintf("TriggerType is expected to be \"%s\" or \"%s\", but got \"%s\"",
+			TriggerTypeStopLimit, TriggerTypeTakeProfit, item.TriggerType))
+	}
+	return nil
+}
+
+// validateOrderType checks the worst acceptable price of a market order, which is either given by the price
+// or derived from the max slippage
+func validateOrderType(item OrderItem) sdk.Error {
+	hasMaxSlippage := item.MaxSlippage.Int != nil && !item.MaxSlippage.IsZero()
+	switch item.OrderType {
+	case "", OrderTypeLimit:
+		if hasMaxSlippage {
+			return sdk.ErrUnknownRequest("MaxSlippage is only allowed for market orders")
+		}
+	case OrderTypeMarket:
+		hasPrice := item.Price.Int != nil && !item.Price.IsZero()
+		if hasPrice == hasMaxSlippage {
+			return sdk.ErrUnknownRequest("market orders require either a Price or a MaxSlippage")
+		}
+		if hasPrice && !item.Price.IsPositive() {
 			return sdk.ErrUnknownRequest("Price/Quantity must be positive")
 		}
+		if hasMaxSlippage && !(item.MaxSlippage.IsPositive() && item.MaxSlippage.LT(sdk.OneDec())) {
+			return sdk.ErrUnknownRequest("MaxSlippage should be between 0 and 1")
+		}
+		if item.TimeInForce != "" && item.TimeInForce != TimeInForceIOC {
+			return sdk.ErrUnknownRequest("market orders only support TimeInForce IOC")
+		}
+		if item.TriggerType != "" {
+			return sdk.ErrUnknownRequest("market orders can't be trigger orders")
+		}
+	default:
+		return sdk.ErrUnknownRequest(fmt.Sprintf("OrderType is expected to be \"%s\" or \"%s\", but got \"%s\"",
+			OrderTypeLimit, OrderTypeMarket, item.OrderType))
 	}
+	return nil
+}
 
+// validateIceberg checks the display quantity of an iceberg order, which rests in the depth book
+func validateIceberg(item OrderItem) sdk.Error {
+	if item.DisplayQuantity.Int == nil || item.DisplayQuantity.IsZero() {
+		return nil
+	}
+	if !(item.DisplayQuantity.IsPositive() && item.DisplayQuantity.LT(item.Quantity)) {
+		return sdk.ErrUnknownRequest("DisplayQuantity must be positive and less than Quantity")
+	}
+	if item.OrderType == OrderTypeMarket || (item.TimeInForce != "" && item.TimeInForce != TimeInForceGTC) {
+		return sdk.ErrUnknownRequest("iceberg orders only support limit orders with TimeInForce GTC")
+	}
 	return nil
 }
 
@@ -136,8 +283,9 @@ func (msg MsgNewOrders) GetSigners() []sdk.AccAddress {
 }
 
 type MsgCancelOrders struct {
-	Sender   sdk.AccAddress `json:"sender"` // order maker address
-	OrderIDs []string       `json:"order_ids"`
+	Sender         sdk.AccAddress `json:"sender"` // order maker address
+	OrderIDs       []string       `json:"order_ids"`
+	ClientOrderIDs []string       `json:"client_order_ids,omitempty"` // open orders identified by client order ids
 }
 
 // NewMsgCancelOrder is a constructor function for MsgCancelOrder
@@ -160,20 +308,28 @@ func (msg MsgCancelOrders) ValidateBasic() sdk.Error {
 	if msg.Sender.Empty() {
 		return sdk.ErrInvalidAddress(msg.Sender.String())
 	}
-	if msg.OrderIDs == nil || len(msg.OrderIDs) == 0 {
+	if len(msg.OrderIDs)+len(msg.ClientOrderIDs) == 0 {
 		return sdk.ErrUnknownRequest("invalid OrderIDs")
 	}
-	if len(msg.OrderIDs) > MultiCancelOrderItemLimit {
+	if len(msg.OrderIDs)+len(msg.ClientOrderIDs) > MultiCancelOrderItemLimit {
 		return sdk.ErrUnknownRequest("Numbers of CancelOrderItem should not be more than " + strconv.Itoa(OrderItemLimit))
 	}
 	if hasDuplicatedID(msg.OrderIDs) {
 		return sdk.ErrUnknownRequest("Duplicated order ids detected")
 	}
+	if hasDuplicatedID(msg.ClientOrderIDs) {
+		return sdk.ErrUnknownRequest("Duplicated client order ids detected")
+	}
 	for _, item := range msg.OrderIDs {
 		if item == "" {
 			return sdk.ErrUnauthorized("orderID cannot be empty")
 		}
 	}
+	for _, item := range msg.ClientOrderIDs {
+		if !IsValidClientOrderID(item) {
+			return sdk.ErrUnknownRequest(fmt.Sprintf("invalid client order id \"%s\"", item))
+		}
+	}
 
 	return nil
 }
@@ -201,8 +357,110 @@ func (msg MsgCancelOrders) GetSigners() []sdk.AccAddress {
 	return []sdk.AccAddress{msg.Sender}
 }
 
+//********************MsgAmendOrder*************
+type MsgAmendOrder struct {
+	Sender   sdk.AccAddress `json:"sender"`   // order maker address
+	OrderID  string         `json:"order_id"` // id of the open order to amend
+	Price    sdk.Dec        `json:"price"`    // new price of the order
+	Quantity sdk.Dec        `json:"quantity"` // new total quantity of the order, including the filled quantity
+}
+
+// NewMsgAmendOrder is a constructor function for MsgAmendOrder
+func NewMsgAmendOrder(sender sdk.AccAddress, orderID string, price, quantity sdk.Dec) MsgAmendOrder {
+	return MsgAmendOrder{
+		Sender:   sender,
+		OrderID:  orderID,
+		Price:    price,
+		Quantity: quantity,
+	}
+}
+
+// Name Implements Msg.
+func (msg MsgAmendOrder) Route() string { return "order" }
+
+// Type Implements Msg.
+func (msg MsgAmendOrder) Type() string { return "amend" }
+
+// ValdateBasic Implements Msg.
+func (msg MsgAmendOrder) ValidateBasic() sdk.Error {
+	if msg.Sender.Empty() {
+		return sdk.ErrInvalidAddress(msg.Sender.String())
+	}
+	if msg.OrderID == "" {
+		return sdk.ErrUnknownRequest("orderID cannot be empty")
+	}
+	if !(msg.Price.IsPositive() && msg.Quantity.IsPositive()) {
+		return sdk.ErrUnknownRequest("Price/Quantity must be positive")
+	}
+	return nil
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgAmendOrder) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgAmendOrder) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+//********************MsgCancelAllOrders*************
+type MsgCancelAllOrders struct {
+	Sender  sdk.AccAddress `json:"sender"`            // order maker address
+	Product string         `json:"product,omitempty"` // only cancel the orders of the product if set
+	Side    string         `json:"side,omitempty"`    // only cancel the orders of the side if set
+}
+
+// NewMsgCancelAllOrders is a constructor function for MsgCancelAllOrders
+func NewMsgCancelAllOrders(sender sdk.AccAddress, product, side string) MsgCancelAllOrders {
+	return MsgCancelAllOrders{
+		Sender:  sender,
+		Product: product,
+		Side:    side,
+	}
+}
+
+// Name Implements Msg.
+func (msg MsgCancelAllOrders) Route() string { return "order" }
+
+// Type Implements Msg.
+func (msg MsgCancelAllOrders) Type() string { return "cancel_all" }
+
+// ValdateBasic Implements Msg.
+func (msg MsgCancelAllOrders) ValidateBasic() sdk.Error {
+	if msg.Sender.Empty() {
+		return sdk.ErrInvalidAddress(msg.Sender.String())
+	}
+	if msg.Product != "" {
+		symbols := strings.Split(msg.Product, "_")
+		if len(symbols) != 2 || symbols[0] == symbols[1] {
+			return sdk.ErrUnknownRequest("Product should be in the format of \"base_quote\"")
+		}
+	}
+	if msg.Side != "" && msg.Side != BuyOrder && msg.Side != SellOrder {
+		return sdk.ErrUnknownRequest(
+			fmt.Sprintf("Side is expected to be \"BUY\" or \"SELL\", but got \"%s\"", msg.Side))
+	}
+	return nil
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgCancelAllOrders) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgCancelAllOrders) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
 type OrderResult struct {
-	Code    sdk.CodeType `json:"code"`    // order return code
-	Message string       `json:"msg"`     // order return error message
-	OrderID string       `json:"orderid"` // order return orderid
+	Code          sdk.CodeType      `json:"code"`                      // order return code
+	Message       string            `json:"msg"`                       // order return error message
+	OrderID       string            `json:"orderid"`                   // order return orderid
+	ClientOrderID string            `json:"client_order_id,omitempty"` // order return client order id
+	Codespace     sdk.CodespaceType `json:"codespace,omitempty"`       // codespace of the code, empty for the root one
 }
@@ -4,8 +4,11 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"strconv"
+	"strings"
 	"testing"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
 	"github.com/okex/okchain/x/common"
 
 	"github.com/stretchr/testify/require"
@@ -91,6 +94,141 @@ func TestMsgNewOrderInvalid(t *testing.T) {
 	orderMsg = NewMsgNewOrder(addr, common.TestToken+"_"+common.TestToken, BuyOrder, testPrice, "-1")
 	err = orderMsg.ValidateBasic()
 	require.NotNil(t, err)
+
+	//valid time in force
+	for _, timeInForce := range []string{TimeInForceGTC, TimeInForceIOC, TimeInForceFOK, TimeInForcePostOnly} {
+		orderMsg = NewMsgNewOrder(addr, "btc_"+common.NativeToken, BuyOrder, testPrice, testQuantity)
+		orderMsg.OrderItems[0].TimeInForce = timeInForce
+		err = orderMsg.ValidateBasic()
+		require.Nil(t, err)
+	}
+
+	//invalid time in force
+	orderMsg = NewMsgNewOrder(addr, "btc_"+common.NativeToken, BuyOrder, testPrice, testQuantity)
+	orderMsg.OrderItems[0].TimeInForce = "GTD"
+	err = orderMsg.ValidateBasic()
+	require.NotNil(t, err)
+
+	//valid trigger order
+	orderMsg = NewMsgNewOrder(addr, "btc_"+common.NativeToken, BuyOrder, testPrice, testQuantity)
+	orderMsg.OrderItems[0].TriggerType = TriggerTypeStopLimit
+	orderMsg.OrderItems[0].TriggerPrice = sdk.MustNewDecFromStr(testPrice)
+	err = orderMsg.ValidateBasic()
+	require.Nil(t, err)
+
+	//trigger order with IOC
+	orderMsg.OrderItems[0].TimeInForce = TimeInForceIOC
+	err = orderMsg.ValidateBasic()
+	require.NotNil(t, err)
+
+	//trigger order without trigger price
+	orderMsg = NewMsgNewOrder(addr, "btc_"+common.NativeToken, BuyOrder, testPrice, testQuantity)
+	orderMsg.OrderItems[0].TriggerType = TriggerTypeTakeProfit
+	err = orderMsg.ValidateBasic()
+	require.NotNil(t, err)
+
+	//invalid trigger type
+	orderMsg.OrderItems[0].TriggerType = "STOP_LOSS"
+	orderMsg.OrderItems[0].TriggerPrice = sdk.MustNewDecFromStr(testPrice)
+	err = orderMsg.ValidateBasic()
+	require.NotNil(t, err)
+
+	//trigger price of a normal order
+	orderMsg.OrderItems[0].TriggerType = ""
+	err = orderMsg.ValidateBasic()
+	require.NotNil(t, err)
+}
+
+func TestMsgNewOrderClientOrderID(t *testing.T) {
+	addr, err := hex.DecodeString("1212121212121212123412121212121212121234")
+	require.Nil(t, err)
+	orderItem := NewOrderItem("btc_"+common.NativeToken, BuyOrder, testPrice, testQuantity)
+	orderItem.ClientOrderID = "0a1B-2c_3D"
+	require.Nil(t, NewMsgNewOrders(addr, []OrderItem{orderItem}).ValidateBasic())
+
+	// invalid characters
+	invalidItem := orderItem
+	invalidItem.ClientOrderID = "id'1"
+	require.NotNil(t, NewMsgNewOrders(addr, []OrderItem{invalidItem}).ValidateBasic())
+
+	// too long
+	invalidItem.ClientOrderID = strings.Repeat("a", MaxClientOrderIDLength+1)
+	require.NotNil(t, NewMsgNewOrders(addr, []OrderItem{invalidItem}).ValidateBasic())
+
+	// duplicated in one msg
+	require.NotNil(t, NewMsgNewOrders(addr, []OrderItem{orderItem, orderItem}).ValidateBasic())
+
+	// cancel by client order ids
+	cancelMsg := NewMsgCancelOrders(addr, nil)
+	cancelMsg.ClientOrderIDs = []string{orderItem.ClientOrderID}
+	require.Nil(t, cancelMsg.ValidateBasic())
+	cancelMsg.ClientOrderIDs = []string{orderItem.ClientOrderID, orderItem.ClientOrderID}
+	require.NotNil(t, cancelMsg.ValidateBasic())
+	cancelMsg.ClientOrderIDs = []string{"id'1"}
+	require.NotNil(t, cancelMsg.ValidateBasic())
+}
+
+func TestMsgNewMarketOrder(t *testing.T) {
+	addr, err := hex.DecodeString("1212121212121212123412121212121212121234")
+	require.Nil(t, err)
+	// the worst price is given
+	orderItem := NewOrderItem("btc_"+common.NativeToken, BuyOrder, testPrice, testQuantity)
+	orderItem.OrderType = OrderTypeMarket
+	require.Nil(t, NewMsgNewOrders(addr, []OrderItem{orderItem}).ValidateBasic())
+	orderItem.TimeInForce = TimeInForceIOC
+	require.Nil(t, NewMsgNewOrders(addr, []OrderItem{orderItem}).ValidateBasic())
+	// the worst price is derived from the max slippage
+	slippageItem := OrderItem{
+		Product:     "btc_" + common.NativeToken,
+		Side:        SellOrder,
+		Quantity:    sdk.MustNewDecFromStr(testQuantity),
+		OrderType:   OrderTypeMarket,
+		MaxSlippage: sdk.MustNewDecFromStr("0.05"),
+	}
+	require.Nil(t, NewMsgNewOrders(addr, []OrderItem{slippageItem}).ValidateBasic())
+
+	invalidItems := []OrderItem{orderItem, orderItem, orderItem, orderItem, orderItem, slippageItem, slippageItem}
+	invalidItems[0].OrderType = "STOP"
+	invalidItems[1].MaxSlippage = sdk.MustNewDecFromStr("0.05") // both price and max slippage
+	invalidItems[2].TimeInForce = TimeInForceGTC
+	invalidItems[3].TriggerType = TriggerTypeStopLimit
+	invalidItems[3].TriggerPrice = sdk.MustNewDecFromStr(testPrice)
+	invalidItems[4].OrderType = OrderTypeLimit
+	invalidItems[4].MaxSlippage = sdk.MustNewDecFromStr("0.05")
+	invalidItems[5].MaxSlippage = sdk.OneDec()
+	invalidItems[6].MaxSlippage = sdk.ZeroDec() // neither price nor max slippage
+	for _, item := range invalidItems {
+		require.NotNil(t, NewMsgNewOrders(addr, []OrderItem{item}).ValidateBasic())
+	}
+}
+
+func TestMsgNewIcebergOrder(t *testing.T) {
+	addr, err := hex.DecodeString("1212121212121212123412121212121212121234")
+	require.Nil(t, err)
+	orderItem := NewOrderItem("btc_"+common.NativeToken, BuyOrder, testPrice, testQuantity)
+	orderItem.DisplayQuantity = sdk.MustNewDecFromStr("0.5")
+	require.Nil(t, NewMsgNewOrders(addr, []OrderItem{orderItem}).ValidateBasic())
+
+	invalidItems := []OrderItem{orderItem, orderItem, orderItem}
+	invalidItems[0].DisplayQuantity = sdk.MustNewDecFromStr(testQuantity)
+	invalidItems[1].TimeInForce = TimeInForceIOC
+	invalidItems[2].OrderType = OrderTypeMarket
+	for _, item := range invalidItems {
+		require.NotNil(t, NewMsgNewOrders(addr, []OrderItem{item}).ValidateBasic())
+	}
+}
+
+func TestMsgNewOrderSelfTradePrevention(t *testing.T) {
+	addr, err := hex.DecodeString("1212121212121212123412121212121212121234")
+	require.Nil(t, err)
+	orderItem := NewOrderItem("btc_"+common.NativeToken, BuyOrder, testPrice, testQuantity)
+	for _, mode := range []string{"", SelfTradePreventionNone, SelfTradePreventionCancelNewest,
+		SelfTradePreventionCancelOldest, SelfTradePreventionCancelBoth, SelfTradePreventionDecrementAndCancel} {
+		orderItem.SelfTradePrevention = mode
+		require.Nil(t, NewMsgNewOrders(addr, []OrderItem{orderItem}).ValidateBasic())
+	}
+	orderItem.SelfTradePrevention = "CANCEL"
+	require.NotNil(t, NewMsgNewOrders(addr, []OrderItem{orderItem}).ValidateBasic())
 }
 
 func TestMsgCancelOrder(t *testing.T) {
@@ -129,6 +267,78 @@ func TestMsgCancelOrderInvalid(t *testing.T) {
 	require.NotNil(t, sdkErr)
 }
 
+func TestMsgAmendOrder(t *testing.T) {
+	addr, err := hex.DecodeString("1212121212121212123412121212121212121234")
+	require.Nil(t, err)
+	orderMsg := NewMsgAmendOrder(addr, testOrderID, sdk.MustNewDecFromStr(testPrice),
+		sdk.MustNewDecFromStr(testQuantity))
+	require.Nil(t, orderMsg.ValidateBasic())
+	require.Equal(t, "order", orderMsg.Route())
+	require.Equal(t, "amend", orderMsg.Type())
+
+	bytesMsg := orderMsg.GetSignBytes()
+	resOrderMsg := &MsgAmendOrder{}
+	err = json.Unmarshal(bytesMsg, resOrderMsg)
+	require.Nil(t, err)
+	resAddr := orderMsg.GetSigners()[0]
+	require.EqualValues(t, addr, resAddr)
+}
+
+func TestMsgAmendOrderInvalid(t *testing.T) {
+	addr, err := hex.DecodeString("1212121212121212123412121212121212121234")
+	require.Nil(t, err)
+	price := sdk.MustNewDecFromStr(testPrice)
+	quantity := sdk.MustNewDecFromStr(testQuantity)
+
+	// empty sender
+	orderMsg := NewMsgAmendOrder(nil, testOrderID, price, quantity)
+	require.NotNil(t, orderMsg.ValidateBasic())
+
+	// empty orderID
+	orderMsg = NewMsgAmendOrder(addr, "", price, quantity)
+	require.NotNil(t, orderMsg.ValidateBasic())
+
+	// non-positive price
+	orderMsg = NewMsgAmendOrder(addr, testOrderID, sdk.ZeroDec(), quantity)
+	require.NotNil(t, orderMsg.ValidateBasic())
+
+	// non-positive quantity
+	orderMsg = NewMsgAmendOrder(addr, testOrderID, price, sdk.MustNewDecFromStr("-1"))
+	require.NotNil(t, orderMsg.ValidateBasic())
+}
+
+func TestMsgCancelAllOrders(t *testing.T) {
+	addr, err := hex.DecodeString("1212121212121212123412121212121212121234")
+	require.Nil(t, err)
+	orderMsg := NewMsgCancelAllOrders(addr, "btc_"+common.NativeToken, BuyOrder)
+	require.Nil(t, orderMsg.ValidateBasic())
+	require.Equal(t, "order", orderMsg.Route())
+	require.Equal(t, "cancel_all", orderMsg.Type())
+
+	// product and side are optional
+	require.Nil(t, NewMsgCancelAllOrders(addr, "", "").ValidateBasic())
+
+	bytesMsg := orderMsg.GetSignBytes()
+	resOrderMsg := &MsgCancelAllOrders{}
+	err = json.Unmarshal(bytesMsg, resOrderMsg)
+	require.Nil(t, err)
+	resAddr := orderMsg.GetSigners()[0]
+	require.EqualValues(t, addr, resAddr)
+}
+
+func TestMsgCancelAllOrdersInvalid(t *testing.T) {
+	addr, err := hex.DecodeString("1212121212121212123412121212121212121234")
+	require.Nil(t, err)
+
+	// empty sender
+	require.NotNil(t, NewMsgCancelAllOrders(nil, "", "").ValidateBasic())
+	// invalid product
+	require.NotNil(t, NewMsgCancelAllOrders(addr, "btc", "").ValidateBasic())
+	require.NotNil(t, NewMsgCancelAllOrders(addr, "btc_btc", "").ValidateBasic())
+	// invalid side
+	require.NotNil(t, NewMsgCancelAllOrders(addr, "", "BUY_SELL").ValidateBasic())
+}
+
 func TestMsgMultiNewOrder(t *testing.T) {
 	addr, err := hex.DecodeString("1212121212121212123412121212121212121234")
 	require.Nil(t, err)
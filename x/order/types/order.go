@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math/big"
 	"strings"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -18,6 +19,12 @@ const (
 	Expired
 	PartialFilledCancelled
 	PartialFilledExpired
+	PartialFilled
+	IOCCancelled
+	FOKKilled
+	PostOnlyRejected
+	Untriggered
+	SelfTradeCancelled
 )
 
 func (p OrderStatus) String() string {
@@ -34,6 +41,18 @@ func (p OrderStatus) String() string {
 		return "PartialFilledCancelled"
 	case PartialFilledExpired:
 		return "PartialFilledExpired"
+	case PartialFilled:
+		return "PartialFilled"
+	case IOCCancelled:
+		return "IOCCancelled"
+	case FOKKilled:
+		return "FOKKilled"
+	case PostOnlyRejected:
+		return "PostOnlyRejected"
+	case Untriggered:
+		return "Untriggered"
+	case SelfTradeCancelled:
+		return "SelfTradeCancelled"
 	default:
 		return "Unknown"
 	}
@@ -47,14 +66,20 @@ const (
 	OrderStatusPartialFilledCancelled = 4
 	OrderStatusPartialFilledExpired   = 5
 	OrderStatusPartialFilled          = 6
+	OrderStatusIOCCancelled           = 7
+	OrderStatusFOKKilled              = 8
+	OrderStatusPostOnlyRejected       = 9
+	OrderStatusUntriggered            = 10
+	OrderStatusSelfTradeCancelled     = 11
 )
 
 const (
-	OrderExtraInfoKeyNewFee     = "newFee"
-	OrderExtraInfoKeyCancelFee  = "cancelFee"
-	OrderExtraInfoKeyExpireFee  = "expireFee"
-	OrderExtraInfoKeyDealFee    = "dealFee"
-	OrderExtraInfoKeyReceiveFee = "receiveFee"
+	OrderExtraInfoKeyNewFee        = "newFee"
+	OrderExtraInfoKeyCancelFee     = "cancelFee"
+	OrderExtraInfoKeyExpireFee     = "expireFee"
+	OrderExtraInfoKeyDealFee       = "dealFee"
+	OrderExtraInfoKeyReceiveFee    = "receiveFee"
+	OrderExtraInfoKeyBurnedDeposit = "burnedDeposit"
 )
 
 type Order struct {
@@ -72,7 +97,40 @@ type Order struct {
 	Timestamp         int64          `json:"timestamp"`        // created timestamp
 	OrderExpireBlocks int64          `json:"order_expire_blocks"`
 	FeePerBlock       sdk.DecCoin    `json:"fee_per_block"`
-	ExtraInfo         string         `json:"extra_info"` // extra info of order in json format
+	ExtraInfo         string         `json:"extra_info"`                 // extra info of order in json format
+	TimeInForce       string         `json:"time_in_force,omitempty"`    // GTC/IOC/FOK/POST_ONLY, empty means GTC
+	Trigger           *OrderTrigger  `json:"trigger,omitempty"`          // trigger condition of a trigger order
+	ClientOrderID     string         `json:"client_order_id,omitempty"`  // id given by the sender, unique among its open orders
+	OrderType         string         `json:"order_type,omitempty"`       // LIMIT/MARKET, empty means LIMIT
+	DisplayQuantity   *sdk.Dec       `json:"display_quantity,omitempty"` // size of the display slices of an iceberg order
+	// what happens when the order would trade with another order of the same sender
+	SelfTradePrevention string `json:"self_trade_prevention,omitempty"`
+	// whether the deal fees are paid in the native token at a discount
+	NativeFee bool `json:"native_fee,omitempty"`
+	// refundable deposit held by the order till it's closed, nil if it holds none
+	Deposit *sdk.DecCoin `json:"deposit,omitempty"`
+	// quote amount a market buy order spends, which is locked instead of its price times its quantity
+	QuoteAmount *sdk.Dec `json:"quote_amount,omitempty"`
+}
+
+// OrderTrigger is the condition a trigger order waits for before it enters the depth book
+type OrderTrigger struct {
+	Type  string  `json:"type"`  // STOP_LIMIT/TAKE_PROFIT
+	Price sdk.Dec `json:"price"` // the order is triggered when the last price crosses it
+}
+
+// IsRising checks whether the order waits for the last price to rise to the trigger price
+func (trigger OrderTrigger) IsRising(side string) bool {
+	// a stop-limit buy order and a take-profit sell order wait for a rising price
+	return (trigger.Type == TriggerTypeStopLimit) == (side == BuyOrder)
+}
+
+// IsTriggered checks whether the trigger condition is met at the last price
+func (trigger OrderTrigger) IsTriggered(side string, lastPrice sdk.Dec) bool {
+	if trigger.IsRising(side) {
+		return lastPrice.GTE(trigger.Price)
+	}
+	return lastPrice.LTE(trigger.Price)
 }
 
 func NewOrder(txHash string, sender sdk.AccAddress, product, side string, price, quantity sdk.Dec,
@@ -156,6 +214,11 @@ func (order *Order) RecordOrderReceiveFee(fee sdk.DecCoins) {
 	order.SetExtraInfoWithKeyValue(OrderExtraInfoKeyReceiveFee, fee.String())
 }
 
+// RecordOrderBurnedDeposit records the deposit burned for cancelling the order too early
+func (order *Order) RecordOrderBurnedDeposit(deposit sdk.DecCoins) {
+	order.SetExtraInfoWithKeyValue(OrderExtraInfoKeyBurnedDeposit, deposit.String())
+}
+
 // An order may have several deals
 func (order *Order) RecordOrderDealFee(fee sdk.DecCoins) {
 	oldValue := order.GetExtraInfoWithKey(OrderExtraInfoKeyDealFee)
@@ -203,11 +266,123 @@ func (order *Order) Expire() {
 	}
 }
 
+// Amend changes the price and the total quantity of the order, the filled quantity is kept
+func (order *Order) Amend(price, quantity sdk.Dec) {
+	filledQuantity := order.Quantity.Sub(order.RemainQuantity)
+	order.Price = price
+	order.Quantity = quantity
+	order.RemainQuantity = quantity.Sub(filledQuantity)
+	if order.Side == BuyOrder {
+		order.RemainLocked = price.Mul(order.RemainQuantity)
+	} else {
+		order.RemainLocked = order.RemainQuantity
+	}
+}
+
+// CancelIOC cancels the remaining quantity of an IOC order after matching
+func (order *Order) CancelIOC() {
+	order.Status = OrderStatusIOCCancelled
+}
+
+// KillFOK kills a FOK order which can't be filled entirely
+func (order *Order) KillFOK() {
+	order.Status = OrderStatusFOKKilled
+}
+
+// RejectPostOnly rejects a post-only order which would be matched when it enters the book
+func (order *Order) RejectPostOnly() {
+	order.Status = OrderStatusPostOnlyRejected
+}
+
+// CancelSelfTrade cancels an order which would trade with another order of the same sender
+func (order *Order) CancelSelfTrade() {
+	order.Status = OrderStatusSelfTradeCancelled
+}
+
+// Decrement reduces the total quantity of the order by quantity, which is never traded
+func (order *Order) Decrement(quantity sdk.Dec) {
+	order.Quantity = order.Quantity.Sub(quantity)
+	order.RemainQuantity = order.RemainQuantity.Sub(quantity)
+	if order.Side == BuyOrder {
+		order.RemainLocked = order.RemainLocked.Sub(order.Price.Mul(quantity))
+	} else {
+		order.RemainLocked = order.RemainLocked.Sub(quantity)
+	}
+}
+
+// Activate turns a triggered order into a normal limit order
+func (order *Order) Activate() {
+	order.Status = OrderStatusOpen
+}
+
+// GetTimeInForce returns the time in force of the order, GTC if it's not set
+func (order *Order) GetTimeInForce() string {
+	if order.TimeInForce == "" {
+		return TimeInForceGTC
+	}
+	return order.TimeInForce
+}
+
+// GetSelfTradePrevention returns the self-trade prevention mode of the order, NONE if it's not set or unknown
+func (order *Order) GetSelfTradePrevention() string {
+	if order.SelfTradePrevention == "" || !IsValidSelfTradePrevention(order.SelfTradePrevention) {
+		return SelfTradePreventionNone
+	}
+	return order.SelfTradePrevention
+}
+
+// IsIceberg checks whether only a slice of the order is displayed in the depth book
+func (order *Order) IsIceberg() bool {
+	return order.DisplayQuantity != nil && order.DisplayQuantity.IsPositive()
+}
+
+// VisibleQuantity returns the quantity of the order displayed in the depth book, which is the remaining quantity
+// of its current display slice for an iceberg order. The next slice is displayed once the current one is filled.
+func (order *Order) VisibleQuantity() sdk.Dec {
+	if !order.IsIceberg() {
+		return order.RemainQuantity
+	}
+	filledQuantity := order.Quantity.Sub(order.RemainQuantity)
+	sliceFilled := sdk.Dec{Int: new(big.Int).Mod(filledQuantity.Int, order.DisplayQuantity.Int)}
+	return sdk.MinDec(order.DisplayQuantity.Sub(sliceFilled), order.RemainQuantity)
+}
+
+// SpendQuoteAmount makes a market buy order spend the quote amount, whose quantity is what the amount affords at
+// its worst price until it's filled at better prices
+func (order *Order) SpendQuoteAmount(amount sdk.Dec) {
+	order.QuoteAmount = &amount
+	order.RemainLocked = amount
+}
+
+// AffordableQuantity returns the remaining quantity of the order which can be filled at price. The remaining quote
+// amount of a market buy order affords more at a price better than its worst one, rounded down to quantityDigit.
+func (order *Order) AffordableQuantity(price sdk.Dec, quantityDigit int64) sdk.Dec {
+	if order.QuoteAmount == nil || !price.IsPositive() {
+		return order.RemainQuantity
+	}
+	unit := sdk.NewDecWithPrec(1, quantityDigit)
+	return order.RemainLocked.QuoTruncate(price).QuoTruncate(unit).TruncateDec().Mul(unit)
+}
+
+// ResizeToAffordable sets the remaining quantity of a market buy order to what its remaining quote amount affords
+// at price, before it's filled at the price
+func (order *Order) ResizeToAffordable(price sdk.Dec, quantityDigit int64) {
+	if order.QuoteAmount == nil {
+		return
+	}
+	remainQuantity := order.AffordableQuantity(price, quantityDigit)
+	order.Quantity = order.Quantity.Sub(order.RemainQuantity).Add(remainQuantity)
+	order.RemainQuantity = remainQuantity
+}
+
 // when place a new order, we should lock the coins of sender
 func (order *Order) NeedLockCoins() sdk.DecCoins {
 	if order.Side == BuyOrder {
 		token := strings.Split(order.Product, "_")[1]
 		amount := order.Price.Mul(order.Quantity)
+		if order.QuoteAmount != nil {
+			amount = *order.QuoteAmount
+		}
 		return sdk.DecCoins{{Denom: token, Amount: amount}}
 	}
 	token := strings.Split(order.Product, "_")[0]
@@ -260,6 +435,15 @@ func FormatOrderID(blockHeight, orderNum int64) string {
 	return fmt.Sprintf(format, blockHeight, orderNum)
 }
 
+// FormatOrderIDPrefix returns the common prefix of the ids of orders placed at blockHeight
+func FormatOrderIDPrefix(blockHeight int64) string {
+	format := "ID%010d-"
+	if blockHeight > 9999999999 {
+		format = "ID%d-"
+	}
+	return fmt.Sprintf(format, blockHeight)
+}
+
 func GetBlockHeightFromOrderID(orderID string) int64 {
 	var blockHeight int64
 	var id int64
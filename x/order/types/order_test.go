@@ -30,7 +30,7 @@ func TestNewOrder(t *testing.T) {
 
 	require.Equal(t, expected, order1.String())
 
-	order1.Status = 10
+	order1.Status = 100
 	require.Equal(t, "Unknown", OrderStatus(order1.Status).String())
 }
 
@@ -98,6 +98,29 @@ func TestOrderFill(t *testing.T) {
 	require.EqualValues(t, OrderStatusFilled, order.Status)
 }
 
+func TestOrderVisibleQuantity(t *testing.T) {
+	order := MockOrder("", "", SellOrder, "0.1", "10.0")
+	require.False(t, order.IsIceberg())
+	require.EqualValues(t, sdk.MustNewDecFromStr("10"), order.VisibleQuantity())
+
+	displayQuantity := sdk.MustNewDecFromStr("4")
+	order.DisplayQuantity = &displayQuantity
+	require.True(t, order.IsIceberg())
+	require.EqualValues(t, sdk.MustNewDecFromStr("4"), order.VisibleQuantity())
+
+	// partially fill the current slice
+	order.Fill(sdk.MustNewDecFromStr("0.1"), sdk.MustNewDecFromStr("1"))
+	require.EqualValues(t, sdk.MustNewDecFromStr("3"), order.VisibleQuantity())
+
+	// the next slice is displayed once the current one is filled
+	order.Fill(sdk.MustNewDecFromStr("0.1"), sdk.MustNewDecFromStr("3"))
+	require.EqualValues(t, sdk.MustNewDecFromStr("4"), order.VisibleQuantity())
+
+	// the last slice is limited by the remaining quantity
+	order.Fill(sdk.MustNewDecFromStr("0.1"), sdk.MustNewDecFromStr("4"))
+	require.EqualValues(t, sdk.MustNewDecFromStr("2"), order.VisibleQuantity())
+}
+
 func TestOrderCancel(t *testing.T) {
 	// Full cancel
 	order := MockOrder("", TestTokenPair, SellOrder, "0.1", "10.0")
@@ -13,20 +13,53 @@ const (
 	// System param
 	DefaultOrderExpireBlocks = 259200 // order will be expired after 86400 blocks.
 	DefaultMaxDealsPerBlock  = 1000   // deals limit per block
+	MaxExpireOrdersPerBlock  = 1000   // limit of orders expired or dropped per block
+	MaxPruneOrdersPerBlock   = 1000   // limit of closed orders pruned per block
+	MaxTriggerOrdersPerBlock = 1000   // limit of trigger orders activated per block
 
 	// Fee param
 	DefaultFeeAmountPerBlock = "0.000001" // okt
 	DefaultFeeDenomPerBlock  = common.NativeToken
-	DefaultFeeRateTrade      = "0.001" // percentage
+	DefaultFeeRateMaker      = "0.001" // percentage
+	DefaultFeeRateTaker      = "0.001" // percentage
+
+	// Discount of the deal fees paid in the native token
+	DefaultNativeFeeDiscount = "0.25" // percentage off
+
+	// Trailing traded volume of accounts which decides their fee tier
+	DefaultVolumeWindowBlocks = 2592000 // volume traded in the last 30 days at 1 block per second
+
+	// Self-trade prevention param, orders of the same sender trade with each other unless they choose a mode
+	DefaultSelfTradePrevention = SelfTradePreventionNone
+
+	// Anti-spam params of every account, 0 means no limit or no deposit
+	DefaultMaxOpenOrdersPerProduct = 0 // open orders per product, untriggered orders included
+	DefaultMaxNewOrdersPerBlock    = 0 // new orders per block
+	DefaultOrderDepositAmount      = "0"
+	DefaultDepositLockBlocks       = 0 // the deposit of an order cancelled within these blocks is burned
+
+	// Closed orders are pruned after these blocks, 0 means they're kept till the orders placed with them expire
+	DefaultClosedOrderRetentionBlocks = 0
 )
 
 // Parameter keys
 var (
-	KeyOrderExpireBlocks = []byte("OrderExpireBlocks")
-	KeyMaxDealsPerBlock  = []byte("MaxDealsPerBlock")
-	KeyFeePerBlock       = []byte("FeePerBlock")
-	KeyTradeFeeRate      = []byte("TradeFeeRate")
-	DefaultFeePerBlock   = sdk.NewDecCoinFromDec(DefaultFeeDenomPerBlock, sdk.MustNewDecFromStr(DefaultFeeAmountPerBlock))
+	KeyOrderExpireBlocks          = []byte("OrderExpireBlocks")
+	KeyMaxDealsPerBlock           = []byte("MaxDealsPerBlock")
+	KeyFeePerBlock                = []byte("FeePerBlock")
+	KeyMakerFeeRate               = []byte("MakerFeeRate")
+	KeyTakerFeeRate               = []byte("TakerFeeRate")
+	KeyFeeTiers                   = []byte("FeeTiers")
+	KeyVolumeWindowBlocks         = []byte("VolumeWindowBlocks")
+	KeyNativeFeeDiscount          = []byte("NativeFeeDiscount")
+	KeySelfTradePrevention        = []byte("SelfTradePrevention")
+	KeyMaxOpenOrdersPerProduct    = []byte("MaxOpenOrdersPerProduct")
+	KeyMaxNewOrdersPerBlock       = []byte("MaxNewOrdersPerBlock")
+	KeyOrderDeposit               = []byte("OrderDeposit")
+	KeyDepositLockBlocks          = []byte("DepositLockBlocks")
+	KeyClosedOrderRetentionBlocks = []byte("ClosedOrderRetentionBlocks")
+	DefaultFeePerBlock            = sdk.NewDecCoinFromDec(DefaultFeeDenomPerBlock, sdk.MustNewDecFromStr(DefaultFeeAmountPerBlock))
+	DefaultOrderDeposit           = sdk.NewDecCoinFromDec(common.NativeToken, sdk.MustNewDecFromStr(DefaultOrderDepositAmount))
 )
 
 var _ params.ParamSet = &Params{}
@@ -36,7 +69,25 @@ type Params struct {
 	OrderExpireBlocks int64       `json:"order_expire_blocks"`
 	MaxDealsPerBlock  int64       `json:"max_deals_per_block"`
 	FeePerBlock       sdk.DecCoin `json:"fee_per_block"`
-	TradeFeeRate      sdk.Dec     `json:"trade_fee_rate"`
+	MakerFeeRate      sdk.Dec     `json:"maker_fee_rate"` // deal fee rate of the orders resting in the depth book
+	TakerFeeRate      sdk.Dec     `json:"taker_fee_rate"` // deal fee rate of the orders taking the resting orders
+	// lower fee rates of the accounts with more traded volume in the last VolumeWindowBlocks blocks
+	FeeTiers           []FeeTier `json:"fee_tiers"`
+	VolumeWindowBlocks int64     `json:"volume_window_blocks"`
+	// discount of the deal fees of the orders which pay them in the native token
+	NativeFeeDiscount sdk.Dec `json:"native_fee_discount"`
+	// default self-trade prevention mode of the orders which don't choose one
+	SelfTradePrevention string `json:"self_trade_prevention"`
+	// limits of the open orders per product and the new orders per block of an account, 0 means no limit
+	MaxOpenOrdersPerProduct int64 `json:"max_open_orders_per_product"`
+	MaxNewOrdersPerBlock    int64 `json:"max_new_orders_per_block"`
+	// refundable deposit locked by every new order, which is burned if the sender cancels the order
+	// within DepositLockBlocks blocks, a zero amount means no deposit
+	OrderDeposit      sdk.DecCoin `json:"order_deposit"`
+	DepositLockBlocks int64       `json:"deposit_lock_blocks"`
+	// blocks the filled, cancelled and expired orders stay in the store after they're closed, 0 means they stay
+	// till the orders placed with them expire
+	ClosedOrderRetentionBlocks int64 `json:"closed_order_retention_blocks"`
 }
 
 // ParamKeyTable for auth module
@@ -52,17 +103,37 @@ func (p *Params) ParamSetPairs() params.ParamSetPairs {
 		{KeyOrderExpireBlocks, &p.OrderExpireBlocks},
 		{KeyMaxDealsPerBlock, &p.MaxDealsPerBlock},
 		{KeyFeePerBlock, &p.FeePerBlock},
-		{KeyTradeFeeRate, &p.TradeFeeRate},
+		{KeyMakerFeeRate, &p.MakerFeeRate},
+		{KeyTakerFeeRate, &p.TakerFeeRate},
+		{KeyFeeTiers, &p.FeeTiers},
+		{KeyVolumeWindowBlocks, &p.VolumeWindowBlocks},
+		{KeyNativeFeeDiscount, &p.NativeFeeDiscount},
+		{KeySelfTradePrevention, &p.SelfTradePrevention},
+		{KeyMaxOpenOrdersPerProduct, &p.MaxOpenOrdersPerProduct},
+		{KeyMaxNewOrdersPerBlock, &p.MaxNewOrdersPerBlock},
+		{KeyOrderDeposit, &p.OrderDeposit},
+		{KeyDepositLockBlocks, &p.DepositLockBlocks},
+		{KeyClosedOrderRetentionBlocks, &p.ClosedOrderRetentionBlocks},
 	}
 }
 
 // DefaultParams returns a default set of parameters.
 func DefaultParams() Params {
 	return Params{
-		OrderExpireBlocks: DefaultOrderExpireBlocks,
-		MaxDealsPerBlock:  DefaultMaxDealsPerBlock,
-		FeePerBlock:       DefaultFeePerBlock,
-		TradeFeeRate:      sdk.MustNewDecFromStr(DefaultFeeRateTrade),
+		OrderExpireBlocks:       DefaultOrderExpireBlocks,
+		MaxDealsPerBlock:        DefaultMaxDealsPerBlock,
+		FeePerBlock:             DefaultFeePerBlock,
+		MakerFeeRate:            sdk.MustNewDecFromStr(DefaultFeeRateMaker),
+		TakerFeeRate:            sdk.MustNewDecFromStr(DefaultFeeRateTaker),
+		VolumeWindowBlocks:      DefaultVolumeWindowBlocks,
+		NativeFeeDiscount:       sdk.MustNewDecFromStr(DefaultNativeFeeDiscount),
+		SelfTradePrevention:     DefaultSelfTradePrevention,
+		MaxOpenOrdersPerProduct: DefaultMaxOpenOrdersPerProduct,
+		MaxNewOrdersPerBlock:    DefaultMaxNewOrdersPerBlock,
+		OrderDeposit:            DefaultOrderDeposit,
+		DepositLockBlocks:       DefaultDepositLockBlocks,
+
+		ClosedOrderRetentionBlocks: DefaultClosedOrderRetentionBlocks,
 	}
 }
 
@@ -73,7 +144,109 @@ func (p Params) String() string {
 	sb.WriteString(fmt.Sprintf("OrderExpireBlocks: %d\n", p.OrderExpireBlocks))
 	sb.WriteString(fmt.Sprintf("MaxDealsPerBlock: %d\n", p.MaxDealsPerBlock))
 	sb.WriteString(fmt.Sprintf("FeePerBlock: %s\n", p.FeePerBlock))
-	sb.WriteString(fmt.Sprintf("TradeFeeRate: %s\n", p.TradeFeeRate))
+	sb.WriteString(fmt.Sprintf("MakerFeeRate: %s\n", p.MakerFeeRate))
+	sb.WriteString(fmt.Sprintf("TakerFeeRate: %s\n", p.TakerFeeRate))
+	for _, tier := range p.FeeTiers {
+		sb.WriteString(fmt.Sprintf("FeeTier: %s\n", tier))
+	}
+	sb.WriteString(fmt.Sprintf("VolumeWindowBlocks: %d\n", p.VolumeWindowBlocks))
+	sb.WriteString(fmt.Sprintf("NativeFeeDiscount: %s\n", p.NativeFeeDiscount))
+	sb.WriteString(fmt.Sprintf("SelfTradePrevention: %s\n", p.SelfTradePrevention))
+	sb.WriteString(fmt.Sprintf("MaxOpenOrdersPerProduct: %d\n", p.MaxOpenOrdersPerProduct))
+	sb.WriteString(fmt.Sprintf("MaxNewOrdersPerBlock: %d\n", p.MaxNewOrdersPerBlock))
+	sb.WriteString(fmt.Sprintf("OrderDeposit: %s\n", p.OrderDeposit))
+	sb.WriteString(fmt.Sprintf("DepositLockBlocks: %d\n", p.DepositLockBlocks))
+	sb.WriteString(fmt.Sprintf("ClosedOrderRetentionBlocks: %d\n", p.ClosedOrderRetentionBlocks))
 
 	return sb.String()
 }
+
+// FeeTier is the deal fee rates of the accounts whose trailing traded volume reaches MinVolume,
+// the volume is valued in the native token, so the deals whose quote token has no price against it don't count
+type FeeTier struct {
+	MinVolume    sdk.Dec `json:"min_volume"`
+	MakerFeeRate sdk.Dec `json:"maker_fee_rate"`
+	TakerFeeRate sdk.Dec `json:"taker_fee_rate"`
+}
+
+// String implements the stringer interface.
+func (tier FeeTier) String() string {
+	return fmt.Sprintf("MinVolume<%s> MakerFeeRate<%s> TakerFeeRate<%s>", tier.MinVolume, tier.MakerFeeRate,
+		tier.TakerFeeRate)
+}
+
+// GetDealFeeRate returns the deal fee rate of the highest fee tier the trailing traded volume reaches
+func (p Params) GetDealFeeRate(volume sdk.Dec, isMaker bool) sdk.Dec {
+	makerFeeRate, takerFeeRate := p.MakerFeeRate, p.TakerFeeRate
+	// fee tiers are sorted by min volume asc
+	for _, tier := range p.FeeTiers {
+		if volume.LT(tier.MinVolume) {
+			break
+		}
+		makerFeeRate, takerFeeRate = tier.MakerFeeRate, tier.TakerFeeRate
+	}
+	if isMaker {
+		return makerFeeRate
+	}
+	return takerFeeRate
+}
+
+// Validate checks the params are valid, which is done at genesis and after the params are changed by a proposal
+func (p Params) Validate() error {
+	if err := p.ValidateFeeTiers(); err != nil {
+		return err
+	}
+	if !IsValidSelfTradePrevention(p.SelfTradePrevention) {
+		return fmt.Errorf("invalid self-trade prevention mode: %s", p.SelfTradePrevention)
+	}
+	if err := p.ValidateOrderLimits(); err != nil {
+		return err
+	}
+	if p.ClosedOrderRetentionBlocks < 0 {
+		return fmt.Errorf("closed order retention blocks should not be negative, but got %d",
+			p.ClosedOrderRetentionBlocks)
+	}
+	return nil
+}
+
+// ValidateFeeTiers checks the deal fee rates are not negative, the native fee discount is in [0, 1), and the fee
+// tiers are sorted by min volume asc
+func (p Params) ValidateFeeTiers() error {
+	if p.MakerFeeRate.IsNegative() || p.TakerFeeRate.IsNegative() {
+		return fmt.Errorf("fee rates should not be negative")
+	}
+	if p.NativeFeeDiscount.IsNegative() || p.NativeFeeDiscount.GTE(sdk.OneDec()) {
+		return fmt.Errorf("native fee discount should be in [0, 1), but got %s", p.NativeFeeDiscount)
+	}
+	if p.VolumeWindowBlocks <= 0 {
+		return fmt.Errorf("volume window blocks should be positive, but got %d", p.VolumeWindowBlocks)
+	}
+	minVolume := sdk.ZeroDec()
+	for _, tier := range p.FeeTiers {
+		if !tier.MinVolume.GT(minVolume) {
+			return fmt.Errorf("min volumes of fee tiers should be positive and increasing, but got %s",
+				tier.MinVolume)
+		}
+		if tier.MakerFeeRate.IsNegative() || tier.TakerFeeRate.IsNegative() {
+			return fmt.Errorf("fee rates should not be negative, but got %s", tier)
+		}
+		minVolume = tier.MinVolume
+	}
+	return nil
+}
+
+// ValidateOrderLimits checks the order limits and the deposit lock blocks are not negative, and the order deposit
+// is valid
+func (p Params) ValidateOrderLimits() error {
+	if p.MaxOpenOrdersPerProduct < 0 || p.MaxNewOrdersPerBlock < 0 {
+		return fmt.Errorf("order limits should not be negative, but got %d open orders per product and "+
+			"%d new orders per block", p.MaxOpenOrdersPerProduct, p.MaxNewOrdersPerBlock)
+	}
+	if p.OrderDeposit.Amount.Int == nil || p.OrderDeposit.IsNegative() {
+		return fmt.Errorf("order deposit should not be negative, but got %s", p.OrderDeposit)
+	}
+	if p.DepositLockBlocks < 0 {
+		return fmt.Errorf("deposit lock blocks should not be negative, but got %d", p.DepositLockBlocks)
+	}
+	return nil
+}
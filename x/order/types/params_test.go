@@ -13,7 +13,20 @@ func TestParamSetPairs(t *testing.T) {
 			OrderExpireBlocks: 1000,
 			MaxDealsPerBlock:  10000,
 			FeePerBlock:       sdk.NewDecCoinFromDec(DefaultFeeDenomPerBlock, sdk.MustNewDecFromStr("0.000001")),
-			TradeFeeRate:      sdk.MustNewDecFromStr("0.001"),
+			MakerFeeRate:      sdk.MustNewDecFromStr("0.001"),
+			TakerFeeRate:      sdk.MustNewDecFromStr("0.002"),
+			FeeTiers: []FeeTier{
+				{sdk.NewDec(1000), sdk.MustNewDecFromStr("0.0005"), sdk.MustNewDecFromStr("0.001")},
+			},
+			VolumeWindowBlocks:      1000,
+			NativeFeeDiscount:       sdk.MustNewDecFromStr("0.25"),
+			SelfTradePrevention:     SelfTradePreventionCancelNewest,
+			MaxOpenOrdersPerProduct: 100,
+			MaxNewOrdersPerBlock:    50,
+			OrderDeposit:            sdk.NewDecCoinFromDec(DefaultFeeDenomPerBlock, sdk.MustNewDecFromStr("0.1")),
+			DepositLockBlocks:       10,
+
+			ClosedOrderRetentionBlocks: 100,
 		},
 	}
 
@@ -29,10 +42,36 @@ func TestParamSetPairs(t *testing.T) {
 				if !v.Value.(*sdk.DecCoin).IsEqual(test.FeePerBlock) {
 					t.Errorf("key(%s) -> %x, want %x", v.Key, test.FeePerBlock, v.Value)
 				}
-			case string(KeyTradeFeeRate):
-				if !v.Value.(*sdk.Dec).Equal(test.TradeFeeRate) {
-					t.Errorf("key(%s) -> %x, want %x", v.Key, test.TradeFeeRate, v.Value)
+			case string(KeyMakerFeeRate):
+				if !v.Value.(*sdk.Dec).Equal(test.MakerFeeRate) {
+					t.Errorf("key(%s) -> %x, want %x", v.Key, test.MakerFeeRate, v.Value)
+				}
+			case string(KeyTakerFeeRate):
+				if !v.Value.(*sdk.Dec).Equal(test.TakerFeeRate) {
+					t.Errorf("key(%s) -> %x, want %x", v.Key, test.TakerFeeRate, v.Value)
+				}
+			case string(KeyFeeTiers):
+				require.EqualValues(t, test.FeeTiers, *(v.Value.(*[]FeeTier)))
+			case string(KeyVolumeWindowBlocks):
+				require.EqualValues(t, test.VolumeWindowBlocks, *(v.Value.(*int64)))
+			case string(KeyNativeFeeDiscount):
+				if !v.Value.(*sdk.Dec).Equal(test.NativeFeeDiscount) {
+					t.Errorf("key(%s) -> %x, want %x", v.Key, test.NativeFeeDiscount, v.Value)
 				}
+			case string(KeySelfTradePrevention):
+				require.EqualValues(t, test.SelfTradePrevention, *(v.Value.(*string)))
+			case string(KeyMaxOpenOrdersPerProduct):
+				require.EqualValues(t, test.MaxOpenOrdersPerProduct, *(v.Value.(*int64)))
+			case string(KeyMaxNewOrdersPerBlock):
+				require.EqualValues(t, test.MaxNewOrdersPerBlock, *(v.Value.(*int64)))
+			case string(KeyOrderDeposit):
+				if !v.Value.(*sdk.DecCoin).IsEqual(test.OrderDeposit) {
+					t.Errorf("key(%s) -> %x, want %x", v.Key, test.OrderDeposit, v.Value)
+				}
+			case string(KeyDepositLockBlocks):
+				require.EqualValues(t, test.DepositLockBlocks, *(v.Value.(*int64)))
+			case string(KeyClosedOrderRetentionBlocks):
+				require.EqualValues(t, test.ClosedOrderRetentionBlocks, *(v.Value.(*int64)))
 			}
 
 		}
@@ -77,6 +116,48 @@ func TestParamSetPairs(t *testing.T) {
 
 func TestParamsString(t *testing.T) {
 	param := DefaultParams()
-	expectString := "Params: \nOrderExpireBlocks: 259200\nMaxDealsPerBlock: 1000\nFeePerBlock: 0.00000100okt\nTradeFeeRate: 0.00100000\n"
+	expectString := "Params: \nOrderExpireBlocks: 259200\nMaxDealsPerBlock: 1000\nFeePerBlock: 0.00000100okt\nMakerFeeRate: 0.00100000\nTakerFeeRate: 0.00100000\nVolumeWindowBlocks: 2592000\n" +
+		"NativeFeeDiscount: 0.25000000\nSelfTradePrevention: NONE\nMaxOpenOrdersPerProduct: 0\nMaxNewOrdersPerBlock: 0\n" +
+		"OrderDeposit: 0.00000000okt\nDepositLockBlocks: 0\nClosedOrderRetentionBlocks: 0\n"
 	require.EqualValues(t, expectString, param.String())
 }
+
+func TestValidateOrderLimits(t *testing.T) {
+	params := DefaultParams()
+	require.Nil(t, params.ValidateOrderLimits())
+
+	params.MaxOpenOrdersPerProduct = -1
+	require.NotNil(t, params.ValidateOrderLimits())
+	params = DefaultParams()
+	params.MaxNewOrdersPerBlock = -1
+	require.NotNil(t, params.ValidateOrderLimits())
+	params = DefaultParams()
+	params.OrderDeposit = sdk.DecCoin{Denom: DefaultFeeDenomPerBlock, Amount: sdk.NewDec(-1)}
+	require.NotNil(t, params.ValidateOrderLimits())
+	params = DefaultParams()
+	params.DepositLockBlocks = -1
+	require.NotNil(t, params.ValidateOrderLimits())
+}
+
+func TestParamsValidate(t *testing.T) {
+	params := DefaultParams()
+	require.Nil(t, params.Validate())
+
+	params.SelfTradePrevention = "CANCEL"
+	require.NotNil(t, params.Validate())
+	params = DefaultParams()
+	params.TakerFeeRate = sdk.NewDec(-1)
+	require.NotNil(t, params.Validate())
+	params = DefaultParams()
+	params.VolumeWindowBlocks = 0
+	require.NotNil(t, params.Validate())
+	params = DefaultParams()
+	params.NativeFeeDiscount = sdk.OneDec()
+	require.NotNil(t, params.Validate())
+	params = DefaultParams()
+	params.MaxNewOrdersPerBlock = -1
+	require.NotNil(t, params.Validate())
+	params = DefaultParams()
+	params.ClosedOrderRetentionBlocks = -1
+	require.NotNil(t, params.Validate())
+}
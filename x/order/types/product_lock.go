@@ -10,6 +10,13 @@ type ProductLock struct {
 	Quantity     sdk.Dec
 	BuyExecuted  sdk.Dec
 	SellExecuted sdk.Dec
+	// a product halted by its price band is locked with nothing to execute till HaltEndHeight
+	HaltEndHeight int64
+}
+
+// IsHalt checks whether the product is locked by a halt rather than an execution of the periodic auction
+func (lock *ProductLock) IsHalt() bool {
+	return lock.HaltEndHeight > 0
 }
 
 type ProductLockMap struct {
@@ -0,0 +1,19 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// BlockTradedVolume is the volume an account traded at a block, which is valued in the native token
+type BlockTradedVolume struct {
+	BlockHeight int64          `json:"block_height"`
+	Address     sdk.AccAddress `json:"address"`
+	Volume      sdk.Dec        `json:"volume"`
+}
+
+// String implements the stringer interface.
+func (volume BlockTradedVolume) String() string {
+	return fmt.Sprintf("BlockHeight<%d> Address<%s> Volume<%s>", volume.BlockHeight, volume.Address, volume.Volume)
+}
@@ -7,9 +7,15 @@ import (
 // const
 const (
 	CodeInvalidMaxProposalNum sdk.CodeType = 4
+	CodeInvalidParamSet       sdk.CodeType = 5
 )
 
 // ErrInvalidMaxProposalNum returns error when the number of params to change are out of limit
 func ErrInvalidMaxProposalNum(codespace sdk.CodespaceType, msg string) sdk.Error {
 	return sdk.NewError(codespace, CodeInvalidMaxProposalNum, msg)
-}
\ No newline at end of file
+}
+
+// ErrInvalidParamSet returns error when the params of a subspace are invalid after they're changed
+func ErrInvalidParamSet(codespace sdk.CodespaceType, subspace string, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidParamSet, "invalid params of %s: %s", subspace, msg)
+}
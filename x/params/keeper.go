@@ -17,13 +17,22 @@ type Keeper struct {
 	ck BankKeeper
 	// the reference to the GovKeeper to insert waiting queue
 	gk GovKeeper
+	// the param sets validated after their params are changed by a proposal, keyed by the subspace
+	paramSets map[string]func() ValidatableParamSet
+}
+
+// ValidatableParamSet is a param set which validates its params as a whole
+type ValidatableParamSet interface {
+	ParamSet
+	Validate() error
 }
 
 // NewKeeper creates a new instance of params keeper
 func NewKeeper(cdc *codec.Codec, key *sdk.KVStoreKey, tkey *sdk.TransientStoreKey, codespace sdk.CodespaceType) (
 	k Keeper) {
 	k = Keeper{
-		Keeper: sdkparams.NewKeeper(cdc, key, tkey, codespace),
+		Keeper:    sdkparams.NewKeeper(cdc, key, tkey, codespace),
+		paramSets: make(map[string]func() ValidatableParamSet),
 	}
 	k.paramSpace = k.Subspace(DefaultParamspace).WithKeyTable(ParamKeyTable())
 	return k
@@ -44,6 +53,12 @@ func (keeper *Keeper) SetGovKeeper(gk GovKeeper) {
 	keeper.gk = gk
 }
 
+// RegisterParamSet registers the param set of a subspace, whose params are validated as a whole after any of them
+// is changed by a proposal
+func (keeper *Keeper) RegisterParamSet(subspace string, newParamSet func() ValidatableParamSet) {
+	keeper.paramSets[subspace] = newParamSet
+}
+
 // SetParams sets the params into the store
 func (keeper *Keeper) SetParams(ctx sdk.Context, params Params) {
 	keeper.paramSpace.Set(ctx, ParamStoreKeyParamsParams, params)
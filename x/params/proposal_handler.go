@@ -41,11 +41,13 @@ func handleParameterChangeProposal(ctx sdk.Context, k *Keeper, proposal *govtype
 }
 
 func changeParams(ctx sdk.Context, k *Keeper, paramProposal ParameterChangeProposal) sdk.Error {
+	var changedSubspaces []string
 	for _, c := range paramProposal.Changes {
 		ss, ok := k.GetSubspace(c.Subspace)
 		if !ok {
 			return sdkparams.ErrUnknownSubspace(k.Codespace(), c.Subspace)
 		}
+		changedSubspaces = append(changedSubspaces, c.Subspace)
 
 		var err error
 		if len(c.Subkey) == 0 {
@@ -65,6 +67,25 @@ func changeParams(ctx sdk.Context, k *Keeper, paramProposal ParameterChangePropo
 			return sdkparams.ErrSettingParameter(k.Codespace(), c.Key, c.Subkey, c.Value, err.Error())
 		}
 	}
+	return validateParamSets(ctx, k, changedSubspaces)
+}
+
+// validateParamSets validates the registered param sets of the subspaces with the changed params
+func validateParamSets(ctx sdk.Context, k *Keeper, subspaces []string) sdk.Error {
+	validated := make(map[string]bool)
+	for _, subspace := range subspaces {
+		newParamSet, ok := k.paramSets[subspace]
+		if !ok || validated[subspace] {
+			continue
+		}
+		validated[subspace] = true
+		ss, _ := k.GetSubspace(subspace)
+		paramSet := newParamSet()
+		ss.GetParamSet(ctx, paramSet)
+		if err := paramSet.Validate(); err != nil {
+			return ErrInvalidParamSet(k.Codespace(), subspace, err.Error())
+		}
+	}
 	return nil
 }
 